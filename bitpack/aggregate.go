@@ -0,0 +1,93 @@
+package bitpack
+
+import (
+	"math"
+	"math/bits"
+)
+
+// MinValue returns the minimum value contained in a packed buffer (of any format) without
+// unpacking it
+func MinValue(b []byte) (uint64, error) {
+	return reduce(b, math.MaxUint64, func(acc, v uint64) uint64 {
+		return min(acc, v)
+	})
+}
+
+// MaxValue returns the maximum value contained in a packed buffer (of any format) without
+// unpacking it
+func MaxValue(b []byte) (uint64, error) {
+	return reduce(b, 0, func(acc, v uint64) uint64 {
+		return max(acc, v)
+	})
+}
+
+// Sum returns the sum of all values contained in a packed buffer (of any format) without
+// unpacking it (returning an error if the sum exceeds the range of uint64)
+func Sum(b []byte) (uint64, error) {
+	var (
+		sum, carry uint64
+		overflow   bool
+		err        error
+	)
+
+	// Run-length encoded data can be summed up per run
+	if data := payload(b); FormatOf(data) == FormatRLE {
+		err = iterateRuns(data, func(v, run uint64) bool {
+			hi, lo := bits.Mul64(v, run)
+			sum, carry = bits.Add64(sum, lo, 0)
+			overflow = hi != 0 || carry != 0
+			return !overflow
+		})
+	} else {
+		err = Iterate(b, func(_ int, v uint64) bool {
+			sum, carry = bits.Add64(sum, v, 0)
+			overflow = carry != 0
+			return !overflow
+		})
+	}
+
+	if err != nil {
+		return 0, err
+	}
+	if overflow {
+		return 0, ErrOverflow
+	}
+
+	return sum, nil
+}
+
+////////////////////////////////////////////////////////////////////////////////////////
+
+func reduce(b []byte, init uint64, fn func(acc, v uint64) uint64) (uint64, error) {
+	var (
+		acc = init
+		n   int
+		err error
+	)
+
+	// Run-length encoded data only requires inspection of the distinct values
+	if data := payload(b); FormatOf(data) == FormatRLE {
+		err = iterateRuns(data, func(v, run uint64) bool {
+			if run > 0 {
+				acc = fn(acc, v)
+				n++
+			}
+			return true
+		})
+	} else {
+		err = Iterate(b, func(_ int, v uint64) bool {
+			acc = fn(acc, v)
+			n++
+			return true
+		})
+	}
+
+	if err != nil {
+		return 0, err
+	}
+	if n == 0 {
+		return 0, ErrEmpty
+	}
+
+	return acc, nil
+}
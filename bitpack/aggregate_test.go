@@ -0,0 +1,67 @@
+package bitpack
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAggregates(t *testing.T) {
+	input := []uint64{7, 7, 7, 3, 1000, 1000, 12}
+
+	for _, buf := range [][]byte{
+		Pack(input),
+		Pack(input, WithBigEndian(), WithChecksum()),
+		PackVarint(input),
+		PackRLE(input),
+		AddHeader(PackRLE(input)),
+	} {
+		minVal, err := MinValue(buf)
+		require.Nil(t, err)
+		require.Equal(t, uint64(3), minVal)
+
+		maxVal, err := MaxValue(buf)
+		require.Nil(t, err)
+		require.Equal(t, uint64(1000), maxVal)
+
+		sum, err := Sum(buf)
+		require.Nil(t, err)
+		require.Equal(t, uint64(2036), sum)
+	}
+
+	bitsBuf := PackBits([]uint64{1, 0, 1, 1})
+	minVal, err := MinValue(bitsBuf)
+	require.Nil(t, err)
+	require.Zero(t, minVal)
+	sum, err := Sum(bitsBuf)
+	require.Nil(t, err)
+	require.Equal(t, uint64(3), sum)
+}
+
+func TestAggregatesEmpty(t *testing.T) {
+	for _, buf := range [][]byte{nil, Pack(nil), PackVarint(nil), PackRLE(nil)} {
+		_, err := MinValue(buf)
+		require.ErrorIs(t, err, ErrEmpty)
+		_, err = MaxValue(buf)
+		require.ErrorIs(t, err, ErrEmpty)
+
+		sum, err := Sum(buf)
+		require.Nil(t, err)
+		require.Zero(t, sum)
+	}
+}
+
+func TestSumOverflow(t *testing.T) {
+	_, err := Sum(Pack([]uint64{math.MaxUint64, 1}))
+	require.ErrorIs(t, err, ErrOverflow)
+	_, err = Sum(PackRLE([]uint64{math.MaxUint64 / 2, math.MaxUint64 / 2, math.MaxUint64 / 2}))
+	require.ErrorIs(t, err, ErrOverflow)
+
+	sum, err := Sum(PackRLE([]uint64{math.MaxUint64 / 2, math.MaxUint64 / 2}))
+	require.Nil(t, err)
+	require.Equal(t, uint64(math.MaxUint64-1), sum)
+
+	_, err = Sum([]byte{0x9, 0x1})
+	require.ErrorIs(t, err, ErrInvalidWidth)
+}
@@ -1,52 +1,196 @@
 package bitpack
 
 import (
+	"errors"
+	"fmt"
 	"math/bits"
+	"slices"
 )
 
+var (
+	// ErrInvalidWidth denotes that the byte width encoded in a packed buffer is invalid
+	ErrInvalidWidth = errors.New("invalid byte width")
+
+	// ErrIndexOutOfRange denotes that an element index exceeds the number of packed elements
+	ErrIndexOutOfRange = errors.New("index out of range")
+
+	// ErrInvalidHeader denotes that the header of a packed buffer is malformed
+	ErrInvalidHeader = errors.New("invalid header")
+
+	// ErrInvalidFormat denotes that a packed buffer uses an unknown format or contains malformed data
+	ErrInvalidFormat = errors.New("invalid format")
+
+	// ErrBufferTooSmall denotes that a provided buffer is too small to hold the packed data
+	ErrBufferTooSmall = errors.New("buffer too small")
+
+	// ErrChecksumMismatch denotes that the checksum of a packed buffer does not match its contents
+	ErrChecksumMismatch = errors.New("checksum mismatch")
+
+	// ErrUnsupportedVersion denotes that a packed buffer uses an unknown header version
+	ErrUnsupportedVersion = errors.New("unsupported version")
+
+	// ErrEmpty denotes that a packed buffer does not contain any elements
+	ErrEmpty = errors.New("no elements")
+
+	// ErrOverflow denotes that an aggregate exceeds the range of uint64
+	ErrOverflow = errors.New("overflow")
+
+	// ErrLimitExceeded denotes that a packed buffer contains more elements than permitted
+	ErrLimitExceeded = errors.New("element limit exceeded")
+
+	// ErrTruncated denotes that a packed buffer is shorter than required by its header / contents
+	ErrTruncated = errors.New("truncated buffer")
+)
+
+// PackOption denotes a functional option for Pack
+type PackOption func(*packConfig)
+
+// WithHeader prepends a self-describing, versioned header to the packed output (cf. AddHeader)
+func WithHeader() PackOption {
+	return func(cfg *packConfig) {
+		cfg.header = true
+	}
+}
+
+// WithBigEndian stores the elements in big-endian (network) byte order instead of the default
+// little-endian order, e.g. for consumption by non-Go readers (implies WithHeader, which allows
+// the unpack functions to detect the byte order automatically)
+func WithBigEndian() PackOption {
+	return func(cfg *packConfig) {
+		cfg.header = true
+		cfg.bigEndian = true
+	}
+}
+
+// WithChecksum appends a checksum trailer to the packed output (cf. AddChecksum)
+func WithChecksum() PackOption {
+	return func(cfg *packConfig) {
+		cfg.checksum = true
+	}
+}
+
 // Pack compresses a slice of uint64 values into a byte slice using the minimal
 // possible number of bytes to represent all values in the input slice.
 // The first byte of the output is reserved to hold the byte with for decompression
-func Pack(data []uint64) []byte {
+func Pack(data []uint64, opts ...PackOption) []byte {
+	var cfg packConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
 	neededBytes := getNeededBytes(data)
 
-	b := make([]byte, 1+len(data)*neededBytes)
-	b[0] = byte(neededBytes)
+	off := cfg.headerLen()
+	n := off + 1 + len(data)*neededBytes
+	b := make([]byte, n, n+cfg.trailerLen())
+	if cfg.header {
+		putHeader(b, cfg.headerFlags())
+	}
+	b[off] = byte(neededBytes)
 
-	b2 := b[1:]
-	switch neededBytes {
-	case 1:
-		packAll1(b2, data)
-	case 2:
-		packAll2(b2, data)
-	case 3:
-		packAll3(b2, data)
-	case 4:
-		packAll4(b2, data)
-	case 5:
-		packAll5(b2, data)
-	case 6:
-		packAll6(b2, data)
-	case 7:
-		packAll7(b2, data)
-	default:
-		packAll8(b2, data)
+	packAll(b[off+1:], data, neededBytes)
+	if cfg.bigEndian {
+		swapAllBytes(b[off+1:], neededBytes)
+	}
+
+	if cfg.checksum {
+		return AddChecksum(b)
 	}
 
 	return b
 }
 
+// PackWidth compresses a slice of uint64 values (cf. Pack) using a caller-provided byte width
+// (1-8), skipping the scan for the maximum value. The caller must ensure that all values can be
+// represented using the given width, otherwise they are silently truncated
+func PackWidth(data []uint64, width int) ([]byte, error) {
+	if width < 1 || width > 8 {
+		return nil, fmt.Errorf("%w: %d", ErrInvalidWidth, width)
+	}
+
+	b := make([]byte, 1+len(data)*width)
+	b[0] = byte(width)
+
+	packAll(b[1:], data, width)
+
+	return b, nil
+}
+
+// AppendPack compresses a slice of uint64 values (cf. Pack) and appends the result to the
+// provided byte slice, growing it if required (following the semantics of append). This allows
+// to write multiple packed blocks into a single buffer without allocating for each of them
+func AppendPack(dst []byte, data []uint64) []byte {
+	neededBytes := getNeededBytes(data)
+
+	n := 1 + len(data)*neededBytes
+	dst = slices.Grow(dst, n)
+	b := dst[len(dst) : len(dst)+n]
+	b[0] = byte(neededBytes)
+
+	packAll(b[1:], data, neededBytes)
+
+	return dst[:len(dst)+n]
+}
+
+// PackInto compresses a slice of uint64 values (cf. Pack) into a caller-provided buffer (e.g.
+// obtained from a concurrency.MemPool), returning the number of bytes written. If the buffer
+// is too small an error is returned (the required size can be determined via PackedSize)
+func PackInto(buf []byte, data []uint64) (n int, err error) {
+	neededBytes := getNeededBytes(data)
+
+	n = 1 + len(data)*neededBytes
+	if len(buf) < n {
+		return 0, fmt.Errorf("%w: %d bytes required, %d available", ErrBufferTooSmall, n, len(buf))
+	}
+	buf[0] = byte(neededBytes)
+
+	packAll(buf[1:n], data, neededBytes)
+
+	return n, nil
+}
+
+// PackedSize returns the number of bytes required to hold the packed representation of a slice
+// of uint64 values (cf. Pack)
+func PackedSize(data []uint64) int {
+	return 1 + len(data)*getNeededBytes(data)
+}
+
+// Stats returns the projected byte width, packed size (in bytes) and compression ratio (packed
+// size relative to the raw size of 8 bytes per element, i.e. lower is better) of a slice of uint64
+// values without actually packing it (cf. Pack). For an empty slice the ratio is zero
+func Stats(data []uint64) (byteWidth int, packedSize int, ratio float64) {
+	byteWidth = getNeededBytes(data)
+	packedSize = 1 + len(data)*byteWidth
+	if len(data) > 0 {
+		ratio = float64(packedSize) / float64(8*len(data))
+	}
+
+	return
+}
+
 // UnpackInto decompresses a compressed byte slice into a pre-existing slice of
 // uint64 values (which will be allocated / grown in case its capacity is insufficient)
 func UnpackInto(b []byte, res []uint64) []uint64 {
 
 	// If the byte slice is empty, truncate and return the buffer
+	flags := headerFlags(b)
+	b = payload(b)
 	if len(b) == 0 {
 		return res[:0]
 	}
+	switch FormatOf(b) {
+	case FormatVarint:
+		return unpackVarintInto(b[1:], res)
+	case FormatRLE:
+		return unpackRLEInto(b, res)
+	case FormatBits:
+		return unpackBitsInto(b, res)
+	case FormatChunked:
+		return unpackChunkedInto(b, res)
+	}
 
-	// If the number of unpacked bytes is zero, truncate and return the buffer
-	neededBytes := int(b[0])
+	// If the format or width is unknown, truncate and return the buffer
+	neededBytes := validWidth(b)
 	if neededBytes == 0 {
 		return res[:0]
 	}
@@ -57,24 +201,9 @@ func UnpackInto(b []byte, res []uint64) []uint64 {
 	}
 	res = res[:nElements]
 
-	b2 := b[1:]
-	switch neededBytes {
-	case 1:
-		unpackAll1(b2, res, nElements)
-	case 2:
-		unpackAll2(b2, res, nElements)
-	case 3:
-		unpackAll3(b2, res, nElements)
-	case 4:
-		unpackAll4(b2, res, nElements)
-	case 5:
-		unpackAll5(b2, res, nElements)
-	case 6:
-		unpackAll6(b2, res, nElements)
-	case 7:
-		unpackAll7(b2, res, nElements)
-	default:
-		unpackAll8(b2, res, nElements)
+	unpackAll(b[1:], res, nElements, neededBytes)
+	if flags&FlagBigEndian != 0 {
+		swapAll(res, neededBytes)
 	}
 
 	return res
@@ -85,66 +214,188 @@ func UnpackInto(b []byte, res []uint64) []uint64 {
 func Unpack(b []byte) []uint64 {
 
 	// If the byte slice is empty, return an empty result
+	flags := headerFlags(b)
+	b = payload(b)
 	if len(b) == 0 {
 		return []uint64{}
 	}
+	switch FormatOf(b) {
+	case FormatVarint:
+		return unpackVarintInto(b[1:], []uint64{})
+	case FormatRLE:
+		return unpackRLEInto(b, []uint64{})
+	case FormatBits:
+		return unpackBitsInto(b, []uint64{})
+	case FormatChunked:
+		return unpackChunkedInto(b, []uint64{})
+	}
 
-	// If the number of unpacked bytes is zero, return an empty result
-	neededBytes := int(b[0])
+	// If the format or width is unknown, return an empty result
+	neededBytes := validWidth(b)
 	if neededBytes == 0 {
 		return []uint64{}
 	}
 	nElements := (len(b) - 1) / neededBytes
 	res := make([]uint64, nElements)
 
-	b2 := b[1:]
-	switch neededBytes {
-	case 1:
-		unpackAll1(b2, res, nElements)
-	case 2:
-		unpackAll2(b2, res, nElements)
-	case 3:
-		unpackAll3(b2, res, nElements)
-	case 4:
-		unpackAll4(b2, res, nElements)
-	case 5:
-		unpackAll5(b2, res, nElements)
-	case 6:
-		unpackAll6(b2, res, nElements)
-	case 7:
-		unpackAll7(b2, res, nElements)
-	default:
-		unpackAll8(b2, res, nElements)
+	unpackAll(b[1:], res, nElements, neededBytes)
+	if flags&FlagBigEndian != 0 {
+		swapAll(res, neededBytes)
 	}
 
 	return res
 }
 
+// UnpackIntoLimit decompresses a compressed byte slice into a pre-existing slice of uint64
+// values (cf. UnpackInto), returning an error without allocating if the number of encoded elements
+// exceeds the provided limit (protecting against oversized or hostile input)
+func UnpackIntoLimit(b []byte, res []uint64, maxElements int) ([]uint64, error) {
+	if n := Len(b); n > maxElements {
+		return res[:0], fmt.Errorf("%w: %d elements (limit %d)", ErrLimitExceeded, n, maxElements)
+	}
+
+	return UnpackInto(b, res), nil
+}
+
+// UnpackLimit decompresses a previously compressed data slice into the original slice of uint64
+// values (cf. Unpack), returning an error without allocating if the number of encoded elements
+// exceeds the provided limit (protecting against oversized or hostile input)
+func UnpackLimit(b []byte, maxElements int) ([]uint64, error) {
+	if n := Len(b); n > maxElements {
+		return nil, fmt.Errorf("%w: %d elements (limit %d)", ErrLimitExceeded, n, maxElements)
+	}
+
+	return Unpack(b), nil
+}
+
 // Uint64At returns the decoded singular value from the provided slice at a given index from the
 // original slice
 func Uint64At(b []byte, at int, neededBytes int) uint64 {
 	return unpackTable[neededBytes]((b[neededBytes*at+1 : neededBytes*at+1+neededBytes]))
 }
 
+// At returns the decoded singular value at a given index from the original slice, reading the
+// byte width from the packed buffer (without unpacking any other elements)
+func At(b []byte, i int) (uint64, error) {
+	flags := headerFlags(b)
+	b = payload(b)
+	switch FormatOf(b) {
+	case FormatVarint:
+		if i >= 0 {
+			if v, ok := varintAt(b[1:], i); ok {
+				return v, nil
+			}
+		}
+		return 0, fmt.Errorf("%w: %d (length %d)", ErrIndexOutOfRange, i, numElements(b))
+	case FormatRLE:
+		return rleAt(b, i)
+	case FormatBits:
+		if v, ok := bitsAt(b, i); ok {
+			return v, nil
+		}
+		return 0, fmt.Errorf("%w: %d (length %d)", ErrIndexOutOfRange, i, numElements(b))
+	case FormatChunked:
+		return chunkedAt(b, i)
+	}
+
+	neededBytes := ByteWidth(b)
+	if neededBytes < 1 || neededBytes > 8 {
+		return 0, fmt.Errorf("%w: %d", ErrInvalidWidth, neededBytes)
+	}
+	if i < 0 || i >= numElements(b) {
+		return 0, fmt.Errorf("%w: %d (length %d)", ErrIndexOutOfRange, i, numElements(b))
+	}
+
+	if flags&FlagBigEndian != 0 {
+		return swapBytes(Uint64At(b, i, neededBytes), neededBytes), nil
+	}
+
+	return Uint64At(b, i, neededBytes), nil
+}
+
 // Len returns the number of encoded elements in the compressed bfer / byte slice
 func Len(b []byte) int {
-	if len(b) == 0 || b[0] == 0x0 {
-		return 0
-	}
-	return (len(b) - 1) / ByteWidth(b)
+	return numElements(payload(b))
 }
 
 // ByteWidth returns the amount of bytes used to encode each element in the input
-// from the encoded byte slice
+// from the encoded byte slice (zero for formats other than the fixed-width one)
 func ByteWidth(b []byte) int {
-	if len(b) == 0 {
+	if len(b) == 0 || FormatOf(b) != FormatFixed {
 		return 0
 	}
-	return int(b[0])
+	return int(b[0] & widthMask)
+}
+
+// BitWidth returns the amount of bits used to encode each element in the input from the
+// encoded byte slice (zero for variable-width formats)
+func BitWidth(b []byte) int {
+	if FormatOf(b) == FormatBits {
+		return int(b[0] & widthMask)
+	}
+	return 8 * ByteWidth(b)
 }
 
 ////////////////////////////////////////////////////////////////////////////////////////
 
+func numElements(b []byte) int {
+	if len(b) == 0 || header(b) == 0x0 {
+		return 0
+	}
+	switch FormatOf(b) {
+	case FormatVarint:
+		return varintLen(b[1:])
+	case FormatRLE:
+		return rleLen(b)
+	case FormatBits:
+		return bitsLen(b)
+	case FormatChunked:
+		return chunkedLen(b)
+	}
+
+	width := validWidth(b)
+	if width == 0 {
+		return 0
+	}
+	return (len(b) - 1) / width
+}
+
+// validWidth returns the byte width of a buffer in fixed-width format, or zero if the format
+// is unknown or the width is invalid
+func validWidth(b []byte) int {
+	if width := ByteWidth(b); width >= 1 && width <= 8 {
+		return width
+	}
+	return 0
+}
+
+type packConfig struct {
+	header    bool
+	bigEndian bool
+	checksum  bool
+}
+
+func (cfg packConfig) headerFlags() (flags HeaderFlags) {
+	if cfg.bigEndian {
+		flags |= FlagBigEndian
+	}
+	return
+}
+
+func (cfg packConfig) headerLen() int {
+	if cfg.header {
+		return headerLen
+	}
+	return 0
+}
+
+func (cfg packConfig) trailerLen() int {
+	if cfg.checksum {
+		return checksumLen
+	}
+	return 0
+}
+
 func getNeededBytes(data []uint64) int {
 	var maxVal uint64
 	for _, v := range data {
@@ -170,6 +421,27 @@ func neededBytes(val uint64) int {
 	return neededBytes + 1
 }
 
+func packAll(b []byte, data []uint64, neededBytes int) {
+	switch neededBytes {
+	case 1:
+		packAll1(b, data)
+	case 2:
+		packAll2(b, data)
+	case 3:
+		packAll3(b, data)
+	case 4:
+		packAll4(b, data)
+	case 5:
+		packAll5(b, data)
+	case 6:
+		packAll6(b, data)
+	case 7:
+		packAll7(b, data)
+	default:
+		packAll8(b, data)
+	}
+}
+
 func pack1(b []byte, x uint64) {
 	_ = b[0] // bounds check hint to compiler; see golang.org/issue/14808
 	b[0] = byte(x)
@@ -350,6 +622,27 @@ func unpackAll8(b []byte, res []uint64, n int) {
 	}
 }
 
+func unpackAllGeneric(b []byte, res []uint64, n, neededBytes int) {
+	switch neededBytes {
+	case 1:
+		unpackAll1(b, res, n)
+	case 2:
+		unpackAll2(b, res, n)
+	case 3:
+		unpackAll3(b, res, n)
+	case 4:
+		unpackAll4(b, res, n)
+	case 5:
+		unpackAll5(b, res, n)
+	case 6:
+		unpackAll6(b, res, n)
+	case 7:
+		unpackAll7(b, res, n)
+	default:
+		unpackAll8(b, res, n)
+	}
+}
+
 var unpackTable = [9]func(b []byte) uint64{
 	0x00: nil, // Should never happen (and panic)
 	0x01: unpack1,
@@ -53,11 +53,120 @@ func TestTable(t *testing.T) {
 			assert.Equal(t, c.input[i], Uint64At(buf, i, int(expectedNeededBytes)))
 		}
 
+		for i := 0; i < len(c.input); i++ {
+			v, err := At(buf, i)
+			require.Nil(t, err)
+			assert.Equal(t, c.input[i], v)
+		}
+
 		// Test extraction of number of elements
 		assert.Equal(t, Len(buf), len(c.input))
 	}
 }
 
+func TestAppendPack(t *testing.T) {
+	var (
+		buf    []byte
+		inputs = [][]uint64{{}, {1, 2, 3}, {0, 1, intPow(2, 63)}, {256, 65536}}
+	)
+	for _, input := range inputs {
+		buf = AppendPack(buf, input)
+	}
+
+	for _, input := range inputs {
+		expected := Pack(input)
+		require.Equal(t, expected, buf[:len(expected)])
+		buf = buf[len(expected):]
+	}
+	require.Empty(t, buf)
+
+	// Sufficient capacity must not cause a reallocation
+	dst := make([]byte, 2, 64)
+	res := AppendPack(dst, []uint64{1, 2, 3})
+	require.Equal(t, []byte{0x0, 0x0, 0x1, 0x1, 0x2, 0x3}, res)
+	require.Equal(t, &dst[:1][0], &res[0])
+}
+
+func TestPackInto(t *testing.T) {
+	for _, input := range [][]uint64{{}, {1, 2, 3}, {0, 1, intPow(2, 63)}, {256, 65536}} {
+		expected := Pack(input)
+		require.Equal(t, len(expected), PackedSize(input))
+
+		buf := make([]byte, len(expected)+3)
+		n, err := PackInto(buf, input)
+		require.Nil(t, err)
+		require.Equal(t, len(expected), n)
+		require.Equal(t, expected, buf[:n])
+
+		_, err = PackInto(buf[:len(expected)-1], input)
+		require.ErrorIs(t, err, ErrBufferTooSmall)
+	}
+}
+
+func TestPackWidth(t *testing.T) {
+	input := []uint64{1, 2, 255}
+	for width := 1; width <= 8; width++ {
+		packed, err := PackWidth(input, width)
+		require.Nil(t, err)
+		require.Equal(t, width, ByteWidth(packed))
+		require.Equal(t, input, Unpack(packed))
+		require.Nil(t, Validate(packed))
+	}
+
+	packed, err := PackWidth(input, 1)
+	require.Nil(t, err)
+	require.Equal(t, Pack(input), packed)
+
+	// Values exceeding the width are truncated
+	packed, err = PackWidth([]uint64{256, 1}, 1)
+	require.Nil(t, err)
+	require.Equal(t, []uint64{0, 1}, Unpack(packed))
+
+	for _, width := range []int{-1, 0, 9} {
+		_, err = PackWidth(input, width)
+		require.ErrorIs(t, err, ErrInvalidWidth)
+	}
+}
+
+func TestStats(t *testing.T) {
+	for _, input := range [][]uint64{{1, 2, 3}, {0, 1, intPow(2, 63)}, {256, 65536}} {
+		byteWidth, packedSize, ratio := Stats(input)
+
+		packed := Pack(input)
+		require.Equal(t, ByteWidth(packed), byteWidth)
+		require.Equal(t, len(packed), packedSize)
+		require.InDelta(t, float64(len(packed))/float64(8*len(input)), ratio, 1e-9)
+	}
+
+	byteWidth, packedSize, ratio := Stats(nil)
+	require.Equal(t, 1, byteWidth)
+	require.Equal(t, 1, packedSize)
+	require.Zero(t, ratio)
+}
+
+func TestUnpackLimit(t *testing.T) {
+	input := []uint64{1, 2, 1 << 20}
+	for _, packed := range [][]byte{Pack(input), Pack(input, WithChecksum()), PackVarint(input), PackRLE(input)} {
+		res, err := UnpackLimit(packed, 3)
+		require.Nil(t, err)
+		require.Equal(t, input, res)
+
+		res, err = UnpackIntoLimit(packed, make([]uint64, 0, 1), 3)
+		require.Nil(t, err)
+		require.Equal(t, input, res)
+
+		_, err = UnpackLimit(packed, 2)
+		require.ErrorIs(t, err, ErrLimitExceeded)
+		res, err = UnpackIntoLimit(packed, make([]uint64, 0, 1), 2)
+		require.ErrorIs(t, err, ErrLimitExceeded)
+		require.Empty(t, res)
+	}
+
+	// Run-length encoded buffers are limited by their logical (not physical) length
+	_, err := UnpackLimit(PackRLE([]uint64{1, 1, 1}), 2)
+	require.ErrorIs(t, err, ErrLimitExceeded)
+}
+
 func TestCorruptInput(t *testing.T) {
 	require.Zero(t, Len([]byte{0x0}))
 	require.Zero(t, Len([]byte{}))
@@ -74,6 +183,63 @@ func TestCorruptInput(t *testing.T) {
 	require.Empty(t, Unpack(buf))
 }
 
+func TestUnknownFormat(t *testing.T) {
+	chunk := append([]byte{0x50}, make([]byte, 5000)...)
+	for _, input := range [][]byte{
+		[]byte("X0000000000000"),
+		{0x50, 0x0, 0x0},
+		{0x60, 0x0, 0x0},
+		{0x70, 0x0, 0x0},
+		{0x9, 0x1, 0x2, 0x3, 0x4, 0x5, 0x6, 0x7, 0x8, 0x9},
+		append([]byte{0x40, 0x89, 0x27}, chunk...),
+	} {
+		require.NotPanics(t, func() {
+			require.Zero(t, Len(input))
+			require.Empty(t, Unpack(input))
+			require.Empty(t, UnpackInto(input, make([]uint64, 16)))
+			require.Empty(t, UnpackUint32(input))
+			require.Empty(t, UnpackUint16(input))
+			require.Empty(t, UnpackInt64(input))
+
+			res, err := UnpackLimit(input, 1)
+			require.Nil(t, err)
+			require.Empty(t, res)
+			_, err = UnpackDelta(append([]byte{0x1}, input...))
+			require.Nil(t, err)
+
+			require.Error(t, Iterate(input, func(int, uint64) bool { return true }))
+			_, err = MinValue(input)
+			require.Error(t, err)
+			_, err = MaxValue(input)
+			require.Error(t, err)
+			_, err = Sum(input)
+			require.Error(t, err)
+			_, err = At(input, 0)
+			require.Error(t, err)
+		}, "input: %x", input)
+	}
+}
+
+func TestAtInvalid(t *testing.T) {
+	buf := Pack([]uint64{1, 2, 3})
+
+	_, err := At(buf, -1)
+	require.ErrorIs(t, err, ErrIndexOutOfRange)
+	_, err = At(buf, 3)
+	require.ErrorIs(t, err, ErrIndexOutOfRange)
+
+	_, err = At(nil, 0)
+	require.ErrorIs(t, err, ErrInvalidWidth)
+	_, err = At([]byte{0x0}, 0)
+	require.ErrorIs(t, err, ErrInvalidWidth)
+	_, err = At([]byte{0x9, 0x1, 0x2}, 0)
+	require.ErrorIs(t, err, ErrInvalidWidth)
+
+	// Trailing bytes not forming a full element must not be accessible
+	_, err = At([]byte{0x2, 0x1, 0x0, 0x1}, 1)
+	require.ErrorIs(t, err, ErrIndexOutOfRange)
+}
+
 func TestAllByteWidths(t *testing.T) {
 	for i := 0; i < 64; i += 8 {
 		t.Run(fmt.Sprintf("%d_bytes", i/8+1), func(t *testing.T) {
@@ -163,6 +329,22 @@ func BenchmarkEncode(b *testing.B) {
 	})
 }
 
+func BenchmarkAppendPack(b *testing.B) {
+	var input []uint64
+	for i := 1; i < 512; i++ {
+		input = append(input, intPow(2, 31))
+	}
+	buf := make([]byte, 0, 1+len(input)*4)
+
+	b.ReportAllocs()
+	b.SetBytes(int64(len(input) * 8))
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		buf = AppendPack(buf[:0], input)
+	}
+}
+
 func BenchmarkDecodeAsBlock(b *testing.B) {
 
 	for nBytes := 1; nBytes <= 8; nBytes++ {
@@ -0,0 +1,108 @@
+package bitpack
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math/bits"
+)
+
+// PackBits compresses a slice of uint64 values into a byte slice using the minimal possible
+// number of bits (instead of bytes) per element if the maximum value can be represented in
+// fewer than eight bits (e.g. boolean or small enum values), falling back to the byte-aligned
+// format of Pack otherwise. The result can be decoded using any of the generic unpack functions
+func PackBits(data []uint64) []byte {
+	var maxVal uint64
+	for _, v := range data {
+		maxVal |= v
+	}
+
+	bitWidth := max(bits.Len64(maxVal), 1)
+	if bitWidth >= 8 {
+		return Pack(data)
+	}
+
+	// Layout: header byte (format + bit width), number of elements (varint), bit stream
+	w := bitWriter{
+		buf: make([]byte, 1, 1+binary.MaxVarintLen64+(len(data)*bitWidth+7)/8),
+	}
+	w.buf[0] = byte(FormatBits) | byte(bitWidth)
+	w.buf = binary.AppendUvarint(w.buf, uint64(len(data)))
+	for _, v := range data {
+		w.writeBits(v, bitWidth)
+	}
+
+	return w.buf
+}
+
+////////////////////////////////////////////////////////////////////////////////////////
+
+func bitsHeader(b []byte) (bitWidth, nElements int, data []byte, ok bool) {
+	bitWidth = int(b[0] & widthMask)
+	if bitWidth < 1 || bitWidth > 7 || len(b) < 2 {
+		return 0, 0, nil, false
+	}
+
+	n, k := binary.Uvarint(b[1:])
+	if k <= 0 {
+		return 0, 0, nil, false
+	}
+	data = b[1+k:]
+
+	// Limit the number of elements to what is actually contained in the buffer
+	if n > uint64(len(data)*8/bitWidth) { // #nosec G115
+		return 0, 0, nil, false
+	}
+
+	return bitWidth, int(n), data, true // #nosec G115
+}
+
+func unpackBitsInto(b []byte, res []uint64) []uint64 {
+	bitWidth, nElements, data, ok := bitsHeader(b)
+	if !ok {
+		return res[:0]
+	}
+
+	if cap(res) < nElements {
+		res = make([]uint64, nElements, nElements*2)
+	}
+	res = res[:nElements]
+
+	r := bitReader{buf: data}
+	for i := range res {
+		res[i], _ = r.readBits(bitWidth)
+	}
+
+	return res
+}
+
+func bitsAt(b []byte, at int) (uint64, bool) {
+	bitWidth, nElements, data, ok := bitsHeader(b)
+	if !ok || at < 0 || at >= nElements {
+		return 0, false
+	}
+
+	r := bitReader{buf: data, pos: at * bitWidth}
+	return r.readBits(bitWidth)
+}
+
+func bitsLen(b []byte) int {
+	_, nElements, _, _ := bitsHeader(b)
+	return nElements
+}
+
+func validateBits(b []byte) error {
+	bitWidth := int(b[0] & widthMask)
+	if bitWidth < 1 || bitWidth > 7 {
+		return fmt.Errorf("%w: %d bit(s)", ErrInvalidWidth, bitWidth)
+	}
+
+	_, nElements, data, ok := bitsHeader(b)
+	if !ok {
+		return fmt.Errorf("%w: invalid bit-level header", ErrTruncated)
+	}
+	if expected := (nElements*bitWidth + 7) / 8; len(data) != expected {
+		return fmt.Errorf("%w: %d byte(s) of data, expected %d", ErrInvalidFormat, len(data), expected)
+	}
+
+	return nil
+}
@@ -0,0 +1,71 @@
+package bitpack
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPackBits(t *testing.T) {
+	for bitWidth := 1; bitWidth <= 7; bitWidth++ {
+		t.Run(fmt.Sprintf("%d_bits", bitWidth), func(t *testing.T) {
+			for n := 0; n <= 20; n++ {
+				input := make([]uint64, n)
+				for i := range input {
+					input[i] = uint64(i*7) % (1 << bitWidth)
+				}
+				expectedWidth := 1
+				if n > 0 {
+					input[0] = 1<<bitWidth - 1
+					expectedWidth = bitWidth
+				}
+
+				buf := PackBits(input)
+				require.Nil(t, Validate(buf))
+				require.Equal(t, FormatBits, FormatOf(buf))
+				require.Equal(t, expectedWidth, BitWidth(buf))
+				require.Zero(t, ByteWidth(buf))
+				require.Equal(t, n, Len(buf))
+				require.Equal(t, 2+(n*BitWidth(buf)+7)/8, len(buf))
+
+				require.Equal(t, input, Unpack(buf))
+				require.Equal(t, input, UnpackInto(buf, make([]uint64, 0, 1)))
+				for i := range input {
+					v, err := At(buf, i)
+					require.Nil(t, err)
+					require.Equal(t, input[i], v)
+				}
+				_, err := At(buf, n)
+				require.ErrorIs(t, err, ErrIndexOutOfRange)
+			}
+		})
+	}
+}
+
+func TestPackBitsFallback(t *testing.T) {
+	input := []uint64{0, 1, 128}
+	require.Equal(t, Pack(input), PackBits(input))
+	require.Equal(t, 8, BitWidth(PackBits(input)))
+}
+
+func TestPackBitsInvalid(t *testing.T) {
+	buf := PackBits([]uint64{1, 0, 1, 1, 0, 1, 1, 1, 1})
+
+	for _, c := range []struct {
+		buf      []byte
+		expected error
+	}{
+		{[]byte{byte(FormatBits)}, ErrInvalidWidth},
+		{[]byte{byte(FormatBits) | 0x8, 0x1, 0x0}, ErrInvalidWidth},
+		{[]byte{byte(FormatBits) | 0x1}, ErrTruncated},
+		{buf[:len(buf)-1], ErrTruncated},
+		{append(buf, 0x0), ErrInvalidFormat},
+	} {
+		require.ErrorIs(t, Validate(c.buf), c.expected, "%x", c.buf)
+	}
+
+	// Element counts exceeding the buffer must not cause excessive allocations
+	require.Empty(t, Unpack([]byte{byte(FormatBits) | 0x1, 0xFF, 0xFF, 0xFF, 0xFF, 0x0F, 0x1}))
+	require.Zero(t, Len([]byte{byte(FormatBits) | 0x1, 0xFF, 0xFF, 0xFF, 0xFF, 0x0F, 0x1}))
+}
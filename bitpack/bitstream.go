@@ -0,0 +1,65 @@
+package bitpack
+
+// bitWriter denotes a simple MSB-first bit-level writer, appending to a byte slice
+type bitWriter struct {
+	buf  []byte
+	free int // Number of unused bits in the last byte of buf
+}
+
+func (w *bitWriter) writeBit(bit bool) {
+	if bit {
+		w.writeBits(1, 1)
+	} else {
+		w.writeBits(0, 1)
+	}
+}
+
+func (w *bitWriter) writeBits(v uint64, n int) {
+	for n > 0 {
+		if w.free == 0 {
+			w.buf = append(w.buf, 0)
+			w.free = 8
+		}
+
+		// Write as many of the (remaining) most significant bits as fit into the current byte
+		k := min(n, w.free)
+		chunk := byte((v >> (n - k)) & (1<<k - 1))
+		w.buf[len(w.buf)-1] |= chunk << (w.free - k)
+
+		w.free -= k
+		n -= k
+	}
+}
+
+// bitReader denotes a simple MSB-first bit-level reader, consuming a byte slice
+type bitReader struct {
+	buf []byte
+	pos int // Current position in bits
+}
+
+func (r *bitReader) remaining() int {
+	return len(r.buf)*8 - r.pos
+}
+
+func (r *bitReader) readBit() (bool, bool) {
+	v, ok := r.readBits(1)
+	return v == 1, ok
+}
+
+func (r *bitReader) readBits(n int) (v uint64, ok bool) {
+	if n > r.remaining() {
+		return 0, false
+	}
+
+	for n > 0 {
+		avail := 8 - r.pos%8
+		k := min(n, avail)
+		chunk := (r.buf[r.pos/8] >> (avail - k)) & (1<<k - 1)
+		v = v<<k | uint64(chunk)
+
+		r.pos += k
+		n -= k
+	}
+
+	return v, true
+}
@@ -0,0 +1,47 @@
+package bitpack
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBitStream(t *testing.T) {
+	type item struct {
+		v uint64
+		n int
+	}
+
+	var (
+		w     bitWriter
+		items []item
+	)
+	for i := 0; i < 1000; i++ {
+		n := rand.Intn(64) + 1                            // #nosec G404
+		v := rand.Uint64() & (math.MaxUint64 >> (64 - n)) // #nosec G404
+		items = append(items, item{v, n})
+		w.writeBits(v, n)
+	}
+	w.writeBit(true)
+	w.writeBit(false)
+
+	r := bitReader{buf: w.buf}
+	for _, it := range items {
+		v, ok := r.readBits(it.n)
+		require.True(t, ok)
+		require.Equal(t, it.v, v)
+	}
+	bit, ok := r.readBit()
+	require.True(t, ok)
+	require.True(t, bit)
+	bit, ok = r.readBit()
+	require.True(t, ok)
+	require.False(t, bit)
+
+	// Only padding is left
+	require.Less(t, r.remaining(), 8)
+	_, ok = r.readBits(8)
+	require.False(t, ok)
+}
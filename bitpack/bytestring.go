@@ -0,0 +1,126 @@
+package bitpack
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math/bits"
+)
+
+// bytesEncWidths denotes the (fixed) number of characters required to encode a chunk of n bytes
+// (index n) using the alphanumeric alphabet, the widths are unique, allowing to derive the length of
+// a trailing partial chunk from the length of the encoded string
+var bytesEncWidths = [9]int{0, 2, 3, 5, 6, 7, 9, 10, stringEncUint64MaxBytes}
+
+// EncodeBytesToString converts a byte slice to a compact string representation using only
+// alphanumeric characters (compatible e.g. with filesystem limitations), leading zero bytes are
+// preserved
+func EncodeBytesToString(data []byte) string {
+	nFull, rem := len(data)/8, len(data)%8
+
+	buf := make([]byte, nFull*stringEncUint64MaxBytes+bytesEncWidths[rem])
+	encodeBytesInto(data, buf)
+
+	return string(buf)
+}
+
+// DecodeBytesFromString converts a string representation of a byte slice (cf. EncodeBytesToString)
+// back to its original form
+func DecodeBytesFromString(enc string) ([]byte, error) {
+	nFull, remWidth := len(enc)/stringEncUint64MaxBytes, len(enc)%stringEncUint64MaxBytes
+
+	rem := -1
+	for i, width := range bytesEncWidths[:8] {
+		if width == remWidth {
+			rem = i
+			break
+		}
+	}
+	if rem < 0 {
+		return nil, fmt.Errorf("%w: unexpected length %d", ErrInvalidString, len(enc))
+	}
+
+	res := make([]byte, nFull*8+rem)
+	if err := decodeBytesInto(enc, res); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// EncodeUUIDToString converts a 16-byte ID (e.g. a UUID or a 128-bit hash) to a fixed-width string
+// representation (22 characters) using only alphanumeric characters
+func EncodeUUIDToString(id [16]byte) string {
+	buf := make([]byte, 2*stringEncUint64MaxBytes)
+	encodeBytesInto(id[:], buf)
+
+	return string(buf)
+}
+
+// DecodeUUIDFromString converts a string representation of a 16-byte ID (cf. EncodeUUIDToString)
+// back to its original form
+func DecodeUUIDFromString(enc string) (id [16]byte, err error) {
+	if len(enc) != 2*stringEncUint64MaxBytes {
+		return id, fmt.Errorf("%w: unexpected length %d", ErrInvalidString, len(enc))
+	}
+	err = decodeBytesInto(enc, id[:])
+
+	return
+}
+
+////////////////////////////////////////////////////////////////////////////////////////
+
+func encodeBytesInto(data, buf []byte) {
+	var chunk [8]byte
+	for len(data) > 0 {
+		n := min(len(data), 8)
+
+		// Interpret the chunk as big-endian number and encode it using a fixed width
+		chunk = [8]byte{}
+		copy(chunk[8-n:], data[:n])
+		cfg := encodeConfig{
+			alphabet: AlphabetAlphanumeric,
+			width:    bytesEncWidths[n],
+		}
+		buf = buf[cfg.encode(binary.BigEndian.Uint64(chunk[:]), buf):]
+		data = data[n:]
+	}
+}
+
+func decodeBytesInto(enc string, res []byte) error {
+	var chunk [8]byte
+	for len(res) > 0 {
+		n := min(len(res), 8)
+		width := bytesEncWidths[n]
+
+		num, err := decodeChunk(enc[:width])
+		if err != nil {
+			return err
+		}
+		if n < 8 && num>>(8*n) != 0 {
+			return fmt.Errorf("%w: chunk value exceeds %d bytes", ErrInvalidString, n)
+		}
+
+		binary.BigEndian.PutUint64(chunk[:], num)
+		copy(res, chunk[8-n:])
+		res, enc = res[n:], enc[width:]
+	}
+
+	return nil
+}
+
+func decodeChunk(enc string) (res uint64, err error) {
+	for i := len(enc); i > 0; i-- {
+		digit := AlphabetAlphanumeric.dec[enc[i-1]]
+		if digit < 0 {
+			return 0, fmt.Errorf("%w: invalid character `%c`", ErrInvalidString, enc[i-1])
+		}
+
+		hi, lo := bits.Mul64(res, stringEncUin64DictLen)
+		lo, carry := bits.Add64(lo, uint64(digit), 0)
+		if hi != 0 || carry != 0 {
+			return 0, fmt.Errorf("%w: chunk value overflows uint64 (%s)", ErrInvalidString, enc)
+		}
+		res = lo
+	}
+	return
+}
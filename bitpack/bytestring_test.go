@@ -0,0 +1,79 @@
+package bitpack
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncodeDecodeBytes(t *testing.T) {
+	hash := sha256.Sum256([]byte("test"))
+	for _, data := range [][]byte{
+		{},
+		{0},
+		{0, 0, 0},
+		{0xff},
+		{0, 1, 2, 3, 4, 5, 6, 7},
+		bytes.Repeat([]byte{0xff}, 8),
+		bytes.Repeat([]byte{0xff}, 15),
+		hash[:],
+	} {
+		enc := EncodeBytesToString(data)
+		for _, c := range []byte(enc) {
+			require.Contains(t, AlphabetAlphanumeric.String(), string(c))
+		}
+
+		dec, err := DecodeBytesFromString(enc)
+		require.Nil(t, err)
+		require.Equal(t, data, dec)
+	}
+
+	// Encoded lengths must be unique per input length
+	lengths := make(map[int]int)
+	for n := 0; n <= 64; n++ {
+		enc := EncodeBytesToString(make([]byte, n))
+		_, exists := lengths[len(enc)]
+		require.False(t, exists, n)
+		lengths[len(enc)] = n
+	}
+}
+
+func TestEncodeDecodeUUID(t *testing.T) {
+	for _, id := range [][16]byte{
+		{},
+		{0: 1},
+		{15: 1},
+		{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff},
+		{0x12, 0x3e, 0x45, 0x67, 0xe8, 0x9b, 0x12, 0xd3, 0xa4, 0x56, 0x42, 0x66, 0x14, 0x17, 0x40, 0x00},
+	} {
+		enc := EncodeUUIDToString(id)
+		require.Len(t, enc, 22)
+		require.Equal(t, EncodeBytesToString(id[:]), enc)
+
+		dec, err := DecodeUUIDFromString(enc)
+		require.Nil(t, err)
+		require.Equal(t, id, dec)
+	}
+}
+
+func TestInvalidDecodeBytes(t *testing.T) {
+	for _, enc := range []string{
+		"a",
+		"abcd",
+		"abcdefgh",
+		"ab-",
+		"ZZ",
+		strings.Repeat("Z", 11),
+	} {
+		_, err := DecodeBytesFromString(enc)
+		require.ErrorIs(t, err, ErrInvalidString, enc)
+	}
+
+	_, err := DecodeUUIDFromString("abc")
+	require.ErrorIs(t, err, ErrInvalidString)
+	_, err = DecodeUUIDFromString(strings.Repeat("-", 22))
+	require.ErrorIs(t, err, ErrInvalidString)
+}
@@ -0,0 +1,73 @@
+package bitpack
+
+import (
+	"encoding/binary"
+	"fmt"
+	"hash/crc32"
+)
+
+const checksumLen = crc32.Size
+
+var crcTable = crc32.MakeTable(crc32.Castagnoli)
+
+// AddChecksum flags a packed buffer (of any format) as carrying a checksum and appends a CRC32
+// (Castagnoli) trailer covering all preceding bytes of the payload, following the semantics of append. The
+// trailer is ignored by all unpack functions (and verified by Validate / UnpackChecked)
+func AddChecksum(b []byte) []byte {
+
+	// The checksum only covers the actual payload (following a versioned header, if present)
+	off := 0
+	if HasHeader(b) {
+		off = headerLen
+	}
+	if len(b) <= off || b[off]&flagChecksum != 0 {
+		return b
+	}
+
+	b[off] |= flagChecksum
+	return binary.LittleEndian.AppendUint32(b, crc32.Checksum(b[off:], crcTable))
+}
+
+// UnpackIntoChecked validates a packed buffer (including its checksum, if present, cf. Validate)
+// and decompresses it into a pre-existing slice of uint64 values (which will be allocated / grown
+// in case its capacity is insufficient)
+func UnpackIntoChecked(b []byte, res []uint64) ([]uint64, error) {
+	if err := Validate(b); err != nil {
+		return res[:0], err
+	}
+
+	return UnpackInto(b, res), nil
+}
+
+// UnpackChecked validates a packed buffer (including its checksum, if present, cf. Validate)
+// and decompresses it into the original slice of uint64 values
+func UnpackChecked(b []byte) ([]uint64, error) {
+	return UnpackIntoChecked(b, []uint64{})
+}
+
+////////////////////////////////////////////////////////////////////////////////////////
+
+func trimChecksum(b []byte) []byte {
+	if len(b) == 0 || b[0]&flagChecksum == 0 {
+		return b
+	}
+	if len(b) < 1+checksumLen {
+		return b[:1]
+	}
+
+	return b[:len(b)-checksumLen]
+}
+
+func verifyChecksum(b []byte) error {
+	if len(b) < 1+checksumLen {
+		return fmt.Errorf("%w: missing checksum trailer", ErrTruncated)
+	}
+
+	payload := b[:len(b)-checksumLen]
+	expected := binary.LittleEndian.Uint32(b[len(b)-checksumLen:])
+	if actual := crc32.Checksum(payload, crcTable); actual != expected {
+		return fmt.Errorf("%w: expected 0x%08x, got 0x%08x", ErrChecksumMismatch, expected, actual)
+	}
+
+	return nil
+}
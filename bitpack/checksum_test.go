@@ -0,0 +1,70 @@
+package bitpack
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestChecksum(t *testing.T) {
+	input := []uint64{1, 2, 3, 300, 70000}
+
+	for _, c := range []struct {
+		buf      []byte
+		expected []uint64
+	}{
+		{Pack(input, WithChecksum()), input},
+		{AddChecksum(PackVarint(input)), input},
+		{AddChecksum(PackRLE(input)), input},
+		{AddChecksum(PackBits([]uint64{1, 0, 1})), []uint64{1, 0, 1}},
+	} {
+		require.Nil(t, Validate(c.buf))
+		require.Equal(t, len(c.expected), Len(c.buf))
+
+		res, err := UnpackChecked(c.buf)
+		require.Nil(t, err)
+		require.Equal(t, c.expected, res)
+
+		// Adding a checksum twice must not change the buffer
+		require.Equal(t, c.buf, AddChecksum(c.buf))
+	}
+
+	// Transparent decoding of checksummed fixed-width buffers
+	buf := Pack(input, WithChecksum())
+	require.Equal(t, len(Pack(input))+checksumLen, len(buf))
+	require.Equal(t, input, Unpack(buf))
+	require.Equal(t, input, UnpackInto(buf, nil))
+	require.Equal(t, len(input), Len(buf))
+	require.Equal(t, 3, ByteWidth(buf))
+	for i := range input {
+		v, err := At(buf, i)
+		require.Nil(t, err)
+		require.Equal(t, input[i], v)
+	}
+	_, err := At(buf, len(input))
+	require.ErrorIs(t, err, ErrIndexOutOfRange)
+}
+
+func TestChecksumMismatch(t *testing.T) {
+	buf := Pack([]uint64{1, 2, 3, 300, 70000}, WithChecksum())
+
+	for i := range buf {
+		corrupt := append([]byte{}, buf...)
+		corrupt[i] ^= 0x4
+		if i == 0 {
+			corrupt[i] ^= 0x40
+		}
+		_, err := UnpackChecked(corrupt)
+		require.ErrorIs(t, err, ErrChecksumMismatch, "byte %d", i)
+	}
+
+	_, err := UnpackChecked(buf[:len(buf)-1])
+	require.ErrorIs(t, err, ErrChecksumMismatch)
+	_, err = UnpackChecked(buf[:3])
+	require.ErrorIs(t, err, ErrTruncated)
+
+	require.Empty(t, Unpack(buf[:3]))
+	require.Empty(t, Unpack([]byte{flagChecksum, 0x1, 0x2, 0x3, 0x4, 0x5}))
+	require.Zero(t, Len([]byte{flagChecksum, 0x1, 0x2, 0x3, 0x4, 0x5}))
+	require.Zero(t, Len(buf[:3]))
+}
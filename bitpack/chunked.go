@@ -0,0 +1,190 @@
+package bitpack
+
+import (
+	"encoding/binary"
+	"fmt"
+	"sync"
+
+	"github.com/fako1024/gotools/concurrency"
+)
+
+// ParallelChunkSize denotes the number of elements per independently packed chunk (cf. PackParallel)
+const ParallelChunkSize = 1 << 16
+
+// PackParallel compresses a (large) slice of uint64 values by splitting it into chunks of
+// ParallelChunkSize elements that are packed concurrently by up to the given number of workers.
+// Each chunk is width-reduced individually, the result can be decoded using any of the generic
+// unpack functions and multiple results can be concatenated using ConcatChunked
+func PackParallel(data []uint64, workers int) []byte {
+	nChunks := (len(data) + ParallelChunkSize - 1) / ParallelChunkSize
+
+	var (
+		chunks = make([][]byte, nChunks)
+		sem    = concurrency.New(max(workers, 1))
+		wg     sync.WaitGroup
+	)
+	for i := 0; i < nChunks; i++ {
+		sem.Add()
+		wg.Add(1)
+		go func(i int) {
+			defer func() {
+				sem.Done()
+				wg.Done()
+			}()
+
+			chunks[i] = Pack(data[i*ParallelChunkSize : min((i+1)*ParallelChunkSize, len(data))])
+		}(i)
+	}
+	wg.Wait()
+
+	// Layout: header byte, followed by a length-prefixed (varint) frame per chunk
+	size := 1
+	for _, chunk := range chunks {
+		size += binary.MaxVarintLen64 + len(chunk)
+	}
+	b := make([]byte, 1, size)
+	b[0] = byte(FormatChunked)
+	for _, chunk := range chunks {
+		b = binary.AppendUvarint(b, uint64(len(chunk)))
+		b = append(b, chunk...)
+	}
+
+	return b
+}
+
+// ConcatChunked concatenates multiple buffers in the multi-chunk format (cf. PackParallel)
+// into a single one without unpacking / repacking any of their chunks
+func ConcatChunked(bufs ...[]byte) ([]byte, error) {
+	size := 1
+	for i, buf := range bufs {
+		if len(buf) == 0 || buf[0] != byte(FormatChunked) {
+			return nil, fmt.Errorf("%w: buffer %d is not in multi-chunk format", ErrInvalidFormat, i)
+		}
+		size += len(buf) - 1
+	}
+
+	b := make([]byte, 1, size)
+	b[0] = byte(FormatChunked)
+	for _, buf := range bufs {
+		b = append(b, buf[1:]...)
+	}
+
+	return b, nil
+}
+
+////////////////////////////////////////////////////////////////////////////////////////
+
+// forEachChunk calls fn for each chunk of a buffer in multi-chunk format until it returns false
+func forEachChunk(b []byte, fn func(chunk []byte) bool) error {
+	for b = b[1:]; len(b) > 0; {
+		size, n := binary.Uvarint(b)
+		if n <= 0 || size > uint64(len(b)-n) { // #nosec G115
+			return fmt.Errorf("%w: invalid chunk frame", ErrTruncated)
+		}
+		chunk := b[n : n+int(size)] // #nosec G115
+		b = b[n+int(size):]         // #nosec G115
+
+		// Nested multi-chunk buffers are not supported (avoiding unbounded recursion)
+		if FormatOf(chunk) == FormatChunked {
+			return fmt.Errorf("%w: nested multi-chunk buffer", ErrInvalidFormat)
+		}
+		if !fn(chunk) {
+			return nil
+		}
+	}
+
+	return nil
+}
+
+func unpackChunkedInto(b []byte, res []uint64) []uint64 {
+	nElements := chunkedLen(b)
+	if cap(res) < nElements {
+		res = make([]uint64, nElements, nElements*2)
+	}
+	res = res[:0]
+
+	if err := forEachChunk(b, func(chunk []byte) bool {
+		res = append(res, UnpackInto(chunk, res[len(res):])...)
+		return true
+	}); err != nil {
+		return res[:0]
+	}
+
+	return res
+}
+
+func chunkedAt(b []byte, at int) (v uint64, err error) {
+	if at < 0 {
+		return 0, fmt.Errorf("%w: %d", ErrIndexOutOfRange, at)
+	}
+
+	found, idx := false, at
+	if err = forEachChunk(b, func(chunk []byte) bool {
+		if n := numElements(chunk); idx >= n {
+			idx -= n
+			return true
+		}
+		v, err = At(chunk, idx)
+		found = true
+		return false
+	}); err != nil {
+		return 0, err
+	}
+	if !found {
+		return 0, fmt.Errorf("%w: %d (length %d)", ErrIndexOutOfRange, at, at-idx)
+	}
+
+	return
+}
+
+func chunkedLen(b []byte) (n int) {
+	if err := forEachChunk(b, func(chunk []byte) bool {
+		n += numElements(chunk)
+		return true
+	}); err != nil {
+		return 0
+	}
+	return
+}
+
+func iterateChunked(b []byte, fn IterFunc) error {
+	var (
+		offset  int
+		stopped bool
+		err     error
+	)
+	if cerr := forEachChunk(b, func(chunk []byte) bool {
+		err = Iterate(chunk, func(i int, v uint64) bool {
+			stopped = !fn(offset+i, v)
+			return !stopped
+		})
+		offset += numElements(chunk)
+		return err == nil && !stopped
+	}); cerr != nil {
+		return cerr
+	}
+
+	return err
+}
+
+func validateChunked(b []byte) error {
+	if header(b) != byte(FormatChunked) {
+		return fmt.Errorf("%w: unknown header 0x%02x", ErrInvalidFormat, header(b))
+	}
+
+	var (
+		i   int
+		err error
+	)
+	if cerr := forEachChunk(b, func(chunk []byte) bool {
+		if err = Validate(chunk); err != nil {
+			err = fmt.Errorf("invalid chunk %d: %w", i, err)
+		}
+		i++
+		return err == nil
+	}); cerr != nil {
+		return cerr
+	}
+
+	return err
+}
@@ -0,0 +1,94 @@
+package bitpack
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPackParallel(t *testing.T) {
+	for _, n := range []int{0, 1, ParallelChunkSize - 1, ParallelChunkSize, 3*ParallelChunkSize + 17} {
+		input := make([]uint64, n)
+		for i := range input {
+			input[i] = uint64(i) * 3
+		}
+
+		for _, workers := range []int{0, 1, 4} {
+			buf := PackParallel(input, workers)
+			require.Nil(t, Validate(buf))
+			require.Equal(t, FormatChunked, FormatOf(buf))
+			require.Equal(t, n, Len(buf))
+
+			require.Equal(t, input, Unpack(buf))
+			require.Equal(t, input, UnpackInto(buf, make([]uint64, 0, 1)))
+		}
+
+		buf := PackParallel(input, 2)
+		for _, i := range []int{0, n / 2, n - 1} {
+			if i < 0 || i >= n {
+				continue
+			}
+			v, err := At(buf, i)
+			require.Nil(t, err)
+			require.Equal(t, input[i], v)
+		}
+		_, err := At(buf, n)
+		require.ErrorIs(t, err, ErrIndexOutOfRange)
+
+		var idx int
+		require.Nil(t, Iterate(buf, func(i int, v uint64) bool {
+			require.Equal(t, idx, i)
+			require.Equal(t, input[i], v)
+			idx++
+			return true
+		}))
+		require.Equal(t, n, idx)
+	}
+}
+
+func TestConcatChunked(t *testing.T) {
+	a, b := []uint64{1, 2, 3}, []uint64{1 << 40, 5}
+
+	buf, err := ConcatChunked(PackParallel(a, 1), PackParallel(nil, 1), PackParallel(b, 1))
+	require.Nil(t, err)
+	require.Nil(t, Validate(buf))
+	require.Equal(t, append(a, b...), Unpack(buf))
+
+	sum, err := Sum(buf)
+	require.Nil(t, err)
+	require.Equal(t, uint64(1<<40+11), sum)
+
+	_, err = ConcatChunked(PackParallel(a, 1), Pack(b))
+	require.ErrorIs(t, err, ErrInvalidFormat)
+}
+
+func TestChunkedInvalid(t *testing.T) {
+	buf := PackParallel([]uint64{1, 2, 3}, 1)
+
+	require.ErrorIs(t, Validate(buf[:len(buf)-1]), ErrTruncated)
+	require.Empty(t, Unpack(buf[:len(buf)-1]))
+	require.Zero(t, Len(buf[:len(buf)-1]))
+
+	nested := append([]byte{byte(FormatChunked), byte(len(buf))}, buf...)
+	require.ErrorIs(t, Validate(nested), ErrInvalidFormat)
+	require.ErrorIs(t, Iterate(nested, func(int, uint64) bool { return true }), ErrInvalidFormat)
+
+	corrupt := append([]byte{}, buf...)
+	corrupt[2] = 0x9
+	require.ErrorIs(t, Validate(corrupt), ErrInvalidWidth)
+}
+
+func BenchmarkPackParallel(b *testing.B) {
+	input := make([]uint64, 4*ParallelChunkSize)
+	for i := range input {
+		input[i] = uint64(i)
+	}
+
+	b.ReportAllocs()
+	b.SetBytes(int64(len(input) * 8))
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		PackParallel(input, 4)
+	}
+}
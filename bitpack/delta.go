@@ -0,0 +1,64 @@
+package bitpack
+
+import (
+	"encoding/binary"
+)
+
+// PackDelta compresses a slice of uint64 values by storing the first value followed by the
+// (zigzag-encoded) differences between consecutive values, which are then width-reduced as
+// in Pack. For monotonic sequences (e.g. timestamps or offsets) this typically yields a
+// much smaller width than packing the values themselves
+func PackDelta(data []uint64) []byte {
+	if len(data) == 0 {
+		return []byte{}
+	}
+
+	deltas := make([]uint64, len(data)-1)
+	for i := 1; i < len(data); i++ {
+		deltas[i-1] = zigzagEncode(int64(data[i] - data[i-1])) // #nosec G115
+	}
+	packed := Pack(deltas)
+
+	b := make([]byte, 0, binary.MaxVarintLen64+len(packed))
+	b = binary.AppendUvarint(b, data[0])
+
+	return append(b, packed...)
+}
+
+// UnpackDeltaInto decompresses a byte slice compressed via PackDelta into a pre-existing slice
+// of uint64 values (which will be allocated / grown in case its capacity is insufficient)
+func UnpackDeltaInto(b []byte, res []uint64) ([]uint64, error) {
+	if len(b) == 0 {
+		return res[:0], nil
+	}
+
+	first, n := binary.Uvarint(b)
+	if n <= 0 || n >= len(b) {
+		return res[:0], ErrInvalidHeader
+	}
+
+	// Decode the deltas directly into the result (offset by one to leave room for the
+	// first value) and accumulate them in-place
+	b = b[n:]
+	nElements := Len(b) + 1
+	if cap(res) < nElements {
+		res = make([]uint64, nElements, nElements*2)
+	}
+	res = res[:nElements]
+	if nElements > 1 {
+		UnpackInto(b, res[1:])
+	}
+
+	res[0] = first
+	for i := 1; i < nElements; i++ {
+		res[i] = res[i-1] + uint64(zigzagDecode(res[i])) // #nosec G115
+	}
+
+	return res, nil
+}
+
+// UnpackDelta decompresses a byte slice compressed via PackDelta into the original slice of
+// uint64 values
+func UnpackDelta(b []byte) ([]uint64, error) {
+	return UnpackDeltaInto(b, []uint64{})
+}
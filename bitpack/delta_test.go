@@ -0,0 +1,47 @@
+package bitpack
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPackDelta(t *testing.T) {
+	for _, input := range [][]uint64{
+		{},
+		{0},
+		{math.MaxUint64},
+		{1700000000000, 1700000000001, 1700000000005, 1700000000250},
+		{10, 5, 0, 5, 10},
+		{0, math.MaxUint64, 0, 1},
+	} {
+		buf := PackDelta(input)
+		res, err := UnpackDelta(buf)
+		require.Nil(t, err)
+		require.Equal(t, input, res)
+
+		res, err = UnpackDeltaInto(buf, make([]uint64, 0, 1))
+		require.Nil(t, err)
+		require.Equal(t, input, res)
+	}
+}
+
+func TestPackDeltaWidth(t *testing.T) {
+	input := make([]uint64, 1000)
+	for i := range input {
+		input[i] = 1700000000000000000 + uint64(i)*10
+	}
+
+	// Header (first value as varint) + width byte + one byte per delta
+	buf := PackDelta(input)
+	require.Equal(t, 9+1+len(input)-1, len(buf))
+	require.Less(t, len(buf), len(Pack(input))/7)
+}
+
+func TestUnpackDeltaInvalid(t *testing.T) {
+	_, err := UnpackDelta([]byte{0x80})
+	require.ErrorIs(t, err, ErrInvalidHeader)
+	_, err = UnpackDelta([]byte{0x1})
+	require.ErrorIs(t, err, ErrInvalidHeader)
+}
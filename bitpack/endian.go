@@ -0,0 +1,30 @@
+package bitpack
+
+import (
+	"math/bits"
+	"slices"
+)
+
+// swapBytes converts a value of the given byte width between little- and big-endian order
+func swapBytes(v uint64, neededBytes int) uint64 {
+	neededBytes = min(neededBytes, 8)
+	return bits.ReverseBytes64(v) >> (64 - 8*neededBytes)
+}
+
+// swapAll converts all values of the given byte width between little- and big-endian order
+func swapAll(res []uint64, neededBytes int) {
+	for i, v := range res {
+		res[i] = swapBytes(v, neededBytes)
+	}
+}
+
+// swapAllBytes converts all packed elements of the given byte width between little- and
+// big-endian order in-place
+func swapAllBytes(b []byte, neededBytes int) {
+	if neededBytes <= 1 {
+		return
+	}
+	for i := 0; i+neededBytes <= len(b); i += neededBytes {
+		slices.Reverse(b[i : i+neededBytes])
+	}
+}
@@ -0,0 +1,58 @@
+package bitpack
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBigEndian(t *testing.T) {
+	for _, input := range [][]uint64{
+		{},
+		{1, 2, 3},
+		{0x0102, 0x0304},
+		{0x010203, 0x040506},
+		{0x0102030405060708, 0x1},
+	} {
+		buf := Pack(input, WithBigEndian())
+		require.Nil(t, Validate(buf))
+
+		hdr, data, err := ParseHeader(buf)
+		require.Nil(t, err)
+		require.Equal(t, FlagBigEndian, hdr.Flags)
+
+		// Elements must be stored most significant byte first
+		width := ByteWidth(data)
+		for i, v := range input {
+			elem := make([]byte, 8)
+			copy(elem[8-width:], data[1+i*width:1+(i+1)*width])
+			require.Equal(t, v, binary.BigEndian.Uint64(elem))
+		}
+
+		require.Equal(t, input, Unpack(buf))
+		require.Equal(t, input, UnpackInto(buf, make([]uint64, 0, 1)))
+		for i := range input {
+			v, err := At(buf, i)
+			require.Nil(t, err)
+			require.Equal(t, input[i], v)
+		}
+	}
+
+	buf := Pack([]uint64{0x0102, 0x0304}, WithBigEndian(), WithChecksum())
+	require.Equal(t, []byte{0x7F, 'B', HeaderVersion, byte(FlagBigEndian), 0x2 | flagChecksum, 0x01, 0x02, 0x03, 0x04}, buf[:len(buf)-checksumLen])
+	res, err := UnpackChecked(buf)
+	require.Nil(t, err)
+	require.Equal(t, []uint64{0x0102, 0x0304}, res)
+
+	// Narrow / signed unpack variants must detect the byte order as well
+	require.Equal(t, []uint16{0x0102, 0x0304}, UnpackUint16(buf))
+	require.Equal(t, []int64{-300, 300}, UnpackInt64(Pack([]uint64{zigzagEncode(-300), zigzagEncode(300)}, WithBigEndian())))
+}
+
+func TestSwapBytes(t *testing.T) {
+	require.Equal(t, uint64(0x01), swapBytes(0x01, 1))
+	require.Equal(t, uint64(0x0201), swapBytes(0x0102, 2))
+	require.Equal(t, uint64(0x030201), swapBytes(0x010203, 3))
+	require.Equal(t, uint64(0x0807060504030201), swapBytes(0x0102030405060708, 8))
+}
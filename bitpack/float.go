@@ -0,0 +1,133 @@
+package bitpack
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"math/bits"
+)
+
+// PackFloat64 compresses a slice of float64 values using the XOR-based scheme introduced by
+// Facebook's Gorilla time series database: each value is XORed with its predecessor and only
+// the meaningful (non-zero) bits of the result are stored, such that repeated or slowly
+// changing values (as typical for metrics) require only a few bits each
+func PackFloat64(data []float64) []byte {
+	w := bitWriter{
+		buf: binary.AppendUvarint(make([]byte, 0, binary.MaxVarintLen64+len(data)), uint64(len(data))),
+	}
+	if len(data) == 0 {
+		return w.buf
+	}
+
+	prev := math.Float64bits(data[0])
+	w.writeBits(prev, 64)
+
+	prevLeading, prevTrailing := -1, 0
+	for _, f := range data[1:] {
+		cur := math.Float64bits(f)
+		xor := cur ^ prev
+		prev = cur
+
+		// Identical values are represented by a single bit
+		if xor == 0 {
+			w.writeBit(false)
+			continue
+		}
+		w.writeBit(true)
+
+		leading, trailing := min(bits.LeadingZeros64(xor), 31), bits.TrailingZeros64(xor)
+
+		// If the meaningful bits fall into the window of the previous value, reuse it
+		if prevLeading >= 0 && leading >= prevLeading && trailing >= prevTrailing {
+			w.writeBit(false)
+			w.writeBits(xor>>prevTrailing, 64-prevLeading-prevTrailing)
+			continue
+		}
+
+		// Otherwise, store the new window (5 bits leading zeros, 6 bits length, where a
+		// length of 64 is represented as zero) followed by the meaningful bits
+		sigBits := 64 - leading - trailing
+		w.writeBit(true)
+		w.writeBits(uint64(leading), 5)      // #nosec G115
+		w.writeBits(uint64(sigBits)&0x3F, 6) // #nosec G115
+		w.writeBits(xor>>trailing, sigBits)
+		prevLeading, prevTrailing = leading, trailing
+	}
+
+	return w.buf
+}
+
+// UnpackIntoFloat64 decompresses a byte slice compressed via PackFloat64 into a pre-existing slice
+// of float64 values (which will be allocated / grown in case its capacity is insufficient)
+func UnpackIntoFloat64(b []byte, res []float64) ([]float64, error) {
+	nElements, n := binary.Uvarint(b)
+	if n <= 0 {
+		return res[:0], ErrInvalidHeader
+	}
+	r := bitReader{buf: b[n:]}
+
+	// Each element except for the first one requires at least one bit, so the element count
+	// can be validated before allocating anything
+	if nElements > 0 && (r.remaining() < 64 || nElements-1 > uint64(r.remaining()-64)) { // #nosec G115
+		return res[:0], fmt.Errorf("%w: %d elements cannot fit into %d bytes", ErrTruncated, nElements, len(b)-n)
+	}
+
+	if uint64(cap(res)) < nElements {
+		res = make([]float64, nElements, nElements*2)
+	}
+	res = res[:nElements]
+	if nElements == 0 {
+		return res, nil
+	}
+
+	prev, _ := r.readBits(64)
+	res[0] = math.Float64frombits(prev)
+
+	var leading, trailing int
+	for i := 1; i < len(res); i++ {
+		changed, ok := r.readBit()
+		if !ok {
+			return res[:i], fmt.Errorf("%w: at element %d", ErrTruncated, i)
+		}
+		if !changed {
+			res[i] = math.Float64frombits(prev)
+			continue
+		}
+
+		newWindow, ok := r.readBit()
+		if !ok {
+			return res[:i], fmt.Errorf("%w: at element %d", ErrTruncated, i)
+		}
+		if newWindow {
+			l, okL := r.readBits(5)
+			s, okS := r.readBits(6)
+			if !okL || !okS {
+				return res[:i], fmt.Errorf("%w: at element %d", ErrTruncated, i)
+			}
+			if s == 0 {
+				s = 64
+			}
+			if l+s > 64 {
+				return res[:i], fmt.Errorf("%w: invalid window at element %d", ErrInvalidFormat, i)
+			}
+			leading, trailing = int(l), 64-int(l)-int(s)
+		} else if i == 1 {
+			return res[:i], fmt.Errorf("%w: missing window at element %d", ErrInvalidFormat, i)
+		}
+
+		xor, ok := r.readBits(64 - leading - trailing)
+		if !ok {
+			return res[:i], fmt.Errorf("%w: at element %d", ErrTruncated, i)
+		}
+		prev ^= xor << trailing
+		res[i] = math.Float64frombits(prev)
+	}
+
+	return res, nil
+}
+
+// UnpackFloat64 decompresses a byte slice compressed via PackFloat64 into the original slice of
+// float64 values
+func UnpackFloat64(b []byte) ([]float64, error) {
+	return UnpackIntoFloat64(b, []float64{})
+}
@@ -0,0 +1,68 @@
+package bitpack
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPackFloat64(t *testing.T) {
+	random := make([]float64, 1000)
+	for i := range random {
+		random[i] = rand.NormFloat64() * 1e6 // #nosec G404
+	}
+
+	for _, input := range [][]float64{
+		{},
+		{0},
+		{1.5},
+		{1, 1, 1, 1, 2, 2, 3.25},
+		{math.Inf(1), math.Inf(-1), math.MaxFloat64, math.SmallestNonzeroFloat64, -0.0, 0},
+		{math.Float64frombits(1), math.Float64frombits(math.MaxUint64 >> 1)},
+		random,
+	} {
+		buf := PackFloat64(input)
+		res, err := UnpackFloat64(buf)
+		require.Nil(t, err)
+		require.Equal(t, len(input), len(res))
+		for i := range input {
+			require.Equal(t, math.Float64bits(input[i]), math.Float64bits(res[i]))
+		}
+
+		res, err = UnpackIntoFloat64(buf, make([]float64, 0, 1))
+		require.Nil(t, err)
+		require.Equal(t, len(input), len(res))
+	}
+
+	// NaN values must be preserved bit-exactly
+	res, err := UnpackFloat64(PackFloat64([]float64{math.NaN(), 1}))
+	require.Nil(t, err)
+	require.True(t, math.IsNaN(res[0]))
+}
+
+func TestPackFloat64Ratio(t *testing.T) {
+	input := make([]float64, 1000)
+	for i := range input {
+		input[i] = 42.0 + float64(i%4)*0.5
+	}
+
+	// Slowly changing metrics must compress well below raw 8-byte encoding
+	require.Less(t, len(PackFloat64(input)), len(input)*8/4)
+}
+
+func TestUnpackFloat64Invalid(t *testing.T) {
+	_, err := UnpackFloat64(nil)
+	require.ErrorIs(t, err, ErrInvalidHeader)
+
+	// Element count exceeding the available data
+	_, err = UnpackFloat64([]byte{0xFF, 0xFF, 0xFF, 0xFF, 0x0F, 0x1})
+	require.ErrorIs(t, err, ErrTruncated)
+
+	buf := PackFloat64([]float64{1, 2, 3, 4.5, 1e10})
+	for i := 1; i < len(buf)-1; i++ {
+		_, err = UnpackFloat64(buf[:i])
+		require.ErrorIs(t, err, ErrTruncated, "length %d", i)
+	}
+}
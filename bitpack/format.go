@@ -0,0 +1,43 @@
+package bitpack
+
+// Format denotes the encoding format of a packed buffer, stored in the upper bits of its
+// header byte (the lower bits holding the byte width for fixed-width formats)
+type Format byte
+
+const (
+
+	// FormatFixed denotes the default fixed-width format (cf. Pack)
+	FormatFixed Format = 0x00
+
+	// FormatVarint denotes the per-element variable-width (LEB128) format (cf. PackVarint)
+	FormatVarint Format = 0x10
+
+	// FormatRLE denotes the run-length encoded format (cf. PackRLE)
+	FormatRLE Format = 0x20
+
+	// FormatBits denotes the sub-byte (bit-level) fixed-width format (cf. PackBits)
+	FormatBits Format = 0x30
+
+	// FormatChunked denotes the multi-chunk format consisting of independently packed chunks
+	// (cf. PackParallel)
+	FormatChunked Format = 0x40
+
+	formatMask   = 0x70
+	widthMask    = 0x0F
+	flagChecksum = 0x80
+)
+
+// FormatOf returns the encoding format of a packed buffer
+func FormatOf(b []byte) Format {
+	if len(b) == 0 {
+		return FormatFixed
+	}
+	return Format(b[0] & formatMask)
+}
+
+////////////////////////////////////////////////////////////////////////////////////////
+
+// header returns the header byte of a packed buffer without any flags
+func header(b []byte) byte {
+	return b[0] &^ flagChecksum
+}
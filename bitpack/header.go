@@ -0,0 +1,112 @@
+package bitpack
+
+import (
+	"fmt"
+)
+
+const (
+
+	// HeaderVersion denotes the current version of the self-describing header
+	HeaderVersion = 1
+
+	headerLen = 4
+)
+
+// The magic bytes identifying a versioned header. The first byte can never occur as the
+// first byte of a headerless (legacy) buffer, since it does not denote any valid format
+var headerMagic = [2]byte{0x7F, 'B'}
+
+// HeaderFlags denotes the set of flags stored in a versioned header
+type HeaderFlags byte
+
+const (
+
+	// FlagBigEndian denotes that the elements of a fixed-width buffer are stored in big-endian
+	// (network) byte order (cf. WithBigEndian)
+	FlagBigEndian HeaderFlags = 1 << iota
+)
+
+// Header denotes the self-describing, versioned header of a packed buffer, allowing different
+// encodings and future format revisions to coexist. Buffers without such a header (i.e. as
+// produced by Pack without the WithHeader option) are reported as version zero
+type Header struct {
+	Version byte
+	Flags   HeaderFlags
+}
+
+// HasHeader returns if a packed buffer starts with a versioned header
+func HasHeader(b []byte) bool {
+	return len(b) >= len(headerMagic) && b[0] == headerMagic[0] && b[1] == headerMagic[1]
+}
+
+// AddHeader prepends a versioned header to a packed buffer (of any format), which is returned
+// unmodified if it already has a header
+func AddHeader(b []byte) []byte {
+	if HasHeader(b) {
+		return b
+	}
+
+	res := make([]byte, headerLen+len(b))
+	putHeader(res, 0)
+	copy(res[headerLen:], b)
+
+	return res
+}
+
+// ParseHeader parses the versioned header of a packed buffer, returning it along with the
+// actual payload. For buffers without a versioned header, a zero-version header and the
+// unmodified buffer are returned
+func ParseHeader(b []byte) (Header, []byte, error) {
+	if !HasHeader(b) {
+		return Header{}, b, nil
+	}
+	if len(b) < headerLen {
+		return Header{}, nil, fmt.Errorf("%w: incomplete versioned header", ErrTruncated)
+	}
+
+	hdr := Header{
+		Version: b[2],
+		Flags:   HeaderFlags(b[3]),
+	}
+	if hdr.Version == 0 || hdr.Version > HeaderVersion {
+		return hdr, nil, fmt.Errorf("%w: %d", ErrUnsupportedVersion, hdr.Version)
+	}
+	if unknown := hdr.Flags &^ knownHeaderFlags; unknown != 0 {
+		return hdr, nil, fmt.Errorf("%w: unknown flag(s) 0x%02x", ErrInvalidHeader, byte(unknown))
+	}
+
+	return hdr, b[headerLen:], nil
+}
+
+////////////////////////////////////////////////////////////////////////////////////////
+
+const knownHeaderFlags = FlagBigEndian
+
+func putHeader(b []byte, flags HeaderFlags) {
+	_ = b[headerLen-1] // bounds check hint to compiler; see golang.org/issue/14808
+	b[0] = headerMagic[0]
+	b[1] = headerMagic[1]
+	b[2] = HeaderVersion
+	b[3] = byte(flags)
+}
+
+// headerFlags returns the flags of the versioned header of a packed buffer (if present)
+func headerFlags(b []byte) HeaderFlags {
+	if !HasHeader(b) || len(b) < headerLen {
+		return 0
+	}
+	return HeaderFlags(b[3])
+}
+
+// payload strips any versioned header and checksum trailer from a packed buffer, returning
+// the actual encoded data (a truncated header yields an empty payload)
+func payload(b []byte) []byte {
+	if HasHeader(b) {
+		if len(b) < headerLen {
+			return nil
+		}
+		b = b[headerLen:]
+	}
+
+	return trimChecksum(b)
+}
@@ -0,0 +1,89 @@
+package bitpack
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestHeader(t *testing.T) {
+	input := []uint64{1, 2, 3, 300, 70000}
+
+	for _, c := range []struct {
+		buf      []byte
+		checksum bool
+	}{
+		{Pack(input, WithHeader()), false},
+		{Pack(input, WithHeader(), WithChecksum()), true},
+		{AddHeader(Pack(input)), false},
+		{AddHeader(Pack(input, WithChecksum())), true},
+		{AddChecksum(AddHeader(Pack(input))), true},
+		{AddHeader(PackVarint(input)), false},
+		{AddHeader(PackRLE(input)), false},
+	} {
+		require.True(t, HasHeader(c.buf))
+		require.Nil(t, Validate(c.buf))
+
+		hdr, data, err := ParseHeader(c.buf)
+		require.Nil(t, err)
+		require.Equal(t, Header{Version: HeaderVersion}, hdr)
+		require.Equal(t, c.buf[headerLen:], data)
+
+		require.Equal(t, input, Unpack(c.buf))
+		require.Equal(t, input, UnpackInto(c.buf, nil))
+		require.Equal(t, len(input), Len(c.buf))
+		for i := range input {
+			v, err := At(c.buf, i)
+			require.Nil(t, err)
+			require.Equal(t, input[i], v)
+		}
+
+		res, err := UnpackChecked(c.buf)
+		require.Nil(t, err)
+		require.Equal(t, input, res)
+
+		// Adding a header twice must not change the buffer
+		require.Equal(t, c.buf, AddHeader(c.buf))
+
+		if c.checksum {
+			corrupt := append([]byte{}, c.buf...)
+			corrupt[headerLen+2] ^= 0x1
+			_, err = UnpackChecked(corrupt)
+			require.ErrorIs(t, err, ErrChecksumMismatch)
+		}
+	}
+
+	// Narrow / signed unpack variants must handle the header transparently
+	require.Equal(t, []uint32{1, 2, 3, 300, 70000}, UnpackUint32(Pack(input, WithHeader())))
+	require.Equal(t, []int64{-1, 1}, UnpackInt64(AddHeader(PackInt64([]int64{-1, 1}))))
+}
+
+func TestHeaderLegacy(t *testing.T) {
+	buf := Pack([]uint64{1, 2, 3})
+	require.False(t, HasHeader(buf))
+
+	hdr, data, err := ParseHeader(buf)
+	require.Nil(t, err)
+	require.Zero(t, hdr.Version)
+	require.Equal(t, buf, data)
+}
+
+func TestHeaderInvalid(t *testing.T) {
+	buf := Pack([]uint64{1, 2, 3}, WithHeader())
+
+	for _, c := range []struct {
+		buf      []byte
+		expected error
+	}{
+		{buf[:3], ErrTruncated},
+		{append([]byte{0x7F, 'B', 0x0, 0x0}, buf[headerLen:]...), ErrUnsupportedVersion},
+		{append([]byte{0x7F, 'B', HeaderVersion + 1, 0x0}, buf[headerLen:]...), ErrUnsupportedVersion},
+		{append([]byte{0x7F, 'B', HeaderVersion, 0x80}, buf[headerLen:]...), ErrInvalidHeader},
+		{buf[:headerLen], ErrTruncated},
+	} {
+		require.ErrorIs(t, Validate(c.buf), c.expected, "%x", c.buf)
+	}
+
+	require.Empty(t, Unpack(buf[:3]))
+	require.Zero(t, Len(buf[:3]))
+}
@@ -0,0 +1,119 @@
+package bitpack
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+// IterFunc denotes a function called for each element of a packed buffer, returning false
+// to stop the iteration
+type IterFunc func(i int, v uint64) bool
+
+// Iterate walks all elements of a packed buffer (of any format) in order without allocating
+// a destination slice, calling fn for each of them until it returns false
+func Iterate(b []byte, fn IterFunc) error {
+	flags := headerFlags(b)
+	if b = payload(b); len(b) == 0 {
+		return nil
+	}
+
+	switch FormatOf(b) {
+	case FormatFixed:
+		return iterateFixed(b, flags, fn)
+	case FormatVarint:
+		return iterateVarint(b[1:], fn)
+	case FormatRLE:
+		return iterateRLE(b, fn)
+	case FormatBits:
+		return iterateBits(b, fn)
+	case FormatChunked:
+		return iterateChunked(b, fn)
+	}
+
+	return fmt.Errorf("%w: unknown header 0x%02x", ErrInvalidFormat, header(b))
+}
+
+////////////////////////////////////////////////////////////////////////////////////////
+
+func iterateFixed(b []byte, flags HeaderFlags, fn IterFunc) error {
+	neededBytes := ByteWidth(b)
+	if neededBytes < 1 || neededBytes > 8 {
+		return fmt.Errorf("%w: %d", ErrInvalidWidth, neededBytes)
+	}
+
+	unpackFn, bigEndian := unpackTable[neededBytes], flags&FlagBigEndian != 0
+	for i, n := 0, numElements(b); i < n; i++ {
+		v := unpackFn(b[1+i*neededBytes:])
+		if bigEndian {
+			v = swapBytes(v, neededBytes)
+		}
+		if !fn(i, v) {
+			return nil
+		}
+	}
+
+	return nil
+}
+
+func iterateVarint(b []byte, fn IterFunc) error {
+	for i := 0; len(b) > 0; i++ {
+		v, n := binary.Uvarint(b)
+		if n == 0 {
+			return fmt.Errorf("%w: incomplete varint at element %d", ErrTruncated, i)
+		}
+		if n < 0 {
+			return fmt.Errorf("%w: varint overflow at element %d", ErrInvalidFormat, i)
+		}
+		if !fn(i, v) {
+			return nil
+		}
+		b = b[n:]
+	}
+
+	return nil
+}
+
+func iterateRLE(b []byte, fn IterFunc) error {
+	idx := 0
+	return iterateRuns(b, func(v, run uint64) bool {
+		for j := uint64(0); j < run; j++ {
+			if !fn(idx, v) {
+				return false
+			}
+			idx++
+		}
+		return true
+	})
+}
+
+func iterateRuns(b []byte, fn func(v, run uint64) bool) error {
+	r, err := parseRLE(b)
+	if err != nil {
+		return err
+	}
+
+	for i := 0; i < r.nRuns; i++ {
+		if !fn(r.value(i), r.run(i)) {
+			return nil
+		}
+	}
+
+	return nil
+}
+
+func iterateBits(b []byte, fn IterFunc) error {
+	bitWidth, nElements, data, ok := bitsHeader(b)
+	if !ok {
+		return fmt.Errorf("%w: invalid bit-level header", ErrTruncated)
+	}
+
+	r := bitReader{buf: data}
+	for i := 0; i < nElements; i++ {
+		v, _ := r.readBits(bitWidth)
+		if !fn(i, v) {
+			return nil
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,75 @@
+package bitpack
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestIterate(t *testing.T) {
+	input := []uint64{3, 3, 3, 1, 0, 7, 7, 2}
+	wide := []uint64{1, 300, 70000, math.MaxUint64}
+
+	for _, c := range []struct {
+		buf      []byte
+		expected []uint64
+	}{
+		{Pack(nil), []uint64{}},
+		{nil, []uint64{}},
+		{Pack(input), input},
+		{Pack(wide), wide},
+		{Pack(wide, WithBigEndian(), WithChecksum()), wide},
+		{PackVarint(wide), wide},
+		{PackRLE(input), input},
+		{PackBits(input), input},
+		{AddHeader(PackBits(input)), input},
+	} {
+		res := []uint64{}
+		require.Nil(t, Iterate(c.buf, func(i int, v uint64) bool {
+			require.Equal(t, len(res), i)
+			res = append(res, v)
+			return true
+		}))
+		require.Equal(t, c.expected, res)
+
+		// Early termination
+		var n int
+		require.Nil(t, Iterate(c.buf, func(i int, v uint64) bool {
+			n++
+			return i < 1
+		}))
+		require.Equal(t, min(len(c.expected), 2), n)
+	}
+}
+
+func TestIterateInvalid(t *testing.T) {
+	noop := func(int, uint64) bool { return true }
+
+	require.ErrorIs(t, Iterate([]byte{0x9, 0x1}, noop), ErrInvalidWidth)
+	require.ErrorIs(t, Iterate([]byte{0x71, 0x1}, noop), ErrInvalidFormat)
+	require.ErrorIs(t, Iterate([]byte{byte(FormatVarint), 0x1, 0x80}, noop), ErrTruncated)
+	require.ErrorIs(t, Iterate([]byte{byte(FormatRLE), 0x10}, noop), ErrTruncated)
+	require.ErrorIs(t, Iterate([]byte{byte(FormatBits) | 0x1}, noop), ErrTruncated)
+}
+
+func BenchmarkIterate(b *testing.B) {
+	var input []uint64
+	for i := 1; i < 512; i++ {
+		input = append(input, intPow(2, 31))
+	}
+	buf := Pack(input)
+
+	b.ReportAllocs()
+	b.SetBytes(int64(len(input) * 8))
+	b.ResetTimer()
+
+	var sum uint64
+	for i := 0; i < b.N; i++ {
+		_ = Iterate(buf, func(_ int, v uint64) bool {
+			sum += v
+			return true
+		})
+	}
+	_ = sum
+}
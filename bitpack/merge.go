@@ -0,0 +1,72 @@
+package bitpack
+
+import "fmt"
+
+// Merge combines two packed buffers into a single one holding the elements of a followed by the
+// elements of b. Buffers in the fixed-width format are merged in a single pass without unpacking
+// (re-widening the elements of the narrower buffer), all other formats are transparently unpacked
+// and repacked. The result is always in the (legacy) fixed-width format without header / checksum
+func Merge(a, b []byte) ([]byte, error) {
+	if err := Validate(a); err != nil {
+		return nil, fmt.Errorf("invalid first buffer: %w", err)
+	}
+	if err := Validate(b); err != nil {
+		return nil, fmt.Errorf("invalid second buffer: %w", err)
+	}
+
+	if !mergeable(a) || !mergeable(b) {
+		return Pack(append(Unpack(a), Unpack(b)...)), nil
+	}
+
+	pa, pb := payload(a), payload(b)
+	wa, wb := fixedWidth(pa), fixedWidth(pb)
+	width := max(wa, wb)
+
+	na, nb := numElements(pa), numElements(pb)
+	res := make([]byte, 1+(na+nb)*width)
+	res[0] = byte(width)
+
+	off := 1 + widenInto(res[1:], pa, wa, width)
+	widenInto(res[off:], pb, wb, width)
+
+	return res, nil
+}
+
+////////////////////////////////////////////////////////////////////////////////////////
+
+// mergeable returns if a (validated) buffer can be merged without unpacking
+func mergeable(b []byte) bool {
+	if headerFlags(b)&FlagBigEndian != 0 {
+		return false
+	}
+	p := payload(b)
+	return len(p) == 0 || FormatOf(p) == FormatFixed
+}
+
+// fixedWidth returns the byte width of a fixed-width payload (zero for an empty payload)
+func fixedWidth(p []byte) int {
+	if len(p) == 0 {
+		return 0
+	}
+	return ByteWidth(p)
+}
+
+// widenInto copies all elements of a fixed-width payload into dst using the target width (zero
+// extending each little-endian element), returning the number of bytes written
+func widenInto(dst, p []byte, width, targetWidth int) int {
+	if width == 0 || len(p) < 2 {
+		return 0
+	}
+	src := p[1:]
+	if width == targetWidth {
+		return copy(dst, src)
+	}
+
+	n := 0
+	for i := 0; i+width <= len(src); i += width {
+		copy(dst[n:n+width], src[i:i+width])
+		n += targetWidth
+	}
+
+	return n
+}
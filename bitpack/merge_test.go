@@ -0,0 +1,63 @@
+package bitpack
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMerge(t *testing.T) {
+	for _, inputs := range [][2][]uint64{
+		{{}, {}},
+		{{1, 2, 3}, {}},
+		{{}, {1 << 40}},
+		{{1, 2, 3}, {4, 5}},
+		{{1, 2, 3}, {1 << 40, 5}},
+		{{1 << 63, 7}, {1, 2, 255}},
+	} {
+		expected := append(append([]uint64{}, inputs[0]...), inputs[1]...)
+
+		res, err := Merge(Pack(inputs[0]), Pack(inputs[1]))
+		require.Nil(t, err)
+		require.Equal(t, Pack(expected), res)
+		require.Equal(t, expected, Unpack(res))
+
+		// Buffers with header / checksum / non-default byte order or other formats
+		for _, packed := range [][2][]byte{
+			{Pack(inputs[0], WithChecksum()), Pack(inputs[1], WithHeader())},
+			{Pack(inputs[0], WithBigEndian()), Pack(inputs[1])},
+			{PackVarint(inputs[0]), PackRLE(inputs[1])},
+			{Pack(inputs[0]), PackBits(inputs[1])},
+		} {
+			res, err := Merge(packed[0], packed[1])
+			require.Nil(t, err)
+			require.Equal(t, FormatFixed, FormatOf(res))
+			require.Equal(t, len(expected), Len(res))
+			if len(expected) > 0 {
+				require.Equal(t, expected, Unpack(res))
+			}
+		}
+	}
+}
+
+func TestMergeInvalid(t *testing.T) {
+	_, err := Merge([]byte{0x9, 0x0}, Pack([]uint64{1}))
+	require.ErrorIs(t, err, ErrInvalidWidth)
+	_, err = Merge(Pack([]uint64{1}), []byte{0x2, 0x0})
+	require.ErrorIs(t, err, ErrTruncated)
+}
+
+func BenchmarkMerge(b *testing.B) {
+	small, large := make([]uint64, 1<<16), make([]uint64, 1<<16)
+	for i := range small {
+		small[i], large[i] = uint64(i%256), uint64(i)<<20
+	}
+	a, c := Pack(small), Pack(large)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		_, _ = Merge(a, c)
+	}
+}
@@ -0,0 +1,116 @@
+package bitpack
+
+type narrowUint interface {
+	~uint16 | ~uint32
+}
+
+// PackUint32 compresses a slice of uint32 values into a byte slice (cf. Pack) without widening
+// them to an intermediate slice of uint64 values. The result is compatible with Pack, i.e. it
+// may be decoded using any of the unpack functions
+func PackUint32(data []uint32) []byte {
+	return packNarrow(data)
+}
+
+// PackUint16 compresses a slice of uint16 values into a byte slice (cf. Pack) without widening
+// them to an intermediate slice of uint64 values. The result is compatible with Pack, i.e. it
+// may be decoded using any of the unpack functions
+func PackUint16(data []uint16) []byte {
+	return packNarrow(data)
+}
+
+// UnpackIntoUint32 decompresses a byte slice into a pre-existing slice of uint32 values (which
+// will be allocated / grown in case its capacity is insufficient). Values exceeding the range
+// of uint32 are truncated
+func UnpackIntoUint32(b []byte, res []uint32) []uint32 {
+	return unpackNarrowInto(b, res)
+}
+
+// UnpackUint32 decompresses a byte slice into a slice of uint32 values (cf. UnpackIntoUint32)
+func UnpackUint32(b []byte) []uint32 {
+	return unpackNarrowInto(b, []uint32{})
+}
+
+// UnpackIntoUint16 decompresses a byte slice into a pre-existing slice of uint16 values (which
+// will be allocated / grown in case its capacity is insufficient). Values exceeding the range
+// of uint16 are truncated
+func UnpackIntoUint16(b []byte, res []uint16) []uint16 {
+	return unpackNarrowInto(b, res)
+}
+
+// UnpackUint16 decompresses a byte slice into a slice of uint16 values (cf. UnpackIntoUint16)
+func UnpackUint16(b []byte) []uint16 {
+	return unpackNarrowInto(b, []uint16{})
+}
+
+////////////////////////////////////////////////////////////////////////////////////////
+
+func packNarrow[T narrowUint](data []T) []byte {
+	var maxVal T
+	for _, v := range data {
+		if v > maxVal {
+			maxVal = v
+		}
+	}
+	neededBytes := neededBytes(uint64(maxVal))
+
+	b := make([]byte, 1+len(data)*neededBytes)
+	b[0] = byte(neededBytes)
+
+	b2 := b[1:]
+	switch neededBytes {
+	case 1:
+		for i, v := range data {
+			pack1(b2[i:], uint64(v))
+		}
+	case 2:
+		for i, v := range data {
+			pack2(b2[i*2:], uint64(v))
+		}
+	case 3:
+		for i, v := range data {
+			pack3(b2[i*3:], uint64(v))
+		}
+	default:
+		for i, v := range data {
+			pack4(b2[i*4:], uint64(v))
+		}
+	}
+
+	return b
+}
+
+func unpackNarrowInto[T narrowUint](b []byte, res []T) []T {
+	// Non-fixed-width formats and big-endian data are decoded generically and narrowed afterwards
+	if FormatOf(payload(b)) != FormatFixed || headerFlags(b)&FlagBigEndian != 0 {
+		values := Unpack(b)
+		if cap(res) < len(values) {
+			res = make([]T, len(values), len(values)*2)
+		}
+		res = res[:len(values)]
+		for i, v := range values {
+			res[i] = T(v)
+		}
+		return res
+	}
+
+	b = payload(b)
+	nElements := Len(b)
+	if cap(res) < nElements {
+		res = make([]T, nElements, nElements*2)
+	}
+	res = res[:nElements]
+	if nElements == 0 {
+		return res
+	}
+
+	neededBytes := ByteWidth(b)
+	if neededBytes > 8 {
+		neededBytes = 8
+	}
+	unpackFn := unpackTable[neededBytes]
+	for i := range res {
+		res[i] = T(unpackFn(b[1+i*neededBytes:]))
+	}
+
+	return res
+}
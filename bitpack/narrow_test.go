@@ -0,0 +1,68 @@
+package bitpack
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPackUint32(t *testing.T) {
+	for _, input := range [][]uint32{
+		{},
+		{0},
+		{1, 255},
+		{256, 65535},
+		{65536, 1<<24 - 1},
+		{1 << 24, math.MaxUint32},
+	} {
+		buf := PackUint32(input)
+
+		widened := make([]uint64, len(input))
+		for i, v := range input {
+			widened[i] = uint64(v)
+		}
+		require.Equal(t, Pack(widened), buf)
+		require.Equal(t, widened, Unpack(buf))
+
+		require.Equal(t, input, UnpackUint32(buf))
+		require.Equal(t, input, UnpackIntoUint32(buf, make([]uint32, 0, 1)))
+		require.Equal(t, input, UnpackUint32(PackVarint(widened)))
+	}
+}
+
+func TestPackUint16(t *testing.T) {
+	for _, input := range [][]uint16{
+		{},
+		{0},
+		{1, 255},
+		{256, math.MaxUint16},
+	} {
+		buf := PackUint16(input)
+
+		widened := make([]uint64, len(input))
+		for i, v := range input {
+			widened[i] = uint64(v)
+		}
+		require.Equal(t, Pack(widened), buf)
+
+		require.Equal(t, input, UnpackUint16(buf))
+		require.Equal(t, input, UnpackIntoUint16(buf, make([]uint16, 0, 1)))
+		require.Equal(t, input, UnpackUint16(PackBits(widened)))
+	}
+}
+
+func BenchmarkPackUint32(b *testing.B) {
+	input := make([]uint32, 4096)
+	for i := range input {
+		input[i] = uint32(i * 1000)
+	}
+
+	b.ReportAllocs()
+	b.SetBytes(int64(len(input) * 4))
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		PackUint32(input)
+	}
+}
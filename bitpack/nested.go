@@ -0,0 +1,121 @@
+package bitpack
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+// PackNested compresses a slice of uint64 slices (e.g. variable-length lists per record) by storing
+// all values contiguously (cf. Pack), preceded by a packed index of the offsets of the individual
+// slices, allowing to retrieve each of them without unpacking the others (cf. UnpackNestedAt)
+func PackNested(data [][]uint64) []byte {
+	var (
+		offsets = make([]uint64, len(data)+1)
+		nValues int
+	)
+	for i, row := range data {
+		nValues += len(row)
+		offsets[i+1] = uint64(nValues)
+	}
+
+	values := make([]uint64, 0, nValues)
+	for _, row := range data {
+		values = append(values, row...)
+	}
+
+	// Layout: size of the offsets block (varint), offsets block, values block
+	index := Pack(offsets)
+	b := make([]byte, 0, binary.MaxVarintLen64+len(index)+PackedSize(values))
+	b = binary.AppendUvarint(b, uint64(len(index)))
+	b = append(b, index...)
+
+	return AppendPack(b, values)
+}
+
+// NestedLen returns the number of slices in a byte slice compressed via PackNested
+func NestedLen(b []byte) int {
+	index, _, err := nestedBlocks(b)
+	if err != nil {
+		return 0
+	}
+	return Len(index) - 1
+}
+
+// UnpackNestedAt decompresses the i-th slice from a byte slice compressed via PackNested
+func UnpackNestedAt(b []byte, i int) ([]uint64, error) {
+	index, values, err := nestedBlocks(b)
+	if err != nil {
+		return nil, err
+	}
+	if i < 0 || i >= Len(index)-1 {
+		return nil, fmt.Errorf("%w: %d (length %d)", ErrIndexOutOfRange, i, Len(index)-1)
+	}
+
+	start, err := At(index, i)
+	if err != nil {
+		return nil, err
+	}
+	end, err := At(index, i+1)
+	if err != nil {
+		return nil, err
+	}
+	if start > end || end > uint64(Len(values)) {
+		return nil, fmt.Errorf("%w: invalid offsets [%d, %d)", ErrInvalidFormat, start, end)
+	}
+
+	width := ByteWidth(values)
+	res := make([]uint64, end-start)
+	if len(res) > 0 {
+		unpackAll(values[1+int(start)*width:1+int(end)*width], res, len(res), width) // #nosec G115
+	}
+
+	return res, nil
+}
+
+// UnpackNested decompresses a byte slice compressed via PackNested into the original slice of
+// uint64 slices
+func UnpackNested(b []byte) ([][]uint64, error) {
+	index, values, err := nestedBlocks(b)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		offsets = Unpack(index)
+		all     = Unpack(values)
+		res     = make([][]uint64, 0, max(len(offsets)-1, 0))
+	)
+	for i := 1; i < len(offsets); i++ {
+		if offsets[i-1] > offsets[i] || offsets[i] > uint64(len(all)) {
+			return nil, fmt.Errorf("%w: invalid offsets [%d, %d)", ErrInvalidFormat, offsets[i-1], offsets[i])
+		}
+		res = append(res, all[offsets[i-1]:offsets[i]:offsets[i]])
+	}
+
+	return res, nil
+}
+
+////////////////////////////////////////////////////////////////////////////////////////
+
+func nestedBlocks(b []byte) (index, values []byte, err error) {
+	size, n := binary.Uvarint(b)
+	if n <= 0 || size > uint64(len(b)-n) {
+		return nil, nil, ErrInvalidHeader
+	}
+	index, values = b[n:n+int(size)], b[n+int(size):] // #nosec G115
+
+	if err = Validate(index); err != nil {
+		return nil, nil, fmt.Errorf("invalid offsets block: %w", err)
+	}
+	if Len(index) < 1 {
+		return nil, nil, fmt.Errorf("%w: empty offsets block", ErrInvalidFormat)
+	}
+	if err = Validate(values); err != nil {
+		return nil, nil, fmt.Errorf("invalid values block: %w", err)
+	}
+	if len(values) == 0 || FormatOf(values) != FormatFixed {
+		return nil, nil, fmt.Errorf("%w: values block not in fixed-width format", ErrInvalidFormat)
+	}
+
+	return
+}
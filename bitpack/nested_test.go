@@ -0,0 +1,59 @@
+package bitpack
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPackNested(t *testing.T) {
+	for _, input := range [][][]uint64{
+		{},
+		{{}},
+		{{1, 2, 3}},
+		{{1, 2, 3}, {}, {1 << 40}, {4, 5, 6, 7, 8, 9, 10, 11, 12}},
+		{{}, {}, {0}},
+	} {
+		packed := PackNested(input)
+		require.Equal(t, len(input), NestedLen(packed))
+
+		res, err := UnpackNested(packed)
+		require.Nil(t, err)
+		require.Len(t, res, len(input))
+		for i, row := range input {
+			require.Equal(t, row, res[i])
+
+			rowRes, err := UnpackNestedAt(packed, i)
+			require.Nil(t, err)
+			require.Equal(t, row, rowRes)
+		}
+
+		_, err = UnpackNestedAt(packed, len(input))
+		require.ErrorIs(t, err, ErrIndexOutOfRange)
+		_, err = UnpackNestedAt(packed, -1)
+		require.ErrorIs(t, err, ErrIndexOutOfRange)
+	}
+}
+
+func TestPackNestedInvalid(t *testing.T) {
+	for _, b := range [][]byte{
+		nil,
+		{},
+		{0x5, 0x1},
+		{0x0},
+	} {
+		_, err := UnpackNested(b)
+		require.Error(t, err)
+		_, err = UnpackNestedAt(b, 0)
+		require.Error(t, err)
+		require.Zero(t, NestedLen(b))
+	}
+
+	// Offsets exceeding the values block
+	b := []byte{0x3, 0x1, 0x0, 0x5}
+	b = AppendPack(b, []uint64{1, 2})
+	_, err := UnpackNestedAt(b, 0)
+	require.ErrorIs(t, err, ErrInvalidFormat)
+	_, err = UnpackNested(b)
+	require.ErrorIs(t, err, ErrInvalidFormat)
+}
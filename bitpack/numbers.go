@@ -1,7 +1,12 @@
 package bitpack
 
 import (
-	"unsafe"
+	"errors"
+	"fmt"
+	"math"
+	"math/bits"
+
+	"github.com/fako1024/gotools/byteconv"
 )
 
 const (
@@ -28,16 +33,126 @@ var (
 	}
 )
 
+var (
+	// AlphabetAlphanumeric denotes the (default) alphabet consisting of digits and lower- / uppercase letters
+	AlphabetAlphanumeric = mustNewAlphabet("0123456789abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ")
+
+	// AlphabetLowercase denotes an alphabet consisting of digits and lowercase letters only (safe
+	// for use on case-insensitive filesystems)
+	AlphabetLowercase = mustNewAlphabet("0123456789abcdefghijklmnopqrstuvwxyz")
+
+	// AlphabetURLSafe denotes an alphabet consisting of all characters that do not require escaping
+	// in URLs (digits, letters, '-' and '_')
+	AlphabetURLSafe = mustNewAlphabet("0123456789abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ-_")
+
+	// AlphabetSortable denotes an alphabet consisting of digits and upper- / lowercase letters in
+	// ascending byte order (default alphabet for order-preserving encoding)
+	AlphabetSortable = mustNewAlphabet("0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz")
+
+	// ErrInvalidAlphabet denotes that an alphabet is too short or contains duplicate characters
+	ErrInvalidAlphabet = errors.New("invalid alphabet")
+
+	// ErrInvalidString denotes that a string cannot be decoded (e.g. due to invalid characters or length)
+	ErrInvalidString = errors.New("invalid string encoding")
+)
+
+// Alphabet denotes a set of characters used for string encoding of numbers (the first character
+// representing the zero digit)
+type Alphabet struct {
+	enc    []byte
+	dec    [256]int16
+	maxLen int
+}
+
+// NewAlphabet instantiates a new alphabet from the provided (single-byte) characters
+func NewAlphabet(chars string) (*Alphabet, error) {
+	if len(chars) < 2 {
+		return nil, fmt.Errorf("%w: at least two characters required", ErrInvalidAlphabet)
+	}
+	if len(chars) > 256 {
+		return nil, fmt.Errorf("%w: at most 256 characters allowed", ErrInvalidAlphabet)
+	}
+
+	a := &Alphabet{
+		enc: []byte(chars),
+	}
+	for i := range a.dec {
+		a.dec[i] = -1
+	}
+	for i, c := range a.enc {
+		if a.dec[c] >= 0 {
+			return nil, fmt.Errorf("%w: duplicate character `%c`", ErrInvalidAlphabet, c)
+		}
+		a.dec[c] = int16(i) // #nosec G115
+	}
+
+	// Determine the maximum length of an encoded uint64
+	for num := uint64(math.MaxUint64); num > 0; num /= uint64(len(a.enc)) {
+		a.maxLen++
+	}
+
+	return a, nil
+}
+
+// String returns the characters of the alphabet
+func (a *Alphabet) String() string {
+	return string(a.enc)
+}
+
+// IsSorted returns if the characters of the alphabet are in ascending byte order (which is required
+// for order-preserving encoding, cf. EncodeUint64ToSortableString)
+func (a *Alphabet) IsSorted() bool {
+	for i := 1; i < len(a.enc); i++ {
+		if a.enc[i] <= a.enc[i-1] {
+			return false
+		}
+	}
+	return true
+}
+
+// MaxLen returns the maximum length of a uint64 encoded using the alphabet (excluding any padding)
+func (a *Alphabet) MaxLen() int {
+	return a.maxLen
+}
+
+// EncodeOption denotes a functional option for string encoding / decoding of numbers
+type EncodeOption func(*encodeConfig)
+
+// WithAlphabet sets a custom alphabet for string encoding / decoding of numbers
+func WithAlphabet(a *Alphabet) EncodeOption {
+	return func(cfg *encodeConfig) {
+		cfg.alphabet = a
+	}
+}
+
+// WithPadding sets a fixed minimum width for string encoding of numbers (shorter representations
+// are padded using the zero character of the alphabet, which is transparent upon decoding)
+func WithPadding(width int) EncodeOption {
+	return func(cfg *encodeConfig) {
+		cfg.width = width
+	}
+}
+
 // EncodeUint64ToString converts a uint64 to the smallest possible strinng representation using
-// only alphanumeric characters (compatible e.g. with filesystem limitations)
-func EncodeUint64ToString(num uint64) string {
-	return EncodeUint64ToStringBuf(num, nil)
+// only alphanumeric characters (compatible e.g. with filesystem limitations), optionally using a
+// custom alphabet and / or fixed-width padding
+func EncodeUint64ToString(num uint64, opts ...EncodeOption) string {
+	return EncodeUint64ToStringBuf(num, nil, opts...)
 }
 
 // EncodeUint64ToStringBuf converts a uint64 to the smallest possible strinng representation using
 // only alphanumeric characters (compatible e.g. with filesystem limitations) using a buffer (must
 // have sufficient size
-func EncodeUint64ToStringBuf(num uint64, buf []byte) string {
+func EncodeUint64ToStringBuf(num uint64, buf []byte, opts ...EncodeOption) string {
+
+	// Handle custom alphabet / padding (if requested)
+	if len(opts) > 0 {
+		cfg := newEncodeConfig(opts)
+		if buf == nil {
+			buf = make([]byte, max(cfg.alphabet.maxLen, cfg.width))
+		}
+		return byteconv.BytesToString(buf[0:cfg.encode(num, buf)])
+	}
 
 	// Trivial case
 	if num == 0 {
@@ -53,14 +168,18 @@ func EncodeUint64ToStringBuf(num uint64, buf []byte) string {
 	n := EncodeUint64ToByteBuf(num, buf)
 
 	// Subslice to string length and cast to string (zero-allocation)
-	buf = buf[0:n]
-	return *(*string)(unsafe.Pointer(&buf)) // #nosec G103
+	return byteconv.BytesToString(buf[0:n])
 }
 
 // EncodeUint64ToByteBuf converts a uint64 to the smallest possible byte representation using
 // only alphanumeric characters (compatible e.g. with filesystem limitations) using a buffer (must
 // have sufficient size
-func EncodeUint64ToByteBuf(num uint64, buf []byte) (n int) {
+func EncodeUint64ToByteBuf(num uint64, buf []byte, opts ...EncodeOption) (n int) {
+
+	// Handle custom alphabet / padding (if requested)
+	if len(opts) > 0 {
+		return newEncodeConfig(opts).encode(num, buf)
+	}
 
 	// Trivial case
 	if num == 0 {
@@ -83,10 +202,150 @@ func encodeUint64ToByteBuf(num uint64, buf []byte) (n int) {
 }
 
 // DecodeUint64FromString converts a string representation of a uint64 back to its numeric representation
-func DecodeUint64FromString(enc string) (res uint64) {
+// (if a custom alphabet was used for encoding, the same has to be provided via WithAlphabet). The input
+// is not validated, use ParseUint64FromString for untrusted input
+func DecodeUint64FromString(enc string, opts ...EncodeOption) (res uint64) {
+	if len(opts) > 0 {
+		return newEncodeConfig(opts).decode(enc)
+	}
+
 	for i := len(enc); i > 0; i-- {
 		res *= stringEncUin64DictLen
 		res += decodeLookup[enc[i-1]]
 	}
 	return
 }
+
+// ParseUint64FromString converts a string representation of a uint64 back to its numeric representation
+// (cf. DecodeUint64FromString), returning an error if it is empty, contains characters not part of the
+// alphabet (e.g. an upper-cased string encoded using AlphabetLowercase) or overflows a uint64
+func ParseUint64FromString(enc string, opts ...EncodeOption) (uint64, error) {
+	return newEncodeConfig(opts).parse(enc, false)
+}
+
+// EncodeUint64ToSortableString converts a uint64 to a fixed-width string representation (most
+// significant digit first) whose lexicographic order matches the numeric order of the input, using
+// AlphabetSortable by default. Custom alphabets provided via WithAlphabet must be sorted (cf. IsSorted)
+// and the width equals the maximum length of an encoded uint64 (or the padding, if larger)
+func EncodeUint64ToSortableString(num uint64, opts ...EncodeOption) string {
+	cfg := encodeConfig{
+		alphabet: AlphabetSortable,
+	}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	if cfg.alphabet == nil {
+		cfg.alphabet = AlphabetSortable
+	}
+	cfg.width = max(cfg.width, cfg.alphabet.maxLen)
+
+	buf := make([]byte, cfg.width)
+	n := cfg.encode(num, buf)
+
+	// Reverse the digits (most significant digit first)
+	for i, j := 0, n-1; i < j; i, j = i+1, j-1 {
+		buf[i], buf[j] = buf[j], buf[i]
+	}
+
+	return byteconv.BytesToString(buf[:n])
+}
+
+// DecodeUint64FromSortableString converts an order-preserving string representation of a uint64
+// (cf. EncodeUint64ToSortableString) back to its numeric representation (if a custom alphabet was
+// used for encoding, the same has to be provided via WithAlphabet), returning an error if it is
+// empty, contains characters not part of the alphabet or overflows a uint64
+func DecodeUint64FromSortableString(enc string, opts ...EncodeOption) (uint64, error) {
+	cfg := encodeConfig{}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	if cfg.alphabet == nil {
+		cfg.alphabet = AlphabetSortable
+	}
+
+	return cfg.parse(enc, true)
+}
+
+////////////////////////////////////////////////////////////////////////////////////////
+
+type encodeConfig struct {
+	alphabet *Alphabet
+	width    int
+}
+
+func newEncodeConfig(opts []EncodeOption) encodeConfig {
+	cfg := encodeConfig{
+		alphabet: AlphabetAlphanumeric,
+	}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	if cfg.alphabet == nil {
+		cfg.alphabet = AlphabetAlphanumeric
+	}
+
+	return cfg
+}
+
+func (cfg encodeConfig) encode(num uint64, buf []byte) (n int) {
+	base := uint64(len(cfg.alphabet.enc))
+	for num > 0 || n == 0 {
+		buf[n] = cfg.alphabet.enc[num%base]
+		num /= base
+		n++
+	}
+
+	// Digits are stored least significant first, so padding is appended
+	for ; n < cfg.width; n++ {
+		buf[n] = cfg.alphabet.enc[0]
+	}
+
+	return
+}
+
+func (cfg encodeConfig) decode(enc string) (res uint64) {
+	base := uint64(len(cfg.alphabet.enc))
+	for i := len(enc); i > 0; i-- {
+		res *= base
+		if digit := cfg.alphabet.dec[enc[i-1]]; digit > 0 {
+			res += uint64(digit)
+		}
+	}
+	return
+}
+
+// parse decodes a string (least significant digit first unless msdFirst is set), rejecting characters
+// not part of the alphabet and values overflowing a uint64
+func (cfg encodeConfig) parse(enc string, msdFirst bool) (res uint64, err error) {
+	if len(enc) == 0 {
+		return 0, fmt.Errorf("%w: unexpected length 0", ErrInvalidString)
+	}
+
+	base := uint64(len(cfg.alphabet.enc))
+	for i := 0; i < len(enc); i++ {
+		c := enc[len(enc)-1-i]
+		if msdFirst {
+			c = enc[i]
+		}
+		digit := cfg.alphabet.dec[c]
+		if digit < 0 {
+			return 0, fmt.Errorf("%w: invalid character `%c`", ErrInvalidString, c)
+		}
+
+		hi, lo := bits.Mul64(res, base)
+		lo, carry := bits.Add64(lo, uint64(digit), 0)
+		if hi != 0 || carry != 0 {
+			return 0, fmt.Errorf("%w: value overflows uint64 (%s)", ErrInvalidString, enc)
+		}
+		res = lo
+	}
+	return
+}
+
+func mustNewAlphabet(chars string) *Alphabet {
+	a, err := NewAlphabet(chars)
+	if err != nil {
+		panic(err)
+	}
+	return a
+}
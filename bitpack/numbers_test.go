@@ -2,6 +2,8 @@ package bitpack
 
 import (
 	"fmt"
+	"sort"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -55,6 +57,127 @@ func TestInvalidDecodeUint64(t *testing.T) {
 	}
 }
 
+func TestEncodeDecodeUint64Options(t *testing.T) {
+	for _, alphabet := range []*Alphabet{AlphabetAlphanumeric, AlphabetLowercase, AlphabetURLSafe} {
+		for _, val := range []uint64{0, 1, 100, 10000, maxUint32, maxUint64} {
+			enc := EncodeUint64ToString(val, WithAlphabet(alphabet))
+			require.LessOrEqual(t, len(enc), alphabet.MaxLen())
+			for _, c := range []byte(enc) {
+				require.Contains(t, alphabet.String(), string(c))
+			}
+			require.Equal(t, val, DecodeUint64FromString(enc, WithAlphabet(alphabet)))
+
+			// Padded encoding must be of fixed width and decode transparently
+			padded := EncodeUint64ToString(val, WithAlphabet(alphabet), WithPadding(alphabet.MaxLen()))
+			require.Len(t, padded, alphabet.MaxLen())
+			require.Equal(t, val, DecodeUint64FromString(padded, WithAlphabet(alphabet)))
+		}
+	}
+
+	// The default alphabet must yield results identical to the non-optioned encoding
+	for _, val := range []uint64{0, 1, 100, maxUint64} {
+		require.Equal(t, EncodeUint64ToString(val), EncodeUint64ToString(val, WithAlphabet(AlphabetAlphanumeric)))
+	}
+	require.Equal(t, "1000", EncodeUint64ToString(1, WithPadding(4)))
+	require.Equal(t, uint64(1), DecodeUint64FromString("1000"))
+
+	lower := EncodeUint64ToString(maxUint64, WithAlphabet(AlphabetLowercase))
+	require.Equal(t, strings.ToLower(lower), lower)
+
+	buf := make([]byte, 16)
+	n := EncodeUint64ToByteBuf(35, buf, WithAlphabet(AlphabetLowercase), WithPadding(3))
+	require.Equal(t, "z00", string(buf[:n]))
+	require.Equal(t, "z00", EncodeUint64ToStringBuf(35, buf, WithAlphabet(AlphabetLowercase), WithPadding(3)))
+
+	binary, err := NewAlphabet("01")
+	require.Nil(t, err)
+	require.Equal(t, 64, binary.MaxLen())
+	require.Equal(t, "0101", EncodeUint64ToString(10, WithAlphabet(binary)))
+}
+
+func TestParseUint64(t *testing.T) {
+	for _, alphabet := range []*Alphabet{AlphabetAlphanumeric, AlphabetLowercase, AlphabetURLSafe} {
+		for _, val := range []uint64{0, 1, 100, maxUint32, maxUint64} {
+			res, err := ParseUint64FromString(EncodeUint64ToString(val, WithAlphabet(alphabet)), WithAlphabet(alphabet))
+			require.Nil(t, err)
+			require.Equal(t, val, res)
+
+			res, err = ParseUint64FromString(EncodeUint64ToString(val, WithAlphabet(alphabet), WithPadding(32)), WithAlphabet(alphabet))
+			require.Nil(t, err)
+			require.Equal(t, val, res)
+		}
+	}
+	res, err := ParseUint64FromString(EncodeUint64ToString(maxUint64))
+	require.Nil(t, err)
+	require.Equal(t, uint64(maxUint64), res)
+
+	// Characters outside of the alphabet (e.g. an upper-cased file name on a case-insensitive
+	// filesystem) and values overflowing a uint64 must be rejected
+	upper := strings.ToUpper(EncodeUint64ToString(1000, WithAlphabet(AlphabetLowercase)))
+	for _, cs := range []struct {
+		enc  string
+		opts []EncodeOption
+	}{
+		{"", nil},
+		{"a-b", nil},
+		{upper, []EncodeOption{WithAlphabet(AlphabetLowercase)}},
+		{"aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa", nil},
+		{EncodeUint64ToString(maxUint64) + "1", nil},
+		{"zzzzzzzzzzzzz", []EncodeOption{WithAlphabet(AlphabetLowercase)}},
+	} {
+		_, err := ParseUint64FromString(cs.enc, cs.opts...)
+		require.ErrorIs(t, err, ErrInvalidString, cs.enc)
+	}
+}
+
+func TestNewAlphabetInvalid(t *testing.T) {
+	for _, chars := range []string{"", "a", "abca", strings.Repeat("x", 257)} {
+		_, err := NewAlphabet(chars)
+		require.ErrorIs(t, err, ErrInvalidAlphabet)
+	}
+}
+
+func TestEncodeDecodeUint64Sortable(t *testing.T) {
+	require.True(t, AlphabetSortable.IsSorted())
+	require.True(t, AlphabetLowercase.IsSorted())
+	require.False(t, AlphabetAlphanumeric.IsSorted())
+	require.False(t, AlphabetURLSafe.IsSorted())
+
+	for _, alphabet := range []*Alphabet{AlphabetSortable, AlphabetLowercase} {
+		vals := []uint64{0, 1, 9, 10, 61, 62, 63, 100, 3843, 3844, 10000, maxUint32, 1 << 40, maxUint64 - 1, maxUint64}
+		encs := make([]string, len(vals))
+		for i, val := range vals {
+			encs[i] = EncodeUint64ToSortableString(val, WithAlphabet(alphabet))
+			require.Len(t, encs[i], alphabet.MaxLen())
+			dec, err := DecodeUint64FromSortableString(encs[i], WithAlphabet(alphabet))
+			require.Nil(t, err)
+			require.Equal(t, val, dec)
+		}
+		require.True(t, sort.StringsAreSorted(encs))
+	}
+
+	require.Equal(t, "00000000001", EncodeUint64ToSortableString(1))
+	require.Len(t, EncodeUint64ToSortableString(1, WithPadding(16)), 16)
+	dec, err := DecodeUint64FromSortableString(EncodeUint64ToSortableString(1, WithPadding(16)))
+	require.Nil(t, err)
+	require.Equal(t, uint64(1), dec)
+
+	// Externally provided names (e.g. from directory / S3 listings) must be validated
+	for _, cs := range []struct {
+		enc  string
+		opts []EncodeOption
+	}{
+		{"", nil},
+		{"0000000000.", nil},
+		{"0000000001Z", []EncodeOption{WithAlphabet(AlphabetLowercase)}},
+		{"1" + EncodeUint64ToSortableString(maxUint64), nil},
+		{"zzzzzzzzzzzz", nil},
+	} {
+		_, err := DecodeUint64FromSortableString(cs.enc, cs.opts...)
+		require.ErrorIs(t, err, ErrInvalidString, cs.enc)
+	}
+}
+
 // Test package level variables to avoid compiler optimizations in benchmarks
 var (
 	benchNum uint64
@@ -0,0 +1,163 @@
+package bitpack
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+)
+
+// maxRLERun denotes the maximum length of a single run (longer runs are split when packing),
+// bounding the number of elements a run-length encoded buffer can expand to relative to its size
+const maxRLERun = math.MaxUint16
+
+// PackRLE compresses a slice of uint64 values by collapsing runs of identical values into
+// (value, run length) pairs before width reduction, which is beneficial for data containing
+// long runs of identical values (e.g. protocol / port columns). The result can be decoded
+// using any of the generic unpack functions
+func PackRLE(data []uint64) []byte {
+	var values, runs []uint64
+	for i, v := range data {
+		if i > 0 && v == values[len(values)-1] && runs[len(runs)-1] < maxRLERun {
+			runs[len(runs)-1]++
+			continue
+		}
+		values = append(values, v)
+		runs = append(runs, 1)
+	}
+
+	// Layout: header byte, length of the packed values block (varint), packed values block,
+	// packed run lengths block
+	b := make([]byte, 1, 1+binary.MaxVarintLen64+PackedSize(values)+PackedSize(runs))
+	b[0] = byte(FormatRLE)
+	b = binary.AppendUvarint(b, uint64(PackedSize(values)))
+	b = AppendPack(b, values)
+
+	return AppendPack(b, runs)
+}
+
+////////////////////////////////////////////////////////////////////////////////////////
+
+// rleBuffer denotes the (validated) blocks of a run-length encoded buffer
+type rleBuffer struct {
+	values, runs           []byte
+	valuesWidth, runsWidth int
+	nRuns, nElements       int
+}
+
+func (r rleBuffer) value(i int) uint64 {
+	return Uint64At(r.values, i, r.valuesWidth)
+}
+
+func (r rleBuffer) run(i int) uint64 {
+	return Uint64At(r.runs, i, r.runsWidth)
+}
+
+func rleBlocks(b []byte) (values, runs []byte, ok bool) {
+	if len(b) < 2 {
+		return nil, nil, false
+	}
+
+	size, n := binary.Uvarint(b[1:])
+	if n <= 0 || size > uint64(len(b)-1-n) { // #nosec G115
+		return nil, nil, false
+	}
+	b = b[1+n:]
+
+	return b[:size], b[size:], true
+}
+
+// parseRLE parses and validates the blocks of a run-length encoded buffer, such that the (untrusted)
+// run lengths can be used without further checks. Since the length of each run is bounded (cf.
+// maxRLERun), so is the total number of elements relative to the size of the buffer
+func parseRLE(b []byte) (r rleBuffer, err error) {
+	var ok bool
+	if r.values, r.runs, ok = rleBlocks(b); !ok {
+		return r, fmt.Errorf("%w: invalid run-length header", ErrTruncated)
+	}
+
+	r.valuesWidth, r.runsWidth = validWidth(r.values), validWidth(r.runs)
+	if r.valuesWidth == 0 || r.runsWidth == 0 {
+		return r, fmt.Errorf("%w: invalid run-length blocks", ErrInvalidWidth)
+	}
+	r.nRuns = numElements(r.values)
+	if nRunLengths := numElements(r.runs); r.nRuns != nRunLengths {
+		return r, fmt.Errorf("%w: %d values vs. %d run lengths", ErrInvalidFormat, r.nRuns, nRunLengths)
+	}
+
+	for i := 0; i < r.nRuns; i++ {
+		run := r.run(i)
+		if run == 0 || run > maxRLERun || int(run) > math.MaxInt-r.nElements { // #nosec G115
+			return r, fmt.Errorf("%w: invalid run length %d", ErrInvalidFormat, run)
+		}
+		r.nElements += int(run) // #nosec G115
+	}
+
+	return r, nil
+}
+
+func unpackRLEInto(b []byte, res []uint64) []uint64 {
+	r, err := parseRLE(b)
+	if err != nil {
+		return res[:0]
+	}
+
+	if cap(res) < r.nElements {
+		res = make([]uint64, r.nElements, r.nElements*2)
+	}
+	res = res[:0]
+	for i := 0; i < r.nRuns; i++ {
+		v := r.value(i)
+		for j := r.run(i); j > 0; j-- {
+			res = append(res, v)
+		}
+	}
+
+	return res
+}
+
+func rleAt(b []byte, at int) (uint64, error) {
+	r, err := parseRLE(b)
+	if err != nil {
+		return 0, err
+	}
+	if at < 0 || at >= r.nElements {
+		return 0, fmt.Errorf("%w: %d (length %d)", ErrIndexOutOfRange, at, r.nElements)
+	}
+
+	for i := 0; ; i++ {
+		run := int(r.run(i)) // #nosec G115
+		if at < run {
+			return r.value(i), nil
+		}
+		at -= run
+	}
+}
+
+func rleLen(b []byte) int {
+	r, err := parseRLE(b)
+	if err != nil {
+		return 0
+	}
+
+	return r.nElements
+}
+
+func validateRLE(b []byte) error {
+	if header(b) != byte(FormatRLE) {
+		return fmt.Errorf("%w: unknown header 0x%02x", ErrInvalidFormat, header(b))
+	}
+
+	valuesBlock, runsBlock, ok := rleBlocks(b)
+	if !ok {
+		return fmt.Errorf("%w: invalid run-length header", ErrTruncated)
+	}
+	if err := Validate(valuesBlock); err != nil || FormatOf(valuesBlock) != FormatFixed {
+		return fmt.Errorf("%w: invalid values block: %w", ErrInvalidFormat, err)
+	}
+	if err := Validate(runsBlock); err != nil || FormatOf(runsBlock) != FormatFixed {
+		return fmt.Errorf("%w: invalid run lengths block: %w", ErrInvalidFormat, err)
+	}
+
+	_, err := parseRLE(b)
+	return err
+}
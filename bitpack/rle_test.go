@@ -0,0 +1,101 @@
+package bitpack
+
+import (
+	"encoding/hex"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPackRLE(t *testing.T) {
+	long := make([]uint64, 10000)
+	for i := range long {
+		long[i] = uint64(i / 1000 * 443)
+	}
+	huge := make([]uint64, 3*maxRLERun+5)
+	for i := range huge {
+		huge[i] = 42
+	}
+
+	for _, input := range [][]uint64{
+		{},
+		{0},
+		{1, 1, 1, 1},
+		{1, 2, 3, 4},
+		{6, 6, 6, 17, 17, 6, intPow(2, 40), intPow(2, 40)},
+		long,
+		huge,
+	} {
+		buf := PackRLE(input)
+		require.Nil(t, Validate(buf))
+		require.Equal(t, FormatRLE, FormatOf(buf))
+		require.Equal(t, len(input), Len(buf))
+
+		require.Equal(t, input, Unpack(buf))
+		require.Equal(t, input, UnpackInto(buf, make([]uint64, 0, 1)))
+
+		for i := range input {
+			v, err := At(buf, i)
+			require.Nil(t, err)
+			require.Equal(t, input[i], v)
+		}
+		_, err := At(buf, len(input))
+		require.ErrorIs(t, err, ErrIndexOutOfRange)
+	}
+
+	// Long runs must compress far below plain fixed-width packing
+	require.Less(t, len(PackRLE(long)), 64)
+}
+
+func TestPackRLEInvalid(t *testing.T) {
+	buf := PackRLE([]uint64{1, 1, 2})
+
+	for _, c := range []struct {
+		buf      []byte
+		expected error
+	}{
+		{[]byte{byte(FormatRLE)}, ErrTruncated},
+		{[]byte{byte(FormatRLE), 0x10, 0x1}, ErrTruncated},
+		{[]byte{byte(FormatRLE) | 0x1, 0x1, 0x1}, ErrInvalidFormat},
+		{buf[:len(buf)-1], ErrInvalidFormat},
+		{append([]byte{byte(FormatRLE), 0x2, 0x1, 0x1}, Pack([]uint64{0})...), ErrInvalidFormat},
+	} {
+		require.ErrorIs(t, Validate(c.buf), c.expected, "%x", c.buf)
+		require.Empty(t, Unpack(c.buf))
+	}
+}
+
+func TestPackRLEUntrusted(t *testing.T) {
+	// A run length block in any format other than fixed-width must not be decoded
+	notFixed, err := hex.DecodeString("2003110100012301")
+	require.Nil(t, err)
+
+	// A few bytes must not be able to claim an arbitrary number of elements
+	values, runs := Pack([]uint64{7}), Pack([]uint64{intPow(2, 40)})
+	tooLong := append(append([]byte{byte(FormatRLE), byte(len(values))}, values...), runs...)
+
+	// The sum of run lengths must not overflow
+	runs = Pack([]uint64{math.MaxInt64, math.MaxInt64, 2})
+	values = Pack([]uint64{1, 2, 3})
+	overflow := append(append([]byte{byte(FormatRLE), byte(len(values))}, values...), runs...)
+
+	for _, input := range [][]byte{notFixed, tooLong, overflow} {
+		require.NotPanics(t, func() {
+			require.Error(t, Validate(input))
+			require.Zero(t, Len(input))
+			require.Empty(t, Unpack(input))
+			require.Empty(t, UnpackInto(input, nil))
+
+			_, err := At(input, 0)
+			require.Error(t, err)
+			require.Error(t, Iterate(input, func(int, uint64) bool { return true }))
+			_, err = Sum(input)
+			require.Error(t, err)
+
+			res, err := UnpackDelta(append([]byte{0x1}, input...))
+			require.Nil(t, err)
+			require.Equal(t, []uint64{1}, res)
+		}, "%x", input)
+	}
+}
@@ -0,0 +1,68 @@
+package bitpack
+
+// PackInt64 compresses a slice of int64 values into a byte slice, using zigzag encoding
+// to map signed values onto unsigned ones (such that values of small magnitude require
+// a small width regardless of their sign) before applying the same width reduction as Pack
+func PackInt64(data []int64) []byte {
+	udata := make([]uint64, len(data))
+	for i, v := range data {
+		udata[i] = zigzagEncode(v)
+	}
+
+	return Pack(udata)
+}
+
+// UnpackIntoInt64 decompresses a byte slice compressed via PackInt64 into a pre-existing slice
+// of int64 values (which will be allocated / grown in case its capacity is insufficient)
+func UnpackIntoInt64(b []byte, res []int64) []int64 {
+
+	// Big-endian data (as well as any format other than fixed-width) is decoded generically and
+	// converted afterwards
+	if headerFlags(b)&FlagBigEndian != 0 || FormatOf(payload(b)) != FormatFixed {
+		values := Unpack(b)
+		if cap(res) < len(values) {
+			res = make([]int64, len(values), len(values)*2)
+		}
+		res = res[:len(values)]
+		for i, v := range values {
+			res[i] = zigzagDecode(v)
+		}
+		return res
+	}
+
+	// If the width is unknown, there is nothing to unpack
+	b = payload(b)
+	neededBytes := validWidth(b)
+	if neededBytes == 0 {
+		return res[:0]
+	}
+
+	nElements := (len(b) - 1) / neededBytes
+	if cap(res) < nElements {
+		res = make([]int64, nElements, nElements*2)
+	}
+	res = res[:nElements]
+
+	unpackFn := unpackTable[neededBytes]
+	for i := 0; i < nElements; i++ {
+		res[i] = zigzagDecode(unpackFn(b[1+i*neededBytes:]))
+	}
+
+	return res
+}
+
+// UnpackInt64 decompresses a byte slice compressed via PackInt64 into the original slice of
+// int64 values
+func UnpackInt64(b []byte) []int64 {
+	return UnpackIntoInt64(b, []int64{})
+}
+
+////////////////////////////////////////////////////////////////////////////////////////
+
+func zigzagEncode(v int64) uint64 {
+	return uint64((v << 1) ^ (v >> 63)) // #nosec G115
+}
+
+func zigzagDecode(v uint64) int64 {
+	return int64(v>>1) ^ -int64(v&1) // #nosec G115
+}
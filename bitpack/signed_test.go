@@ -0,0 +1,93 @@
+package bitpack
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestZigzag(t *testing.T) {
+	for _, c := range []struct {
+		in  int64
+		out uint64
+	}{
+		{0, 0},
+		{-1, 1},
+		{1, 2},
+		{-2, 3},
+		{2, 4},
+		{math.MaxInt64, math.MaxUint64 - 1},
+		{math.MinInt64, math.MaxUint64},
+	} {
+		require.Equal(t, c.out, zigzagEncode(c.in))
+		require.Equal(t, c.in, zigzagDecode(c.out))
+	}
+}
+
+func TestPackInt64(t *testing.T) {
+	for _, input := range [][]int64{
+		{},
+		{0},
+		{-1, 1, -64, 63},
+		{-129, 128, 0},
+		{math.MinInt64, math.MaxInt64, 0, -1},
+	} {
+		buf := PackInt64(input)
+		require.Equal(t, input, UnpackInt64(buf))
+		require.Equal(t, len(input), Len(buf))
+
+		res := make([]int64, 0, 1)
+		require.Equal(t, input, UnpackIntoInt64(buf, res))
+	}
+
+	// Small negative values must not require a larger width than their absolute value
+	require.Equal(t, 1, ByteWidth(PackInt64([]int64{-128, 127})))
+	require.Equal(t, 2, ByteWidth(PackInt64([]int64{-129, 128})))
+
+	require.Empty(t, UnpackInt64(nil))
+	require.Empty(t, UnpackIntoInt64([]byte{0}, nil))
+}
+
+func TestPackInt64Formats(t *testing.T) {
+	input := []int64{-3, -3, -3, 17, 5000, -1}
+	udata := make([]uint64, len(input))
+	for i, v := range input {
+		udata[i] = zigzagEncode(v)
+	}
+
+	// Buffers in any format (and with any option) must decode to the original values
+	for _, buf := range [][]byte{
+		PackInt64(input),
+		PackVarint(udata),
+		PackRLE(udata),
+		PackBits(udata),
+		PackParallel(udata, 2),
+		Pack(udata, WithHeader(), WithChecksum()),
+		Pack(udata, WithBigEndian()),
+	} {
+		require.Equal(t, input, UnpackInt64(buf), "%x", buf)
+		require.Equal(t, input, UnpackIntoInt64(buf, make([]int64, 0, 1)), "%x", buf)
+	}
+
+	// Varint-encoded buffer produced by PackVarint
+	buf := []byte{0x10, 0x09, 0x88, 0x80, 0x80, 0x84, 0x80, 0x20}
+	require.Equal(t, []int64{-5, 549760008196}, UnpackInt64(buf))
+}
+
+func TestPackInt64Invalid(t *testing.T) {
+	tooLong := append(append([]byte{byte(FormatRLE), 0x2}, Pack([]uint64{7})...), Pack([]uint64{math.MaxInt64})...)
+
+	for _, input := range [][]byte{
+		{0x09, 0x62, 0xd3, 0x1, 0x2, 0x3, 0x4, 0x5, 0x6, 0x7, 0x8, 0x9},
+		{0x0f, 0x1, 0x2, 0x3},
+		{0x50, 0x0, 0x0},
+		tooLong,
+	} {
+		require.NotPanics(t, func() {
+			require.Empty(t, UnpackInt64(input))
+			require.Empty(t, UnpackDurations(input, time.Second))
+		}, "%x", input)
+	}
+}
@@ -0,0 +1,201 @@
+package bitpack
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+const (
+	// DefaultBlockSize denotes the default number of values packed per block in a stream
+	DefaultBlockSize = 4096
+
+	// MaxBlockSize denotes the maximum number of values packed per block in a stream
+	MaxBlockSize = 1 << 20
+
+	maxBlockBytes = 1 + 8*MaxBlockSize
+)
+
+// ErrInvalidBlock denotes that a block read from a stream is malformed
+var ErrInvalidBlock = errors.New("invalid block in packed stream")
+
+// PackerOption denotes a functional option for a Packer
+type PackerOption func(*Packer)
+
+// WithBlockSize sets the number of values accumulated before a block is packed and written
+// (larger blocks amortize the per-block overhead, smaller blocks reduce memory usage and
+// avoid a single large value widening many others)
+func WithBlockSize(n int) PackerOption {
+	return func(p *Packer) {
+		if n > 0 && n <= MaxBlockSize {
+			p.blockSize = n
+		}
+	}
+}
+
+// Packer denotes a streaming packer, accepting individual values and writing them to an
+// underlying io.Writer in independently packed, length-prefixed blocks
+type Packer struct {
+	w         io.Writer
+	blockSize int
+
+	block  []uint64
+	buf    []byte
+	lenBuf [binary.MaxVarintLen64]byte
+}
+
+// NewPacker instantiates a new streaming packer writing to the provided io.Writer
+func NewPacker(w io.Writer, opts ...PackerOption) *Packer {
+	obj := &Packer{
+		w:         w,
+		blockSize: DefaultBlockSize,
+	}
+	for _, opt := range opts {
+		opt(obj)
+	}
+	obj.block = make([]uint64, 0, obj.blockSize)
+
+	return obj
+}
+
+// Add adds a value to the stream (writing a block to the underlying writer once the block
+// size has been reached)
+func (p *Packer) Add(v uint64) error {
+	p.block = append(p.block, v)
+	if len(p.block) >= p.blockSize {
+		return p.Flush()
+	}
+
+	return nil
+}
+
+// Flush packs and writes all pending values to the underlying writer
+func (p *Packer) Flush() error {
+	if len(p.block) == 0 {
+		return nil
+	}
+
+	// Pack into the reusable buffer (growing it only if required)
+	if size := PackedSize(p.block); len(p.buf) < size {
+		p.buf = make([]byte, size)
+	}
+	nPacked, err := PackInto(p.buf, p.block)
+	if err != nil {
+		return err
+	}
+
+	n := binary.PutUvarint(p.lenBuf[:], uint64(nPacked))
+	if _, err := p.w.Write(p.lenBuf[:n]); err != nil {
+		return err
+	}
+	if _, err := p.w.Write(p.buf[:nPacked]); err != nil {
+		return err
+	}
+	p.block = p.block[:0]
+
+	return nil
+}
+
+// Close flushes all pending values (the underlying writer is not closed)
+func (p *Packer) Close() error {
+	return p.Flush()
+}
+
+// Unpacker denotes a streaming unpacker, reading values written by a Packer from an
+// underlying io.Reader one block at a time
+type Unpacker struct {
+	r *bufio.Reader
+
+	buf   []byte
+	block []uint64
+	pos   int
+}
+
+// NewUnpacker instantiates a new streaming unpacker reading from the provided io.Reader
+func NewUnpacker(r io.Reader) *Unpacker {
+	return &Unpacker{
+		r: bufio.NewReader(r),
+	}
+}
+
+// Next returns the next value from the stream (returning io.EOF once the stream has been
+// fully consumed)
+func (u *Unpacker) Next() (uint64, error) {
+	for u.pos >= len(u.block) {
+		if err := u.readBlock(); err != nil {
+			return 0, err
+		}
+	}
+
+	v := u.block[u.pos]
+	u.pos++
+
+	return v, nil
+}
+
+// PackToWriter consumes all values from the provided channel (until it is closed) and writes them
+// to the provided io.Writer in fixed-size, independently decodable blocks (cf. Packer), allowing
+// to pack arbitrarily large streams with bounded memory usage. In case of an error the channel is
+// not drained any further
+func PackToWriter(w io.Writer, src <-chan uint64, opts ...PackerOption) error {
+	p := NewPacker(w, opts...)
+	for v := range src {
+		if err := p.Add(v); err != nil {
+			return err
+		}
+	}
+
+	return p.Close()
+}
+
+// UnpackFromReader reads all blocks written by a Packer (or PackToWriter) from the provided
+// io.Reader and sends the contained values to the provided channel (which is not closed)
+func UnpackFromReader(r io.Reader, dst chan<- uint64) error {
+	u := NewUnpacker(r)
+	for {
+		v, err := u.Next()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return err
+		}
+		dst <- v
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////////////
+
+func (u *Unpacker) readBlock() error {
+	size, err := binary.ReadUvarint(u.r)
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return io.EOF
+		}
+		return err
+	}
+	if size < 2 || size > maxBlockBytes {
+		return fmt.Errorf("%w: block size %d out of range", ErrInvalidBlock, size)
+	}
+
+	if uint64(cap(u.buf)) < size {
+		u.buf = make([]byte, size)
+	}
+	u.buf = u.buf[:size]
+	if _, err = io.ReadFull(u.r, u.buf); err != nil {
+		if errors.Is(err, io.EOF) {
+			return io.ErrUnexpectedEOF
+		}
+		return err
+	}
+
+	if err = Validate(u.buf); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidBlock, err)
+	}
+
+	u.block, u.pos = UnpackInto(u.buf, u.block), 0
+
+	return nil
+}
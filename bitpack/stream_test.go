@@ -0,0 +1,100 @@
+package bitpack
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestStream(t *testing.T) {
+	for _, blockSize := range []int{1, 3, 100, DefaultBlockSize} {
+		var (
+			buf   bytes.Buffer
+			input []uint64
+		)
+		for i := 0; i < 1000; i++ {
+			input = append(input, uint64(i*i))
+		}
+
+		p := NewPacker(&buf, WithBlockSize(blockSize))
+		for _, v := range input {
+			require.Nil(t, p.Add(v))
+		}
+		require.Nil(t, p.Close())
+
+		u := NewUnpacker(&buf)
+		for _, v := range input {
+			res, err := u.Next()
+			require.Nil(t, err)
+			require.Equal(t, v, res)
+		}
+		_, err := u.Next()
+		require.ErrorIs(t, err, io.EOF)
+	}
+}
+
+func TestPackToWriter(t *testing.T) {
+	var buf bytes.Buffer
+
+	src := make(chan uint64)
+	go func() {
+		for i := 0; i < 10000; i++ {
+			src <- uint64(i) * 7
+		}
+		close(src)
+	}()
+	require.Nil(t, PackToWriter(&buf, src, WithBlockSize(1000)))
+
+	dst := make(chan uint64, 100)
+	errs := make(chan error, 1)
+	go func() {
+		errs <- UnpackFromReader(&buf, dst)
+		close(dst)
+	}()
+
+	var n int
+	for v := range dst {
+		require.Equal(t, uint64(n)*7, v)
+		n++
+	}
+	require.Equal(t, 10000, n)
+	require.Nil(t, <-errs)
+
+	// Corrupt input must be reported
+	require.ErrorIs(t, UnpackFromReader(bytes.NewReader([]byte{2, 0x9, 0}), make(chan uint64, 1)), ErrInvalidBlock)
+}
+
+func TestStreamEmpty(t *testing.T) {
+	var buf bytes.Buffer
+	require.Nil(t, NewPacker(&buf).Close())
+	require.Zero(t, buf.Len())
+
+	_, err := NewUnpacker(&buf).Next()
+	require.ErrorIs(t, err, io.EOF)
+}
+
+func TestStreamCorrupt(t *testing.T) {
+	var buf bytes.Buffer
+	p := NewPacker(&buf)
+	for i := 0; i < 10; i++ {
+		require.Nil(t, p.Add(uint64(i)<<12))
+	}
+	require.Nil(t, p.Flush())
+	data := buf.Bytes()
+
+	// Truncated block
+	_, err := NewUnpacker(bytes.NewReader(data[:len(data)-1])).Next()
+	require.ErrorIs(t, err, io.ErrUnexpectedEOF)
+
+	// Invalid width
+	corrupt := append([]byte{}, data...)
+	corrupt[1] = 3
+	_, err = NewUnpacker(bytes.NewReader(corrupt)).Next()
+	require.ErrorIs(t, err, ErrInvalidBlock)
+
+	// Invalid size
+	_, err = NewUnpacker(bytes.NewReader([]byte{0x1})).Next()
+	require.ErrorIs(t, err, ErrInvalidBlock)
+}
@@ -0,0 +1,73 @@
+package bitpack
+
+import "time"
+
+// PackDurations compresses a slice of durations by normalizing them to integers at the given
+// resolution (e.g. time.Millisecond, truncating any remainder) before packing them (cf. PackInt64).
+// A resolution <= 0 is treated as time.Nanosecond
+func PackDurations(data []time.Duration, resolution time.Duration) []byte {
+	resolution = normalizeResolution(resolution)
+
+	idata := make([]int64, len(data))
+	for i, d := range data {
+		idata[i] = int64(d / resolution)
+	}
+
+	return PackInt64(idata)
+}
+
+// UnpackDurations decompresses a byte slice compressed via PackDurations into the original slice
+// of durations (the resolution must match the one used for packing)
+func UnpackDurations(b []byte, resolution time.Duration) []time.Duration {
+	resolution = normalizeResolution(resolution)
+
+	idata := UnpackInt64(b)
+	res := make([]time.Duration, len(idata))
+	for i, v := range idata {
+		res[i] = time.Duration(v) * resolution
+	}
+
+	return res
+}
+
+// PackTimes compresses a slice of points in time by normalizing them to integers (relative to the
+// Unix epoch) at the given resolution (e.g. time.Second, truncating any remainder) before packing
+// them (cf. PackTimestamps). Only times representable as int64 Unix nanoseconds (years 1678 to 2262)
+// are supported. A resolution <= 0 is treated as time.Nanosecond
+func PackTimes(data []time.Time, resolution time.Duration) []byte {
+	resolution = normalizeResolution(resolution)
+
+	idata := make([]int64, len(data))
+	for i, t := range data {
+		idata[i] = t.UnixNano() / int64(resolution)
+	}
+
+	return PackTimestamps(idata)
+}
+
+// UnpackTimes decompresses a byte slice compressed via PackTimes into the original slice of points
+// in time (in UTC), the resolution must match the one used for packing
+func UnpackTimes(b []byte, resolution time.Duration) ([]time.Time, error) {
+	resolution = normalizeResolution(resolution)
+
+	idata, err := UnpackTimestamps(b)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]time.Time, len(idata))
+	for i, v := range idata {
+		res[i] = time.Unix(0, v*int64(resolution)).UTC()
+	}
+
+	return res, nil
+}
+
+////////////////////////////////////////////////////////////////////////////////////////
+
+func normalizeResolution(resolution time.Duration) time.Duration {
+	if resolution <= 0 {
+		return time.Nanosecond
+	}
+	return resolution
+}
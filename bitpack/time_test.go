@@ -0,0 +1,49 @@
+package bitpack
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPackDurations(t *testing.T) {
+	input := []time.Duration{0, time.Millisecond, -time.Second, 1500 * time.Microsecond, time.Hour}
+
+	require.Equal(t, input, UnpackDurations(PackDurations(input, 0), 0))
+	require.Equal(t, input, UnpackDurations(PackDurations(input, time.Nanosecond), time.Nanosecond))
+	require.Equal(t, []time.Duration{0, time.Millisecond, -time.Second, time.Millisecond, time.Hour},
+		UnpackDurations(PackDurations(input, time.Millisecond), time.Millisecond))
+
+	// Coarser resolutions yield smaller output
+	require.Less(t, len(PackDurations(input, time.Millisecond)), len(PackDurations(input, 0)))
+}
+
+func TestPackTimes(t *testing.T) {
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	input := make([]time.Time, 100)
+	for i := range input {
+		input[i] = start.Add(time.Duration(i)*time.Second + 123*time.Microsecond)
+	}
+
+	res, err := UnpackTimes(PackTimes(input, 0), 0)
+	require.Nil(t, err)
+	require.Equal(t, input, res)
+
+	res, err = UnpackTimes(PackTimes(input, time.Second), time.Second)
+	require.Nil(t, err)
+	require.Len(t, res, len(input))
+	for i := range input {
+		require.True(t, input[i].Truncate(time.Second).Equal(res[i]))
+	}
+
+	// Non-UTC input is returned in UTC
+	loc := time.FixedZone("test", 3600)
+	res, err = UnpackTimes(PackTimes([]time.Time{start.In(loc)}, time.Millisecond), time.Millisecond)
+	require.Nil(t, err)
+	require.Equal(t, []time.Time{start}, res)
+
+	_, err = UnpackTimes(nil, time.Second)
+	require.ErrorIs(t, err, ErrInvalidHeader)
+}
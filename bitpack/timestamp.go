@@ -0,0 +1,117 @@
+package bitpack
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+// tsBuckets denotes the bit widths used to store (zigzag-encoded) delta-of-delta values, each
+// bucket i being identified by a prefix of i one bits followed by a zero bit (the last bucket
+// omitting the terminating zero bit)
+var tsBuckets = [...]int{7, 9, 12, 64}
+
+// PackTimestamps compresses a slice of int64 timestamps (or any other sequence of nearly-regular
+// intervals) using delta-of-delta encoding as introduced by Facebook's Gorilla time series database:
+// the difference between consecutive deltas is stored using a variable-length bit representation,
+// such that regularly sampled timestamps require a single bit per element
+func PackTimestamps(data []int64) []byte {
+	w := bitWriter{
+		buf: binary.AppendUvarint(make([]byte, 0, binary.MaxVarintLen64+len(data)), uint64(len(data))),
+	}
+	if len(data) == 0 {
+		return w.buf
+	}
+
+	w.writeBits(uint64(data[0]), 64) // #nosec G115
+
+	var prevDelta int64
+	for i := 1; i < len(data); i++ {
+		delta := data[i] - data[i-1]
+		dod := zigzagEncode(delta - prevDelta)
+		prevDelta = delta
+
+		// Identical deltas are represented by a single bit
+		if dod == 0 {
+			w.writeBit(false)
+			continue
+		}
+
+		for j, width := range tsBuckets {
+			w.writeBit(true)
+			if j == len(tsBuckets)-1 {
+				w.writeBits(dod, width)
+				break
+			}
+			if dod < 1<<width {
+				w.writeBit(false)
+				w.writeBits(dod, width)
+				break
+			}
+		}
+	}
+
+	return w.buf
+}
+
+// UnpackIntoTimestamps decompresses a byte slice compressed via PackTimestamps into a pre-existing
+// slice of int64 values (which will be allocated / grown in case its capacity is insufficient)
+func UnpackIntoTimestamps(b []byte, res []int64) ([]int64, error) {
+	nElements, n := binary.Uvarint(b)
+	if n <= 0 {
+		return res[:0], ErrInvalidHeader
+	}
+	r := bitReader{buf: b[n:]}
+
+	// Each element except for the first one requires at least one bit, so the element count
+	// can be validated before allocating anything
+	if nElements > 0 && (r.remaining() < 64 || nElements-1 > uint64(r.remaining()-64)) { // #nosec G115
+		return res[:0], fmt.Errorf("%w: %d elements cannot fit into %d bytes", ErrTruncated, nElements, len(b)-n)
+	}
+
+	if uint64(cap(res)) < nElements {
+		res = make([]int64, nElements, nElements*2)
+	}
+	res = res[:nElements]
+	if nElements == 0 {
+		return res, nil
+	}
+
+	first, _ := r.readBits(64)
+	res[0] = int64(first) // #nosec G115
+
+	var delta int64
+	for i := 1; i < len(res); i++ {
+		var dod uint64
+		for j, width := range tsBuckets {
+			bit, ok := r.readBit()
+			if !ok {
+				return res[:i], fmt.Errorf("%w: at element %d", ErrTruncated, i)
+			}
+			if !bit {
+				if j > 0 {
+					dod, ok = r.readBits(tsBuckets[j-1])
+				}
+				if !ok {
+					return res[:i], fmt.Errorf("%w: at element %d", ErrTruncated, i)
+				}
+				break
+			}
+			if j == len(tsBuckets)-1 {
+				if dod, ok = r.readBits(width); !ok {
+					return res[:i], fmt.Errorf("%w: at element %d", ErrTruncated, i)
+				}
+			}
+		}
+
+		delta += zigzagDecode(dod)
+		res[i] = res[i-1] + delta
+	}
+
+	return res, nil
+}
+
+// UnpackTimestamps decompresses a byte slice compressed via PackTimestamps into the original slice
+// of int64 values
+func UnpackTimestamps(b []byte) ([]int64, error) {
+	return UnpackIntoTimestamps(b, []int64{})
+}
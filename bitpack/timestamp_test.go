@@ -0,0 +1,59 @@
+package bitpack
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPackTimestamps(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC).Unix()
+
+	regular := make([]int64, 1000)
+	jitter := make([]int64, 1000)
+	for i := range regular {
+		regular[i] = start + int64(i)*60
+		jitter[i] = start + int64(i)*60 + int64(i%7) - 3
+	}
+
+	for _, input := range [][]int64{
+		{},
+		{0},
+		{start},
+		{-1, 0, 1},
+		regular,
+		jitter,
+		{start, start + 1, start + 100, start + 1000, start + 100000, start - 1<<40},
+		{math.MinInt64, math.MaxInt64, 0, math.MinInt64},
+	} {
+		packed := PackTimestamps(input)
+		res, err := UnpackTimestamps(packed)
+		require.Nil(t, err)
+		require.Equal(t, input, res)
+
+		res, err = UnpackIntoTimestamps(packed, make([]int64, 0, 1))
+		require.Nil(t, err)
+		require.Equal(t, input, res)
+	}
+
+	// Regular intervals require a single bit per element
+	require.Less(t, len(PackTimestamps(regular)), 8+2+len(regular)/8+10)
+	require.Less(t, len(PackTimestamps(jitter)), len(PackInt64(jitter))/2)
+}
+
+func TestPackTimestampsInvalid(t *testing.T) {
+	_, err := UnpackTimestamps(nil)
+	require.ErrorIs(t, err, ErrInvalidHeader)
+
+	packed := PackTimestamps([]int64{1, 2, 100, 1 << 50})
+	for i := 1; i < len(packed); i++ {
+		_, err := UnpackTimestamps(packed[:i])
+		require.ErrorIs(t, err, ErrTruncated)
+	}
+
+	// Huge element count
+	_, err = UnpackTimestamps([]byte{0xff, 0xff, 0xff, 0xff, 0x0f, 0x0, 0x0, 0x0, 0x0, 0x0, 0x0, 0x0, 0x0})
+	require.ErrorIs(t, err, ErrTruncated)
+}
@@ -0,0 +1,8 @@
+//go:build amd64 && !purego
+
+package bitpack
+
+import "golang.org/x/sys/cpu"
+
+// useSIMD denotes if the vectorized (PSHUFB-based) unpack implementation can be used
+var useSIMD = cpu.X86.HasSSSE3
@@ -0,0 +1,55 @@
+//go:build amd64 && !purego
+
+#include "textflag.h"
+
+// func unpackPairs(dst *uint64, src *byte, nPairs, width int, shuf *[16]byte)
+TEXT ·unpackPairs(SB), NOSPLIT, $0-40
+	MOVQ dst+0(FP), DI
+	MOVQ src+8(FP), SI
+	MOVQ nPairs+16(FP), CX
+	MOVQ width+24(FP), DX
+	MOVQ shuf+32(FP), AX
+	MOVOU (AX), X8
+	SHLQ $1, DX
+
+	// Unpack four pairs per iteration (using independent registers)
+	CMPQ CX, $4
+	JB   single
+
+loop4:
+	MOVOU  (SI), X0
+	ADDQ   DX, SI
+	MOVOU  (SI), X1
+	ADDQ   DX, SI
+	MOVOU  (SI), X2
+	ADDQ   DX, SI
+	MOVOU  (SI), X3
+	ADDQ   DX, SI
+	PSHUFB X8, X0
+	PSHUFB X8, X1
+	PSHUFB X8, X2
+	PSHUFB X8, X3
+	MOVOU  X0, (DI)
+	MOVOU  X1, 16(DI)
+	MOVOU  X2, 32(DI)
+	MOVOU  X3, 48(DI)
+	ADDQ   $64, DI
+	SUBQ   $4, CX
+	CMPQ   CX, $4
+	JAE    loop4
+
+single:
+	TESTQ CX, CX
+	JZ    done
+
+loop1:
+	MOVOU  (SI), X0
+	PSHUFB X8, X0
+	MOVOU  X0, (DI)
+	ADDQ   DX, SI
+	ADDQ   $16, DI
+	DECQ   CX
+	JNZ    loop1
+
+done:
+	RET
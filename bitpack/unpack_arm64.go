@@ -0,0 +1,7 @@
+//go:build arm64 && !purego
+
+package bitpack
+
+// useSIMD denotes if the vectorized (TBL-based) unpack implementation can be used (NEON / ASIMD
+// being mandatory on arm64)
+const useSIMD = true
@@ -0,0 +1,49 @@
+//go:build arm64 && !purego
+
+#include "textflag.h"
+
+// func unpackPairs(dst *uint64, src *byte, nPairs, width int, shuf *[16]byte)
+TEXT ·unpackPairs(SB), NOSPLIT, $0-40
+	MOVD dst+0(FP), R0
+	MOVD src+8(FP), R1
+	MOVD nPairs+16(FP), R2
+	MOVD width+24(FP), R3
+	MOVD shuf+32(FP), R4
+	VLD1 (R4), [V1.B16]
+	LSL  $1, R3, R3
+
+	// Unpack four pairs per iteration (using independent registers)
+	CMP $4, R2
+	BLT single
+
+loop4:
+	ADD  R3, R1, R5
+	ADD  R3, R5, R6
+	ADD  R3, R6, R7
+	VLD1 (R1), [V16.B16]
+	VLD1 (R5), [V17.B16]
+	VLD1 (R6), [V18.B16]
+	VLD1 (R7), [V19.B16]
+	ADD  R3, R7, R1
+	VTBL V1.B16, [V16.B16], V20.B16
+	VTBL V1.B16, [V17.B16], V21.B16
+	VTBL V1.B16, [V18.B16], V22.B16
+	VTBL V1.B16, [V19.B16], V23.B16
+	VST1.P [V20.B16, V21.B16, V22.B16, V23.B16], 64(R0)
+	SUB  $4, R2, R2
+	CMP  $4, R2
+	BGE  loop4
+
+single:
+	CBZ R2, done
+
+loop1:
+	VLD1   (R1), [V16.B16]
+	VTBL   V1.B16, [V16.B16], V20.B16
+	VST1.P [V20.B16], 16(R0)
+	ADD    R3, R1, R1
+	SUBS   $1, R2, R2
+	BNE    loop1
+
+done:
+	RET
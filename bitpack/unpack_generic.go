@@ -0,0 +1,7 @@
+//go:build !(amd64 || arm64) || purego
+
+package bitpack
+
+func unpackAll(b []byte, res []uint64, n, neededBytes int) {
+	unpackAllGeneric(b, res, n, neededBytes)
+}
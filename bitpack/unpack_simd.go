@@ -0,0 +1,42 @@
+//go:build (amd64 || arm64) && !purego
+
+package bitpack
+
+// unpackShuffleMasks holds the byte shuffle (PSHUFB / TBL) masks for each width, expanding two
+// consecutive elements of a 16-byte block into two 8-byte little-endian lanes (bytes beyond the
+// width being zeroed by the out-of-range index)
+var unpackShuffleMasks = func() (masks [9][16]byte) {
+	for width := 1; width <= 8; width++ {
+		for lane := 0; lane < 2; lane++ {
+			for i := 0; i < 8; i++ {
+				masks[width][lane*8+i] = 0x80
+				if i < width {
+					masks[width][lane*8+i] = byte(lane*width + i)
+				}
+			}
+		}
+	}
+	return
+}()
+
+// unpackPairs unpacks nPairs pairs of elements of the given width from src to dst, reading
+// 16 bytes per pair (hence src must provide at least 2*width*(nPairs-1)+16 bytes)
+//
+//go:noescape
+func unpackPairs(dst *uint64, src *byte, nPairs, width int, shuf *[16]byte)
+
+// On amd64 / arm64, elements are unpacked in pairs using a single 16-byte load and shuffle (if
+// supported by the CPU). Only the trailing elements whose load would exceed the buffer are decoded
+// using the generic functions
+func unpackAll(b []byte, res []uint64, n, neededBytes int) {
+	if useSIMD && len(b) >= 16 {
+		res = res[:n]
+		nPairs := min((len(b)-16)/(2*neededBytes)+1, n/2)
+		if nPairs > 0 {
+			unpackPairs(&res[0], &b[0], nPairs, neededBytes, &unpackShuffleMasks[neededBytes])
+			b, res, n = b[2*nPairs*neededBytes:], res[2*nPairs:], n-2*nPairs
+		}
+	}
+
+	unpackAllGeneric(b, res, n, neededBytes)
+}
@@ -0,0 +1,37 @@
+//go:build (amd64 || arm64) && !purego
+
+package bitpack
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnpackPairs(t *testing.T) {
+	if !useSIMD {
+		t.Skip("vectorized unpack implementation not supported by the CPU")
+	}
+
+	for neededBytes := 1; neededBytes <= 8; neededBytes++ {
+		t.Run(fmt.Sprintf("%d_bytes", neededBytes), func(t *testing.T) {
+
+			// Cover both the four-pair loop and the single-pair remainder, using exactly the
+			// minimum source size required by the kernel
+			for nPairs := 1; nPairs <= 20; nPairs++ {
+				b := make([]byte, 2*neededBytes*(nPairs-1)+16)
+				rand.Read(b)
+
+				res := make([]uint64, 2*nPairs+1)
+				res[2*nPairs] = 42
+				unpackPairs(&res[0], &b[0], nPairs, neededBytes, &unpackShuffleMasks[neededBytes])
+				for i := 0; i < 2*nPairs; i++ {
+					require.Equal(t, unpackTable[neededBytes](b[i*neededBytes:]), res[i], "element %d of %d pairs", i, nPairs)
+				}
+				require.Equal(t, uint64(42), res[2*nPairs], "kernel wrote beyond the requested pairs")
+			}
+		})
+	}
+}
@@ -0,0 +1,50 @@
+package bitpack
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnpackAll(t *testing.T) {
+	for neededBytes := 1; neededBytes <= 8; neededBytes++ {
+		t.Run(fmt.Sprintf("%d_bytes", neededBytes), func(t *testing.T) {
+
+			// Cover buffers both shorter and longer than a single (vectorized) load, ensuring
+			// that the tail elements are decoded correctly
+			for n := 0; n <= 100; n++ {
+				b := make([]byte, n*neededBytes)
+				rand.Read(b)
+
+				res := make([]uint64, n)
+				unpackAll(b, res, n, neededBytes)
+				for i := 0; i < n; i++ {
+					require.Equal(t, unpackTable[neededBytes](b[i*neededBytes:]), res[i], "element %d of %d", i, n)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkUnpackAll(b *testing.B) {
+	for neededBytes := 1; neededBytes <= 8; neededBytes++ {
+		buf := make([]byte, 512*neededBytes)
+		rand.Read(buf)
+		res := make([]uint64, 512)
+
+		b.Run(fmt.Sprintf("%d_bytes/generic", neededBytes), func(b *testing.B) {
+			b.SetBytes(int64(len(res) * 8))
+			for i := 0; i < b.N; i++ {
+				unpackAllGeneric(buf, res, len(res), neededBytes)
+			}
+		})
+		b.Run(fmt.Sprintf("%d_bytes/default", neededBytes), func(b *testing.B) {
+			b.SetBytes(int64(len(res) * 8))
+			for i := 0; i < b.N; i++ {
+				unpackAll(buf, res, len(res), neededBytes)
+			}
+		})
+	}
+}
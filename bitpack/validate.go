@@ -0,0 +1,81 @@
+package bitpack
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+// Validate checks a packed buffer for structural consistency (header sanity, width bounds and
+// length consistency), allowing to verify data read from untrusted sources (e.g. disk) before
+// processing it further (including verification of the versioned header and checksum trailer, if
+// present). Returned errors wrap ErrInvalidWidth, ErrInvalidFormat, ErrInvalidHeader, ErrTruncated,
+// ErrUnsupportedVersion or ErrChecksumMismatch
+func Validate(b []byte) error {
+	_, b, err := ParseHeader(b)
+	if err != nil {
+		return err
+	}
+	if len(b) == 0 {
+		return fmt.Errorf("%w: missing header", ErrTruncated)
+	}
+
+	// If present, verify the checksum trailer before inspecting the actual contents
+	if b[0]&flagChecksum != 0 {
+		if err := verifyChecksum(b); err != nil {
+			return err
+		}
+		b = b[:len(b)-checksumLen]
+	}
+
+	switch FormatOf(b) {
+	case FormatFixed:
+		return validateFixed(b)
+	case FormatVarint:
+		return validateVarint(b)
+	case FormatRLE:
+		return validateRLE(b)
+	case FormatBits:
+		return validateBits(b)
+	case FormatChunked:
+		return validateChunked(b)
+	}
+
+	return fmt.Errorf("%w: unknown header 0x%02x", ErrInvalidFormat, header(b))
+}
+
+////////////////////////////////////////////////////////////////////////////////////////
+
+func validateFixed(b []byte) error {
+	if header(b)&^widthMask != 0 {
+		return fmt.Errorf("%w: unknown header 0x%02x", ErrInvalidFormat, header(b))
+	}
+
+	width := ByteWidth(b)
+	if width < 1 || width > 8 {
+		return fmt.Errorf("%w: %d", ErrInvalidWidth, width)
+	}
+	if rem := (len(b) - 1) % width; rem != 0 {
+		return fmt.Errorf("%w: %d trailing byte(s) for width %d", ErrTruncated, rem, width)
+	}
+
+	return nil
+}
+
+func validateVarint(b []byte) error {
+	if header(b) != byte(FormatVarint) {
+		return fmt.Errorf("%w: unknown header 0x%02x", ErrInvalidFormat, header(b))
+	}
+
+	for i, data := 0, b[1:]; len(data) > 0; i++ {
+		_, n := binary.Uvarint(data)
+		if n == 0 {
+			return fmt.Errorf("%w: incomplete varint at element %d", ErrTruncated, i)
+		}
+		if n < 0 {
+			return fmt.Errorf("%w: varint overflow at element %d", ErrInvalidFormat, i)
+		}
+		data = data[n:]
+	}
+
+	return nil
+}
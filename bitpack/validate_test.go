@@ -0,0 +1,43 @@
+package bitpack
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidate(t *testing.T) {
+	for _, buf := range [][]byte{
+		Pack(nil),
+		Pack([]uint64{1, 2, 3}),
+		Pack([]uint64{math.MaxUint64}),
+		PackVarint(nil),
+		PackVarint([]uint64{1, 300, math.MaxUint64}),
+	} {
+		require.Nil(t, Validate(buf))
+	}
+}
+
+func TestValidateInvalid(t *testing.T) {
+	for _, c := range []struct {
+		buf      []byte
+		expected error
+	}{
+		{nil, ErrTruncated},
+		{[]byte{}, ErrTruncated},
+		{[]byte{0x0}, ErrInvalidWidth},
+		{[]byte{0x9, 0x1}, ErrInvalidWidth},
+		{[]byte{0x2, 0x1, 0x2, 0x3}, ErrTruncated},
+		{[]byte{0x8, 0x1, 0x2, 0x3, 0x4, 0x5, 0x6, 0x7}, ErrTruncated},
+		{[]byte{0x12, 0x1}, ErrInvalidFormat},
+		{[]byte{0x60, 0x1}, ErrInvalidFormat},
+		{[]byte{0x71, 0x1}, ErrInvalidFormat},
+		{[]byte{0x81, 0x1}, ErrTruncated},
+		{[]byte{0x91, 0x1}, ErrTruncated},
+		{[]byte{byte(FormatVarint), 0x1, 0x80}, ErrTruncated},
+		{[]byte{byte(FormatVarint), 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0x1}, ErrInvalidFormat},
+	} {
+		require.ErrorIs(t, Validate(c.buf), c.expected, "%x", c.buf)
+	}
+}
@@ -0,0 +1,61 @@
+package bitpack
+
+import (
+	"encoding/binary"
+)
+
+// PackVarint compresses a slice of uint64 values into a byte slice using per-element variable-width
+// (LEB128) encoding, such that each value only occupies as many bytes as it requires by itself
+// (beneficial for datasets where a few large outliers would otherwise force the whole slice to a
+// large width). The result can be decoded using any of the generic unpack functions
+func PackVarint(data []uint64) []byte {
+	b := make([]byte, 1, 1+2*len(data))
+	b[0] = byte(FormatVarint)
+	for _, v := range data {
+		b = binary.AppendUvarint(b, v)
+	}
+
+	return b
+}
+
+////////////////////////////////////////////////////////////////////////////////////////
+
+func unpackVarintInto(b []byte, res []uint64) []uint64 {
+	nElements := varintLen(b)
+	if cap(res) < nElements {
+		res = make([]uint64, nElements, nElements*2)
+	}
+	res = res[:nElements]
+
+	for i := 0; i < nElements; i++ {
+		v, n := binary.Uvarint(b)
+		if n <= 0 {
+			return res[:i]
+		}
+		res[i], b = v, b[n:]
+	}
+
+	return res
+}
+
+func varintAt(b []byte, at int) (uint64, bool) {
+	for i := 0; ; i++ {
+		v, n := binary.Uvarint(b)
+		if n <= 0 {
+			return 0, false
+		}
+		if i == at {
+			return v, true
+		}
+		b = b[n:]
+	}
+}
+
+func varintLen(b []byte) (n int) {
+	for _, c := range b {
+		if c < 0x80 {
+			n++
+		}
+	}
+	return
+}
@@ -0,0 +1,58 @@
+package bitpack
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPackVarint(t *testing.T) {
+	for _, input := range [][]uint64{
+		{},
+		{0},
+		{0, 1, 127, 128, 16383, 16384},
+		{1, 2, 3, math.MaxUint64, 4, 5},
+	} {
+		buf := PackVarint(input)
+		require.Equal(t, FormatVarint, FormatOf(buf))
+		require.Zero(t, ByteWidth(buf))
+		require.Equal(t, len(input), Len(buf))
+
+		require.Equal(t, input, Unpack(buf))
+		require.Equal(t, input, UnpackInto(buf, make([]uint64, 0, 1)))
+
+		for i := range input {
+			v, err := At(buf, i)
+			require.Nil(t, err)
+			require.Equal(t, input[i], v)
+		}
+		_, err := At(buf, len(input))
+		require.ErrorIs(t, err, ErrIndexOutOfRange)
+		_, err = At(buf, -1)
+		require.ErrorIs(t, err, ErrIndexOutOfRange)
+	}
+}
+
+func TestPackVarintOutliers(t *testing.T) {
+	input := make([]uint64, 1000)
+	for i := range input {
+		input[i] = uint64(i % 100)
+	}
+	input[500] = math.MaxUint64
+
+	// A single outlier forces the fixed-width format to full width
+	require.Equal(t, 1+8*len(input), len(Pack(input)))
+	require.Equal(t, 1+len(input)-1+10, len(PackVarint(input)))
+}
+
+func TestUnpackVarintCorrupt(t *testing.T) {
+
+	// Truncated trailing element is dropped
+	buf := PackVarint([]uint64{1, 300})
+	require.Equal(t, []uint64{1}, Unpack(buf[:len(buf)-1]))
+
+	// Overflowing element terminates decoding
+	buf = append([]byte{byte(FormatVarint), 0x1}, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0x1)
+	require.Equal(t, []uint64{1}, Unpack(buf))
+}
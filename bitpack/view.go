@@ -0,0 +1,37 @@
+package bitpack
+
+import (
+	"encoding/binary"
+	"unsafe"
+)
+
+// nativeLittleEndian denotes if the host uses little-endian byte order (matching the default
+// byte order of packed buffers)
+var nativeLittleEndian = binary.NativeEndian.Uint16([]byte{0x1, 0x0}) == 0x1
+
+// UnpackView provides a zero-copy view of a packed buffer using full byte width (8) as slice of
+// uint64 values by reinterpreting the underlying memory. This is only possible if the host byte order
+// matches the one of the buffer and the packed elements are aligned to 8 bytes in memory (i.e. the
+// address of the first element, following the header byte, is a multiple of 8), otherwise false is
+// returned and the buffer has to be decoded via Unpack / UnpackInto instead. Since the view shares
+// memory with the buffer it must not be modified and is only valid as long as the buffer is
+func UnpackView(b []byte) ([]uint64, bool) {
+	if !nativeLittleEndian || headerFlags(b)&FlagBigEndian != 0 {
+		return nil, false
+	}
+
+	b = payload(b)
+	if len(b) == 0 || FormatOf(b) != FormatFixed || ByteWidth(b) != 8 || (len(b)-1)%8 != 0 {
+		return nil, false
+	}
+
+	data := b[1:]
+	if len(data) == 0 {
+		return []uint64{}, true
+	}
+	if uintptr(unsafe.Pointer(&data[0]))%unsafe.Alignof(uint64(0)) != 0 {
+		return nil, false
+	}
+
+	return unsafe.Slice((*uint64)(unsafe.Pointer(&data[0])), len(data)/8), true // #nosec G103
+}
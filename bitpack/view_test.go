@@ -0,0 +1,85 @@
+package bitpack
+
+import (
+	"testing"
+	"unsafe"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnpackView(t *testing.T) {
+	input := []uint64{1 << 63, 1, 2, 1<<64 - 1}
+
+	for _, opts := range [][]PackOption{nil, {WithHeader()}, {WithChecksum()}} {
+		packed := alignedCopy(Pack(input, opts...), opts)
+
+		view, ok := UnpackView(packed)
+		require.True(t, ok)
+		require.Equal(t, input, view)
+
+		// The view must share memory with the buffer
+		require.Equal(t, unsafe.Pointer(&packed[len(packed)-len(input)*8-trailerLen(opts)]), unsafe.Pointer(&view[0]))
+	}
+
+	// Misaligned buffers cannot be viewed
+	packed := alignedCopy(Pack(input), nil)
+	misaligned := append(make([]byte, 0, len(packed)+1), 0)
+	_, ok := UnpackView(append(misaligned, packed...)[1:])
+	require.False(t, ok)
+
+	// Buffers of other width / format / byte order cannot be viewed
+	for _, packed := range [][]byte{
+		Pack([]uint64{1, 2, 3}),
+		PackVarint(input),
+		Pack(input, WithBigEndian()),
+		nil,
+	} {
+		_, ok := UnpackView(packed)
+		require.False(t, ok)
+	}
+}
+
+func BenchmarkUnpackView(b *testing.B) {
+	input := make([]uint64, 1<<16)
+	for i := range input {
+		input[i] = uint64(i) << 56
+	}
+	packed := alignedCopy(Pack(input), nil)
+
+	b.Run("view", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			_, _ = UnpackView(packed)
+		}
+	})
+	b.Run("unpack", func(b *testing.B) {
+		res := make([]uint64, len(input))
+		b.ReportAllocs()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			_ = UnpackInto(packed, res)
+		}
+	})
+}
+
+func trailerLen(opts []PackOption) int {
+	var cfg packConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	return cfg.trailerLen()
+}
+
+// alignedCopy copies a packed buffer such that its first element is aligned to 8 bytes
+func alignedCopy(packed []byte, opts []PackOption) []byte {
+	var cfg packConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	off := cfg.headerLen() + 1
+
+	buf := make([]byte, len(packed)+16)
+	shift := (8 - (int(uintptr(unsafe.Pointer(&buf[0])))+off)%8) % 8
+
+	return append(buf[shift:shift], packed...)
+}
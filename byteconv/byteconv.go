@@ -0,0 +1,23 @@
+// Package byteconv provides zero-copy conversions between strings and byte slices as well as
+// allocation-free parsing of numbers and IP addresses from byte slices
+package byteconv
+
+import "unsafe"
+
+// BytesToString converts a byte slice to a string without copying / allocation (the byte slice
+// must not be modified as long as the returned string is in use)
+func BytesToString(b []byte) string {
+	if len(b) == 0 {
+		return ""
+	}
+	return unsafe.String(unsafe.SliceData(b), len(b)) // #nosec G103
+}
+
+// StringToBytes converts a string to a byte slice without copying / allocation (the returned
+// byte slice must never be modified, since strings are immutable)
+func StringToBytes(s string) []byte {
+	if len(s) == 0 {
+		return nil
+	}
+	return unsafe.Slice(unsafe.StringData(s), len(s)) // #nosec G103
+}
@@ -0,0 +1,42 @@
+package byteconv
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestConversion(t *testing.T) {
+	for _, s := range []string{"", "a", "test string", "\x00\xff"} {
+		b := StringToBytes(s)
+		require.Equal(t, len(s), len(b))
+		require.Equal(t, s, string(b))
+		require.Equal(t, s, BytesToString(b))
+	}
+	require.Equal(t, "", BytesToString(nil))
+	require.Nil(t, StringToBytes(""))
+
+	// BytesToString must not copy the underlying data
+	buf := []byte("abc")
+	s := BytesToString(buf)
+	buf[0] = 'x'
+	require.Equal(t, "xbc", s)
+}
+
+func TestConversionAllocs(t *testing.T) {
+	buf, str := []byte("test string"), "test string"
+	require.Zero(t, testing.AllocsPerRun(100, func() {
+		_ = BytesToString(buf)
+		_ = StringToBytes(str)
+	}))
+}
+
+func FuzzConversion(f *testing.F) {
+	f.Add([]byte("test"))
+	f.Add([]byte{})
+	f.Fuzz(func(t *testing.T, data []byte) {
+		s := BytesToString(data)
+		require.Equal(t, string(data), s)
+		require.Equal(t, len(data), len(StringToBytes(s)))
+	})
+}
@@ -0,0 +1,69 @@
+package byteconv
+
+import (
+	"math"
+	"net/netip"
+	"strconv"
+)
+
+// ParseUint parses a base 10 unsigned integer from a byte slice without allocation (returning
+// strconv.ErrSyntax / strconv.ErrRange in case of invalid / out-of-range input)
+func ParseUint(b []byte) (uint64, error) {
+	if len(b) == 0 {
+		return 0, strconv.ErrSyntax
+	}
+
+	var n uint64
+	for _, c := range b {
+		if c < '0' || c > '9' {
+			return 0, strconv.ErrSyntax
+		}
+
+		d := uint64(c - '0')
+		if n > (math.MaxUint64-d)/10 {
+			return 0, strconv.ErrRange
+		}
+		n = n*10 + d
+	}
+
+	return n, nil
+}
+
+// ParseInt parses a base 10 signed integer (with optional sign) from a byte slice without
+// allocation (returning strconv.ErrSyntax / strconv.ErrRange in case of invalid / out-of-range input)
+func ParseInt(b []byte) (int64, error) {
+	if len(b) == 0 {
+		return 0, strconv.ErrSyntax
+	}
+
+	neg := false
+	switch b[0] {
+	case '-':
+		neg, b = true, b[1:]
+	case '+':
+		b = b[1:]
+	}
+
+	n, err := ParseUint(b)
+	if err != nil {
+		return 0, err
+	}
+
+	if neg {
+		if n > -math.MinInt64 {
+			return 0, strconv.ErrRange
+		}
+		return -int64(n), nil // #nosec G115
+	}
+	if n > math.MaxInt64 {
+		return 0, strconv.ErrRange
+	}
+
+	return int64(n), nil
+}
+
+// ParseIP parses an IPv4 / IPv6 address from a byte slice without allocation (for addresses
+// without IPv6 zone)
+func ParseIP(b []byte) (netip.Addr, error) {
+	return netip.ParseAddr(BytesToString(b))
+}
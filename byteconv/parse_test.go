@@ -0,0 +1,122 @@
+package byteconv
+
+import (
+	"net/netip"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseUint(t *testing.T) {
+	for _, s := range []string{"0", "1", "42", "18446744073709551615", "00012"} {
+		expected, err := strconv.ParseUint(s, 10, 64)
+		require.Nil(t, err)
+		n, err := ParseUint([]byte(s))
+		require.Nil(t, err)
+		require.Equal(t, expected, n)
+	}
+
+	for s, expectedErr := range map[string]error{
+		"":                     strconv.ErrSyntax,
+		"-1":                   strconv.ErrSyntax,
+		"1a":                   strconv.ErrSyntax,
+		" 1":                   strconv.ErrSyntax,
+		"18446744073709551616": strconv.ErrRange,
+		"99999999999999999999": strconv.ErrRange,
+	} {
+		_, err := ParseUint([]byte(s))
+		require.ErrorIs(t, err, expectedErr, s)
+	}
+}
+
+func TestParseInt(t *testing.T) {
+	for _, s := range []string{"0", "-1", "+1", "42", "9223372036854775807", "-9223372036854775808"} {
+		expected, err := strconv.ParseInt(s, 10, 64)
+		require.Nil(t, err)
+		n, err := ParseInt([]byte(s))
+		require.Nil(t, err)
+		require.Equal(t, expected, n)
+	}
+
+	for s, expectedErr := range map[string]error{
+		"":                     strconv.ErrSyntax,
+		"-":                    strconv.ErrSyntax,
+		"--1":                  strconv.ErrSyntax,
+		"9223372036854775808":  strconv.ErrRange,
+		"-9223372036854775809": strconv.ErrRange,
+	} {
+		_, err := ParseInt([]byte(s))
+		require.ErrorIs(t, err, expectedErr, s)
+	}
+}
+
+func TestParseIP(t *testing.T) {
+	for _, s := range []string{"127.0.0.1", "192.168.1.254", "::1", "2001:db8::1"} {
+		addr, err := ParseIP([]byte(s))
+		require.Nil(t, err)
+		require.Equal(t, netip.MustParseAddr(s), addr)
+	}
+
+	for _, s := range []string{"", "1.2.3", "1.2.3.256", "example.org"} {
+		_, err := ParseIP([]byte(s))
+		require.Error(t, err)
+	}
+}
+
+func TestParseAllocs(t *testing.T) {
+	num, ip := []byte("-1234567890"), []byte("192.168.1.1")
+	require.Zero(t, testing.AllocsPerRun(100, func() {
+		_, _ = ParseUint(num[1:])
+		_, _ = ParseInt(num)
+		_, _ = ParseIP(ip)
+	}))
+}
+
+func FuzzParseUint(f *testing.F) {
+	for _, seed := range []string{"0", "42", "18446744073709551615", "18446744073709551616", "-1", "a"} {
+		f.Add([]byte(seed))
+	}
+	f.Fuzz(func(t *testing.T, data []byte) {
+		expected, expectedErr := strconv.ParseUint(string(data), 10, 64)
+		n, err := ParseUint(data)
+		if expectedErr != nil {
+			require.Error(t, err)
+			return
+		}
+		require.Nil(t, err)
+		require.Equal(t, expected, n)
+	})
+}
+
+func FuzzParseInt(f *testing.F) {
+	for _, seed := range []string{"0", "-42", "+42", "9223372036854775807", "-9223372036854775809", "-"} {
+		f.Add([]byte(seed))
+	}
+	f.Fuzz(func(t *testing.T, data []byte) {
+		expected, expectedErr := strconv.ParseInt(string(data), 10, 64)
+		n, err := ParseInt(data)
+		if expectedErr != nil {
+			require.Error(t, err)
+			return
+		}
+		require.Nil(t, err)
+		require.Equal(t, expected, n)
+	})
+}
+
+func FuzzParseIP(f *testing.F) {
+	for _, seed := range []string{"127.0.0.1", "::1", "fe80::1%eth0", "1.2.3"} {
+		f.Add([]byte(seed))
+	}
+	f.Fuzz(func(t *testing.T, data []byte) {
+		expected, expectedErr := netip.ParseAddr(string(data))
+		addr, err := ParseIP(data)
+		if expectedErr != nil {
+			require.Error(t, err)
+			return
+		}
+		require.Nil(t, err)
+		require.Equal(t, expected, addr)
+	})
+}
@@ -0,0 +1,284 @@
+// Package cache provides a generic in-memory cache with TTL expiry and LRU eviction
+package cache
+
+import (
+	"container/list"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+const (
+	// NoTTL denotes that cache entries never expire
+	NoTTL = 0
+
+	// NoLimit denotes that the cache size is not limited
+	NoLimit = 0
+)
+
+// ErrLoaderPanicked denotes that the loader function panicked (returned to all callers waiting for
+// the respective load, while the panic itself is propagated to the caller that ran the loader)
+var ErrLoaderPanicked = errors.New("loader panicked")
+
+// Option denotes a functional option for a Cache
+type Option func(*config)
+
+// WithTTL sets the default time-to-live for cache entries
+func WithTTL(ttl time.Duration) Option {
+	return func(c *config) {
+		c.ttl = ttl
+	}
+}
+
+// WithMaxSize sets the maximum number of entries held by the cache (evicting the least recently
+// used entry once reached)
+func WithMaxSize(n int) Option {
+	return func(c *config) {
+		c.maxSize = n
+	}
+}
+
+// LoaderFunc denotes a function that loads a value for a key not (or no longer) present in the cache
+type LoaderFunc[K comparable, V any] func(key K) (V, error)
+
+// Stats denotes cache usage statistics
+type Stats struct {
+	Hits        uint64
+	Misses      uint64
+	Evictions   uint64
+	Expirations uint64
+	Loads       uint64
+	LoadErrors  uint64
+}
+
+// Cache denotes a generic, concurrency-safe cache with TTL expiry and LRU eviction
+type Cache[K comparable, V any] struct {
+	config
+
+	items    map[K]*list.Element
+	lru      *list.List
+	inFlight map[K]*call[V]
+	stats    Stats
+
+	sync.Mutex
+}
+
+// New instantiates a new cache
+func New[K comparable, V any](opts ...Option) *Cache[K, V] {
+	obj := &Cache[K, V]{
+		config: config{
+			ttl:     NoTTL,
+			maxSize: NoLimit,
+			now:     time.Now,
+		},
+		items:    make(map[K]*list.Element),
+		lru:      list.New(),
+		inFlight: make(map[K]*call[V]),
+	}
+	for _, opt := range opts {
+		opt(&obj.config)
+	}
+
+	return obj
+}
+
+// Get retrieves a value from the cache
+func (c *Cache[K, V]) Get(key K) (V, bool) {
+	c.Lock()
+	defer c.Unlock()
+
+	return c.get(key)
+}
+
+// Set adds / updates a value in the cache, using the default TTL
+func (c *Cache[K, V]) Set(key K, value V) {
+	c.SetWithTTL(key, value, c.ttl)
+}
+
+// SetWithTTL adds / updates a value in the cache, using a specific TTL
+func (c *Cache[K, V]) SetWithTTL(key K, value V, ttl time.Duration) {
+	c.Lock()
+	defer c.Unlock()
+
+	c.set(key, value, ttl)
+}
+
+// GetOrLoad retrieves a value from the cache or loads it using the provided loader function if not
+// present (concurrent calls for the same key are deduplicated, i.e. the loader is only called once
+// and all callers receive its result), loader errors are not cached
+func (c *Cache[K, V]) GetOrLoad(key K, loadFn LoaderFunc[K, V]) (V, error) {
+	c.Lock()
+	if value, ok := c.get(key); ok {
+		c.Unlock()
+		return value, nil
+	}
+
+	// If a load for this key is already in progress, wait for its result
+	if cl, exists := c.inFlight[key]; exists {
+		c.Unlock()
+		cl.wg.Wait()
+		return cl.value, cl.err
+	}
+
+	cl := new(call[V])
+	cl.wg.Add(1)
+	c.inFlight[key] = cl
+	c.Unlock()
+
+	// The in-flight call is completed in any case (releasing all waiting callers), even if the loader
+	// panics (in which case the panic is propagated after completion)
+	defer func() {
+		r := recover()
+		if r != nil {
+			cl.err = fmt.Errorf("%w: %v", ErrLoaderPanicked, r)
+		}
+
+		c.Lock()
+		c.stats.Loads++
+		if cl.err == nil {
+			c.set(key, cl.value, c.ttl)
+		} else {
+			c.stats.LoadErrors++
+		}
+		delete(c.inFlight, key)
+		c.Unlock()
+		cl.wg.Done()
+
+		if r != nil {
+			panic(r)
+		}
+	}()
+
+	cl.value, cl.err = loadFn(key)
+
+	return cl.value, cl.err
+}
+
+// Delete removes a value from the cache
+func (c *Cache[K, V]) Delete(key K) {
+	c.Lock()
+	defer c.Unlock()
+
+	if elem, exists := c.items[key]; exists {
+		c.remove(elem)
+	}
+}
+
+// Len returns the number of entries in the cache (including any expired entries that have not
+// been removed yet)
+func (c *Cache[K, V]) Len() int {
+	c.Lock()
+	defer c.Unlock()
+
+	return c.lru.Len()
+}
+
+// PurgeExpired removes all expired entries from the cache
+func (c *Cache[K, V]) PurgeExpired() {
+	c.Lock()
+	defer c.Unlock()
+
+	now := c.now()
+	for elem := c.lru.Back(); elem != nil; {
+		prev := elem.Prev()
+		if elem.Value.(*entry[K, V]).expired(now) {
+			c.remove(elem)
+			c.stats.Expirations++
+		}
+		elem = prev
+	}
+}
+
+// Clear removes all entries from the cache
+func (c *Cache[K, V]) Clear() {
+	c.Lock()
+	defer c.Unlock()
+
+	c.items = make(map[K]*list.Element)
+	c.lru.Init()
+}
+
+// Stats returns the current cache usage statistics
+func (c *Cache[K, V]) Stats() Stats {
+	c.Lock()
+	defer c.Unlock()
+
+	return c.stats
+}
+
+////////////////////////////////////////////////////////////////////////////////////////
+
+type config struct {
+	ttl     time.Duration
+	maxSize int
+	now     func() time.Time
+}
+
+type entry[K comparable, V any] struct {
+	key      K
+	value    V
+	expireAt time.Time
+}
+
+func (e *entry[K, V]) expired(now time.Time) bool {
+	return !e.expireAt.IsZero() && !now.Before(e.expireAt)
+}
+
+type call[V any] struct {
+	wg    sync.WaitGroup
+	value V
+	err   error
+}
+
+func (c *Cache[K, V]) get(key K) (value V, ok bool) {
+	elem, exists := c.items[key]
+	if !exists {
+		c.stats.Misses++
+		return
+	}
+
+	e := elem.Value.(*entry[K, V])
+	if e.expired(c.now()) {
+		c.remove(elem)
+		c.stats.Expirations++
+		c.stats.Misses++
+		return
+	}
+
+	c.lru.MoveToFront(elem)
+	c.stats.Hits++
+
+	return e.value, true
+}
+
+func (c *Cache[K, V]) set(key K, value V, ttl time.Duration) {
+	var expireAt time.Time
+	if ttl > 0 {
+		expireAt = c.now().Add(ttl)
+	}
+
+	if elem, exists := c.items[key]; exists {
+		e := elem.Value.(*entry[K, V])
+		e.value, e.expireAt = value, expireAt
+		c.lru.MoveToFront(elem)
+		return
+	}
+
+	c.items[key] = c.lru.PushFront(&entry[K, V]{
+		key:      key,
+		value:    value,
+		expireAt: expireAt,
+	})
+
+	// Evict the least recently used entries if the size limit is exceeded
+	for c.maxSize > 0 && c.lru.Len() > c.maxSize {
+		c.remove(c.lru.Back())
+		c.stats.Evictions++
+	}
+}
+
+func (c *Cache[K, V]) remove(elem *list.Element) {
+	c.lru.Remove(elem)
+	delete(c.items, elem.Value.(*entry[K, V]).key)
+}
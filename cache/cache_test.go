@@ -0,0 +1,195 @@
+package cache
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type testClock struct {
+	now time.Time
+}
+
+func (c *testClock) Now() time.Time {
+	return c.now
+}
+
+func TestCache(t *testing.T) {
+	c := New[string, int]()
+
+	_, ok := c.Get("a")
+	require.False(t, ok)
+
+	c.Set("a", 1)
+	c.Set("b", 2)
+	val, ok := c.Get("a")
+	require.True(t, ok)
+	require.Equal(t, 1, val)
+	require.Equal(t, 2, c.Len())
+
+	c.Set("a", 3)
+	val, ok = c.Get("a")
+	require.True(t, ok)
+	require.Equal(t, 3, val)
+
+	c.Delete("a")
+	_, ok = c.Get("a")
+	require.False(t, ok)
+	require.Equal(t, 1, c.Len())
+
+	c.Clear()
+	require.Zero(t, c.Len())
+
+	require.Equal(t, Stats{Hits: 2, Misses: 2}, c.Stats())
+}
+
+func TestTTL(t *testing.T) {
+	clock := &testClock{now: time.Now()}
+	c := New[string, int](WithTTL(time.Minute))
+	c.now = clock.Now
+
+	c.Set("a", 1)
+	c.SetWithTTL("b", 2, time.Hour)
+	c.SetWithTTL("c", 3, NoTTL)
+
+	clock.now = clock.now.Add(time.Minute)
+	_, ok := c.Get("a")
+	require.False(t, ok)
+	_, ok = c.Get("b")
+	require.True(t, ok)
+
+	clock.now = clock.now.Add(time.Hour)
+	c.PurgeExpired()
+	require.Equal(t, 1, c.Len())
+	val, ok := c.Get("c")
+	require.True(t, ok)
+	require.Equal(t, 3, val)
+
+	require.Equal(t, uint64(2), c.Stats().Expirations)
+}
+
+func TestLRU(t *testing.T) {
+	c := New[int, int](WithMaxSize(3))
+	for i := 0; i < 3; i++ {
+		c.Set(i, i)
+	}
+
+	// Access 0 to make 1 the least recently used entry
+	_, ok := c.Get(0)
+	require.True(t, ok)
+
+	c.Set(3, 3)
+	require.Equal(t, 3, c.Len())
+	_, ok = c.Get(1)
+	require.False(t, ok)
+	for _, i := range []int{0, 2, 3} {
+		_, ok = c.Get(i)
+		require.True(t, ok)
+	}
+	require.Equal(t, uint64(1), c.Stats().Evictions)
+}
+
+func TestGetOrLoad(t *testing.T) {
+	c := New[string, int]()
+
+	var (
+		nCalls  int32
+		release = make(chan struct{})
+		wg      sync.WaitGroup
+	)
+	loadFn := func(key string) (int, error) {
+		atomic.AddInt32(&nCalls, 1)
+		<-release
+		return len(key), nil
+	}
+
+	// Concurrent loads for the same key must only trigger a single loader call
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			val, err := c.GetOrLoad("test", loadFn)
+			require.Nil(t, err)
+			require.Equal(t, 4, val)
+		}()
+	}
+	require.Eventually(t, func() bool {
+		return atomic.LoadInt32(&nCalls) == 1
+	}, time.Second, time.Millisecond)
+	close(release)
+	wg.Wait()
+	require.Equal(t, int32(1), atomic.LoadInt32(&nCalls))
+
+	// Subsequent calls are served from the cache
+	val, err := c.GetOrLoad("test", loadFn)
+	require.Nil(t, err)
+	require.Equal(t, 4, val)
+	require.Equal(t, int32(1), atomic.LoadInt32(&nCalls))
+
+	// Errors are not cached
+	errLoad := errors.New("load failed")
+	_, err = c.GetOrLoad("fail", func(string) (int, error) {
+		return 0, errLoad
+	})
+	require.ErrorIs(t, err, errLoad)
+	_, ok := c.Get("fail")
+	require.False(t, ok)
+
+	stats := c.Stats()
+	require.Equal(t, uint64(2), stats.Loads)
+	require.Equal(t, uint64(1), stats.LoadErrors)
+}
+
+func TestGetOrLoadPanic(t *testing.T) {
+	c := New[string, int]()
+
+	var (
+		started = make(chan struct{})
+		release = make(chan struct{})
+		errWait = make(chan error)
+	)
+
+	// Callers waiting for a panicking loader must be released and receive an error
+	go func() {
+		require.Panics(t, func() {
+			_, _ = c.GetOrLoad("test", func(string) (int, error) {
+				close(started)
+				<-release
+				panic("load failed")
+			})
+		})
+	}()
+	<-started
+	go func() {
+		_, err := c.GetOrLoad("test", func(string) (int, error) {
+			return 0, nil
+		})
+		errWait <- err
+	}()
+	require.Eventually(t, func() bool {
+		return c.Stats().Misses >= 2
+	}, time.Second, time.Millisecond)
+	close(release)
+
+	select {
+	case err := <-errWait:
+		require.ErrorIs(t, err, ErrLoaderPanicked)
+	case <-time.After(time.Second):
+		t.Fatal("waiting caller was not released")
+	}
+
+	// The key must be loadable again afterwards
+	val, err := c.GetOrLoad("test", func(key string) (int, error) {
+		return len(key), nil
+	})
+	require.Nil(t, err)
+	require.Equal(t, 4, val)
+
+	stats := c.Stats()
+	require.Equal(t, uint64(2), stats.Loads)
+	require.Equal(t, uint64(1), stats.LoadErrors)
+}
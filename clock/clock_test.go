@@ -0,0 +1,28 @@
+package clock
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSystem(t *testing.T) {
+	start := System.Now()
+	System.Sleep(time.Millisecond)
+	require.GreaterOrEqual(t, System.Since(start), time.Millisecond)
+
+	<-System.After(time.Millisecond)
+
+	timer := System.NewTimer(time.Hour)
+	require.True(t, timer.Stop())
+	timer.Reset(time.Millisecond)
+	<-timer.C()
+
+	ticker := System.NewTicker(time.Millisecond)
+	<-ticker.C()
+	<-ticker.C()
+	ticker.Reset(2 * time.Millisecond)
+	<-ticker.C()
+	ticker.Stop()
+}
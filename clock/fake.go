@@ -0,0 +1,219 @@
+package clock
+
+import (
+	"sync"
+	"time"
+)
+
+// Fake denotes a manually controlled clock, whose time only changes via Advance() / Set(), firing
+// any timers / tickers that expire in the process
+type Fake struct {
+	now     time.Time
+	waiters map[*fakeWaiter]struct{}
+
+	cond *sync.Cond
+	sync.Mutex
+}
+
+// NewFake instantiates a new fake clock set to the provided point in time
+func NewFake(now time.Time) *Fake {
+	obj := &Fake{
+		now:     now,
+		waiters: make(map[*fakeWaiter]struct{}),
+	}
+	obj.cond = sync.NewCond(&obj.Mutex)
+
+	return obj
+}
+
+// Now returns the current (fake) time
+func (f *Fake) Now() time.Time {
+	f.Lock()
+	defer f.Unlock()
+
+	return f.now
+}
+
+// Since returns the (fake) time elapsed since t
+func (f *Fake) Since(t time.Time) time.Duration {
+	return f.Now().Sub(t)
+}
+
+// After returns a channel that receives the (fake) time once the duration has elapsed
+func (f *Fake) After(d time.Duration) <-chan time.Time {
+	return f.NewTimer(d).C()
+}
+
+// Sleep blocks until the (fake) duration has elapsed
+func (f *Fake) Sleep(d time.Duration) {
+	<-f.After(d)
+}
+
+// NewTimer creates a new timer based on the fake clock
+func (f *Fake) NewTimer(d time.Duration) Timer {
+	return &fakeTimer{f.newWaiter(d, 0)}
+}
+
+// NewTicker creates a new ticker based on the fake clock
+func (f *Fake) NewTicker(d time.Duration) Ticker {
+	if d <= 0 {
+		panic("non-positive interval for NewTicker")
+	}
+	return &fakeTicker{f.newWaiter(d, d)}
+}
+
+// Advance moves the fake clock forward by the provided duration
+func (f *Fake) Advance(d time.Duration) {
+	f.Lock()
+	defer f.Unlock()
+
+	f.setLocked(f.now.Add(d))
+}
+
+// Set sets the fake clock to the provided point in time
+func (f *Fake) Set(now time.Time) {
+	f.Lock()
+	defer f.Unlock()
+
+	f.setLocked(now)
+}
+
+// Waiters returns the number of active timers / tickers
+func (f *Fake) Waiters() int {
+	f.Lock()
+	defer f.Unlock()
+
+	return len(f.waiters)
+}
+
+// BlockUntil blocks until at least n timers / tickers are active (e.g. to ensure that the code
+// under test waits on the clock before advancing it)
+func (f *Fake) BlockUntil(n int) {
+	f.Lock()
+	defer f.Unlock()
+
+	for len(f.waiters) < n {
+		f.cond.Wait()
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////////////
+
+type fakeWaiter struct {
+	clock    *Fake
+	ch       chan time.Time
+	deadline time.Time
+	period   time.Duration
+}
+
+func (f *Fake) newWaiter(d, period time.Duration) *fakeWaiter {
+	f.Lock()
+	defer f.Unlock()
+
+	w := &fakeWaiter{
+		clock:  f,
+		ch:     make(chan time.Time, 1),
+		period: period,
+	}
+	f.scheduleLocked(w, d)
+
+	return w
+}
+
+func (f *Fake) scheduleLocked(w *fakeWaiter, d time.Duration) {
+	w.deadline = f.now.Add(d)
+	if d <= 0 && w.period == 0 {
+		w.fire(f.now)
+		return
+	}
+
+	f.waiters[w] = struct{}{}
+	f.cond.Broadcast()
+}
+
+func (f *Fake) setLocked(now time.Time) {
+	f.now = now
+	for w := range f.waiters {
+		if w.deadline.After(now) {
+			continue
+		}
+
+		w.fire(now)
+		if w.period == 0 {
+			delete(f.waiters, w)
+			continue
+		}
+
+		// Tickers drop ticks for slow receivers / large time jumps (cf. time.Ticker)
+		for !w.deadline.After(now) {
+			w.deadline = w.deadline.Add(w.period)
+		}
+	}
+}
+
+func (f *Fake) stopLocked(w *fakeWaiter) bool {
+	_, active := f.waiters[w]
+	delete(f.waiters, w)
+
+	return active
+}
+
+func (w *fakeWaiter) fire(now time.Time) {
+	select {
+	case w.ch <- now:
+	default:
+	}
+}
+
+type fakeTimer struct {
+	*fakeWaiter
+}
+
+func (t *fakeTimer) C() <-chan time.Time {
+	return t.ch
+}
+
+func (t *fakeTimer) Stop() bool {
+	t.clock.Lock()
+	defer t.clock.Unlock()
+
+	return t.clock.stopLocked(t.fakeWaiter)
+}
+
+func (t *fakeTimer) Reset(d time.Duration) bool {
+	t.clock.Lock()
+	defer t.clock.Unlock()
+
+	active := t.clock.stopLocked(t.fakeWaiter)
+	t.clock.scheduleLocked(t.fakeWaiter, d)
+
+	return active
+}
+
+type fakeTicker struct {
+	*fakeWaiter
+}
+
+func (t *fakeTicker) C() <-chan time.Time {
+	return t.ch
+}
+
+func (t *fakeTicker) Stop() {
+	t.clock.Lock()
+	defer t.clock.Unlock()
+
+	t.clock.stopLocked(t.fakeWaiter)
+}
+
+func (t *fakeTicker) Reset(d time.Duration) {
+	if d <= 0 {
+		panic("non-positive interval for Ticker.Reset")
+	}
+
+	t.clock.Lock()
+	defer t.clock.Unlock()
+
+	t.clock.stopLocked(t.fakeWaiter)
+	t.period = d
+	t.clock.scheduleLocked(t.fakeWaiter, d)
+}
@@ -0,0 +1,103 @@
+package clock
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+var testTime = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func TestFakeNow(t *testing.T) {
+	c := NewFake(testTime)
+	require.Equal(t, testTime, c.Now())
+
+	c.Advance(time.Hour)
+	require.Equal(t, testTime.Add(time.Hour), c.Now())
+	require.Equal(t, time.Hour, c.Since(testTime))
+
+	c.Set(testTime)
+	require.Equal(t, testTime, c.Now())
+}
+
+func TestFakeTimer(t *testing.T) {
+	c := NewFake(testTime)
+
+	timer := c.NewTimer(time.Second)
+	require.Equal(t, 1, c.Waiters())
+	c.Advance(999 * time.Millisecond)
+	requireNotFired(t, timer.C())
+
+	c.Advance(time.Millisecond)
+	require.Equal(t, testTime.Add(time.Second), <-timer.C())
+	require.Zero(t, c.Waiters())
+	require.False(t, timer.Stop())
+
+	// Reset an expired timer
+	require.False(t, timer.Reset(time.Second))
+	c.Advance(time.Second)
+	require.Equal(t, testTime.Add(2*time.Second), <-timer.C())
+
+	// Stop an active timer
+	timer.Reset(time.Second)
+	require.True(t, timer.Stop())
+	c.Advance(time.Hour)
+	requireNotFired(t, timer.C())
+
+	// Immediately expiring timer
+	<-c.After(0)
+}
+
+func TestFakeTicker(t *testing.T) {
+	c := NewFake(testTime)
+
+	ticker := c.NewTicker(time.Second)
+	for i := 1; i <= 3; i++ {
+		c.Advance(time.Second)
+		require.Equal(t, testTime.Add(time.Duration(i)*time.Second), <-ticker.C())
+	}
+
+	// Large time jumps must only yield a single tick
+	c.Advance(10 * time.Second)
+	<-ticker.C()
+	requireNotFired(t, ticker.C())
+	c.Advance(time.Second)
+	<-ticker.C()
+
+	ticker.Reset(time.Minute)
+	c.Advance(time.Second)
+	requireNotFired(t, ticker.C())
+	c.Advance(time.Minute)
+	<-ticker.C()
+
+	ticker.Stop()
+	require.Zero(t, c.Waiters())
+	c.Advance(time.Hour)
+	requireNotFired(t, ticker.C())
+
+	require.Panics(t, func() { c.NewTicker(0) })
+	require.Panics(t, func() { ticker.Reset(0) })
+}
+
+func TestFakeBlockUntil(t *testing.T) {
+	c := NewFake(testTime)
+
+	done := make(chan struct{})
+	go func() {
+		c.Sleep(time.Minute)
+		close(done)
+	}()
+
+	c.BlockUntil(1)
+	c.Advance(time.Minute)
+	<-done
+}
+
+func requireNotFired(t *testing.T, ch <-chan time.Time) {
+	select {
+	case <-ch:
+		t.Fatal("unexpected timer / ticker event")
+	default:
+	}
+}
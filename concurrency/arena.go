@@ -0,0 +1,63 @@
+package concurrency
+
+// DefaultArenaSlabSize denotes the default size of the slabs drawn from the memory pool by an Arena
+const DefaultArenaSlabSize = 1 << 16
+
+// Arena provides a region-based allocator carving many small allocations out of large slabs drawn
+// from a memory pool and releasing them all at once (e.g. for request-scoped workloads), avoiding
+// per-allocation pool / GC overhead. Since every slab occupies a pool element until the Arena is
+// released, a limited pool must provide sufficient elements (otherwise Alloc blocks). An Arena is not
+// safe for concurrent use
+type Arena struct {
+	pool     MemPool
+	slabSize int
+
+	slab  []byte
+	slabs [][]byte
+}
+
+// NewArena instantiates a new Arena drawing slabs of the provided size (if zero, defaults to
+// DefaultArenaSlabSize) from the provided memory pool
+func NewArena(pool MemPool, slabSize int) *Arena {
+	if slabSize <= 0 {
+		slabSize = DefaultArenaSlabSize
+	}
+	return &Arena{
+		pool:     pool,
+		slabSize: slabSize,
+	}
+}
+
+// Alloc returns a slice of the requested size carved out of the current slab (drawing a new one
+// from the pool if required). Allocations exceeding the slab size are drawn from the pool directly.
+// As with MemPool.Get(), the content of the slice is undefined. Its capacity is limited to its size,
+// hence appending to it never affects other allocations. The slice must not be used after the Arena
+// has been released
+func (a *Arena) Alloc(size int) []byte {
+	if size > a.slabSize {
+		elem := a.pool.Get(size)
+		a.slabs = append(a.slabs, elem)
+		return elem[:size:size]
+	}
+
+	if len(a.slab)+size > cap(a.slab) {
+		a.slab = a.pool.Get(a.slabSize)[:0]
+		a.slabs = append(a.slabs, a.slab)
+	}
+
+	offset := len(a.slab)
+	a.slab = a.slab[:offset+size]
+
+	return a.slab[offset : offset+size : offset+size]
+}
+
+// Release returns all slabs to the memory pool, invalidating all previous allocations. The Arena can
+// be reused afterwards
+func (a *Arena) Release() {
+	for i, slab := range a.slabs {
+		a.pool.Put(slab)
+		a.slabs[i] = nil
+	}
+	a.slabs = a.slabs[:0]
+	a.slab = nil
+}
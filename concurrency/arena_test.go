@@ -0,0 +1,56 @@
+package concurrency
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestArena(t *testing.T) {
+	for _, pool := range []MemPool{
+		NewMemPool(16),
+		NewMemPoolNoLimit(),
+		NewMemPoolTiered(0, 0),
+	} {
+		arena := NewArena(pool, 256)
+
+		for run := 0; run < 3; run++ {
+			var allocs [][]byte
+			for i := 0; i < 100; i++ {
+				size := i%16 + 1
+				if i%25 == 0 {
+					size = 1000
+				}
+
+				buf := arena.Alloc(size)
+				require.Len(t, buf, size)
+				require.Equal(t, size, cap(buf))
+				for j := range buf {
+					buf[j] = byte(i)
+				}
+				allocs = append(allocs, buf)
+			}
+
+			// Allocations must not overlap
+			for i, buf := range allocs {
+				for _, b := range buf {
+					require.Equal(t, byte(i), b)
+				}
+			}
+			arena.Release()
+		}
+	}
+}
+
+func BenchmarkArena(b *testing.B) {
+	arena := NewArena(NewMemPoolNoLimit(), 0)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for j := 0; j < 1000; j++ {
+			_ = arena.Alloc(64)
+		}
+		arena.Release()
+	}
+}
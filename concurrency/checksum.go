@@ -0,0 +1,195 @@
+package concurrency
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+)
+
+const checksumSize = 4
+
+var (
+	checksumTable = crc32.MakeTable(crc32.Castagnoli)
+
+	// ErrChecksumMismatch denotes that the checksum trailer of a stream does not match its content
+	// (use errors.As with a *ChecksumError to obtain details)
+	ErrChecksumMismatch = errors.New("checksum mismatch")
+)
+
+// ChecksumError denotes a mismatch between the expected (i.e. stored) and the actual checksum of
+// a stream, matching ErrChecksumMismatch via errors.Is
+type ChecksumError struct {
+	Expected uint32
+	Actual   uint32
+}
+
+// Error returns a human-readable representation of the checksum mismatch
+func (e *ChecksumError) Error() string {
+	return fmt.Sprintf("%s (expected %08x, have %08x)", ErrChecksumMismatch, e.Expected, e.Actual)
+}
+
+// Is allows to match the error against ErrChecksumMismatch via errors.Is
+func (e *ChecksumError) Is(target error) bool {
+	return target == ErrChecksumMismatch
+}
+
+// ChecksumWriter provides a pass-through Writer stage computing a CRC-32C checksum of all bytes
+// written through it (at its position in the chain, e.g. after compression), which is appended to
+// the stream as trailer upon Close()
+type ChecksumWriter struct {
+	w   io.Writer
+	crc uint32
+}
+
+// NewChecksumWriter initializes a new checksumming Writer stage, fulfilling the Writer interface
+func NewChecksumWriter() *ChecksumWriter {
+	return &ChecksumWriter{}
+}
+
+// Init resets the checksumming Writer stage for (re-)use, writing to the provided io.Writer
+func (cw *ChecksumWriter) Init(w io.Writer) io.Writer {
+	cw.w, cw.crc = w, 0
+
+	return cw
+}
+
+// Write writes len(p) bytes to the underlying io.Writer, adding all bytes written to the checksum
+func (cw *ChecksumWriter) Write(p []byte) (n int, err error) {
+	n, err = cw.w.Write(p)
+	cw.crc = crc32.Update(cw.crc, checksumTable, p[:n])
+
+	return
+}
+
+// Close appends the checksum trailer to the stream (the underlying io.Writer is not closed)
+func (cw *ChecksumWriter) Close() error {
+	var trailer [checksumSize]byte
+	binary.BigEndian.PutUint32(trailer[:], cw.crc)
+	_, err := cw.w.Write(trailer[:])
+
+	return err
+}
+
+// Return releases the reference to the underlying io.Writer
+func (cw *ChecksumWriter) Return() {
+	cw.w = nil
+}
+
+// ChecksumReader provides a pass-through Reader stage verifying the CRC-32C checksum trailer of a
+// stream written via a ChecksumWriter (at the same position in the chain). The trailer is stripped
+// from the stream and a *ChecksumError is returned once the end of the stream is reached (or upon
+// Close(), if the stream has not been fully consumed) in case of a mismatch
+type ChecksumReader struct {
+	r     io.Reader
+	crc   uint32
+	tail  [checksumSize]byte
+	nTail int
+	err   error
+}
+
+// NewChecksumReader initializes a new checksum verifying Reader stage, fulfilling the Reader interface
+func NewChecksumReader() *ChecksumReader {
+	return &ChecksumReader{}
+}
+
+// Init resets the checksum verifying Reader stage for (re-)use, reading from the provided io.Reader
+func (cr *ChecksumReader) Init(r io.Reader) (io.Reader, error) {
+	cr.r, cr.crc, cr.nTail, cr.err = r, 0, 0, nil
+
+	return cr, nil
+}
+
+// Read reads up to len(p) bytes from the underlying io.Reader, holding back the (potential) checksum
+// trailer until the end of the stream is reached and verifying it
+func (cr *ChecksumReader) Read(p []byte) (int, error) {
+	if cr.err != nil {
+		return 0, cr.err
+	}
+	if len(p) == 0 {
+		return 0, nil
+	}
+
+	for {
+		n, err := cr.r.Read(p)
+		nOut := cr.holdBack(p, n)
+		cr.crc = crc32.Update(cr.crc, checksumTable, p[:nOut])
+
+		if errors.Is(err, io.EOF) {
+			cr.err = cr.verify()
+			return nOut, cr.err
+		}
+		if err != nil || nOut > 0 {
+			return nOut, err
+		}
+	}
+}
+
+// Close verifies the checksum trailer, consuming any remaining data from the stream if it has not
+// been fully read yet (the underlying io.Reader is not closed)
+func (cr *ChecksumReader) Close() error {
+	if cr.err == nil {
+		if _, err := io.Copy(io.Discard, cr); err != nil {
+			return err
+		}
+	}
+	if errors.Is(cr.err, io.EOF) {
+		return nil
+	}
+
+	return cr.err
+}
+
+// Return releases the reference to the underlying io.Reader
+func (cr *ChecksumReader) Return() {
+	cr.r = nil
+}
+
+////////////////////////////////////////////////////////////////////////////////////////
+
+// holdBack takes n bytes read into p, prepends the currently held back bytes and retains the last
+// bytes (that may constitute the checksum trailer) for the next read, returning the number of bytes
+// in p that can be released to the caller
+func (cr *ChecksumReader) holdBack(p []byte, n int) int {
+
+	// Sufficient new data to replace the held back bytes entirely
+	if n >= checksumSize {
+		var tail [checksumSize]byte
+		copy(tail[:], p[n-checksumSize:n])
+		copy(p[cr.nTail:], p[:n-checksumSize])
+		copy(p, cr.tail[:cr.nTail])
+
+		nOut := cr.nTail + n - checksumSize
+		cr.tail, cr.nTail = tail, checksumSize
+		return nOut
+	}
+
+	// Otherwise, combine the held back and new bytes and release only what is not required
+	var combined [2 * checksumSize]byte
+	nCombined := copy(combined[:], cr.tail[:cr.nTail])
+	nCombined += copy(combined[nCombined:], p[:n])
+
+	nOut := 0
+	if nCombined > checksumSize {
+		nOut = nCombined - checksumSize
+	}
+	copy(p, combined[:nOut])
+	cr.nTail = copy(cr.tail[:], combined[nOut:nCombined])
+
+	return nOut
+}
+
+func (cr *ChecksumReader) verify() error {
+	if cr.nTail < checksumSize {
+		return fmt.Errorf("%w: missing checksum trailer", io.ErrUnexpectedEOF)
+	}
+	if expected := binary.BigEndian.Uint32(cr.tail[:]); expected != cr.crc {
+		return &ChecksumError{
+			Expected: expected,
+			Actual:   cr.crc,
+		}
+	}
+
+	return io.EOF
+}
@@ -0,0 +1,400 @@
+package concurrency
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+)
+
+const (
+	// DefaultChunkedFrameSize denotes the default (uncompressed) size of an individual frame of a
+	// chunked container
+	DefaultChunkedFrameSize = 1 << 20
+
+	chunkedMagic      = "GTCF"
+	chunkedFooterSize = 8 + 4 + len(chunkedMagic)
+)
+
+var (
+	// ErrInvalidContainer denotes that a chunked container (or its index) is malformed
+	ErrInvalidContainer = errors.New("invalid chunked container")
+
+	// ErrNotSeekable denotes that the source of a chunked container does not support seeking
+	ErrNotSeekable = errors.New("source does not implement io.ReadSeeker")
+)
+
+// chunkedFrame denotes an index entry of a chunked container
+type chunkedFrame struct {
+	offset, size       int64 // position / size of the (encoded) frame within the container
+	rawOffset, rawSize int64 // position / size of the (raw) frame data within the stream
+}
+
+// ChunkedWriter provides a Writer stage splitting the stream into fixed-size frames that are encoded
+// independently using the provided stage (e.g. a GZIPWriter), followed by an index of all frames. This
+// allows to read parts of the stream (cf. ChunkedReader) without decoding it from the start
+type ChunkedWriter struct {
+	frameSize int
+	stage     Writer
+
+	w      io.Writer
+	buf    []byte
+	frames []chunkedFrame
+	offset int64
+}
+
+// NewChunkedWriter initializes a new chunking Writer stage using the provided frame size (in bytes)
+// and Writer stage for encoding of the individual frames (if nil, frames are stored as is), fulfilling
+// the Writer interface
+func NewChunkedWriter(frameSize int, stage Writer) *ChunkedWriter {
+	if frameSize <= 0 {
+		frameSize = DefaultChunkedFrameSize
+	}
+	return &ChunkedWriter{
+		frameSize: frameSize,
+		stage:     stage,
+	}
+}
+
+// Init resets the chunking Writer stage for (re-)use, writing to the provided io.Writer
+func (cw *ChunkedWriter) Init(w io.Writer) io.Writer {
+	if cw.buf == nil {
+		cw.buf = make([]byte, 0, cw.frameSize)
+	}
+	cw.w, cw.buf, cw.frames, cw.offset = w, cw.buf[:0], cw.frames[:0], 0
+
+	return cw
+}
+
+// Write buffers len(p) bytes, encoding and writing a frame to the underlying io.Writer each time the
+// frame size is reached
+func (cw *ChunkedWriter) Write(p []byte) (n int, err error) {
+	for len(p) > 0 {
+		nCopied := copy(cw.buf[len(cw.buf):cw.frameSize], p)
+		cw.buf = cw.buf[:len(cw.buf)+nCopied]
+		n += nCopied
+		p = p[nCopied:]
+
+		if len(cw.buf) == cw.frameSize {
+			if err = cw.flushFrame(); err != nil {
+				return
+			}
+		}
+	}
+
+	return
+}
+
+// Flush encodes and writes the buffered (partial) frame to the underlying io.Writer (subsequent data
+// starting a new frame)
+func (cw *ChunkedWriter) Flush() error {
+	if len(cw.buf) == 0 {
+		return nil
+	}
+
+	return cw.flushFrame()
+}
+
+// Close writes the remaining (partial) frame and the index to the underlying io.Writer (which is not
+// closed)
+func (cw *ChunkedWriter) Close() error {
+	if err := cw.Flush(); err != nil {
+		return err
+	}
+
+	// Write the index (size of each frame, encoded and raw) and footer
+	index := make([]byte, 0, len(cw.frames)*2*binary.MaxVarintLen64+chunkedFooterSize)
+	for _, frame := range cw.frames {
+		index = binary.AppendUvarint(index, uint64(frame.size))    // #nosec G115
+		index = binary.AppendUvarint(index, uint64(frame.rawSize)) // #nosec G115
+	}
+	index = binary.BigEndian.AppendUint64(index, uint64(len(index)))
+	index = binary.BigEndian.AppendUint32(index, uint32(len(cw.frames))) // #nosec G115
+	index = append(index, chunkedMagic...)
+
+	_, err := cw.w.Write(index)
+	return err
+}
+
+// Return releases the reference to the underlying io.Writer
+func (cw *ChunkedWriter) Return() {
+	cw.w = nil
+}
+
+// NumFrames returns the number of frames written so far
+func (cw *ChunkedWriter) NumFrames() int {
+	return len(cw.frames)
+}
+
+// ChunkedReader provides a Reader stage reading a chunked container written via a ChunkedWriter,
+// decoding the individual frames using the provided stage (e.g. a GZIPReader). Since the index is
+// located at the end of the container, the source must implement io.ReadSeeker. Apart from
+// sequential reads, the stage supports seeking to individual frames (cf. SeekFrame) or arbitrary
+// positions within the (decoded) stream (cf. Seek), only decoding the respective frame
+type ChunkedReader struct {
+	stage Reader
+
+	src    io.ReadSeeker
+	frames []chunkedFrame
+	cur    int
+	frame  io.Reader
+	pos    int64
+}
+
+// NewChunkedReader initializes a new chunked container Reader stage using the provided Reader stage
+// for decoding of the individual frames (if nil, frames are assumed to be stored as is), fulfilling
+// the Reader interface
+func NewChunkedReader(stage Reader) *ChunkedReader {
+	return &ChunkedReader{
+		stage: stage,
+	}
+}
+
+// Init resets the chunked container Reader stage for (re-)use, reading the index of the container
+// from the provided io.Reader (which must implement io.ReadSeeker)
+func (cr *ChunkedReader) Init(r io.Reader) (io.Reader, error) {
+	src, ok := r.(io.ReadSeeker)
+	if !ok {
+		return nil, ErrNotSeekable
+	}
+	cr.src, cr.frames, cr.cur, cr.frame, cr.pos = src, cr.frames[:0], 0, nil, 0
+
+	if err := cr.readIndex(); err != nil {
+		return nil, err
+	}
+
+	return cr, nil
+}
+
+// Read reads up to len(p) bytes from the (decoded) stream, moving on to the next frame once the
+// current one is exhausted
+func (cr *ChunkedReader) Read(p []byte) (n int, err error) {
+	for {
+		if cr.frame == nil {
+			if cr.cur >= len(cr.frames) {
+				return 0, io.EOF
+			}
+			if err = cr.openFrame(cr.cur); err != nil {
+				return 0, err
+			}
+		}
+
+		n, err = cr.frame.Read(p)
+		cr.pos += int64(n)
+		if errors.Is(err, io.EOF) {
+			if err = cr.closeFrame(); err != nil {
+				return
+			}
+			cr.cur++
+			if n == 0 {
+				continue
+			}
+		}
+
+		return
+	}
+}
+
+// NumFrames returns the number of frames in the container
+func (cr *ChunkedReader) NumFrames() int {
+	return len(cr.frames)
+}
+
+// Size returns the total size of the (decoded) stream
+func (cr *ChunkedReader) Size() int64 {
+	if len(cr.frames) == 0 {
+		return 0
+	}
+	last := cr.frames[len(cr.frames)-1]
+	return last.rawOffset + last.rawSize
+}
+
+// SeekFrame positions the stream at the beginning of the n-th frame, returning the resulting position
+// within the (decoded) stream
+func (cr *ChunkedReader) SeekFrame(n int) (int64, error) {
+	if n < 0 || n > len(cr.frames) {
+		return cr.pos, fmt.Errorf("%w: frame %d out of range [0, %d]", ErrInvalidContainer, n, len(cr.frames))
+	}
+	if err := cr.closeFrame(); err != nil {
+		return cr.pos, err
+	}
+
+	cr.cur, cr.pos = n, cr.Size()
+	if n < len(cr.frames) {
+		cr.pos = cr.frames[n].rawOffset
+	}
+
+	return cr.pos, nil
+}
+
+// Seek fulfils the io.Seeker interface, positioning the (decoded) stream at the requested offset
+// (only decoding the frame containing the offset)
+func (cr *ChunkedReader) Seek(offset int64, whence int) (int64, error) {
+	switch whence {
+	case io.SeekStart:
+	case io.SeekCurrent:
+		offset += cr.pos
+	case io.SeekEnd:
+		offset += cr.Size()
+	default:
+		return cr.pos, fmt.Errorf("invalid whence: %d", whence)
+	}
+	if offset < 0 {
+		return cr.pos, fmt.Errorf("invalid negative position: %d", offset)
+	}
+
+	// Determine the frame containing the requested offset and skip to the offset within the frame
+	n := sort.Search(len(cr.frames), func(i int) bool {
+		return cr.frames[i].rawOffset+cr.frames[i].rawSize > offset
+	})
+	if _, err := cr.SeekFrame(n); err != nil {
+		return cr.pos, err
+	}
+	if n == len(cr.frames) {
+		cr.pos = offset
+		return cr.pos, nil
+	}
+	if skip := offset - cr.pos; skip > 0 {
+		if _, err := io.CopyN(io.Discard, cr, skip); err != nil {
+			return cr.pos, err
+		}
+	}
+
+	return cr.pos, nil
+}
+
+// Close closes the currently decoded frame (if any, the underlying io.Reader is not closed)
+func (cr *ChunkedReader) Close() error {
+	return cr.closeFrame()
+}
+
+// Return releases the reference to the underlying io.Reader
+func (cr *ChunkedReader) Return() {
+	cr.src, cr.frame = nil, nil
+}
+
+////////////////////////////////////////////////////////////////////////////////////////
+
+func (cw *ChunkedWriter) flushFrame() error {
+	counter := NewCountingWriter()
+	w := counter.Init(cw.w)
+
+	var err error
+	if cw.stage == nil {
+		_, err = w.Write(cw.buf)
+	} else {
+		if _, err = cw.stage.Init(w).Write(cw.buf); err == nil {
+			err = cw.stage.Close()
+		}
+		cw.stage.Return()
+	}
+	if err != nil {
+		return err
+	}
+
+	frame := chunkedFrame{
+		offset:  cw.offset,
+		size:    counter.Count(),
+		rawSize: int64(len(cw.buf)),
+	}
+	if len(cw.frames) > 0 {
+		last := cw.frames[len(cw.frames)-1]
+		frame.rawOffset = last.rawOffset + last.rawSize
+	}
+	cw.frames = append(cw.frames, frame)
+	cw.offset += frame.size
+	cw.buf = cw.buf[:0]
+
+	return nil
+}
+
+func (cr *ChunkedReader) readIndex() error {
+	end, err := cr.src.Seek(-int64(chunkedFooterSize), io.SeekEnd)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidContainer, err)
+	}
+
+	var footer [chunkedFooterSize]byte
+	if _, err = io.ReadFull(cr.src, footer[:]); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidContainer, err)
+	}
+	if string(footer[12:]) != chunkedMagic {
+		return fmt.Errorf("%w: magic bytes mismatch", ErrInvalidContainer)
+	}
+	indexSize, nFrames := binary.BigEndian.Uint64(footer[:8]), binary.BigEndian.Uint32(footer[8:12])
+	if indexSize > uint64(end) || uint64(nFrames)*2 > indexSize {
+		return fmt.Errorf("%w: index size %d exceeds container", ErrInvalidContainer, indexSize)
+	}
+
+	// Read and parse the index, reconstructing the offsets of all frames
+	index := make([]byte, indexSize)
+	if _, err = cr.src.Seek(end-int64(indexSize), io.SeekStart); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidContainer, err)
+	}
+	if _, err = io.ReadFull(cr.src, index); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidContainer, err)
+	}
+
+	var frame chunkedFrame
+	for i := uint32(0); i < nFrames; i++ {
+		size, n := binary.Uvarint(index)
+		if n <= 0 {
+			return fmt.Errorf("%w: corrupt index entry %d", ErrInvalidContainer, i)
+		}
+		index = index[n:]
+		rawSize, n := binary.Uvarint(index)
+		if n <= 0 {
+			return fmt.Errorf("%w: corrupt index entry %d", ErrInvalidContainer, i)
+		}
+		index = index[n:]
+
+		frame.size, frame.rawSize = int64(size), int64(rawSize) // #nosec G115
+		if frame.size < 0 || frame.rawSize < 0 || frame.offset+frame.size > end-int64(indexSize) {
+			return fmt.Errorf("%w: frame %d exceeds container", ErrInvalidContainer, i)
+		}
+		cr.frames = append(cr.frames, frame)
+		frame.offset += frame.size
+		frame.rawOffset += frame.rawSize
+	}
+	if len(index) != 0 {
+		return fmt.Errorf("%w: trailing index data", ErrInvalidContainer)
+	}
+
+	return nil
+}
+
+func (cr *ChunkedReader) openFrame(n int) error {
+	if _, err := cr.src.Seek(cr.frames[n].offset, io.SeekStart); err != nil {
+		return err
+	}
+
+	src := io.LimitReader(cr.src, cr.frames[n].size)
+	if cr.stage == nil {
+		cr.frame = src
+		return nil
+	}
+
+	frame, err := cr.stage.Init(src)
+	if err != nil {
+		return err
+	}
+	cr.frame = frame
+
+	return nil
+}
+
+func (cr *ChunkedReader) closeFrame() error {
+	if cr.frame == nil {
+		return nil
+	}
+	cr.frame = nil
+	if cr.stage == nil {
+		return nil
+	}
+
+	err := cr.stage.Close()
+	cr.stage.Return()
+
+	return err
+}
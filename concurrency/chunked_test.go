@@ -0,0 +1,129 @@
+package concurrency
+
+import (
+	"bytes"
+	"io"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestChunkedContainer(t *testing.T) {
+	input := make([]byte, 10500)
+	rand.New(rand.NewSource(42)).Read(input)
+	for i := range input {
+		input[i] %= 16 // make it compressible
+	}
+
+	for _, cs := range []struct {
+		name   string
+		writer func() Writer
+		reader func() Reader
+	}{
+		{"raw", func() Writer { return nil }, func() Reader { return nil }},
+		{"gzip", func() Writer { return NewGZIPWriter() }, func() Reader { return NewGZIPReader() }},
+	} {
+		t.Run(cs.name, func(t *testing.T) {
+			var container []byte
+			cw := NewChunkedWriter(1000, cs.writer())
+			require.Nil(t, NewWriterChain().AddWriter(cw).PostFn(func(rw *ReadWriter) error {
+				container = bytes.Clone(rw.Bytes())
+				return nil
+			}).Build().EncodeAndClose(BytesEncoder, input))
+			require.Equal(t, 11, cw.NumFrames())
+
+			// Sequential read
+			var output []byte
+			require.Nil(t, NewReaderChain(bytes.NewReader(container)).
+				AddReader(NewChunkedReader(cs.reader())).
+				Build().
+				DecodeAndClose(BytesDecoder, &output))
+			require.Equal(t, input, output)
+
+			// Seeking to frames / positions
+			cr := NewChunkedReader(cs.reader())
+			rc := NewReaderChain(bytes.NewReader(container)).AddReader(cr).Build()
+			require.Equal(t, 11, cr.NumFrames())
+			require.Equal(t, int64(len(input)), cr.Size())
+
+			pos, err := cr.SeekFrame(3)
+			require.Nil(t, err)
+			require.Equal(t, int64(3000), pos)
+			buf := make([]byte, 1500)
+			_, err = io.ReadFull(rc, buf)
+			require.Nil(t, err)
+			require.Equal(t, input[3000:4500], buf)
+
+			for _, seek := range []struct {
+				offset   int64
+				whence   int
+				expected int64
+			}{
+				{4321, io.SeekStart, 4321},
+				{-100, io.SeekCurrent, int64(len(input) - 100)},
+				{-10, io.SeekEnd, int64(len(input) - 10)},
+				{0, io.SeekStart, 0},
+			} {
+				pos, err = cr.Seek(seek.offset, seek.whence)
+				require.Nil(t, err)
+				require.Equal(t, seek.expected, pos)
+
+				output, err = io.ReadAll(rc)
+				require.Nil(t, err)
+				require.Equal(t, input[seek.expected:], output)
+			}
+
+			pos, err = cr.Seek(100, io.SeekEnd)
+			require.Nil(t, err)
+			require.Equal(t, int64(len(input)+100), pos)
+			n, err := rc.Read(buf)
+			require.Zero(t, n)
+			require.ErrorIs(t, err, io.EOF)
+
+			_, err = cr.SeekFrame(12)
+			require.ErrorIs(t, err, ErrInvalidContainer)
+			_, err = cr.Seek(-1, io.SeekStart)
+			require.NotNil(t, err)
+			require.Nil(t, rc.Close())
+		})
+	}
+}
+
+func TestChunkedContainerInvalid(t *testing.T) {
+	var container []byte
+	require.Nil(t, NewWriterChain().AddWriter(NewChunkedWriter(10, nil)).PostFn(func(rw *ReadWriter) error {
+		container = bytes.Clone(rw.Bytes())
+		return nil
+	}).Build().EncodeAndClose(BytesEncoder, []byte("This is a test")))
+
+	var output []byte
+	require.ErrorIs(t, NewReaderChain(bytes.NewBuffer(container)).
+		AddReader(NewChunkedReader(nil)).
+		Build().
+		Decode(BytesDecoder, &output), ErrNotSeekable)
+
+	// Empty container
+	var empty []byte
+	require.Nil(t, NewWriterChain().AddWriter(NewChunkedWriter(10, nil)).PostFn(func(rw *ReadWriter) error {
+		empty = bytes.Clone(rw.Bytes())
+		return nil
+	}).Build().Close())
+	require.Nil(t, NewReaderChain(bytes.NewReader(empty)).
+		AddReader(NewChunkedReader(nil)).
+		Build().
+		DecodeAndClose(BytesDecoder, &output))
+	require.Empty(t, output)
+
+	for _, corrupt := range [][]byte{
+		container[:len(container)-1],
+		container[len(container)-chunkedFooterSize:],
+		append(bytes.Clone(container[:len(container)-4]), "XXXX"...),
+		{},
+	} {
+		require.ErrorIs(t, NewReaderChain(bytes.NewReader(corrupt)).
+			AddReader(NewChunkedReader(nil)).
+			Build().
+			Decode(BytesDecoder, &output), ErrInvalidContainer)
+	}
+}
@@ -0,0 +1,95 @@
+package concurrency
+
+import "io"
+
+// CountingWriter provides a pass-through Writer stage counting all bytes written through it (at its
+// position in the chain), e.g. to determine raw and encoded sizes by placing one counting stage
+// before and one after a compression stage
+type CountingWriter struct {
+	w io.Writer
+	n int64
+}
+
+// NewCountingWriter initializes a new counting Writer stage, fulfilling the Writer interface
+func NewCountingWriter() *CountingWriter {
+	return &CountingWriter{}
+}
+
+// Init resets the counting Writer stage for (re-)use, writing to the provided io.Writer
+func (cw *CountingWriter) Init(w io.Writer) io.Writer {
+	cw.w, cw.n = w, 0
+	return cw
+}
+
+// Write writes len(p) bytes to the underlying io.Writer, counting all bytes written
+func (cw *CountingWriter) Write(p []byte) (n int, err error) {
+	n, err = cw.w.Write(p)
+	cw.n += int64(n)
+
+	return
+}
+
+// Close closes the counting Writer stage (the underlying io.Writer is not closed)
+func (cw *CountingWriter) Close() error {
+	return nil
+}
+
+// Return releases the reference to the underlying io.Writer
+func (cw *CountingWriter) Return() {
+	cw.w = nil
+}
+
+// Count returns the number of bytes written through the stage
+func (cw *CountingWriter) Count() int64 {
+	return cw.n
+}
+
+// CountingReader provides a pass-through Reader stage counting all bytes read through it (at its
+// position in the chain)
+type CountingReader struct {
+	r io.Reader
+	n int64
+}
+
+// NewCountingReader initializes a new counting Reader stage, fulfilling the Reader interface
+func NewCountingReader() *CountingReader {
+	return &CountingReader{}
+}
+
+// Init resets the counting Reader stage for (re-)use, reading from the provided io.Reader
+func (cr *CountingReader) Init(r io.Reader) (io.Reader, error) {
+	cr.r, cr.n = r, 0
+	return cr, nil
+}
+
+// Read reads up to len(p) bytes from the underlying io.Reader, counting all bytes read
+func (cr *CountingReader) Read(p []byte) (n int, err error) {
+	n, err = cr.r.Read(p)
+	cr.n += int64(n)
+
+	return
+}
+
+// Close closes the counting Reader stage (the underlying io.Reader is not closed)
+func (cr *CountingReader) Close() error {
+	return nil
+}
+
+// Return releases the reference to the underlying io.Reader
+func (cr *CountingReader) Return() {
+	cr.r = nil
+}
+
+// Count returns the number of bytes read through the stage
+func (cr *CountingReader) Count() int64 {
+	return cr.n
+}
+
+// Ratio returns the ratio between two byte counts (e.g. encoded vs. raw size), returning zero if
+// the denominator is zero
+func Ratio(numerator, denominator int64) float64 {
+	if denominator == 0 {
+		return 0
+	}
+	return float64(numerator) / float64(denominator)
+}
@@ -0,0 +1,67 @@
+package concurrency
+
+import (
+	"compress/bzip2"
+	"io"
+
+	"github.com/ulikunitz/xz"
+)
+
+// XZReader provides a read-only chain stage for the xz format (e.g. for archives produced by
+// external tooling). Since the underlying reader cannot be reset, no pooling is performed
+type XZReader struct {
+	*xz.Reader
+}
+
+// NewXZReader initializes a new (wrapped) xz.Reader instance, fulfilling the Reader interface
+func NewXZReader() *XZReader {
+	return &XZReader{}
+}
+
+// Init initializes a (wrapped) xz.Reader instance
+func (x *XZReader) Init(r io.Reader) (io.Reader, error) {
+	xr, err := xz.NewReader(r)
+	if err != nil {
+		return nil, err
+	}
+	x.Reader = xr
+
+	return x.Reader, nil
+}
+
+// Close closes a (wrapped) xz.Reader instance
+func (x *XZReader) Close() error {
+	return nil
+}
+
+// Return releases the (wrapped) xz.Reader instance
+func (x *XZReader) Return() {
+	x.Reader = nil
+}
+
+// BZIP2Reader provides a read-only chain stage for the bzip2 format (e.g. for archives produced
+// by external tooling). Since the underlying reader cannot be reset, no pooling is performed
+type BZIP2Reader struct {
+	io.Reader
+}
+
+// NewBZIP2Reader initializes a new (wrapped) bzip2 reader instance, fulfilling the Reader interface
+func NewBZIP2Reader() *BZIP2Reader {
+	return &BZIP2Reader{}
+}
+
+// Init initializes a (wrapped) bzip2 reader instance
+func (b *BZIP2Reader) Init(r io.Reader) (io.Reader, error) {
+	b.Reader = bzip2.NewReader(r)
+	return b.Reader, nil
+}
+
+// Close closes a (wrapped) bzip2 reader instance
+func (b *BZIP2Reader) Close() error {
+	return nil
+}
+
+// Return releases the (wrapped) bzip2 reader instance
+func (b *BZIP2Reader) Return() {
+	b.Reader = nil
+}
@@ -1,30 +1,88 @@
 package concurrency
 
 import (
+	"bufio"
 	"compress/gzip"
+	"context"
+	"encoding/binary"
 	"errors"
 	"fmt"
 	"io"
+	"reflect"
+	"strings"
 	"sync"
+	"time"
 
-	jsoniter "github.com/json-iterator/go"
 	yaml "gopkg.in/yaml.v3"
 )
 
+// MaxStreamFrameSize denotes the maximum size of an individual object frame in a stream of objects
+// (cf. WriterChain.EncodeStream / ReaderChain.DecodeNext)
+const MaxStreamFrameSize = 1 << 30
+
 var (
 	gzipWPool, gzipRPool sync.Pool
 
+	// ErrInvalidFrame denotes that a frame read from a stream of objects is malformed
+	ErrInvalidFrame = errors.New("invalid frame in object stream")
+
 	// ErrExpectByteSlicePtr denotes that the assertion of a byte slice pointer failed
 	ErrExpectByteSlicePtr = errors.New("expected byte slice reference / pointer argument")
 
 	// ErrExpectByteSlice denotes that the assertion of a byte slice failed
 	ErrExpectByteSlice = errors.New("expected byte slice argument")
+
+	// ErrMaxBytesExceeded denotes that the data to be decoded exceeds the maximum permitted size
+	ErrMaxBytesExceeded = errors.New("maximum number of bytes exceeded")
 )
 
+// BytesDecoderOption denotes a functional option for a bytes decoder (cf. NewBytesDecoder)
+type BytesDecoderOption func(*byteDecoder)
+
+// WithMaxBytes sets the maximum number of bytes to be decoded (failing with ErrMaxBytesExceeded
+// if the data is larger, without reading it in its entirety)
+func WithMaxBytes(n int) BytesDecoderOption {
+	return func(bd *byteDecoder) {
+		bd.maxBytes = n
+	}
+}
+
+// WithExpectedSize sets the expected number of bytes to be decoded, allowing to allocate the
+// destination slice only once (if the actual size is larger, the slice is grown as required)
+func WithExpectedSize(n int) BytesDecoderOption {
+	return func(bd *byteDecoder) {
+		bd.expectedSize = n
+	}
+}
+
+// WithZeroCopy causes the decoder to return a newly allocated slice instead of copying the data into
+// the destination slice (cf. BytesDecoderZeroCopy)
+func WithZeroCopy() BytesDecoderOption {
+	return func(bd *byteDecoder) {
+		bd.zeroCopy = true
+	}
+}
+
+// NewBytesDecoder returns a bytes decoder function (cf. BytesDecoder) using the provided options. If
+// a maximum or expected size is set, the data is read directly into the destination slice (which is
+// truncated to the size of the data)
+func NewBytesDecoder(opts ...BytesDecoderOption) DecoderFn {
+	return func(r io.Reader) Decoder {
+		bd := &byteDecoder{Reader: r}
+		for _, opt := range opts {
+			opt(bd)
+		}
+		return bd
+	}
+}
+
 // byteDecoder reads bytes from a Reader
 type byteDecoder struct {
 	io.Reader
-	zeroCopy bool
+	zeroCopy     bool
+	maxBytes     int
+	expectedSize int
+	probe        [1]byte
 }
 
 // Decode reads bytes from a Reader
@@ -33,6 +91,9 @@ func (bd *byteDecoder) Decode(v any) error {
 	if !ok {
 		return ErrExpectByteSlicePtr
 	}
+	if bd.maxBytes > 0 || bd.expectedSize > 0 {
+		return bd.decodeSized(slice)
+	}
 
 	out, err := io.ReadAll(bd)
 	if err != nil {
@@ -51,6 +112,66 @@ func (bd *byteDecoder) Decode(v any) error {
 	return nil
 }
 
+// decodeSized reads bytes from a Reader directly into the destination slice, preallocating it based
+// on the expected size and enforcing the maximum size (if set)
+func (bd *byteDecoder) decodeSized(slice *[]byte) error {
+	var buf []byte
+	if !bd.zeroCopy {
+		buf = (*slice)[:0]
+	}
+
+	size, r := bd.expectedSize, bd.Reader
+	if bd.maxBytes > 0 {
+		if size > bd.maxBytes {
+			size = bd.maxBytes
+		}
+		r = io.LimitReader(r, int64(bd.maxBytes)+1)
+	}
+	if cap(buf) < size {
+		buf = make([]byte, 0, size)
+	}
+
+	buf, err := bd.readAll(r, buf)
+	if err != nil {
+		return err
+	}
+	if bd.maxBytes > 0 && len(buf) > bd.maxBytes {
+		return fmt.Errorf("%w: more than %d bytes", ErrMaxBytesExceeded, bd.maxBytes)
+	}
+	*slice = buf
+
+	return nil
+}
+
+// readAll reads from r until EOF, appending to b (similar to io.ReadAll, but probing for EOF before
+// growing a full buffer, so a buffer preallocated to the exact size of the data is never reallocated)
+func (bd *byteDecoder) readAll(r io.Reader, b []byte) ([]byte, error) {
+	for {
+		if len(b) == cap(b) {
+			n, err := r.Read(bd.probe[:])
+			if n > 0 {
+				b = append(b, bd.probe[0])
+			}
+			if err != nil {
+				if errors.Is(err, io.EOF) {
+					return b, nil
+				}
+				return b, err
+			}
+			continue
+		}
+
+		n, err := r.Read(b[len(b):cap(b)])
+		b = b[:len(b)+n]
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return b, nil
+			}
+			return b, err
+		}
+	}
+}
+
 // byteEncoder wrties bytes to a Writer
 type byteEncoder struct {
 	io.Writer
@@ -64,20 +185,23 @@ func (be *byteEncoder) Encode(v any) error {
 	}
 
 	n, err := be.Write(slice)
+	if err != nil {
+		return err
+	}
 	if n != len(slice) {
 		return fmt.Errorf("unexpected number of bytes written (want %d, have %d)", len(slice), n)
 	}
 
-	return err
+	return nil
 }
 
 // Some default encoder wrapper / convenience functions
 var (
 	JSONEncoder = func(w io.Writer) Encoder {
-		return jsoniter.NewEncoder(w)
+		return newJSONEncoder(w)
 	}
 	JSONDecoder = func(r io.Reader) Decoder {
-		return jsoniter.NewDecoder(r)
+		return newJSONDecoder(r)
 	}
 	YAMLEncoder = func(w io.Writer) Encoder {
 		return yaml.NewEncoder(w)
@@ -110,6 +234,12 @@ type Reader interface {
 	Return()
 }
 
+// Flusher denotes a Writer stage supporting to flush buffered data to the underlying io.Writer without
+// closing the stage (cf. WriterChain.Flush)
+type Flusher interface {
+	Flush() error
+}
+
 // GZIPWriter provides a wrapper around a standard gzip.Writer instance
 type GZIPWriter struct {
 	*gzip.Writer
@@ -203,13 +333,25 @@ type WriterFn func(w io.Writer) io.Writer
 // EncoderFn denotes an io.Writer based encoder function / method
 type EncoderFn func(w io.Writer) Encoder
 
+// StageMetricsFn denotes a function receiving metrics for an individual stage of a chain upon Close,
+// comprising the name of the stage, the number of (unencoded) bytes written to / read from it and the
+// time spent within the stage itself (excluding time spent in any other stage)
+type StageMetricsFn func(stage string, bytes int64, duration time.Duration)
+
 // WriterChain provides convenient access to a chained io.Writer sequence (and potentially encoding)
 type WriterChain struct {
 	writers []Writer
 
-	postFn  func(rw *ReadWriter) error
-	dest    *ReadWriter
-	memPool *MemPoolNoLimit
+	postFn    func(rw *ReadWriter) error
+	metricsFn StageMetricsFn
+	meters    []*stageMeter
+	target    io.Writer
+	spill     int
+	spillDir  string
+	maxSize   int
+	dest      *ReadWriter
+	frame     *ReadWriter
+	memPool   MemPool
 
 	io.Writer
 }
@@ -228,58 +370,174 @@ func (wc *WriterChain) AddWriter(w Writer) *WriterChain {
 	return wc
 }
 
-// MemPool sets an (external) memory pool for the chain of Writers
-func (wc *WriterChain) MemPool(memPool *MemPoolNoLimit) *WriterChain {
+// MemPool sets an (external) memory pool for the chain of Writers (e.g. a MemPoolTiered for mixed
+// workloads)
+func (wc *WriterChain) MemPool(memPool MemPool) *WriterChain {
 	wc.memPool = memPool
 	return wc
 }
 
+// Destination sets an external io.Writer (e.g. a file or network connection) as target of the
+// chain of Writers instead of the internal (pooled) buffer. The PostFn (if any) is still executed
+// once the chain has been closed, but is passed a nil *ReadWriter (the destination is not closed)
+func (wc *WriterChain) Destination(w io.Writer) *WriterChain {
+	wc.target = w
+	return wc
+}
+
+// SpillToDisk causes the internal (pooled) buffer of the chain of Writers to spill to a temporary file
+// in dir (or the default directory for temporary files if empty) once its size exceeds the provided
+// threshold (in bytes), limiting memory consumption for (occasional) large outputs. The result remains
+// accessible via the *ReadWriter (acting as io.Reader, but no longer providing direct access via Bytes(),
+// cf. ReadWriter.Spilled) and the temporary file is removed once the chain has been closed
+func (wc *WriterChain) SpillToDisk(threshold int, dir string) *WriterChain {
+	wc.spill, wc.spillDir = threshold, dir
+	return wc
+}
+
+// LimitSize limits the size of the internal (pooled) buffer of the chain of Writers (cf.
+// ReadWriter.LimitSize), causing any encoding exceeding it to fail with ErrTooLarge instead of
+// growing the buffer unboundedly (if an external destination is used, the limit does not apply)
+func (wc *WriterChain) LimitSize(n int) *WriterChain {
+	wc.maxSize = n
+	return wc
+}
+
 // PostFn sets a function to be executed at the end of the Writer / encoding chain
 func (wc *WriterChain) PostFn(fn func(rw *ReadWriter) error) *WriterChain {
 	wc.postFn = fn
 	return wc
 }
 
+// Metrics sets a function to be executed for each stage of the Writer chain (in the order of closing)
+// once the chain has been closed, e.g. in order to track the throughput / latency of individual stages
+// (taking effect once the chain is built, i.e. on the next Build / Reset if already built)
+func (wc *WriterChain) Metrics(fn StageMetricsFn) *WriterChain {
+	wc.metricsFn = fn
+	return wc
+}
+
 // Build constructs the chain of Writers and defines / defers potential cleanup function calls
 func (wc *WriterChain) Build() *WriterChain {
 
 	var w io.Writer
-	wc.dest = wc.memPool.GetReadWriter(0)
-	w = wc.dest
+	if wc.target != nil {
+		w = wc.target
+	} else {
+		wc.dest = wc.memPool.GetReadWriter(0)
+		wc.dest.SpillToDisk(wc.spill, wc.spillDir)
+		wc.dest.LimitSize(wc.maxSize)
+		w = wc.dest
+	}
+
+	// If metrics are requested, the destination is metered as well (in order to be able to
+	// deduct the time spent writing to it from the time spent in the last stage)
+	wc.meters = wc.meters[:0]
+	if wc.metricsFn != nil {
+		wc.meters = append(wc.meters, &stageMeter{})
+		w = &meteredWriter{Writer: w, stageMeter: wc.meters[0]}
+	}
 
 	for _, writer := range wc.writers {
-		w = writer.Init(w)
+		if wc.metricsFn == nil {
+			w = writer.Init(w)
+			continue
+		}
+
+		m := &stageMeter{name: stageName(writer)}
+		start := time.Now()
+		w = &meteredWriter{Writer: writer.Init(w), stageMeter: m}
+		m.total = time.Since(start)
+		wc.meters = append(wc.meters, m)
 	}
 
 	wc.Writer = w
 	return wc
 }
 
-// Close closes the Writer chain, flushing all underlying Writers
-func (wc *WriterChain) Close() (err error) {
-	defer wc.memPool.PutReadWriter(wc.dest)
+// Reset prepares a (closed) chain of Writers for reuse (e.g. across requests) without rebuilding it,
+// re-initializing all stages (obtaining pooled instances where applicable) and targeting the provided
+// io.Writer (or the internal pooled buffer if nil, cf. Destination)
+func (wc *WriterChain) Reset(dst io.Writer) *WriterChain {
+	wc.target = dst
+	return wc.Build()
+}
+
+// Close closes the Writer chain, flushing all underlying Writers. All Writers are closed (and
+// returned) even if individual ones fail, in which case the PostFn is skipped and all errors are
+// returned jointly (in the order of closing, i.e. the first error being the primary one)
+func (wc *WriterChain) Close() error {
+	defer wc.releaseDest()
+	defer wc.releaseFrame()
 
+	var errs []error
 	for i := len(wc.writers) - 1; i >= 0; i-- {
-		if err = wc.writers[i].Close(); err != nil {
-			return
+		start := time.Now()
+		if err := wc.writers[i].Close(); err != nil {
+			errs = append(errs, err)
+		}
+		if len(wc.meters) > i+1 {
+			wc.meters[i+1].total += time.Since(start)
 		}
 	}
-	if wc.postFn != nil {
-		err = wc.postFn(wc.dest)
+	if wc.metricsFn != nil && len(wc.meters) > 0 {
+		reportStageMetrics(wc.metricsFn, wc.meters)
+	}
+	if len(errs) == 0 && wc.postFn != nil {
+		if err := wc.postFn(wc.dest); err != nil {
+			errs = append(errs, err)
+		}
 	}
 	for _, writer := range wc.writers {
 		writer.Return()
 	}
 
-	return err
+	return joinErrors(errs)
+}
+
+// Flush flushes all stages of the chain supporting it (cf. Flusher, e.g. compression stages) and the
+// destination (if it supports flushing) without closing the chain, allowing to push partial data to
+// the destination (e.g. for long-lived streaming connections). Note that flushing may reduce the
+// compression ratio of the respective stages
+func (wc *WriterChain) Flush() error {
+	for i := len(wc.writers) - 1; i >= 0; i-- {
+		if flusher, ok := wc.writers[i].(Flusher); ok {
+			if err := flusher.Flush(); err != nil {
+				return err
+			}
+		}
+	}
+
+	switch target := wc.target.(type) {
+	case Flusher:
+		return target.Flush()
+	case interface{ Flush() }:
+		target.Flush()
+	}
+
+	return nil
 }
 
 // Encode encodes the output of the chain of Writers into an object using the provided encoder function
+// (the returned *ReadWriter is nil if an external destination is used)
 func (wc *WriterChain) Encode(fn EncoderFn, v any) (*ReadWriter, error) {
 	if fn == nil {
 		return nil, errors.New("nil encoder function")
 	}
-	err := fn(wc.Writer).Encode(v)
+	err := encode(fn, wc.Writer, v)
+	return wc.dest, err
+}
+
+// EncodeContext encodes an object using the provided encoder function (cf. Encode), aborting with the
+// context error once the provided context is cancelled (checked on every write to the chain)
+func (wc *WriterChain) EncodeContext(ctx context.Context, fn EncoderFn, v any) (*ReadWriter, error) {
+	if fn == nil {
+		return nil, errors.New("nil encoder function")
+	}
+	if err := ctx.Err(); err != nil {
+		return wc.dest, err
+	}
+	err := encode(fn, &ctxWriter{ctx: ctx, Writer: wc.Writer}, v)
 	return wc.dest, err
 }
 
@@ -291,15 +549,74 @@ func (wc *WriterChain) EncodeAndClose(fn EncoderFn, v any) error {
 	return wc.Close()
 }
 
+// EncodePipe builds the chain of Writers targeting an io.Pipe and asynchronously encodes an object
+// using the provided encoder function in a background goroutine, returning the reading end of the
+// pipe. This allows to stream the encoded output (e.g. into an HTTP request body) without buffering
+// it as a whole. Any error during encoding or closing of the chain is returned by the reader, closing
+// the reader early aborts the encoding. The chain must not be built before calling EncodePipe
+func (wc *WriterChain) EncodePipe(fn EncoderFn, v any) io.ReadCloser {
+	pr, pw := io.Pipe()
+	wc.Destination(pw).Build()
+
+	go func() {
+		var err error
+		if fn == nil {
+			err = errors.New("nil encoder function")
+		} else {
+			err = encode(fn, wc.Writer, v)
+		}
+		if cErr := wc.Close(); err == nil {
+			err = cErr
+		}
+		pw.CloseWithError(err)
+	}()
+
+	return pr
+}
+
+// EncodeStream encodes an object using the provided encoder function and writes it to the chain of
+// Writers as length-prefixed frame, allowing to write multiple objects into a single (e.g. compressed)
+// stream, which can be read back one by one via ReaderChain.DecodeNext
+func (wc *WriterChain) EncodeStream(fn EncoderFn, v any) error {
+	if fn == nil {
+		return errors.New("nil encoder function")
+	}
+	if wc.frame == nil {
+		wc.frame = wc.memPool.GetReadWriter(0)
+	}
+	wc.frame.Reset()
+
+	if err := encode(fn, wc.frame, v); err != nil {
+		return err
+	}
+	if wc.frame.len() > MaxStreamFrameSize {
+		return fmt.Errorf("%w: frame size %d exceeds maximum", ErrInvalidFrame, wc.frame.len())
+	}
+
+	var lenBuf [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(lenBuf[:], uint64(wc.frame.len()))
+	if _, err := wc.Writer.Write(lenBuf[:n]); err != nil {
+		return err
+	}
+	_, err := wc.Writer.Write(wc.frame.Bytes())
+
+	return err
+}
+
 // ReaderChain provides convenient access to a chained io.Reader sequence (and potentially decoding)
 type ReaderChain struct {
 	readers  []Reader
 	closers  []io.Closer
 	buildErr error
 
-	postFn  func(rw *ReadWriter) error
-	dest    *ReadWriter
-	memPool *MemPoolNoLimit
+	postFn    func(rw *ReadWriter) error
+	metricsFn StageMetricsFn
+	meters    []*stageMeter
+	dest      *ReadWriter
+	frame     *ReadWriter
+	memPool   MemPool
+
+	streamReader *bufio.Reader
 
 	io.Reader
 }
@@ -320,7 +637,7 @@ func (rc *ReaderChain) AddReader(r Reader) *ReaderChain {
 }
 
 // MemPool sets an (external) memory pool for the chain of Readers
-func (rc *ReaderChain) MemPool(memPool *MemPoolNoLimit) *ReaderChain {
+func (rc *ReaderChain) MemPool(memPool MemPool) *ReaderChain {
 	rc.memPool = memPool
 	return rc
 }
@@ -331,6 +648,14 @@ func (rc *ReaderChain) PostFn(fn func(rw *ReadWriter) error) *ReaderChain {
 	return rc
 }
 
+// Metrics sets a function to be executed for each stage of the Reader chain (in reverse order of the
+// stages) once the chain has been closed, e.g. in order to track the throughput / latency of individual
+// stages (taking effect once the chain is built, i.e. on the next Build / Reset if already built)
+func (rc *ReaderChain) Metrics(fn StageMetricsFn) *ReaderChain {
+	rc.metricsFn = fn
+	return rc
+}
+
 // Build constructs the chain of Readers and defines / defers potential cleanup function calls
 func (rc *ReaderChain) Build() *ReaderChain {
 	r := rc.Reader
@@ -338,7 +663,16 @@ func (rc *ReaderChain) Build() *ReaderChain {
 		rc.closers = append(rc.closers, rCloser)
 	}
 
+	// If metrics are requested, the source is metered as well (in order to be able to deduct
+	// the time spent reading from it from the time spent in the first stage)
+	rc.meters = rc.meters[:0]
+	if rc.metricsFn != nil {
+		rc.meters = append(rc.meters, &stageMeter{})
+		r = &meteredReader{Reader: r, stageMeter: rc.meters[0]}
+	}
+
 	for _, reader := range rc.readers {
+		start := time.Now()
 		addR, err := reader.Init(r)
 		if err != nil {
 			rc.buildErr = err
@@ -347,6 +681,11 @@ func (rc *ReaderChain) Build() *ReaderChain {
 		if addRCloser, ok := addR.(io.Closer); ok {
 			rc.closers = append(rc.closers, addRCloser)
 		}
+		if rc.metricsFn != nil {
+			m := &stageMeter{name: stageName(reader), total: time.Since(start)}
+			addR = &meteredReader{Reader: addR, stageMeter: m}
+			rc.meters = append(rc.meters, m)
+		}
 		r = addR
 	}
 
@@ -354,20 +693,48 @@ func (rc *ReaderChain) Build() *ReaderChain {
 	return rc
 }
 
-// Close closes the Reader chain, flushing all underlying Readers
-func (rc *ReaderChain) Close() (err error) {
+// Reset prepares a (closed) chain of Readers for reuse (e.g. across requests) without rebuilding it,
+// re-initializing all stages (obtaining pooled instances where applicable) to read from the provided
+// io.Reader
+func (rc *ReaderChain) Reset(src io.Reader) *ReaderChain {
+	rc.Reader = src
+	rc.closers = rc.closers[:0]
+	rc.buildErr = nil
+	rc.Build()
+
+	// Reuse the buffered reader for object streams (if any)
+	if rc.streamReader != nil {
+		rc.streamReader.Reset(rc.Reader)
+	}
+
+	return rc
+}
+
+// Close closes the Reader chain, flushing all underlying Readers. All Readers are closed (and
+// returned) even if individual ones fail, in which case the PostFn is skipped and all errors are
+// returned jointly (in the order of closing, i.e. the first error being the primary one)
+func (rc *ReaderChain) Close() error {
+	defer rc.releaseFrame()
+
+	var errs []error
 	for i := len(rc.closers) - 1; i >= 0; i-- {
-		if err = rc.closers[i].Close(); err != nil {
-			return
+		if err := rc.closers[i].Close(); err != nil {
+			errs = append(errs, err)
 		}
 	}
-	if rc.postFn != nil {
-		err = rc.postFn(rc.dest)
+	if rc.metricsFn != nil && len(rc.meters) > 0 {
+		reportStageMetrics(rc.metricsFn, rc.meters)
+	}
+	if len(errs) == 0 && rc.postFn != nil {
+		if err := rc.postFn(rc.dest); err != nil {
+			errs = append(errs, err)
+		}
 	}
 	for _, reader := range rc.readers {
 		reader.Return()
 	}
-	return err
+
+	return joinErrors(errs)
 }
 
 // Decode decodes from an object using the provided decoder function
@@ -378,7 +745,22 @@ func (rc *ReaderChain) Decode(fn DecoderFn, v any) error {
 	if fn == nil {
 		return errors.New("nil decoder function")
 	}
-	return fn(rc.Reader).Decode(v)
+	return decode(fn, rc.Reader, v)
+}
+
+// DecodeContext decodes an object using the provided decoder function (cf. Decode), aborting with the
+// context error once the provided context is cancelled (checked on every read from the chain)
+func (rc *ReaderChain) DecodeContext(ctx context.Context, fn DecoderFn, v any) error {
+	if rc.buildErr != nil {
+		return rc.buildErr
+	}
+	if fn == nil {
+		return errors.New("nil decoder function")
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return decode(fn, &ctxReader{ctx: ctx, Reader: rc.Reader}, v)
 }
 
 // DecodeAndClose performs the decoding and closes / flushes all Readers in the chain simultaneously
@@ -388,3 +770,232 @@ func (rc *ReaderChain) DecodeAndClose(fn DecoderFn, v any) error {
 	}
 	return rc.Close()
 }
+
+// DecodeNext reads the next length-prefixed frame written via WriterChain.EncodeStream from the chain
+// of Readers and decodes it into an object using the provided decoder function, returning io.EOF once
+// the stream has been fully consumed. Zero-copy decoders (e.g. BytesDecoderZeroCopy) yield data that
+// is only valid until the next call
+func (rc *ReaderChain) DecodeNext(fn DecoderFn, v any) error {
+	if rc.buildErr != nil {
+		return rc.buildErr
+	}
+	if fn == nil {
+		return errors.New("nil decoder function")
+	}
+	if rc.streamReader == nil {
+		rc.streamReader = bufio.NewReader(rc.Reader)
+	}
+	if rc.frame == nil {
+		rc.frame = rc.memPool.GetReadWriter(0)
+	}
+
+	size, err := binary.ReadUvarint(rc.streamReader)
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return io.EOF
+		}
+		return fmt.Errorf("%w: %w", ErrInvalidFrame, err)
+	}
+	if size > MaxStreamFrameSize {
+		return fmt.Errorf("%w: frame size %d exceeds maximum", ErrInvalidFrame, size)
+	}
+
+	rc.frame.Reset()
+	if _, err = io.CopyN(rc.frame, rc.streamReader, int64(size)); err != nil {
+		if errors.Is(err, io.EOF) {
+			return fmt.Errorf("%w: %w", ErrInvalidFrame, io.ErrUnexpectedEOF)
+		}
+		return err
+	}
+
+	return decode(fn, rc.frame, v)
+}
+
+////////////////////////////////////////////////////////////////////////////////////////
+
+// joinErrors joins multiple errors, returning a single error as is (retaining its identity for
+// direct comparison / errors.Unwrap)
+func joinErrors(errs []error) error {
+	switch len(errs) {
+	case 0:
+		return nil
+	case 1:
+		return errs[0]
+	}
+	return &chainError{errs: errs}
+}
+
+// chainError denotes multiple errors encountered while closing a chain (formatted like errors.Join),
+// retaining the first one as primary unwrap target while still matching all others via errors.Is / As
+type chainError struct {
+	errs []error
+}
+
+// Error returns the errors separated by newlines (cf. errors.Join)
+func (e *chainError) Error() string {
+	msgs := make([]string, len(e.errs))
+	for i, err := range e.errs {
+		msgs[i] = err.Error()
+	}
+	return strings.Join(msgs, "\n")
+}
+
+// Unwrap returns the first (primary) error
+func (e *chainError) Unwrap() error {
+	return e.errs[0]
+}
+
+// Is reports if any of the secondary errors matches the target (the primary one being covered by Unwrap)
+func (e *chainError) Is(target error) bool {
+	for _, err := range e.errs[1:] {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// As finds the first secondary error matching the target (the primary one being covered by Unwrap)
+func (e *chainError) As(target any) bool {
+	for _, err := range e.errs[1:] {
+		if errors.As(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// pooledCodec denotes an encoder / decoder instance that can be returned to a pool once it has been
+// used by a chain (instances obtained directly, e.g. via JSONEncoder, are never returned)
+type pooledCodec interface {
+	release()
+}
+
+func encode(fn EncoderFn, w io.Writer, v any) error {
+	enc := fn(w)
+	err := enc.Encode(v)
+	if pooled, ok := enc.(pooledCodec); ok {
+		pooled.release()
+	}
+	return err
+}
+
+func decode(fn DecoderFn, r io.Reader, v any) error {
+	dec := fn(r)
+	err := dec.Decode(v)
+	if pooled, ok := dec.(pooledCodec); ok {
+		pooled.release()
+	}
+	return err
+}
+
+func (wc *WriterChain) releaseDest() {
+	if wc.dest != nil {
+		wc.memPool.PutReadWriter(wc.dest)
+		wc.dest = nil
+	}
+}
+
+func (wc *WriterChain) releaseFrame() {
+	if wc.frame != nil {
+		wc.memPool.PutReadWriter(wc.frame)
+		wc.frame = nil
+	}
+}
+
+func (rc *ReaderChain) releaseFrame() {
+	if rc.frame != nil {
+		rc.memPool.PutReadWriter(rc.frame)
+		rc.frame = nil
+	}
+}
+
+// ctxWriter wraps an io.Writer, failing all writes once its context has been cancelled
+type ctxWriter struct {
+	ctx context.Context
+	io.Writer
+}
+
+func (cw *ctxWriter) Write(p []byte) (int, error) {
+	if err := cw.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return cw.Writer.Write(p)
+}
+
+// ctxReader wraps an io.Reader, failing all reads once its context has been cancelled
+type ctxReader struct {
+	ctx context.Context
+	io.Reader
+}
+
+func (cr *ctxReader) Read(p []byte) (int, error) {
+	if err := cr.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return cr.Reader.Read(p)
+}
+
+// stageMeter keeps track of the bytes passing through an individual stage of a chain, the time spent
+// performing I/O on the stage and the total time spent in the stage (including initialization and
+// closing, and hence any I/O on neighbouring stages triggered by it)
+type stageMeter struct {
+	name  string
+	bytes int64
+	io    time.Duration
+	total time.Duration
+}
+
+func (m *stageMeter) track(n int, start time.Time) {
+	elapsed := time.Since(start)
+	m.bytes += int64(n)
+	m.io += elapsed
+	m.total += elapsed
+}
+
+// meteredWriter wraps an io.Writer, tracking all writes to a stage
+type meteredWriter struct {
+	io.Writer
+	*stageMeter
+}
+
+func (mw *meteredWriter) Write(p []byte) (n int, err error) {
+	start := time.Now()
+	n, err = mw.Writer.Write(p)
+	mw.track(n, start)
+	return
+}
+
+// meteredReader wraps an io.Reader, tracking all reads from a stage
+type meteredReader struct {
+	io.Reader
+	*stageMeter
+}
+
+func (mr *meteredReader) Read(p []byte) (n int, err error) {
+	start := time.Now()
+	n, err = mr.Reader.Read(p)
+	mr.track(n, start)
+	return
+}
+
+// reportStageMetrics reports the metrics of all stages (in reverse order, skipping the unnamed
+// destination / source meter at index zero), deducting the I/O time of the respective neighbouring
+// stage, which is fully contained in the total time of each stage
+func reportStageMetrics(fn StageMetricsFn, meters []*stageMeter) {
+	for i := len(meters) - 1; i > 0; i-- {
+		duration := meters[i].total - meters[i-1].io
+		if duration < 0 {
+			duration = 0
+		}
+		fn(meters[i].name, meters[i].bytes, duration)
+	}
+}
+
+func stageName(stage any) string {
+	t := reflect.TypeOf(stage)
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return t.Name()
+}
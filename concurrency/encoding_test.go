@@ -3,9 +3,21 @@ package concurrency
 import (
 	"bytes"
 	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
 	"testing"
+	"testing/iotest"
+	"time"
 
 	jsoniter "github.com/json-iterator/go"
+	"github.com/klauspost/compress/snappy"
 	yaml "gopkg.in/yaml.v3"
 
 	"github.com/stretchr/testify/require"
@@ -131,6 +143,981 @@ func TestEncoderChainBytes(t *testing.T) {
 	}
 }
 
+func TestEncoderChainZSTD(t *testing.T) {
+	input := testStruct{Name: "foo", Value: 42}
+
+	// Repeat test a couple of times to trigger pool re-use scenario
+	for i := 0; i < 100; i++ {
+		wc := NewWriterChain().AddWriter(NewZSTDWriter()).PostFn(func(rw *ReadWriter) error {
+			var res testStruct
+			require.Equal(t, zstdMagic, rw.Bytes()[:4])
+
+			dc := NewReaderChain(rw).AddReader(NewZSTDReader()).Build()
+			require.Nil(t, dc.DecodeAndClose(JSONDecoder, &res))
+
+			require.EqualValues(t, input, res)
+			return nil
+		}).Build()
+		require.Nil(t, wc.EncodeAndClose(JSONEncoder, input))
+	}
+
+	// Chained with gzip (in arbitrary order)
+	payload := bytes.Repeat([]byte("This is a test"), 1000)
+	wc := NewWriterChain().AddWriter(NewGZIPWriter()).AddWriter(NewZSTDWriter()).PostFn(func(rw *ReadWriter) error {
+		var res []byte
+		require.Less(t, len(rw.Bytes()), len(payload))
+
+		dc := NewReaderChain(rw).AddReader(NewGZIPReader()).AddReader(NewZSTDReader()).Build()
+		require.Nil(t, dc.DecodeAndClose(BytesDecoder, &res))
+
+		require.Equal(t, payload, res)
+		return nil
+	}).Build()
+	require.Nil(t, wc.EncodeAndClose(BytesEncoder, payload))
+
+	// Invalid input must be reported
+	var res []byte
+	dc := NewReaderChain(bytes.NewReader([]byte("invalid"))).AddReader(NewZSTDReader()).Build()
+	require.Error(t, dc.DecodeAndClose(BytesDecoder, &res))
+}
+
+func TestEncoderChainSnappy(t *testing.T) {
+	input := testStruct{Name: "foo", Value: 42}
+
+	// Repeat test a couple of times to trigger pool re-use scenario
+	for i := 0; i < 100; i++ {
+		wc := NewWriterChain().AddWriter(NewSnappyWriter()).PostFn(func(rw *ReadWriter) error {
+			var res testStruct
+			require.Equal(t, snappyMagic, rw.Bytes()[:len(snappyMagic)])
+
+			// The output must be decodable by standard Snappy tooling
+			data, err := io.ReadAll(snappy.NewReader(bytes.NewReader(rw.Bytes())))
+			require.Nil(t, err)
+			require.Nil(t, jsoniter.Unmarshal(data, &res))
+			require.EqualValues(t, input, res)
+
+			dc := NewReaderChain(rw).AddReader(NewSnappyReader()).Build()
+			require.Nil(t, dc.DecodeAndClose(JSONDecoder, &res))
+
+			require.EqualValues(t, input, res)
+			return nil
+		}).Build()
+		require.Nil(t, wc.EncodeAndClose(JSONEncoder, input))
+	}
+
+	// Input produced by standard Snappy tooling must be decodable
+	payload := bytes.Repeat([]byte("This is a test"), 1000)
+	buf := bytes.NewBuffer(nil)
+	sw := snappy.NewBufferedWriter(buf)
+	_, err := sw.Write(payload)
+	require.Nil(t, err)
+	require.Nil(t, sw.Close())
+
+	var res []byte
+	dc := NewReaderChain(buf).AddReader(NewSnappyReader()).Build()
+	require.Nil(t, dc.DecodeAndClose(BytesDecoder, &res))
+	require.Equal(t, payload, res)
+}
+
+func TestReaderChainXZBZIP2(t *testing.T) {
+	payload := bytes.Repeat([]byte("This is a test"), 100)
+
+	for _, cs := range []struct {
+		name   string
+		input  string
+		reader func() Reader
+	}{
+		{
+			name:   "xz",
+			input:  xzFixture,
+			reader: func() Reader { return NewXZReader() },
+		},
+		{
+			name:   "bzip2",
+			input:  bzip2Fixture,
+			reader: func() Reader { return NewBZIP2Reader() },
+		},
+	} {
+		t.Run(cs.name, func(t *testing.T) {
+			input, err := base64.StdEncoding.DecodeString(cs.input)
+			require.Nil(t, err)
+
+			// Repeat test a couple of times to ensure stages can be reused
+			for i := 0; i < 10; i++ {
+				var res []byte
+				dc := NewReaderChain(bytes.NewReader(input)).AddReader(cs.reader()).Build()
+				require.Nil(t, dc.DecodeAndClose(BytesDecoder, &res))
+				require.Equal(t, payload, res)
+			}
+
+			// Invalid input must be reported
+			var res []byte
+			dc := NewReaderChain(bytes.NewReader(payload)).AddReader(cs.reader()).Build()
+			require.Error(t, dc.DecodeAndClose(BytesDecoder, &res))
+		})
+	}
+}
+
+func TestHashStage(t *testing.T) {
+	input := bytes.Repeat([]byte("This is a test"), 1000)
+
+	// Repeat test a couple of times to ensure stages can be reused
+	hw, hr := NewHashWriter(sha256.New()), NewHashReader(sha256.New())
+	for i := 0; i < 10; i++ {
+		var (
+			compressed []byte
+			rawHR      = NewHashReader(sha256.New())
+		)
+		wc := NewWriterChain().AddWriter(hw).AddWriter(NewGZIPWriter()).PostFn(func(rw *ReadWriter) error {
+			compressed = rw.BytesCopy()
+
+			// The digest must cover the compressed output
+			ref := sha256.Sum256(compressed)
+			require.Equal(t, ref[:], hw.Sum())
+
+			var res []byte
+			dc := NewReaderChain(rw).AddReader(hr).AddReader(NewGZIPReader()).AddReader(rawHR).Build()
+			require.Nil(t, dc.DecodeAndClose(BytesDecoder, &res))
+			require.Equal(t, input, res)
+
+			return nil
+		}).Build()
+		require.Nil(t, wc.EncodeAndClose(BytesEncoder, input))
+
+		ref := sha256.Sum256(compressed)
+		require.Equal(t, ref[:], hr.Sum())
+		ref = sha256.Sum256(input)
+		require.Equal(t, ref[:], rawHR.Sum())
+	}
+}
+
+func TestChecksumStage(t *testing.T) {
+	input := testStruct{Name: "checksum", Value: 42}
+
+	var encoded []byte
+	wc := NewWriterChain().
+		AddWriter(NewChecksumWriter()).
+		AddWriter(NewGZIPWriter()).
+		PostFn(func(rw *ReadWriter) error {
+			encoded = bytes.Clone(rw.Bytes())
+			return nil
+		}).Build()
+	require.Nil(t, wc.EncodeAndClose(JSONEncoder, input))
+
+	for _, src := range []func([]byte) io.Reader{
+		func(b []byte) io.Reader { return bytes.NewReader(b) },
+		func(b []byte) io.Reader { return iotest.OneByteReader(bytes.NewReader(b)) },
+		func(b []byte) io.Reader { return iotest.HalfReader(bytes.NewReader(b)) },
+	} {
+		var output testStruct
+		require.Nil(t, NewReaderChain(src(encoded)).
+			AddReader(NewChecksumReader()).
+			AddReader(NewGZIPReader()).
+			Build().
+			DecodeAndClose(JSONDecoder, &output))
+		require.Equal(t, input, output)
+	}
+
+	// Corruption of the payload or the trailer must yield a checksum error
+	for _, pos := range []int{len(encoded) / 2, len(encoded) - 1} {
+		corrupted := bytes.Clone(encoded)
+		corrupted[pos] ^= 0xFF
+
+		var output []byte
+		err := NewReaderChain(bytes.NewReader(corrupted)).
+			AddReader(NewChecksumReader()).
+			Build().
+			DecodeAndClose(BytesDecoder, &output)
+		require.ErrorIs(t, err, ErrChecksumMismatch)
+
+		var checksumErr *ChecksumError
+		require.ErrorAs(t, err, &checksumErr)
+		require.NotEqual(t, checksumErr.Expected, checksumErr.Actual)
+	}
+
+	// Verification on Close if the stream was not fully consumed
+	corrupted := bytes.Clone(encoded)
+	corrupted[len(corrupted)-1] ^= 0xFF
+	rc := NewReaderChain(bytes.NewReader(corrupted)).AddReader(NewChecksumReader()).Build()
+	_, err := rc.Read(make([]byte, 1))
+	require.Nil(t, err)
+	require.ErrorIs(t, rc.Close(), ErrChecksumMismatch)
+
+	// Truncated stream (missing trailer)
+	var output []byte
+	err = NewReaderChain(bytes.NewReader(encoded[:2])).
+		AddReader(NewChecksumReader()).
+		Build().
+		DecodeAndClose(BytesDecoder, &output)
+	require.ErrorIs(t, err, io.ErrUnexpectedEOF)
+}
+
+func TestCountingStage(t *testing.T) {
+	input := bytes.Repeat([]byte("This is a test"), 1000)
+
+	// Repeat test a couple of times to ensure stages can be reused
+	encoded, raw := NewCountingWriter(), NewCountingWriter()
+	for i := 0; i < 10; i++ {
+		wc := NewWriterChain().AddWriter(encoded).AddWriter(NewGZIPWriter()).AddWriter(raw).PostFn(func(rw *ReadWriter) error {
+			require.Equal(t, int64(len(input)), raw.Count())
+			require.Equal(t, int64(len(rw.Bytes())), encoded.Count())
+			require.Less(t, Ratio(encoded.Count(), raw.Count()), 0.1)
+
+			var (
+				res         []byte
+				encodedR    = NewCountingReader()
+				rawR        = NewCountingReader()
+				nCompressed = int64(len(rw.Bytes()))
+			)
+			dc := NewReaderChain(rw).AddReader(encodedR).AddReader(NewGZIPReader()).AddReader(rawR).Build()
+			require.Nil(t, dc.DecodeAndClose(BytesDecoder, &res))
+			require.Equal(t, input, res)
+			require.Equal(t, nCompressed, encodedR.Count())
+			require.Equal(t, int64(len(input)), rawR.Count())
+
+			return nil
+		}).Build()
+		require.Nil(t, wc.EncodeAndClose(BytesEncoder, input))
+	}
+
+	require.Zero(t, Ratio(1, 0))
+}
+
+func TestHexStage(t *testing.T) {
+	input := bytes.Repeat([]byte("This is a test"), 1000)
+
+	// Repeat test a couple of times to ensure stages can be reused
+	hw, hr := NewHexWriter(), NewHexReader()
+	for i := 0; i < 10; i++ {
+		wc := NewWriterChain().AddWriter(hw).AddWriter(NewGZIPWriter()).PostFn(func(rw *ReadWriter) error {
+			_, err := hex.DecodeString(string(rw.Bytes()))
+			require.Nil(t, err)
+
+			var res []byte
+			dc := NewReaderChain(rw).AddReader(hr).AddReader(NewGZIPReader()).Build()
+			require.Nil(t, dc.DecodeAndClose(BytesDecoder, &res))
+			require.Equal(t, input, res)
+
+			return nil
+		}).Build()
+		require.Nil(t, wc.EncodeAndClose(BytesEncoder, input))
+	}
+
+	// Encoder / decoder functions
+	rw, err := NewWriterChain().Build().Encode(HexEncoder, input)
+	require.Nil(t, err)
+	require.Equal(t, hex.EncodeToString(input), string(rw.Bytes()))
+
+	var res []byte
+	require.Nil(t, NewReaderChain(rw).Build().DecodeAndClose(HexDecoder, &res))
+	require.Equal(t, input, res)
+
+	// Invalid input must be reported
+	require.Error(t, NewReaderChain(bytes.NewReader([]byte("invalid"))).AddReader(NewHexReader()).Build().DecodeAndClose(BytesDecoder, &res))
+	require.ErrorIs(t, NewReaderChain(bytes.NewReader([]byte("abc"))).Build().DecodeAndClose(HexDecoder, &res), io.ErrUnexpectedEOF)
+}
+
+func TestWriterChainDestination(t *testing.T) {
+	input := testStruct{Name: "foo", Value: 42}
+	ref, err := encodeManualJSON(input)
+	require.Nil(t, err)
+
+	// Repeat test a couple of times to trigger pool re-use scenario
+	for i := 0; i < 10; i++ {
+		var (
+			buf          = bytes.NewBuffer(nil)
+			postFnCalled bool
+		)
+		wc := NewWriterChain().AddWriter(NewGZIPWriter()).Destination(buf).PostFn(func(rw *ReadWriter) error {
+			require.Nil(t, rw)
+			require.Equal(t, ref, buf.Bytes())
+			postFnCalled = true
+			return nil
+		}).Build()
+
+		rw, err := wc.Encode(JSONEncoder, input)
+		require.Nil(t, err)
+		require.Nil(t, rw)
+		require.Nil(t, wc.Close())
+		require.True(t, postFnCalled)
+
+		var res testStruct
+		dc := NewReaderChain(buf).AddReader(NewGZIPReader()).Build()
+		require.Nil(t, dc.DecodeAndClose(JSONDecoder, &res))
+		require.Equal(t, input, res)
+	}
+
+	// Writing to a file
+	f, err := os.CreateTemp(t.TempDir(), "chain")
+	require.Nil(t, err)
+	require.Nil(t, NewWriterChain().AddWriter(NewGZIPWriter()).Destination(f).Build().EncodeAndClose(JSONEncoder, input))
+	require.Nil(t, f.Close())
+	data, err := os.ReadFile(f.Name())
+	require.Nil(t, err)
+	require.Equal(t, ref, data)
+}
+
+func TestChainContext(t *testing.T) {
+	input := bytes.Repeat([]byte("This is a test"), 100000)
+
+	var compressed []byte
+	wc := NewWriterChain().AddWriter(NewGZIPWriter()).PostFn(func(rw *ReadWriter) error {
+		compressed = rw.BytesCopy()
+		return nil
+	}).Build()
+	_, err := wc.EncodeContext(context.Background(), BytesEncoder, input)
+	require.Nil(t, err)
+	require.Nil(t, wc.Close())
+
+	var res []byte
+	rc := NewReaderChain(bytes.NewReader(compressed)).AddReader(NewGZIPReader()).Build()
+	require.Nil(t, rc.DecodeContext(context.Background(), BytesDecoder, &res))
+	require.Equal(t, input, res)
+	require.Nil(t, rc.Close())
+
+	// Cancel the context while decoding (after the first read)
+	ctx, cancel := context.WithCancel(context.Background())
+	rc = NewReaderChain(bytes.NewReader(compressed)).AddReader(NewGZIPReader()).Build()
+	require.ErrorIs(t, rc.DecodeContext(ctx, func(r io.Reader) Decoder {
+		return BytesDecoder(&cancelAfterRead{Reader: r, cancel: cancel})
+	}, &res), context.Canceled)
+
+	// Already cancelled context
+	wc = NewWriterChain().AddWriter(NewGZIPWriter()).Build()
+	_, err = wc.EncodeContext(ctx, BytesEncoder, input)
+	require.ErrorIs(t, err, context.Canceled)
+	require.Nil(t, wc.Close())
+
+	rc = NewReaderChain(bytes.NewReader(compressed)).AddReader(NewGZIPReader()).Build()
+	require.ErrorIs(t, rc.DecodeContext(ctx, BytesDecoder, &res), context.Canceled)
+
+	// Cancel the context while encoding (after the first write)
+	ctx, cancel = context.WithCancel(context.Background())
+	wc = NewWriterChain().AddWriter(NewGZIPWriter()).Build()
+	_, err = wc.EncodeContext(ctx, func(w io.Writer) Encoder {
+		return &chunkedEncoder{Writer: w, cancel: cancel}
+	}, input)
+	require.ErrorIs(t, err, context.Canceled)
+	require.Nil(t, wc.Close())
+}
+
+// cancelAfterRead cancels a context after the first successful read
+type cancelAfterRead struct {
+	io.Reader
+	cancel context.CancelFunc
+}
+
+func (c *cancelAfterRead) Read(p []byte) (int, error) {
+	n, err := c.Reader.Read(p)
+	c.cancel()
+	return n, err
+}
+
+// chunkedEncoder writes byte slices in small chunks, cancelling a context after the first one
+type chunkedEncoder struct {
+	io.Writer
+	cancel context.CancelFunc
+}
+
+func (c *chunkedEncoder) Encode(v any) error {
+	data := v.([]byte)
+	for len(data) > 0 {
+		n := 1024
+		if n > len(data) {
+			n = len(data)
+		}
+		if _, err := c.Write(data[:n]); err != nil {
+			return err
+		}
+		c.cancel()
+		data = data[n:]
+	}
+	return nil
+}
+
+func TestChainReset(t *testing.T) {
+	wc := NewWriterChain().AddWriter(NewGZIPWriter()).Build()
+	rc := NewReaderChain(nil).AddReader(NewGZIPReader())
+
+	for i := 0; i < 100; i++ {
+		input := testStruct{Name: "foo", Value: i}
+
+		buf := bytes.NewBuffer(nil)
+		require.Nil(t, wc.Reset(buf).EncodeAndClose(JSONEncoder, input))
+
+		var res testStruct
+		require.Nil(t, rc.Reset(buf).DecodeAndClose(JSONDecoder, &res))
+		require.Equal(t, input, res)
+	}
+
+	// Reset to the internal buffer (including PostFn)
+	var compressed []byte
+	require.Nil(t, wc.PostFn(func(rw *ReadWriter) error {
+		compressed = rw.BytesCopy()
+		return nil
+	}).Reset(nil).EncodeAndClose(JSONEncoder, testStruct{Name: "bar"}))
+
+	var res testStruct
+	require.Nil(t, rc.Reset(bytes.NewReader(compressed)).DecodeAndClose(JSONDecoder, &res))
+	require.Equal(t, testStruct{Name: "bar"}, res)
+
+	// Invalid input must not affect subsequent reuse
+	require.Error(t, rc.Reset(bytes.NewReader([]byte("invalid"))).DecodeAndClose(JSONDecoder, &res))
+	require.Nil(t, rc.Reset(bytes.NewReader(compressed)).DecodeAndClose(JSONDecoder, &res))
+}
+
+func BenchmarkChainReset(b *testing.B) {
+	input := testStruct{Name: "foo", Value: 42}
+	buf := bytes.NewBuffer(nil)
+
+	b.Run("rebuild", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			buf.Reset()
+			_ = NewWriterChain().AddWriter(NewGZIPWriter()).Destination(buf).Build().EncodeAndClose(JSONEncoder, input)
+		}
+	})
+
+	b.Run("reset", func(b *testing.B) {
+		wc := NewWriterChain().AddWriter(NewGZIPWriter())
+		b.ReportAllocs()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			buf.Reset()
+			_ = wc.Reset(buf).EncodeAndClose(JSONEncoder, input)
+		}
+	})
+}
+
+func TestBytesDecoderSized(t *testing.T) {
+	input := bytes.Repeat([]byte("0123456789"), 1000)
+
+	for _, cs := range []struct {
+		name string
+		opts []BytesDecoderOption
+	}{
+		{"exact", []BytesDecoderOption{WithExpectedSize(len(input))}},
+		{"small", []BytesDecoderOption{WithExpectedSize(10)}},
+		{"large", []BytesDecoderOption{WithExpectedSize(2 * len(input))}},
+		{"limit", []BytesDecoderOption{WithMaxBytes(len(input))}},
+		{"limit_exact", []BytesDecoderOption{WithMaxBytes(len(input)), WithExpectedSize(len(input))}},
+		{"zerocopy", []BytesDecoderOption{WithExpectedSize(len(input)), WithZeroCopy()}},
+	} {
+		t.Run(cs.name, func(t *testing.T) {
+			var output []byte
+			require.Nil(t, NewReaderChain(iotest.HalfReader(bytes.NewReader(input))).Build().
+				DecodeAndClose(NewBytesDecoder(cs.opts...), &output))
+			require.Equal(t, input, output)
+
+			// A previously used destination with sufficient capacity is reused (and truncated)
+			dst := make([]byte, 2*len(input))
+			output = dst
+			require.Nil(t, NewReaderChain(bytes.NewReader(input[:10])).Build().
+				DecodeAndClose(NewBytesDecoder(cs.opts...), &output))
+			require.Equal(t, input[:10], output)
+			if cs.name != "zerocopy" {
+				require.Equal(t, &dst[0], &output[0])
+			}
+		})
+	}
+
+	// A precise size hint avoids any reallocation of the destination
+	r := bytes.NewReader(input)
+	decodeAllocs := func(fn DecoderFn) float64 {
+		return testing.AllocsPerRun(10, func() {
+			r.Reset(input)
+			var output []byte
+			_ = fn(r).Decode(&output)
+		})
+	}
+	require.LessOrEqual(t, decodeAllocs(NewBytesDecoder(WithExpectedSize(len(input)))), 3.)
+	require.Less(t, decodeAllocs(NewBytesDecoder(WithExpectedSize(len(input)))), decodeAllocs(BytesDecoderZeroCopy))
+
+	var output []byte
+	require.ErrorIs(t, NewReaderChain(bytes.NewReader(input)).Build().
+		Decode(NewBytesDecoder(WithMaxBytes(len(input)-1)), &output), ErrMaxBytesExceeded)
+	require.ErrorIs(t, NewReaderChain(bytes.NewReader(input)).Build().
+		Decode(NewBytesDecoder(WithMaxBytes(10), WithExpectedSize(len(input))), &output), ErrMaxBytesExceeded)
+	require.ErrorIs(t, NewReaderChain(bytes.NewReader(input)).Build().
+		Decode(NewBytesDecoder(WithMaxBytes(10)), output), ErrExpectByteSlicePtr)
+}
+
+func TestPooledJSON(t *testing.T) {
+	input := testStruct{Name: "pooled", Value: 42}
+
+	// Repeated use of pooled instances via a chain
+	for i := 0; i < 10; i++ {
+		input.Value = i
+
+		var encoded []byte
+		require.Nil(t, NewWriterChain().PostFn(func(rw *ReadWriter) error {
+			encoded = bytes.Clone(rw.Bytes())
+			return nil
+		}).Build().EncodeAndClose(JSONEncoder, input))
+		ref, err := jsoniter.Marshal(input)
+		require.Nil(t, err)
+		require.Equal(t, append(ref, '\n'), encoded)
+
+		var output testStruct
+		require.Nil(t, NewReaderChain(bytes.NewReader(encoded)).Build().DecodeAndClose(JSONDecoder, &output))
+		require.Equal(t, input, output)
+	}
+
+	// Direct use of a decoder for multiple consecutive values
+	dec := JSONDecoder(bytes.NewBufferString(`{"Name":"a","Value":1} {"Name":"b","Value":2}` + "\n42"))
+	var output testStruct
+	require.Nil(t, dec.Decode(&output))
+	require.Equal(t, testStruct{Name: "a", Value: 1}, output)
+	require.Nil(t, dec.Decode(&output))
+	require.Equal(t, testStruct{Name: "b", Value: 2}, output)
+	var num int
+	require.Nil(t, dec.Decode(&num))
+	require.Equal(t, 42, num)
+	require.ErrorIs(t, dec.Decode(&num), io.EOF)
+
+	// Invalid / empty input
+	require.NotNil(t, NewReaderChain(bytes.NewBufferString("{invalid")).Build().Decode(JSONDecoder, &output))
+	require.ErrorIs(t, NewReaderChain(bytes.NewBufferString(" \n")).Build().Decode(JSONDecoder, &output), io.EOF)
+
+	// Write errors are propagated
+	errWrite := errors.New("write failed")
+	require.ErrorIs(t, NewWriterChain().Destination(failingWriter{err: errWrite}).Build().
+		EncodeAndClose(JSONEncoder, input), errWrite)
+}
+
+func BenchmarkJSONEncoder(b *testing.B) {
+	input := testStruct{Name: "foo", Value: 42}
+
+	b.Run("jsoniter", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			_ = jsoniter.NewEncoder(io.Discard).Encode(input)
+		}
+	})
+
+	b.Run("pooled", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			_ = encode(JSONEncoder, io.Discard, input)
+		}
+	})
+}
+
+func BenchmarkJSONDecoder(b *testing.B) {
+	data, err := jsoniter.Marshal(testStruct{Name: "foo", Value: 42})
+	require.Nil(b, err)
+	r := bytes.NewReader(data)
+
+	b.Run("jsoniter", func(b *testing.B) {
+		var output testStruct
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			r.Reset(data)
+			_ = jsoniter.NewDecoder(r).Decode(&output)
+		}
+	})
+
+	b.Run("pooled", func(b *testing.B) {
+		var output testStruct
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			r.Reset(data)
+			_ = decode(JSONDecoder, r, &output)
+		}
+	})
+}
+
+func TestTeeStage(t *testing.T) {
+	input := bytes.Repeat([]byte("This is a test"), 1000)
+
+	f, err := os.CreateTemp(t.TempDir(), "tee")
+	require.Nil(t, err)
+
+	var (
+		raw        = bytes.NewBuffer(nil)
+		compressed = bytes.NewBuffer(nil)
+		hw         = sha256.New()
+	)
+	wc := NewWriterChain().AddWriter(NewTeeWriter(compressed, f, hw)).AddWriter(NewGZIPWriter()).AddWriter(NewTeeWriter(raw)).PostFn(func(rw *ReadWriter) error {
+		require.Equal(t, rw.Bytes(), compressed.Bytes())
+		require.Equal(t, input, raw.Bytes())
+
+		ref := sha256.Sum256(rw.Bytes())
+		require.Equal(t, ref[:], hw.Sum(nil))
+
+		// The file must already have been closed by the stage
+		require.ErrorIs(t, f.Close(), os.ErrClosed)
+		data, err := os.ReadFile(f.Name())
+		require.Nil(t, err)
+		require.Equal(t, rw.Bytes(), data)
+
+		return nil
+	}).Build()
+	require.Nil(t, wc.EncodeAndClose(BytesEncoder, input))
+
+	// Errors of individual destinations must be reported
+	errWrite := errors.New("write failed")
+	wc = NewWriterChain().AddWriter(NewTeeWriter(failingWriter{err: errWrite})).Build()
+	_, err = wc.Encode(JSONEncoder, input)
+	require.ErrorIs(t, err, errWrite)
+	require.ErrorIs(t, wc.Close(), errWrite)
+
+	tw := NewTeeWriter(failingWriter{err: errWrite}, failingWriter{err: io.ErrClosedPipe})
+	require.ErrorIs(t, tw.Close(), errWrite)
+	require.ErrorIs(t, tw.Close(), io.ErrClosedPipe)
+}
+
+func TestWriterChainSpillToDisk(t *testing.T) {
+	input := bytes.Repeat([]byte("This is a test"), 1000)
+	dir := t.TempDir()
+
+	for _, threshold := range []int{0, 100, 10 * len(input)} {
+		var output []byte
+		require.Nil(t, NewWriterChain().SpillToDisk(threshold, dir).PostFn(func(rw *ReadWriter) error {
+			require.Equal(t, threshold > 0 && threshold < len(input), rw.Spilled())
+			if rw.Spilled() {
+				require.Nil(t, rw.Bytes())
+				entries, err := os.ReadDir(dir)
+				require.Nil(t, err)
+				require.Len(t, entries, 1)
+			}
+
+			var err error
+			output, err = io.ReadAll(rw)
+			return err
+		}).Build().EncodeAndClose(BytesEncoder, input))
+		require.Equal(t, input, output)
+
+		// Temporary files must be removed once the chain has been closed
+		entries, err := os.ReadDir(dir)
+		require.Nil(t, err)
+		require.Empty(t, entries)
+	}
+
+	// Interleaved reads and writes after spilling
+	rw := defaultMemPool.GetReadWriter(0)
+	rw.SpillToDisk(10, dir)
+	_, err := rw.Write([]byte("0123456789"))
+	require.Nil(t, err)
+	require.False(t, rw.Spilled())
+	buf := make([]byte, 5)
+	_, err = io.ReadFull(rw, buf)
+	require.Nil(t, err)
+	_, err = rw.Write([]byte("abcdefghij"))
+	require.Nil(t, err)
+	require.True(t, rw.Spilled())
+	rest, err := io.ReadAll(rw)
+	require.Nil(t, err)
+	require.Equal(t, "56789abcdefghij", string(rest))
+	defaultMemPool.PutReadWriter(rw)
+
+	// Spilling to an invalid directory fails
+	_, err = NewWriterChain().SpillToDisk(1, filepath.Join(dir, "nonexistent")).Build().Encode(BytesEncoder, input)
+	require.Error(t, err)
+}
+
+func TestWriterChainFlush(t *testing.T) {
+	for _, stage := range []struct {
+		writer func() Writer
+		reader func() Reader
+	}{
+		{func() Writer { return NewGZIPWriter() }, func() Reader { return NewGZIPReader() }},
+		{func() Writer { return NewParallelGZIPWriter(64, 2) }, func() Reader { return NewGZIPReader() }},
+		{func() Writer { return NewZSTDWriter() }, func() Reader { return NewZSTDReader() }},
+		{func() Writer { return NewSnappyWriter() }, func() Reader { return NewSnappyReader() }},
+	} {
+		dst := new(bytes.Buffer)
+		wc := NewWriterChain().AddWriter(stage.writer()).Destination(dst).Build()
+
+		// Partial data must be decodable from the destination after each flush
+		var expected []byte
+		for _, part := range []string{"This is a test", "This is another test"} {
+			_, err := wc.Write([]byte(part))
+			require.Nil(t, err)
+			require.Nil(t, wc.Flush())
+			expected = append(expected, part...)
+
+			rd, err := stage.reader().Init(bytes.NewReader(dst.Bytes()))
+			require.Nil(t, err)
+			buf := make([]byte, len(expected))
+			_, err = io.ReadFull(rd, buf)
+			require.Nil(t, err)
+			require.Equal(t, expected, buf)
+		}
+
+		// The chain must remain usable after flushing
+		_, err := wc.Write([]byte("This is the last test"))
+		require.Nil(t, err)
+		require.Nil(t, wc.Close())
+		expected = append(expected, "This is the last test"...)
+
+		rd, err := stage.reader().Init(dst)
+		require.Nil(t, err)
+		output, err := io.ReadAll(rd)
+		require.Nil(t, err)
+		require.Equal(t, expected, output)
+	}
+
+	// Flushing a chain without flushable stages / destination is a no-op
+	wc := NewWriterChain().AddWriter(NewCountingWriter()).Build()
+	_, err := wc.Write([]byte("This is a test"))
+	require.Nil(t, err)
+	require.Nil(t, wc.Flush())
+	require.Nil(t, wc.Close())
+}
+
+func TestChainCloseErrors(t *testing.T) {
+	errFirst, errSecond := errors.New("first"), errors.New("second")
+
+	var (
+		returned     [3]bool
+		postFnCalled bool
+	)
+	wc := NewWriterChain().
+		AddWriter(&closeTrackingWriter{err: errSecond, returned: &returned[0]}).
+		AddWriter(&closeTrackingWriter{returned: &returned[1]}).
+		AddWriter(&closeTrackingWriter{err: errFirst, returned: &returned[2]}).
+		PostFn(func(rw *ReadWriter) error {
+			postFnCalled = true
+			return nil
+		}).Build()
+
+	err := wc.Close()
+	require.ErrorIs(t, err, errFirst)
+	require.ErrorIs(t, err, errSecond)
+	require.Equal(t, "first\nsecond", err.Error())
+	require.Equal(t, errFirst, errors.Unwrap(err))
+	require.Equal(t, [3]bool{true, true, true}, returned)
+	require.False(t, postFnCalled)
+
+	// A single error is returned as is
+	wc = NewWriterChain().AddWriter(&closeTrackingWriter{err: errFirst, returned: &returned[0]}).Build()
+	require.Equal(t, errFirst, wc.Close())
+
+	// Reader chain
+	rc := NewReaderChain(failingWriter{err: errSecond}).AddReader(&closeTrackingReader{err: errFirst}).Build()
+	err = rc.Close()
+	require.ErrorIs(t, err, errFirst)
+	require.ErrorIs(t, err, errSecond)
+	require.Equal(t, "first\nsecond", err.Error())
+	require.Equal(t, errFirst, errors.Unwrap(err))
+
+	// Secondary errors can be extracted as well
+	var pathErr *os.PathError
+	rc = NewReaderChain(failingWriter{err: &os.PathError{Op: "close", Err: os.ErrClosed}}).AddReader(&closeTrackingReader{err: errFirst}).Build()
+	err = rc.Close()
+	require.ErrorAs(t, err, &pathErr)
+	require.ErrorIs(t, err, os.ErrClosed)
+}
+
+func TestChainMetrics(t *testing.T) {
+	input := bytes.Repeat([]byte("metrics"), 1024)
+
+	type stageMetrics struct {
+		name  string
+		bytes int64
+	}
+
+	var (
+		writeMetrics []stageMetrics
+		compressed   []byte
+	)
+	wc := NewWriterChain().
+		AddWriter(NewGZIPWriter()).
+		AddWriter(NewHashWriter(sha256.New())).
+		Metrics(func(stage string, n int64, duration time.Duration) {
+			require.GreaterOrEqual(t, duration, time.Duration(0))
+			writeMetrics = append(writeMetrics, stageMetrics{stage, n})
+		}).
+		PostFn(func(rw *ReadWriter) error {
+			compressed = bytes.Clone(rw.Bytes())
+			return nil
+		}).Build()
+	require.Nil(t, wc.EncodeAndClose(BytesEncoder, input))
+	require.Equal(t, []stageMetrics{
+		{"HashWriter", int64(len(input))},
+		{"GZIPWriter", int64(len(input))},
+	}, writeMetrics)
+
+	var readMetrics []stageMetrics
+	var output []byte
+	rc := NewReaderChain(bytes.NewReader(compressed)).
+		AddReader(NewGZIPReader()).
+		Metrics(func(stage string, n int64, duration time.Duration) {
+			readMetrics = append(readMetrics, stageMetrics{stage, n})
+		}).Build()
+	require.Nil(t, rc.DecodeAndClose(BytesDecoder, &output))
+	require.Equal(t, input, output)
+	require.Equal(t, []stageMetrics{{"GZIPReader", int64(len(input))}}, readMetrics)
+
+	// Metrics are reset when reusing the chain
+	writeMetrics = nil
+	require.Nil(t, wc.Reset(nil).EncodeAndClose(BytesEncoder, input[:10]))
+	require.Equal(t, int64(10), writeMetrics[0].bytes)
+
+	// Metrics requested after building a chain take effect on the next Build / Reset
+	writeMetrics, readMetrics = nil, nil
+	wc = NewWriterChain().AddWriter(NewGZIPWriter()).AddWriter(NewHashWriter(sha256.New())).Build().
+		Metrics(func(stage string, n int64, duration time.Duration) {
+			writeMetrics = append(writeMetrics, stageMetrics{stage, n})
+		})
+	require.Nil(t, wc.EncodeAndClose(BytesEncoder, input))
+	require.Empty(t, writeMetrics)
+	require.Nil(t, wc.Reset(nil).EncodeAndClose(BytesEncoder, input))
+	require.Len(t, writeMetrics, 2)
+
+	rc = NewReaderChain(bytes.NewReader(compressed)).AddReader(NewGZIPReader()).Build().
+		Metrics(func(stage string, n int64, duration time.Duration) {
+			readMetrics = append(readMetrics, stageMetrics{stage, n})
+		})
+	require.Nil(t, rc.DecodeAndClose(BytesDecoder, &output))
+	require.Empty(t, readMetrics)
+	require.Nil(t, rc.Reset(bytes.NewReader(compressed)).DecodeAndClose(BytesDecoder, &output))
+	require.Len(t, readMetrics, 1)
+	require.Equal(t, "GZIPReader", readMetrics[0].name)
+}
+
+type closeTrackingWriter struct {
+	io.Writer
+	err      error
+	returned *bool
+}
+
+func (c *closeTrackingWriter) Init(w io.Writer) io.Writer {
+	c.Writer = w
+	return c
+}
+
+func (c *closeTrackingWriter) Close() error {
+	return c.err
+}
+
+func (c *closeTrackingWriter) Return() {
+	*c.returned = true
+}
+
+type closeTrackingReader struct {
+	io.Reader
+	err error
+}
+
+func (c *closeTrackingReader) Init(r io.Reader) (io.Reader, error) {
+	c.Reader = r
+	return c, nil
+}
+
+func (c *closeTrackingReader) Close() error {
+	return c.err
+}
+
+func (c *closeTrackingReader) Return() {}
+
+type failingWriter struct {
+	err error
+}
+
+func (f failingWriter) Write([]byte) (int, error) {
+	return 0, f.err
+}
+
+func (f failingWriter) Read([]byte) (int, error) {
+	return 0, f.err
+}
+
+func (f failingWriter) Close() error {
+	return f.err
+}
+
+func TestEncodePipe(t *testing.T) {
+	input := bytes.Repeat([]byte("This is a test"), 100000)
+
+	// Repeat test a couple of times to trigger pool re-use scenario
+	for i := 0; i < 10; i++ {
+		r := NewWriterChain().AddWriter(NewGZIPWriter()).EncodePipe(BytesEncoder, input)
+
+		var res []byte
+		rc := NewReaderChain(r).AddReader(NewGZIPReader()).Build()
+		require.Nil(t, rc.DecodeAndClose(BytesDecoder, &res))
+		require.Equal(t, input, res)
+	}
+
+	// Encoding errors must be propagated to the reader
+	r := NewWriterChain().AddWriter(NewGZIPWriter()).EncodePipe(BytesEncoder, "not a byte slice")
+	_, err := io.ReadAll(r)
+	require.ErrorIs(t, err, ErrExpectByteSlice)
+
+	r = NewWriterChain().EncodePipe(nil, input)
+	_, err = io.ReadAll(r)
+	require.Error(t, err)
+
+	// Closing the reader early aborts the encoding
+	r = NewWriterChain().AddWriter(NewGZIPWriter()).EncodePipe(BytesEncoder, input)
+	_, err = r.Read(make([]byte, 16))
+	require.Nil(t, err)
+	require.Nil(t, r.Close())
+}
+
+func TestEncodeStream(t *testing.T) {
+	for _, cs := range []testCase{
+		{
+			name:    "JSON",
+			encoder: JSONEncoder,
+			decoder: JSONDecoder,
+		},
+		{
+			name:    "YAML",
+			encoder: YAMLEncoder,
+			decoder: YAMLDecoder,
+		},
+	} {
+		t.Run(cs.name, func(t *testing.T) {
+			var stream []byte
+			wc := NewWriterChain().AddWriter(NewGZIPWriter()).PostFn(func(rw *ReadWriter) error {
+				stream = rw.BytesCopy()
+				return nil
+			}).Build()
+			for i := 0; i < 1000; i++ {
+				require.Nil(t, wc.EncodeStream(cs.encoder, testStruct{Name: "foo", Value: i}))
+			}
+			require.Nil(t, wc.Close())
+
+			rc := NewReaderChain(bytes.NewReader(stream)).AddReader(NewGZIPReader()).Build()
+			for i := 0; i < 1000; i++ {
+				var res testStruct
+				require.Nil(t, rc.DecodeNext(cs.decoder, &res))
+				require.Equal(t, testStruct{Name: "foo", Value: i}, res)
+			}
+			require.ErrorIs(t, rc.DecodeNext(cs.decoder, &testStruct{}), io.EOF)
+			require.Nil(t, rc.Close())
+		})
+	}
+}
+
+func TestEncodeStreamInvalid(t *testing.T) {
+	var stream []byte
+	wc := NewWriterChain().PostFn(func(rw *ReadWriter) error {
+		stream = rw.BytesCopy()
+		return nil
+	}).Build()
+	require.Error(t, wc.EncodeStream(nil, nil))
+	require.ErrorIs(t, wc.EncodeStream(BytesEncoder, "not a byte slice"), ErrExpectByteSlice)
+	require.Nil(t, wc.EncodeStream(BytesEncoder, []byte("This is a test")))
+	require.Nil(t, wc.Close())
+
+	var res []byte
+	require.Error(t, NewReaderChain(bytes.NewReader(stream)).Build().DecodeNext(nil, &res))
+
+	// Truncated frame / length prefix
+	rc := NewReaderChain(bytes.NewReader(stream[:len(stream)-1])).Build()
+	require.ErrorIs(t, rc.DecodeNext(BytesDecoder, &res), ErrInvalidFrame)
+	rc = NewReaderChain(bytes.NewReader([]byte{0xff})).Build()
+	require.ErrorIs(t, rc.DecodeNext(BytesDecoder, &res), ErrInvalidFrame)
+
+	// Oversized frame
+	rc = NewReaderChain(bytes.NewReader(binary.AppendUvarint(nil, MaxStreamFrameSize+1))).Build()
+	require.ErrorIs(t, rc.DecodeNext(BytesDecoder, &res), ErrInvalidFrame)
+}
+
 func BenchmarkEncoderChain(b *testing.B) {
 	input := testStruct{Name: "foo", Value: 42}
 
@@ -156,6 +1143,15 @@ func BenchmarkEncoderChain(b *testing.B) {
 
 }
 
+var (
+	zstdMagic   = []byte{0x28, 0xb5, 0x2f, 0xfd}
+	snappyMagic = []byte("\xff\x06\x00\x00sNaPpY")
+
+	// Fixtures produced by external tooling (100 repetitions of "This is a test")
+	xzFixture    = "/Td6WFoAAATm1rRGAgAhARYAAAB0L+Wj4AV3ABtdACoaCSdkHIeKT8pM9PiDgfMH24Nnm0nUqLsaAAAAjae+mQN0WUoAATf4CgAAADAY082xxGf7AgAAAAAEWVo="
+	bzip2Fixture = "QlpoOTFBWSZTWSplJrEAAMeTgEAABAAiYAwAIABQhgQFKow06ItEWEWiL4i2RfkXSLBFhFhFsi8LuSKcKEgVMpNYgA=="
+)
+
 func encodeManualJSON(input any) ([]byte, error) {
 	enc, err := jsoniter.Marshal(input)
 	if err != nil {
@@ -3,8 +3,11 @@ module github.com/fako1024/gotools/concurrency
 go 1.20
 
 require (
+	github.com/fako1024/gotools/clock v0.0.0-00010101000000-000000000000
 	github.com/json-iterator/go v1.1.12
+	github.com/klauspost/compress v1.17.9
 	github.com/stretchr/testify v1.10.0
+	github.com/ulikunitz/xz v0.5.12
 	gopkg.in/yaml.v3 v3.0.1
 )
 
@@ -14,3 +17,5 @@ require (
 	github.com/modern-go/reflect2 v1.0.2 // indirect
 	github.com/pmezard/go-difflib v1.0.0 // indirect
 )
+
+replace github.com/fako1024/gotools/clock => ../clock
@@ -0,0 +1,102 @@
+package concurrency
+
+import (
+	"hash"
+	"io"
+)
+
+// HashWriter provides a pass-through Writer stage computing a digest of all bytes written through
+// it (at its position in the chain, e.g. after compression), available after Close()
+type HashWriter struct {
+	h   hash.Hash
+	w   io.Writer
+	sum []byte
+}
+
+// NewHashWriter initializes a new hashing Writer stage using the provided hash (e.g. sha256.New()),
+// fulfilling the Writer interface
+func NewHashWriter(h hash.Hash) *HashWriter {
+	return &HashWriter{
+		h: h,
+	}
+}
+
+// Init resets the hashing Writer stage for (re-)use, writing to the provided io.Writer
+func (hw *HashWriter) Init(w io.Writer) io.Writer {
+	hw.h.Reset()
+	hw.w, hw.sum = w, nil
+
+	return hw
+}
+
+// Write writes len(p) bytes to the underlying io.Writer, adding all bytes written to the digest
+func (hw *HashWriter) Write(p []byte) (n int, err error) {
+	n, err = hw.w.Write(p)
+	_, _ = hw.h.Write(p[:n]) // hash.Hash never returns an error
+
+	return
+}
+
+// Close finalizes the digest (the underlying io.Writer is not closed)
+func (hw *HashWriter) Close() error {
+	hw.sum = hw.h.Sum(nil)
+	return nil
+}
+
+// Return releases the reference to the underlying io.Writer
+func (hw *HashWriter) Return() {
+	hw.w = nil
+}
+
+// Sum returns the digest of all bytes written (only available after Close())
+func (hw *HashWriter) Sum() []byte {
+	return hw.sum
+}
+
+// HashReader provides a pass-through Reader stage computing a digest of all bytes read through it
+// (at its position in the chain, e.g. before decompression), available after Close()
+type HashReader struct {
+	h   hash.Hash
+	r   io.Reader
+	sum []byte
+}
+
+// NewHashReader initializes a new hashing Reader stage using the provided hash (e.g. sha256.New()),
+// fulfilling the Reader interface
+func NewHashReader(h hash.Hash) *HashReader {
+	return &HashReader{
+		h: h,
+	}
+}
+
+// Init resets the hashing Reader stage for (re-)use, reading from the provided io.Reader
+func (hr *HashReader) Init(r io.Reader) (io.Reader, error) {
+	hr.h.Reset()
+	hr.r, hr.sum = r, nil
+
+	return hr, nil
+}
+
+// Read reads up to len(p) bytes from the underlying io.Reader, adding all bytes read to the digest
+func (hr *HashReader) Read(p []byte) (n int, err error) {
+	n, err = hr.r.Read(p)
+	_, _ = hr.h.Write(p[:n]) // hash.Hash never returns an error
+
+	return
+}
+
+// Close finalizes the digest (the underlying io.Reader is not closed)
+func (hr *HashReader) Close() error {
+	hr.sum = hr.h.Sum(nil)
+	return nil
+}
+
+// Return releases the reference to the underlying io.Reader
+func (hr *HashReader) Return() {
+	hr.r = nil
+}
+
+// Sum returns the digest of all bytes read (only available after Close())
+func (hr *HashReader) Sum() []byte {
+	return hr.sum
+}
@@ -0,0 +1,92 @@
+package concurrency
+
+import (
+	"encoding/hex"
+	"io"
+)
+
+// Hexadecimal encoder / decoder functions (writing / reading byte slices, cf. BytesEncoder)
+var (
+	HexEncoder = func(w io.Writer) Encoder {
+		return &byteEncoder{Writer: hex.NewEncoder(w)}
+	}
+	HexDecoder = func(r io.Reader) Decoder {
+		return &byteDecoder{Reader: hex.NewDecoder(r)}
+	}
+)
+
+// HexWriter provides a Writer stage hex-encoding all bytes written through it (at its position in the
+// chain), e.g. for debugging purposes or protocols requiring hex-encoded payloads
+type HexWriter struct {
+	w   io.Writer
+	buf [1024]byte
+}
+
+// NewHexWriter initializes a new hex-encoding Writer stage, fulfilling the Writer interface
+func NewHexWriter() *HexWriter {
+	return &HexWriter{}
+}
+
+// Init resets the hex-encoding Writer stage for (re-)use, writing to the provided io.Writer
+func (hw *HexWriter) Init(w io.Writer) io.Writer {
+	hw.w = w
+	return hw
+}
+
+// Write writes the hex encoding of p to the underlying io.Writer, returning the number of bytes of p
+// that have been encoded and written
+func (hw *HexWriter) Write(p []byte) (n int, err error) {
+	for len(p) > 0 {
+		chunk := p
+		if len(chunk) > len(hw.buf)/2 {
+			chunk = chunk[:len(hw.buf)/2]
+		}
+
+		encoded := hex.Encode(hw.buf[:], chunk)
+		nWritten, err := hw.w.Write(hw.buf[:encoded])
+		n += nWritten / 2
+		if err != nil {
+			return n, err
+		}
+		p = p[len(chunk):]
+	}
+
+	return
+}
+
+// Close closes the hex-encoding Writer stage (the underlying io.Writer is not closed)
+func (hw *HexWriter) Close() error {
+	return nil
+}
+
+// Return releases the reference to the underlying io.Writer
+func (hw *HexWriter) Return() {
+	hw.w = nil
+}
+
+// HexReader provides a Reader stage decoding hex-encoded data read through it (at its position in the
+// chain)
+type HexReader struct {
+	r io.Reader
+}
+
+// NewHexReader initializes a new hex-decoding Reader stage, fulfilling the Reader interface
+func NewHexReader() *HexReader {
+	return &HexReader{}
+}
+
+// Init resets the hex-decoding Reader stage for (re-)use, reading from the provided io.Reader
+func (hr *HexReader) Init(r io.Reader) (io.Reader, error) {
+	hr.r = hex.NewDecoder(r)
+	return hr.r, nil
+}
+
+// Close closes the hex-decoding Reader stage (the underlying io.Reader is not closed)
+func (hr *HexReader) Close() error {
+	return nil
+}
+
+// Return releases the reference to the underlying io.Reader
+func (hr *HexReader) Return() {
+	hr.r = nil
+}
@@ -0,0 +1,164 @@
+package concurrency
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// ChainHandlerOption denotes a functional option for ChainHandler
+type ChainHandlerOption func(*chainHandlerConfig)
+
+// WithEncoding adds a content encoding (e.g. "zstd" using NewZSTDWriter) supported by the handler,
+// encodings are preferred in the order they were added (if accepted by the client). If no encoding
+// is provided explicitly, gzip is used
+func WithEncoding(name string, newWriter func() Writer) ChainHandlerOption {
+	return func(cfg *chainHandlerConfig) {
+		cfg.encodings = append(cfg.encodings, chainEncoding{
+			name:      strings.ToLower(name),
+			newWriter: newWriter,
+		})
+	}
+}
+
+// ChainHandler provides an HTTP middleware that transparently compresses responses using a (pooled)
+// Writer chain, negotiating the content encoding based on the Accept-Encoding header of the request.
+// Responses without body or with a Content-Encoding already set by the wrapped handler are passed
+// through unmodified
+func ChainHandler(next http.Handler, opts ...ChainHandlerOption) http.Handler {
+	cfg := chainHandlerConfig{}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	if len(cfg.encodings) == 0 {
+		cfg.encodings = []chainEncoding{{
+			name:      "gzip",
+			newWriter: func() Writer { return NewGZIPWriter() },
+		}}
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Vary", "Accept-Encoding")
+
+		enc, ok := cfg.negotiate(r.Header.Get("Accept-Encoding"))
+		if !ok || r.Method == http.MethodHead {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		cw := &chainResponseWriter{
+			ResponseWriter: w,
+			enc:            enc,
+		}
+		defer cw.close()
+
+		next.ServeHTTP(cw, r)
+	})
+}
+
+////////////////////////////////////////////////////////////////////////////////////////
+
+type chainEncoding struct {
+	name      string
+	newWriter func() Writer
+}
+
+type chainHandlerConfig struct {
+	encodings []chainEncoding
+}
+
+// negotiate selects the most preferred encoding accepted by the client (if any)
+func (cfg chainHandlerConfig) negotiate(acceptEncoding string) (chainEncoding, bool) {
+	accepted := make(map[string]bool)
+	for _, part := range strings.Split(acceptEncoding, ",") {
+		name, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+
+		// Encodings with a quality value of zero are explicitly not acceptable
+		q := 1.
+		if key, val, found := strings.Cut(strings.TrimSpace(params), "="); found && strings.TrimSpace(key) == "q" {
+			if parsed, err := strconv.ParseFloat(strings.TrimSpace(val), 64); err == nil {
+				q = parsed
+			}
+		}
+		accepted[name] = q > 0
+	}
+
+	for _, enc := range cfg.encodings {
+		if ok, exists := accepted[enc.name]; (exists && ok) || (!exists && accepted["*"]) {
+			return enc, true
+		}
+	}
+
+	return chainEncoding{}, false
+}
+
+// chainResponseWriter wraps an http.ResponseWriter, compressing the response body via a Writer
+// chain (initialized once the response header is written)
+type chainResponseWriter struct {
+	http.ResponseWriter
+
+	enc         chainEncoding
+	wc          *WriterChain
+	wroteHeader bool
+}
+
+func (cw *chainResponseWriter) WriteHeader(code int) {
+	if cw.wroteHeader {
+		return
+	}
+	cw.wroteHeader = true
+
+	// Only compress responses that carry a body and are not encoded already
+	h := cw.Header()
+	if code >= http.StatusOK && code != http.StatusNoContent && code != http.StatusNotModified && h.Get("Content-Encoding") == "" {
+		h.Del("Content-Length")
+		h.Set("Content-Encoding", cw.enc.name)
+		cw.wc = NewWriterChain().AddWriter(cw.enc.newWriter()).Destination(cw.ResponseWriter).Build()
+	}
+
+	cw.ResponseWriter.WriteHeader(code)
+}
+
+func (cw *chainResponseWriter) Write(p []byte) (int, error) {
+	if !cw.wroteHeader {
+		if cw.Header().Get("Content-Type") == "" {
+			cw.Header().Set("Content-Type", http.DetectContentType(p))
+		}
+		cw.WriteHeader(http.StatusOK)
+	}
+	if cw.wc == nil {
+		return cw.ResponseWriter.Write(p)
+	}
+
+	return cw.wc.Write(p)
+}
+
+// Flush fulfils the http.Flusher interface, flushing the Writer chain (if any) and the underlying
+// http.ResponseWriter (e.g. for streaming responses)
+func (cw *chainResponseWriter) Flush() {
+	if !cw.wroteHeader {
+		cw.WriteHeader(http.StatusOK)
+	}
+	if cw.wc != nil {
+		_ = cw.wc.Flush()
+		return
+	}
+	if flusher, ok := cw.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// Unwrap provides access to the underlying http.ResponseWriter (cf. http.ResponseController)
+func (cw *chainResponseWriter) Unwrap() http.ResponseWriter {
+	return cw.ResponseWriter
+}
+
+func (cw *chainResponseWriter) close() {
+	if cw.wc != nil {
+		_ = cw.wc.Close()
+	}
+}
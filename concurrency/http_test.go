@@ -0,0 +1,121 @@
+package concurrency
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestChainHandler(t *testing.T) {
+	payload := bytes.Repeat([]byte("This is a test"), 1000)
+	handler := ChainHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/empty":
+			w.WriteHeader(http.StatusNoContent)
+		case "/encoded":
+			w.Header().Set("Content-Encoding", "br")
+			_, _ = w.Write([]byte("already encoded"))
+		default:
+			w.Header().Set("Content-Length", "14000")
+			_, _ = w.Write(payload)
+		}
+	}))
+
+	// Repeat test a couple of times to trigger pool re-use scenario
+	for i := 0; i < 10; i++ {
+		rec := doRequest(handler, "/", "deflate, gzip;q=0.8")
+		require.Equal(t, http.StatusOK, rec.Code)
+		require.Equal(t, "gzip", rec.Header().Get("Content-Encoding"))
+		require.Equal(t, "Accept-Encoding", rec.Header().Get("Vary"))
+		require.Empty(t, rec.Header().Get("Content-Length"))
+		require.Equal(t, "text/plain; charset=utf-8", rec.Header().Get("Content-Type"))
+
+		gz, err := gzip.NewReader(rec.Body)
+		require.Nil(t, err)
+		data, err := io.ReadAll(gz)
+		require.Nil(t, err)
+		require.Equal(t, payload, data)
+	}
+
+	// No (acceptable) encoding
+	for _, acceptEncoding := range []string{"", "deflate", "gzip;q=0", "*;q=0"} {
+		rec := doRequest(handler, "/", acceptEncoding)
+		require.Empty(t, rec.Header().Get("Content-Encoding"))
+		require.Equal(t, payload, rec.Body.Bytes())
+	}
+
+	// Responses without body or already encoded are passed through
+	rec := doRequest(handler, "/empty", "gzip")
+	require.Equal(t, http.StatusNoContent, rec.Code)
+	require.Empty(t, rec.Header().Get("Content-Encoding"))
+	require.Zero(t, rec.Body.Len())
+
+	rec = doRequest(handler, "/encoded", "gzip")
+	require.Equal(t, "br", rec.Header().Get("Content-Encoding"))
+	require.Equal(t, "already encoded", rec.Body.String())
+
+	// Flushing a streaming response pushes the data compressed so far to the client
+	var partial []byte
+	handler = ChainHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("This is a test"))
+		w.(http.Flusher).Flush()
+		partial = bytes.Clone(w.(interface{ Unwrap() http.ResponseWriter }).Unwrap().(*httptest.ResponseRecorder).Body.Bytes())
+		_, _ = w.Write([]byte("This is another test"))
+	}))
+	rec = doRequest(handler, "/", "gzip")
+	require.True(t, rec.Flushed)
+
+	gz, err := gzip.NewReader(bytes.NewReader(partial))
+	require.Nil(t, err)
+	buf := make([]byte, len("This is a test"))
+	_, err = io.ReadFull(gz, buf)
+	require.Nil(t, err)
+	require.Equal(t, "This is a test", string(buf))
+}
+
+func TestChainHandlerEncodings(t *testing.T) {
+	payload := bytes.Repeat([]byte("This is a test"), 1000)
+	handler := ChainHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write(payload)
+	}),
+		WithEncoding("zstd", func() Writer { return NewZSTDWriter() }),
+		WithEncoding("gzip", func() Writer { return NewGZIPWriter() }),
+	)
+
+	for acceptEncoding, expected := range map[string]string{
+		"gzip, zstd":  "zstd",
+		"gzip":        "gzip",
+		"*":           "zstd",
+		"*, zstd;q=0": "gzip",
+	} {
+		rec := doRequest(handler, "/", acceptEncoding)
+		require.Equal(t, expected, rec.Header().Get("Content-Encoding"), acceptEncoding)
+
+		var (
+			res    []byte
+			reader Reader = NewGZIPReader()
+		)
+		if expected == "zstd" {
+			reader = NewZSTDReader()
+		}
+		rc := NewReaderChain(rec.Body).AddReader(reader).Build()
+		require.Nil(t, rc.DecodeAndClose(BytesDecoder, &res))
+		require.Equal(t, payload, res)
+	}
+}
+
+func doRequest(handler http.Handler, path, acceptEncoding string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	if acceptEncoding != "" {
+		req.Header.Set("Accept-Encoding", acceptEncoding)
+	}
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	return rec
+}
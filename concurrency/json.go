@@ -0,0 +1,89 @@
+package concurrency
+
+import (
+	"errors"
+	"io"
+	"sync"
+
+	jsoniter "github.com/json-iterator/go"
+)
+
+const jsonBufSize = 512
+
+var jsonEncPool, jsonDecPool sync.Pool
+
+// jsonEncoder provides a pooled jsoniter based JSON encoder (cf. jsoniter.Encoder)
+type jsonEncoder struct {
+	stream *jsoniter.Stream
+}
+
+func newJSONEncoder(w io.Writer) *jsonEncoder {
+	if encI := jsonEncPool.Get(); encI != nil {
+		enc := encI.(*jsonEncoder)
+		enc.stream.Reset(w)
+		return enc
+	}
+	return &jsonEncoder{
+		stream: jsoniter.NewStream(jsoniter.ConfigDefault, w, jsonBufSize),
+	}
+}
+
+// Encode writes the JSON encoding of v to the underlying io.Writer (followed by a newline)
+func (e *jsonEncoder) Encode(v any) error {
+	e.stream.WriteVal(v)
+	e.stream.WriteRaw("\n")
+	if err := e.stream.Flush(); err != nil {
+		return err
+	}
+	return e.stream.Error
+}
+
+func (e *jsonEncoder) release() {
+	e.stream.Reset(nil)
+	e.stream.Error = nil
+	e.stream.Attachment = nil
+	jsonEncPool.Put(e)
+}
+
+// jsonDecoder provides a pooled jsoniter based JSON decoder (cf. jsoniter.Decoder)
+type jsonDecoder struct {
+	iter *jsoniter.Iterator
+}
+
+func newJSONDecoder(r io.Reader) *jsonDecoder {
+	if decI := jsonDecPool.Get(); decI != nil {
+		dec := decI.(*jsonDecoder)
+		dec.iter.Reset(r)
+		return dec
+	}
+	return &jsonDecoder{
+		iter: jsoniter.Parse(jsoniter.ConfigDefault, r, jsonBufSize),
+	}
+}
+
+// Decode reads the next JSON-encoded value from the underlying io.Reader and stores it in v,
+// returning io.EOF if there is no more data
+func (d *jsonDecoder) Decode(v any) error {
+
+	// Skip any whitespace and check if there is anything left to decode (a previous value
+	// may have been terminated by the end of the stream)
+	if errors.Is(d.iter.Error, io.EOF) {
+		return io.EOF
+	}
+	if d.iter.WhatIsNext() == jsoniter.InvalidValue && d.iter.Error != nil {
+		return d.iter.Error
+	}
+
+	d.iter.ReadVal(v)
+	if errors.Is(d.iter.Error, io.EOF) {
+		return nil
+	}
+	return d.iter.Error
+}
+
+func (d *jsonDecoder) release() {
+	d.iter.Reset(nil)
+	d.iter.Error = nil
+	d.iter.Attachment = nil
+	jsonDecPool.Put(d)
+}
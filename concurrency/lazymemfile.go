@@ -0,0 +1,214 @@
+package concurrency
+
+import (
+	"container/list"
+	"fmt"
+	"io"
+	"io/fs"
+	"sync"
+)
+
+const (
+	// DefaultLazyMemFileWindowSize denotes the default size of the windows loaded by a LazyMemFile
+	DefaultLazyMemFileWindowSize = 1 << 20
+
+	// DefaultLazyMemFileMaxWindows denotes the default number of windows kept resident by a LazyMemFile
+	DefaultLazyMemFileMaxWindows = 16
+)
+
+// LazyMemFileSource denotes the source of a LazyMemFile (e.g. an *os.File)
+type LazyMemFileSource interface {
+	Stat() (fs.FileInfo, error)
+
+	io.ReaderAt
+	io.Closer
+}
+
+// LazyMemFile denotes a read-only, in-memory abstraction of an underlying file that (in contrast to
+// MemFile) loads the file in fixed-size windows on demand (drawing memory from a pool), keeping only
+// a limited number of (least recently used) windows resident. All methods are safe for concurrent use
+// (the position used by Read / Seek being shared, cf. ReadAt)
+type LazyMemFile struct {
+	src        LazyMemFileSource
+	size       int64
+	windowSize int64
+	maxWindows int
+
+	pos     int64
+	windows map[int64]*list.Element
+	lru     *list.List
+
+	pool MemPool
+	sync.Mutex
+}
+
+type lazyMemFileWindow struct {
+	idx  int64
+	data []byte
+}
+
+// NewLazyMemFile instantiates a new lazily loaded in-memory file buffer using the provided window size
+// and maximum number of resident windows (if zero, defaults to DefaultLazyMemFileWindowSize and
+// DefaultLazyMemFileMaxWindows, respectively). The source is closed once the LazyMemFile is closed
+func NewLazyMemFile(src LazyMemFileSource, pool MemPool, windowSize, maxWindows int) (*LazyMemFile, error) {
+	stat, err := src.Stat()
+	if err != nil {
+		return nil, err
+	}
+	if windowSize <= 0 {
+		windowSize = DefaultLazyMemFileWindowSize
+	}
+	if maxWindows <= 0 {
+		maxWindows = DefaultLazyMemFileMaxWindows
+	}
+
+	return &LazyMemFile{
+		src:        src,
+		size:       stat.Size(),
+		windowSize: int64(windowSize),
+		maxWindows: maxWindows,
+		windows:    make(map[int64]*list.Element),
+		lru:        list.New(),
+		pool:       pool,
+	}, nil
+}
+
+// Read fulfils the io.Reader interface (reading up to len(p) bytes from the current position)
+func (m *LazyMemFile) Read(p []byte) (n int, err error) {
+	m.Lock()
+	defer m.Unlock()
+
+	n, err = m.readAt(p, m.pos)
+	m.pos += int64(n)
+	if n > 0 && err == io.EOF {
+		err = nil
+	}
+	return
+}
+
+// ReadAt fulfils the io.ReaderAt interface (reading len(p) bytes starting at the provided offset,
+// loading all required windows)
+func (m *LazyMemFile) ReadAt(p []byte, off int64) (n int, err error) {
+	m.Lock()
+	defer m.Unlock()
+
+	return m.readAt(p, off)
+}
+
+// Seek fulfils the io.Seeker interface (seeking to a designated position)
+func (m *LazyMemFile) Seek(offset int64, whence int) (int64, error) {
+	m.Lock()
+	defer m.Unlock()
+
+	switch whence {
+	case io.SeekStart:
+	case io.SeekCurrent:
+		offset += m.pos
+	case io.SeekEnd:
+		offset += m.size
+	default:
+		return m.pos, fmt.Errorf("invalid whence: %d", whence)
+	}
+	if offset < 0 {
+		return m.pos, fmt.Errorf("invalid negative position: %d", offset)
+	}
+	m.pos = offset
+
+	return m.pos, nil
+}
+
+// Size returns the size of the underlying file
+func (m *LazyMemFile) Size() int64 {
+	return m.size
+}
+
+// NumResident returns the number of windows currently resident in memory
+func (m *LazyMemFile) NumResident() int {
+	m.Lock()
+	defer m.Unlock()
+
+	return m.lru.Len()
+}
+
+// Stat return the (stub) Stat element providing the length of the underlying data
+func (m *LazyMemFile) Stat() (fs.FileInfo, error) {
+	return &memStat{
+		size: m.size,
+	}, nil
+}
+
+// Close fulfils the underlying io.Closer interface (returning all resident windows to the pool and
+// closing the source)
+func (m *LazyMemFile) Close() error {
+	m.Lock()
+	defer m.Unlock()
+
+	for e := m.lru.Front(); e != nil; e = e.Next() {
+		m.pool.Put(e.Value.(*lazyMemFileWindow).data)
+	}
+	m.lru.Init()
+	m.windows = make(map[int64]*list.Element)
+
+	return m.src.Close()
+}
+
+////////////////////////////////////////////////////////////////////////////////////////
+
+func (m *LazyMemFile) readAt(p []byte, off int64) (n int, err error) {
+	if off < 0 {
+		return 0, fmt.Errorf("invalid negative offset: %d", off)
+	}
+
+	for n < len(p) {
+		if off >= m.size {
+			return n, io.EOF
+		}
+
+		window, err := m.window(off / m.windowSize)
+		if err != nil {
+			return n, err
+		}
+		nCopied := copy(p[n:], window[off%m.windowSize:])
+		n += nCopied
+		off += int64(nCopied)
+	}
+
+	return n, nil
+}
+
+// window returns the data of the window with the provided index, loading it (and evicting the least
+// recently used window if the maximum number of resident windows is exceeded) if required
+func (m *LazyMemFile) window(idx int64) ([]byte, error) {
+	if e, exists := m.windows[idx]; exists {
+		m.lru.MoveToFront(e)
+		return e.Value.(*lazyMemFileWindow).data, nil
+	}
+
+	start := idx * m.windowSize
+	size := m.windowSize
+	if start+size > m.size {
+		size = m.size - start
+	}
+
+	// The least recently used window is returned to the pool before retrieving a new element (which
+	// would otherwise block on a pool limited to the maximum number of resident windows)
+	if m.lru.Len() >= m.maxWindows {
+		oldest := m.lru.Back()
+		window := m.lru.Remove(oldest).(*lazyMemFileWindow)
+		delete(m.windows, window.idx)
+		m.pool.Put(window.data)
+	}
+
+	data := m.pool.Get(int(size))
+	if _, err := m.src.ReadAt(data, start); err != nil && !(err == io.EOF && int64(len(data)) == size) {
+		m.pool.Put(data)
+		return nil, err
+	}
+
+	m.windows[idx] = m.lru.PushFront(&lazyMemFileWindow{
+		idx:  idx,
+		data: data,
+	})
+
+	return data, nil
+}
@@ -1,40 +1,101 @@
 package concurrency
 
 import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
 	"fmt"
+	"hash"
 	"io"
 	"io/fs"
 	"os"
+	"path/filepath"
 	"time"
 )
 
+// ErrNoSourceFile denotes that a MemFile does not originate from a (named) file and hence cannot be
+// synced back to it
+var ErrNoSourceFile = errors.New("MemFile does not originate from a named file")
+
+// MemFileOption denotes a functional option for NewMemFile
+type MemFileOption func(*memFileConfig)
+
+// WithChecksum causes NewMemFile to verify the SHA-256 digest of the file contents during the initial
+// read, failing with ErrChecksumMismatch if it does not match the expected one
+func WithChecksum(expected []byte) MemFileOption {
+	return WithChecksumHash(sha256.New, expected)
+}
+
+// WithChecksumHash causes NewMemFile to verify the digest of the file contents during the initial
+// read using a hash obtained from the provided constructor (e.g. of an xxhash implementation), failing
+// with ErrChecksumMismatch if it does not match the expected one. Since a new hash is instantiated for
+// each call to NewMemFile, the option can safely be shared
+func WithChecksumHash(newHash func() hash.Hash, expected []byte) MemFileOption {
+	return func(cfg *memFileConfig) {
+		cfg.newHash, cfg.expectedChecksum = newHash, expected
+	}
+}
+
+type memFileConfig struct {
+	newHash          func() hash.Hash
+	expectedChecksum []byte
+}
+
+// MemFileSyncOption denotes a functional option for MemFile.Sync
+type MemFileSyncOption func(*memFileSync)
+
+// WithAtomicSync causes MemFile.Sync to write to a temporary file in the same directory, replacing
+// the originating file via rename, i.e. the file either retains its previous content or contains
+// the full new content (even in case of a crash)
+func WithAtomicSync() MemFileSyncOption {
+	return func(s *memFileSync) {
+		s.atomic = true
+	}
+}
+
+type memFileSync struct {
+	atomic bool
+}
+
 // MemFile denotes an in-memory abstraction of an underlying file, acting as
 // a buffer (drawing memory from a pool)
 type MemFile struct {
-	data []byte
-	pos  int
+	data     []byte
+	pos      int
+	growable bool
+
+	name string
+	perm fs.FileMode
 
 	pool MemPool
 }
 
-// NewMemFile instantiates a new in-memory file buffer
-func NewMemFile(r ReadWriteSeekCloser, pool MemPool) (*MemFile, error) {
-	stat, err := r.Stat()
-	if err != nil {
-		return nil, err
+// NewMemFileBuffer instantiates a new (empty) in-memory file buffer, growing via the pool as
+// data is written to it (and which can later be flushed to a file, cf. WriteFile)
+func NewMemFileBuffer(pool MemPool) *MemFile {
+	return &MemFile{
+		data:     pool.Get(0),
+		growable: true,
+		pool:     pool,
 	}
-	obj := MemFile{
-		data: pool.Get(int(stat.Size())),
-		pool: pool,
+}
+
+// NewMemFile instantiates a new in-memory file buffer (if the underlying file is named, e.g. an
+// *os.File, changes can be persisted back to it, cf. Sync). The underlying file is closed in any
+// case, i.e. also if reading (or verifying) its content fails
+func NewMemFile(r ReadWriteSeekCloser, pool MemPool, opts ...MemFileOption) (*MemFile, error) {
+	var cfg memFileConfig
+	for _, opt := range opts {
+		opt(&cfg)
 	}
-	n, err := io.ReadFull(r, obj.data)
+
+	obj, err := readMemFile(r, pool, cfg)
 	if err != nil {
+		_ = r.Close()
 		return nil, err
 	}
-	if n != int(stat.Size()) {
-		return nil, fmt.Errorf("unexpected number of bytes read (want %d, have %d)", stat.Size(), n)
-	}
-	return &obj, r.Close()
+
+	return obj, r.Close()
 }
 
 // Read fulfils the io.Reader interface (reading len(p) bytes from the buffer)
@@ -47,8 +108,12 @@ func (m *MemFile) Read(p []byte) (n int, err error) {
 	return
 }
 
-// Write fulfils the io.Writer interface (writing len(p) bytes to the buffer)
+// Write fulfils the io.Writer interface (writing len(p) bytes to the buffer, growing it
+// if the MemFile has been instantiated via NewMemFileBuffer)
 func (m *MemFile) Write(p []byte) (n int, err error) {
+	if m.growable && m.pos+len(p) > len(m.data) {
+		m.grow(m.pos + len(p))
+	}
 	n = copy(m.data[m.pos:], p)
 	if n != len(p) {
 		return n, fmt.Errorf("unexpected number of bytes written (want %d, have %d)", len(p), n)
@@ -57,12 +122,50 @@ func (m *MemFile) Write(p []byte) (n int, err error) {
 	return
 }
 
+// ReadAt fulfils the io.ReaderAt interface (reading len(p) bytes from the buffer, starting at the
+// provided offset). Since the position of the MemFile is neither used nor modified, concurrent calls
+// are safe (as long as they are not interleaved with writes to the same range)
+func (m *MemFile) ReadAt(p []byte, off int64) (n int, err error) {
+	if off < 0 {
+		return 0, fmt.Errorf("invalid negative offset: %d", off)
+	}
+	if off >= int64(len(m.data)) {
+		return 0, io.EOF
+	}
+	n = copy(p, m.data[off:])
+	if n != len(p) {
+		return n, io.EOF
+	}
+	return
+}
+
+// WriteAt fulfils the io.WriterAt interface (writing len(p) bytes to the buffer, starting at the
+// provided offset, growing it if the MemFile has been instantiated via NewMemFileBuffer). Since the
+// position of the MemFile is neither used nor modified, concurrent calls on disjoint ranges are safe
+// (as long as they do not require the buffer to grow)
+func (m *MemFile) WriteAt(p []byte, off int64) (n int, err error) {
+	if off < 0 {
+		return 0, fmt.Errorf("invalid negative offset: %d", off)
+	}
+	if end := off + int64(len(p)); m.growable && end > int64(len(m.data)) {
+		m.grow(int(end))
+	}
+	if off > int64(len(m.data)) {
+		return 0, fmt.Errorf("unexpected number of bytes written (want %d, have 0)", len(p))
+	}
+	n = copy(m.data[off:], p)
+	if n != len(p) {
+		return n, fmt.Errorf("unexpected number of bytes written (want %d, have %d)", len(p), n)
+	}
+	return
+}
+
 // Seek fulfils the io.Seeker interface (seeking to a designated position)
 func (m *MemFile) Seek(offset int64, whence int) (int64, error) {
 	if whence != 0 {
 		panic("only supports seek from start of buffer")
 	}
-	if int(offset) >= len(m.data) {
+	if int(offset) >= len(m.data) && !(m.growable && int(offset) == len(m.data)) {
 		return 0, io.EOF
 	}
 	m.pos = int(offset)
@@ -74,6 +177,44 @@ func (m *MemFile) Data() []byte {
 	return m.data
 }
 
+// WriteFile writes the data of the MemFile to the named file (creating it with the provided
+// permissions or truncating it if it exists, cf. os.WriteFile)
+func (m *MemFile) WriteFile(name string, perm fs.FileMode) error {
+	return os.WriteFile(name, m.data, perm)
+}
+
+// Sync writes the (modified) contents of the MemFile back to the file it originates from (cf.
+// NewMemFile), optionally atomically (cf. WithAtomicSync). If the MemFile does not originate from
+// a named file, ErrNoSourceFile is returned
+func (m *MemFile) Sync(opts ...MemFileSyncOption) error {
+	if m.name == "" {
+		return ErrNoSourceFile
+	}
+
+	var cfg memFileSync
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	if cfg.atomic {
+		return m.syncAtomic()
+	}
+
+	f, err := os.OpenFile(filepath.Clean(m.name), os.O_WRONLY|os.O_CREATE|os.O_TRUNC, m.perm)
+	if err != nil {
+		return err
+	}
+	if _, err = f.Write(m.data); err != nil {
+		_ = f.Close()
+		return err
+	}
+	if err = f.Sync(); err != nil {
+		_ = f.Close()
+		return err
+	}
+
+	return f.Close()
+}
+
 // Close fulfils the underlying io.Closer interface (returning the buffer to the pool)
 func (m *MemFile) Close() error {
 	m.pool.Put(m.data)
@@ -87,6 +228,116 @@ func (m *MemFile) Stat() (fs.FileInfo, error) {
 	}, nil
 }
 
+func readMemFile(r ReadWriteSeekCloser, pool MemPool, cfg memFileConfig) (*MemFile, error) {
+	stat, err := r.Stat()
+	if err != nil {
+		return nil, err
+	}
+	obj := MemFile{
+		data: pool.Get(int(stat.Size())),
+		perm: stat.Mode().Perm(),
+		pool: pool,
+	}
+	if named, ok := r.(interface{ Name() string }); ok {
+		obj.name = named.Name()
+	}
+	var (
+		src      io.Reader = r
+		checksum hash.Hash
+	)
+	if cfg.newHash != nil {
+		checksum = cfg.newHash()
+		src = io.TeeReader(r, checksum)
+	}
+	n, err := io.ReadFull(src, obj.data)
+	if err != nil {
+		pool.Put(obj.data)
+		return nil, err
+	}
+	if n != int(stat.Size()) {
+		pool.Put(obj.data)
+		return nil, fmt.Errorf("unexpected number of bytes read (want %d, have %d)", stat.Size(), n)
+	}
+	if checksum != nil {
+		if actual := checksum.Sum(nil); !bytes.Equal(actual, cfg.expectedChecksum) {
+			pool.Put(obj.data)
+			return nil, fmt.Errorf("%w (expected %x, have %x)", ErrChecksumMismatch, cfg.expectedChecksum, actual)
+		}
+	}
+
+	return &obj, nil
+}
+
+func (m *MemFile) syncAtomic() (err error) {
+	path := filepath.Clean(m.name)
+	dir := filepath.Dir(path)
+
+	// Write to a temporary file in the same directory (to ensure rename() is atomic)
+	tmpFile, err := os.CreateTemp(dir, filepath.Base(path)+".tmp*")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			_ = os.Remove(tmpFile.Name())
+		}
+	}()
+
+	if _, err = tmpFile.Write(m.data); err != nil {
+		_ = tmpFile.Close()
+		return err
+	}
+	if err = tmpFile.Chmod(m.perm); err != nil {
+		_ = tmpFile.Close()
+		return err
+	}
+	if err = tmpFile.Sync(); err != nil {
+		_ = tmpFile.Close()
+		return err
+	}
+	if err = tmpFile.Close(); err != nil {
+		return err
+	}
+	if err = os.Rename(tmpFile.Name(), path); err != nil {
+		return err
+	}
+
+	// Attempt to persist the rename itself by syncing the parent directory (not supported on all
+	// platforms, hence errors are ignored)
+	if d, dErr := os.Open(filepath.Clean(dir)); dErr == nil {
+		_ = d.Sync()
+		_ = d.Close()
+	}
+
+	return nil
+}
+
+// grow extends the buffer to the provided size, obtaining a larger buffer from the pool if
+// required (at least doubling the capacity in order to amortize the cost of growing). Since
+// pooled memory may hold stale data, the extension is zeroed
+func (m *MemFile) grow(size int) {
+	oldSize := len(m.data)
+	if size <= cap(m.data) {
+		m.data = m.data[:size]
+	} else {
+		want := 2 * cap(m.data)
+		if want < size {
+			want = size
+		}
+
+		// The current element is returned to the pool before retrieving a larger one (which would
+		// otherwise block on a limited pool), hence its content has to be preserved in the meantime
+		content := bytes.Clone(m.data)
+		m.pool.Put(m.data)
+		m.data = m.pool.Get(want)[:size]
+		copy(m.data, content)
+	}
+
+	for i := oldSize; i < size; i++ {
+		m.data[i] = 0
+	}
+}
+
 // A memStat is the (stub) implementation of FileInfo returned by Stat and Lstat, basically
 // only providing the ability to obtain the size / length of the underlying data
 type memStat struct {
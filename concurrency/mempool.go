@@ -1,14 +1,32 @@
 package concurrency
 
 import (
+	"errors"
+	"fmt"
 	"io"
 	"io/fs"
+	"math/bits"
+	"runtime"
 	"sync"
+	"sync/atomic"
+	"time"
 	"unsafe"
 )
 
 var defaultMemPool = NewMemPoolNoLimit()
 
+var (
+
+	// ErrUntrackedElement denotes that an element does not originate from a MemPoolLimitUnique
+	ErrUntrackedElement = errors.New("untracked memory element")
+
+	// ErrElementNotTaken denotes that an element has already been returned to a MemPoolLimitUnique
+	ErrElementNotTaken = errors.New("memory element has already been returned to pool")
+
+	// ErrPoolClosed denotes that a memory pool has been cleared (cf. Clear)
+	ErrPoolClosed = errors.New("memory pool has been cleared")
+)
+
 // ReadWriteSeekCloser provides an interface to all the wrapped interfaces
 // in one instance
 type ReadWriteSeekCloser interface {
@@ -40,207 +58,1119 @@ type MemPoolGCable interface {
 	MemPool
 }
 
+// MemPoolAdopter denotes a generic memory buffer pool that can take over ownership of elements not
+// originating from the pool (e.g. one detached from a ReadWriter of another pool, cf. ReadWriter.Detach)
+type MemPoolAdopter interface {
+	Adopt(elem []byte)
+
+	MemPool
+}
+
+// MemPoolOption denotes a functional option for memory pools (applicable to MemPoolLimit,
+// MemPoolNoLimit, MemPoolTiered and MemPoolSharded)
+type MemPoolOption func(*memPoolConfig)
+
+// WithMaxRetainedSize sets a maximum capacity (in bytes) of elements retained by the pool, elements
+// grown beyond this threshold while in use are dropped (i.e. made available for garbage collection)
+// upon return instead of permanently inflating the pool
+func WithMaxRetainedSize(n int) MemPoolOption {
+	return func(cfg *memPoolConfig) {
+		cfg.maxRetainedSize = n
+	}
+}
+
+// WithIdleShrink enables a background shrink policy, periodically (every interval) releasing the
+// memory of all elements that have remained idle in the pool for the whole interval (i.e. that would
+// not have been required to serve any request), making it available for garbage collection. Only
+// applicable to MemPoolLimit (sync.Pool-based pools inherently release idle elements upon garbage
+// collection), which retains the number of elements and stops the shrink policy upon Clear()
+func WithIdleShrink(interval time.Duration) MemPoolOption {
+	return func(cfg *memPoolConfig) {
+		cfg.idleShrinkInterval = interval
+	}
+}
+
+// WithPoisoning enables a debug mode (intended for tests) filling all elements returned to the pool
+// with a poison pattern and verifying it upon their retrieval, panicking if an element has been
+// modified after being returned to the pool (use-after-put) or if it is returned twice. All elements
+// returned to the pool are tracked (and hence kept from garbage collection) until their retrieval
+func WithPoisoning() MemPoolOption {
+	return func(cfg *memPoolConfig) {
+		cfg.poisoner = &memPoolPoisoner{
+			returned: make(map[uintptr][]byte),
+		}
+	}
+}
+
+type memPoolConfig struct {
+	maxRetainedSize    int
+	idleShrinkInterval time.Duration
+	poisoner           *memPoolPoisoner
+}
+
+func newMemPoolConfig(opts []MemPoolOption) memPoolConfig {
+	var cfg memPoolConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	return cfg
+}
+
+// retain determines if an element of the provided capacity may be returned to the pool
+func (cfg memPoolConfig) retain(capacity int) bool {
+	return cfg.maxRetainedSize <= 0 || capacity <= cfg.maxRetainedSize
+}
+
+// memPoolPoison denotes the pattern elements are filled with while residing in a pool (cf. WithPoisoning)
+const memPoolPoison = 0xDB
+
+// memPoolPoisoner poisons and verifies elements of a memory pool (cf. WithPoisoning), all methods
+// being no-ops on a nil receiver (i.e. if poisoning is disabled)
+type memPoolPoisoner struct {
+	returned map[uintptr][]byte
+	sync.Mutex
+}
+
+// poison fills an element to be returned to the pool with the poison pattern
+func (p *memPoolPoisoner) poison(elem []byte) {
+	if p == nil || cap(elem) == 0 {
+		return
+	}
+	elem = elem[:cap(elem)]
+
+	p.Lock()
+	defer p.Unlock()
+
+	ptr := slicePtr(elem)
+	if _, exists := p.returned[ptr]; exists {
+		panic("memory element returned to pool twice")
+	}
+	p.returned[ptr] = elem
+
+	for i := range elem {
+		elem[i] = memPoolPoison
+	}
+}
+
+// verify ensures that an element retrieved from the pool has not been modified since its return
+func (p *memPoolPoisoner) verify(elem []byte) {
+	if p == nil || cap(elem) == 0 {
+		return
+	}
+	elem = elem[:cap(elem)]
+
+	p.Lock()
+	defer p.Unlock()
+
+	ptr := slicePtr(elem)
+	if _, exists := p.returned[ptr]; !exists {
+		return
+	}
+	delete(p.returned, ptr)
+
+	for i := range elem {
+		if elem[i] != memPoolPoison {
+			panic(fmt.Sprintf("memory element modified after being returned to pool (offset %d of %d)", i, len(elem)))
+		}
+	}
+}
+
+// MemPoolStats denotes usage statistics of a memory pool
+type MemPoolStats struct {
+	Gets    uint64 // Number of elements retrieved from the pool
+	Puts    uint64 // Number of elements returned to the pool
+	Misses  uint64 // Number of elements that had to be allocated (instead of being reused)
+	Dropped uint64 // Number of returned / adopted elements dropped (cf. WithMaxRetainedSize / Adopt)
+	Shrunk  uint64 // Number of idle elements released by the shrink policy (cf. WithIdleShrink)
+
+	BytesInUse    int64 // Capacity of all elements currently retrieved from the pool
+	HighWaterMark int64 // Maximum capacity of elements retrieved from the pool at any time
+}
+
 // MemPoolLimit provides a channel-based memory buffer pool (limiting the number
 // of resources and allowing for cleanup)
 type MemPoolLimit struct {
-	elements chan []byte
+	memPoolElements
+	memPoolConfig
+	memPoolStats
 }
 
 // NewMemPool instantiates a new memory pool that manages bytes slices
-func NewMemPool(n int) *MemPoolLimit {
+func NewMemPool(n int, opts ...MemPoolOption) *MemPoolLimit {
 	obj := MemPoolLimit{
-		elements: make(chan []byte, n),
+		memPoolConfig: newMemPoolConfig(opts),
 	}
+	obj.init(n)
 	for i := 0; i < n; i++ {
 		obj.elements <- make([]byte, 0)
 	}
+	if obj.idleShrinkInterval > 0 {
+		go obj.shrinkEvery(obj.idleShrinkInterval)
+	}
 	return &obj
 }
 
-// Get retrieves a memory element (already performing the type assertion)
+// Get retrieves a memory element (already performing the type assertion). If the
+// pool has been cleared, an unpooled element is allocated (cf. GetSafe)
 func (p *MemPoolLimit) Get(size int) (elem []byte) {
-	elem = <-p.elements
-	if cap(elem) < size {
+	elem, err := p.GetSafe(size)
+	if err != nil {
+		elem = make([]byte, size)
+		p.recordGet(cap(elem), true)
+	}
+	return
+}
+
+// GetSafe retrieves a memory element (already performing the type assertion). In
+// contrast to Get, ErrPoolClosed is returned if the pool has been cleared (also
+// unblocking any call waiting for an element at that time)
+func (p *MemPoolLimit) GetSafe(size int) (elem []byte, err error) {
+	elem, ok := p.receive()
+	if !ok {
+		return nil, ErrPoolClosed
+	}
+	p.poisoner.verify(elem)
+	miss := cap(elem) < size
+	if miss {
 		elem = make([]byte, size*2)
 	}
 	elem = elem[:size]
-	return
+	p.recordGet(cap(elem), miss)
+	return elem, nil
 }
 
 // Put returns a memory element to the pool, resetting its size to capacity
-// in the process
+// in the process. If the pool has been cleared, the element is dropped (cf. PutSafe)
 func (p *MemPoolLimit) Put(elem []byte) {
-	elem = elem[:cap(elem)]
-	p.elements <- elem
+	_ = p.PutSafe(elem)
+}
+
+// PutSafe returns a memory element to the pool, resetting its size to capacity
+// in the process. In contrast to Put, ErrPoolClosed is returned if the pool has
+// been cleared
+func (p *MemPoolLimit) PutSafe(elem []byte) error {
+	if p.closed.Load() {
+		return ErrPoolClosed
+	}
+	p.recordPut(cap(elem))
+	p.put(elem)
+
+	return nil
 }
 
 // GetReadWriter return a wrapped element providing an io.ReadWriter
 func (p *MemPoolLimit) GetReadWriter(size int) *ReadWriter {
+	data := p.Get(size)
 	return &ReadWriter{
-		data: p.Get(size),
+		data:      data,
+		pooledCap: cap(data),
 	}
 }
 
 // PutReadWriter returns a wrapped element providing an io.ReadWriter to the pool
 func (p *MemPoolLimit) PutReadWriter(elem *ReadWriter) {
-	p.Put(elem.data)
+	elem.removeSpill()
+	if p.closed.Load() {
+		return
+	}
+	p.recordPut(elem.pooledCap)
+	p.put(elem.data)
+}
+
+// Adopt transfers ownership of an element not originating from the pool to it. Since the number of
+// elements is limited, the element replaces an idle one of smaller capacity (if any), otherwise it is
+// dropped
+func (p *MemPoolLimit) Adopt(elem []byte) {
+	if cap(elem) == 0 || p.closed.Load() {
+		return
+	}
+	if !p.retain(cap(elem)) {
+		p.recordDrop()
+		return
+	}
+
+	select {
+	case idle := <-p.elements:
+		if cap(idle) >= cap(elem) {
+			p.send(idle)
+			p.recordDrop()
+			return
+		}
+		p.poisoner.verify(idle) // untrack the replaced element
+
+		elem = elem[:cap(elem)]
+		p.poisoner.poison(elem)
+		p.send(elem)
+	default:
+		p.recordDrop()
+	}
+}
+
+func (p *MemPoolLimit) put(elem []byte) {
+
+	// Oversized elements are replaced by an empty one in order to retain the number of elements
+	if !p.retain(cap(elem)) {
+		p.recordDrop()
+		elem = make([]byte, 0)
+	}
+
+	elem = elem[:cap(elem)]
+	p.poisoner.poison(elem)
+	p.send(elem)
 }
 
-// Clear releases all pool resources and makes them available for garbage collection
+// Clear releases all pool resources and makes them available for garbage collection,
+// subsequent operations on the pool fail with ErrPoolClosed (cf. GetSafe / PutSafe)
 func (p *MemPoolLimit) Clear() {
-	p.elements = nil
+	p.close()
+}
+
+// shrinkEvery periodically releases idle elements until the pool is cleared (cf. WithIdleShrink)
+func (p *MemPoolLimit) shrinkEvery(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			p.recordShrink(p.shrink())
+		case <-p.done:
+			return
+		}
+	}
 }
 
+// Number of shards of the element tracker of a MemPoolLimitUnique
+const (
+	memPoolTrackerShardBits = 6
+	memPoolTrackerShards    = 1 << memPoolTrackerShardBits
+)
+
 // MemPoolLimitUnique provides a channel-based memory buffer pool (limiting the number
 // of resources, enforcing their uniqueness and allowing for cleanup)
 type MemPoolLimitUnique struct {
-	elements           chan []byte
-	tracker            map[uintptr]bool
+	tracker            [memPoolTrackerShards]memPoolTrackerShard
 	initialElementSize int
 
+	memPoolElements
+	memPoolStats
+}
+
+// memPoolTrackerShard tracks the state (taken / non-taken) of a subset of the elements of a
+// MemPoolLimitUnique, reducing lock contention under concurrent access
+type memPoolTrackerShard struct {
+	elements map[uintptr]bool
 	sync.Mutex
+
+	_ [48]byte // Padding to avoid false sharing between shards
 }
 
 // NewMemPoolLimitUnique instantiates a new memory pool that manages bytes slices
 func NewMemPoolLimitUnique(n int, initialElementSize int) *MemPoolLimitUnique {
 	obj := MemPoolLimitUnique{
-		elements:           make(chan []byte, n),
-		tracker:            make(map[uintptr]bool),
 		initialElementSize: initialElementSize,
 	}
+	for i := range obj.tracker {
+		obj.tracker[i].elements = make(map[uintptr]bool)
+	}
+	obj.init(n)
 	for i := 0; i < n; i++ {
 		elem := make([]byte, initialElementSize)
 
 		obj.elements <- elem
-		obj.tracker[slicePtr(elem)] = false // track as non-taken
+		obj.shard(slicePtr(elem)).elements[slicePtr(elem)] = false // track as non-taken
 	}
 
 	return &obj
 }
 
-// Get retrieves a memory element (already performing the type assertion)
+// Get retrieves a memory element (already performing the type assertion). If the
+// pool has been cleared, an unpooled element is allocated (cf. GetSafe)
 func (p *MemPoolLimitUnique) Get(size int) (elem []byte) {
+	elem, err := p.GetSafe(size)
+	if err != nil {
+		elem = make([]byte, size)
+		p.recordGet(cap(elem), true)
+	}
+	return
+}
 
-	elem = <-p.elements
+// GetSafe retrieves a memory element (already performing the type assertion). In
+// contrast to Get, ErrPoolClosed is returned if the pool has been cleared (also
+// unblocking any call waiting for an element at that time)
+func (p *MemPoolLimitUnique) GetSafe(size int) (elem []byte, err error) {
 
-	p.Lock()
-	if cap(elem) < size {
-		delete(p.tracker, slicePtr(elem))
+	elem, ok := p.receive()
+	if !ok {
+		return nil, ErrPoolClosed
+	}
+
+	// The element is exclusively owned at this point, hence replacing it (in case
+	// it is too small) does not require to lock both affected shards at once
+	miss := cap(elem) < size
+	if miss {
+		if !p.untrack(slicePtr(elem)) {
+			return nil, ErrPoolClosed
+		}
 		elem = make([]byte, size*2)
-		p.tracker[slicePtr(elem)] = false
 	}
-	p.tracker[slicePtr(elem)] = true // track as taken
-	p.Unlock()
+	if !p.track(slicePtr(elem), true) {
+		return nil, ErrPoolClosed
+	}
 
 	elem = elem[:size]
+	p.recordGet(cap(elem), miss)
 
-	return
+	return elem, nil
 }
 
 // Put returns a memory element to the pool, resetting its size to capacity
-// in the process
+// in the process (panics if the element is not tracked by the pool, cf. PutSafe).
+// If the pool has been cleared, the element is dropped
 func (p *MemPoolLimitUnique) Put(elem []byte) {
 
-	elem = elem[:cap(elem)]
-
-	p.Lock()
-	taken, exists := p.tracker[slicePtr(elem)]
-	if !exists {
+	// If the tracked element isn't taken this is probably a duplicate Put()
+	// operation and we ignore it to avoid potential deadlocks on the memory
+	// pool channel
+	if err := p.PutSafe(elem); errors.Is(err, ErrUntrackedElement) {
 		panic("cannot return untracked memory element to pool")
 	}
+}
 
-	p.tracker[slicePtr(elem)] = false // track as non-taken
-	p.Unlock()
+// PutSafe returns a memory element to the pool, resetting its size to capacity
+// in the process. In contrast to Put, an error is returned if the element is not
+// tracked by the pool (ErrUntrackedElement), has already been returned to it
+// (ErrElementNotTaken) or the pool has been cleared (ErrPoolClosed), in which
+// case the pool remains unmodified
+func (p *MemPoolLimitUnique) PutSafe(elem []byte) error {
 
-	// If the tracked element isn't taken this is probably a duplicate Put()
-	// operation and we ignore it to avoid potential deadlocks on the memory
-	// pool channel
+	elem = elem[:cap(elem)]
+	if len(elem) == 0 {
+		if p.closed.Load() {
+			return ErrPoolClosed
+		}
+		return ErrUntrackedElement
+	}
+
+	ptr := slicePtr(elem)
+	shard := p.shard(ptr)
+
+	shard.Lock()
+	if p.closed.Load() {
+		shard.Unlock()
+		return ErrPoolClosed
+	}
+	taken, exists := shard.elements[ptr]
+	if !exists {
+		shard.Unlock()
+		return ErrUntrackedElement
+	}
 	if !taken {
-		return
+		shard.Unlock()
+		return ErrElementNotTaken
 	}
 
-	p.elements <- elem
+	shard.elements[ptr] = false // track as non-taken
+	shard.Unlock()
+
+	p.recordPut(cap(elem))
+	p.send(elem)
+
+	return nil
 }
 
 // Resize resizes an element of the pool, updating its tracking information
-// in the process
+// in the process (panics if the element is not tracked by the pool, cf. ResizeSafe).
+// If the pool has been cleared, the element is resized without tracking
 func (p *MemPoolLimitUnique) Resize(elem []byte, size int) []byte {
-
-	p.Lock()
-	ptr := slicePtr(elem)
-	if _, exists := p.tracker[ptr]; !exists {
+	newElem, err := p.ResizeSafe(elem, size)
+	if errors.Is(err, ErrPoolClosed) {
+		if cap(elem) < size {
+			newElem = make([]byte, size)
+			copy(newElem, elem)
+			return newElem
+		}
+		return elem[:size]
+	}
+	if err != nil {
 		panic("cannot resize untracked memory element")
 	}
 
+	return newElem
+}
+
+// ResizeSafe resizes an element of the pool, updating its tracking information
+// in the process. In contrast to Resize, an error is returned if the element is
+// not tracked by the pool (ErrUntrackedElement) or the pool has been cleared
+// (ErrPoolClosed)
+func (p *MemPoolLimitUnique) ResizeSafe(elem []byte, size int) ([]byte, error) {
+
+	if cap(elem) == 0 {
+		if p.closed.Load() {
+			return nil, ErrPoolClosed
+		}
+		return nil, ErrUntrackedElement
+	}
+
+	ptr := slicePtr(elem[:cap(elem)])
+	shard := p.shard(ptr)
+
+	shard.Lock()
+	if p.closed.Load() {
+		shard.Unlock()
+		return nil, ErrPoolClosed
+	}
+	if _, exists := shard.elements[ptr]; !exists {
+		shard.Unlock()
+		return nil, ErrUntrackedElement
+	}
+
 	if cap(elem) < size {
+		delete(shard.elements, ptr)
+		shard.Unlock()
+
 		newElem := make([]byte, size)
 		copy(newElem, elem)
+		if !p.track(slicePtr(newElem), true) {
+			return nil, ErrPoolClosed
+		}
 
-		delete(p.tracker, ptr)
-		p.tracker[slicePtr(newElem)] = true
-		p.Unlock()
-		return newElem
+		// The resized element replaces the original one (which is not returned to the pool)
+		p.recordPut(cap(elem))
+		p.recordGet(cap(newElem), true)
+		return newElem, nil
 	}
 
-	elem = elem[:size]
-	p.tracker[ptr] = true
-	p.Unlock()
+	shard.elements[ptr] = true
+	shard.Unlock()
+
+	return elem[:size], nil
+}
+
+// Adopt transfers ownership of an element not originating from the pool to it, tracking it
+// accordingly. Since the number of elements is limited, the element replaces an idle one of smaller
+// capacity (if any), otherwise it is dropped
+func (p *MemPoolLimitUnique) Adopt(elem []byte) {
+	elem = elem[:cap(elem)]
+	if len(elem) == 0 || p.closed.Load() {
+		return
+	}
 
-	return elem
+	select {
+	case idle := <-p.elements:
+		if cap(idle) >= cap(elem) || p.tracked(slicePtr(elem)) {
+			p.send(idle)
+			p.recordDrop()
+			return
+		}
+		if !p.untrack(slicePtr(idle)) || !p.track(slicePtr(elem), false) {
+			return
+		}
+		p.send(elem)
+	default:
+		p.recordDrop()
+	}
 }
 
-// Clear releases all pool resources and makes them available for garbage collection
+// Clear releases all pool resources and makes them available for garbage collection,
+// subsequent operations on the pool fail with ErrPoolClosed (cf. GetSafe / PutSafe /
+// ResizeSafe)
 func (p *MemPoolLimitUnique) Clear() {
-	p.elements = nil
-	p.tracker = nil
+	if !p.close() {
+		return
+	}
+
+	for i := range p.tracker {
+		p.tracker[i].Lock()
+		p.tracker[i].elements = nil
+		p.tracker[i].Unlock()
+	}
+}
+
+// Lock locks all shards of the element tracker (blocking any operation requiring access to it until
+// Unlock is called). It is retained for compatibility with the previously embedded sync.Mutex
+func (p *MemPoolLimitUnique) Lock() {
+	for i := range p.tracker {
+		p.tracker[i].Lock()
+	}
+}
+
+// Unlock unlocks all shards of the element tracker (cf. Lock)
+func (p *MemPoolLimitUnique) Unlock() {
+	for i := len(p.tracker) - 1; i >= 0; i-- {
+		p.tracker[i].Unlock()
+	}
+}
+
+// shard returns the tracker shard responsible for the element with the provided pointer
+func (p *MemPoolLimitUnique) shard(ptr uintptr) *memPoolTrackerShard {
+
+	// Fibonacci hashing to spread (aligned) pointers evenly across shards
+	return &p.tracker[(uint64(ptr)*0x9E3779B97F4A7C15)>>(64-memPoolTrackerShardBits)]
+}
+
+// track sets the state of an element, returning false if the pool has been closed
+func (p *MemPoolLimitUnique) track(ptr uintptr, taken bool) bool {
+	shard := p.shard(ptr)
+
+	shard.Lock()
+	defer shard.Unlock()
+
+	if p.closed.Load() {
+		return false
+	}
+	shard.elements[ptr] = taken
+	return true
+}
+
+// tracked returns if an element is tracked by the pool
+func (p *MemPoolLimitUnique) tracked(ptr uintptr) bool {
+	shard := p.shard(ptr)
+
+	shard.Lock()
+	defer shard.Unlock()
+
+	_, exists := shard.elements[ptr]
+	return exists
+}
+
+// untrack removes an element, returning false if the pool has been closed
+func (p *MemPoolLimitUnique) untrack(ptr uintptr) bool {
+	shard := p.shard(ptr)
+
+	shard.Lock()
+	defer shard.Unlock()
+
+	if p.closed.Load() {
+		return false
+	}
+	delete(shard.elements, ptr)
+	return true
 }
 
 // MemPoolNoLimit wraps a standard sync.Pool (no limit to resources)
 type MemPoolNoLimit struct {
 	sync.Pool
+
+	memPoolConfig
+	memPoolStats
 }
 
 // NewMemPoolNoLimit instantiates a new memory pool that manages bytes slices
 // of arbitrary capacity
-func NewMemPoolNoLimit() *MemPoolNoLimit {
+func NewMemPoolNoLimit(opts ...MemPoolOption) *MemPoolNoLimit {
 	return &MemPoolNoLimit{
 		Pool: sync.Pool{
 			New: func() any {
 				return make([]byte, 0)
 			},
 		},
+		memPoolConfig: newMemPoolConfig(opts),
 	}
 }
 
 // Get retrieves a memory element (already performing the type assertion)
 func (p *MemPoolNoLimit) Get(size int) (elem []byte) {
 	elem = p.Pool.Get().([]byte)
-	if cap(elem) < size {
+	p.poisoner.verify(elem)
+	miss := cap(elem) < size
+	if miss {
 		elem = make([]byte, size*2)
 	}
 	elem = elem[:size]
+	p.recordGet(cap(elem), miss)
 	return
 }
 
 // Put returns a memory element to the pool, resetting its size to capacity
 // in the process
 func (p *MemPoolNoLimit) Put(elem []byte) {
+	p.recordPut(cap(elem))
+	p.put(elem)
+}
+
+// GetReadWriter returns a wrapped element providing an io.ReadWriter
+func (p *MemPoolNoLimit) GetReadWriter(size int) *ReadWriter {
+	data := p.Get(size)
+	return &ReadWriter{
+		data:      data,
+		pooledCap: cap(data),
+	}
+}
+
+// PutReadWriter returns a wrapped element providing an io.ReadWriter to the pool
+func (p *MemPoolNoLimit) PutReadWriter(elem *ReadWriter) {
+	elem.removeSpill()
+	p.recordPut(elem.pooledCap)
+	p.put(elem.data)
+}
+
+// Adopt transfers ownership of an element not originating from the pool to it
+func (p *MemPoolNoLimit) Adopt(elem []byte) {
+	p.put(elem)
+}
+
+func (p *MemPoolNoLimit) put(elem []byte) {
+	if !p.retain(cap(elem)) {
+		p.recordDrop()
+		return
+	}
 	elem = elem[:cap(elem)]
+	p.poisoner.poison(elem)
 
 	// nolint:staticcheck
 	p.Pool.Put(elem)
 }
 
+// Default size classes of a tiered memory pool (64 B - 64 MiB)
+const (
+	defaultMemPoolTieredMinSize = 1 << 6
+	defaultMemPoolTieredMaxSize = 1 << 26
+)
+
+// MemPoolTiered provides a memory buffer pool maintaining separate buckets (each wrapping a standard
+// sync.Pool) per power-of-two size class, ensuring that a requested element never exceeds twice the
+// requested size (i.e. small requests never receive and pin large elements). Elements exceeding the
+// largest size class are not pooled
+type MemPoolTiered struct {
+	minShift, maxShift int
+	buckets            []sync.Pool
+
+	memPoolConfig
+	memPoolStats
+}
+
+// NewMemPoolTiered instantiates a new tiered memory pool with size classes ranging from minSize to
+// maxSize (both rounded up to the next power of two, if zero, defaults to 64 B and 64 MiB, respectively)
+func NewMemPoolTiered(minSize, maxSize int, opts ...MemPoolOption) *MemPoolTiered {
+	if minSize <= 0 {
+		minSize = defaultMemPoolTieredMinSize
+	}
+	if maxSize <= 0 {
+		maxSize = defaultMemPoolTieredMaxSize
+	}
+	if maxSize < minSize {
+		maxSize = minSize
+	}
+
+	minShift, maxShift := sizeClass(minSize), sizeClass(maxSize)
+	return &MemPoolTiered{
+		minShift:      minShift,
+		maxShift:      maxShift,
+		buckets:       make([]sync.Pool, maxShift-minShift+1),
+		memPoolConfig: newMemPoolConfig(opts),
+	}
+}
+
+// Get retrieves a memory element from the bucket of the smallest size class fitting the requested size
+func (p *MemPoolTiered) Get(size int) (elem []byte) {
+	class := sizeClass(size)
+	if class < p.minShift {
+		class = p.minShift
+	}
+	if class > p.maxShift {
+		elem = make([]byte, size)
+		p.recordGet(cap(elem), true)
+		return
+	}
+
+	elemI := p.buckets[class-p.minShift].Get()
+	if elemI != nil {
+		elem = elemI.([]byte)
+		p.poisoner.verify(elem)
+	} else {
+		elem = make([]byte, 1<<class)
+	}
+	elem = elem[:size]
+	p.recordGet(cap(elem), elemI == nil)
+	return
+}
+
+// Put returns a memory element to the bucket of the largest size class not exceeding its capacity,
+// resetting its size to capacity in the process (elements outside of the range of size classes are
+// discarded)
+func (p *MemPoolTiered) Put(elem []byte) {
+	p.recordPut(cap(elem))
+	p.put(elem)
+}
+
 // GetReadWriter returns a wrapped element providing an io.ReadWriter
-func (p *MemPoolNoLimit) GetReadWriter(size int) *ReadWriter {
+func (p *MemPoolTiered) GetReadWriter(size int) *ReadWriter {
+	data := p.Get(size)
 	return &ReadWriter{
-		data: p.Get(size),
+		data:      data,
+		pooledCap: cap(data),
 	}
 }
 
 // PutReadWriter returns a wrapped element providing an io.ReadWriter to the pool
-func (p *MemPoolNoLimit) PutReadWriter(elem *ReadWriter) {
-	p.Put(elem.data)
+func (p *MemPoolTiered) PutReadWriter(elem *ReadWriter) {
+	elem.removeSpill()
+	p.recordPut(elem.pooledCap)
+	p.put(elem.data)
+}
+
+// Adopt transfers ownership of an element not originating from the pool to it
+func (p *MemPoolTiered) Adopt(elem []byte) {
+	p.put(elem)
+}
+
+func (p *MemPoolTiered) put(elem []byte) {
+	elem = elem[:cap(elem)]
+
+	class := bits.Len(uint(cap(elem))) - 1 // #nosec G115
+	if class < p.minShift {
+		return
+	}
+	if class > p.maxShift || !p.retain(cap(elem)) {
+		p.recordDrop()
+		return
+	}
+
+	p.poisoner.poison(elem)
+
+	// nolint:staticcheck
+	p.buckets[class-p.minShift].Put(elem)
+}
+
+// Maximum number of elements retained in the shared list of each shard of a sharded memory pool
+const memPoolShardedMaxElements = 64
+
+// MemPoolSharded provides a memory buffer pool without a limit to resources (cf. MemPoolNoLimit),
+// distributing its elements across one shard per GOMAXPROCS (P) in order to avoid cross-CPU traffic
+// on extremely hot paths. Each shard holds a private element only accessed by its P (without any
+// locking) and a bounded shared list other shards can take elements from if they run empty. In
+// contrast to the sync.Pool-based pools, retained elements are neither boxed upon return nor released
+// upon garbage collection (at most 64 + 1 per shard)
+type MemPoolSharded struct {
+	shards []memPoolShard
+
+	memPoolConfig
+	memPoolStats
+}
+
+type memPoolShard struct {
+	private []byte // Only accessed by the P owning the shard while pinned (cf. procPin)
+
+	shared [][]byte
+	sync.Mutex
+
+	_ [8]byte // Padding to avoid false sharing between shards
+}
+
+// NewMemPoolSharded instantiates a new sharded memory pool that manages bytes slices of arbitrary
+// capacity, using one shard per GOMAXPROCS (if GOMAXPROCS is increased later on, the additional
+// Ps share the existing shards without using their private elements)
+func NewMemPoolSharded(opts ...MemPoolOption) *MemPoolSharded {
+	obj := MemPoolSharded{
+		shards:        make([]memPoolShard, runtime.GOMAXPROCS(0)),
+		memPoolConfig: newMemPoolConfig(opts),
+	}
+	for i := range obj.shards {
+		obj.shards[i].shared = make([][]byte, 0, memPoolShardedMaxElements)
+	}
+	return &obj
+}
+
+// Get retrieves a memory element from the shard of the current P, falling back to the other shards
+// (without waiting for any of them) if it is empty
+func (p *MemPoolSharded) Get(size int) (elem []byte) {
+	pid := procPin()
+	if s := p.ownShard(pid); s != nil {
+		elem, s.private = s.private, nil
+	}
+	procUnpin()
+
+	if elem == nil {
+		elem = p.steal(pid)
+	}
+	if elem != nil {
+		p.poisoner.verify(elem)
+	}
+
+	miss := cap(elem) < size
+	if miss {
+		elem = make([]byte, size*2)
+	}
+	elem = elem[:size]
+	p.recordGet(cap(elem), miss)
+	return
+}
+
+// Put returns a memory element to the shard of the current P, resetting its size to capacity
+// in the process (the element is dropped if the shard is full)
+func (p *MemPoolSharded) Put(elem []byte) {
+	p.recordPut(cap(elem))
+	p.put(elem)
+}
+
+// GetReadWriter returns a wrapped element providing an io.ReadWriter
+func (p *MemPoolSharded) GetReadWriter(size int) *ReadWriter {
+	data := p.Get(size)
+	return &ReadWriter{
+		data:      data,
+		pooledCap: cap(data),
+	}
+}
+
+// PutReadWriter returns a wrapped element providing an io.ReadWriter to the pool
+func (p *MemPoolSharded) PutReadWriter(elem *ReadWriter) {
+	elem.removeSpill()
+	p.recordPut(elem.pooledCap)
+	p.put(elem.data)
+}
+
+// Adopt transfers ownership of an element not originating from the pool to it
+func (p *MemPoolSharded) Adopt(elem []byte) {
+	p.put(elem)
+}
+
+func (p *MemPoolSharded) put(elem []byte) {
+	if cap(elem) == 0 {
+		return
+	}
+	if !p.retain(cap(elem)) {
+		p.recordDrop()
+		return
+	}
+	elem = elem[:cap(elem)]
+	p.poisoner.poison(elem)
+
+	pid := procPin()
+	if s := p.ownShard(pid); s != nil && s.private == nil {
+		s.private = elem
+		procUnpin()
+		return
+	}
+	procUnpin()
+
+	if !p.shards[pid%len(p.shards)].push(elem) {
+		p.poisoner.verify(elem) // untrack the dropped element
+		p.recordDrop()
+	}
+}
+
+// ownShard returns the shard exclusively owned by the (pinned) P with the provided id, or nil if
+// there is none (if GOMAXPROCS was increased or private elements are disabled by the race detector,
+// which cannot observe the implicit synchronization of goroutines running on the same P)
+func (p *MemPoolSharded) ownShard(pid int) *memPoolShard {
+	if raceEnabled || pid >= len(p.shards) {
+		return nil
+	}
+	return &p.shards[pid]
+}
+
+// steal retrieves an element from the shared list of the shard of the P with the provided id or,
+// if it is empty, from any other shard that is not locked at the time
+func (p *MemPoolSharded) steal(pid int) []byte {
+	n := len(p.shards)
+	for i := 0; i < n; i++ {
+		if elem, found := p.shards[(pid+i)%n].pop(i == 0); found {
+			return elem
+		}
+	}
+	return nil
+}
+
+// pop retrieves an element from the shared list of the shard (if blocking is false, the shard is
+// skipped if locked)
+func (s *memPoolShard) pop(blocking bool) ([]byte, bool) {
+	if blocking {
+		s.Lock()
+	} else if !s.TryLock() {
+		return nil, false
+	}
+	defer s.Unlock()
+
+	n := len(s.shared)
+	if n == 0 {
+		return nil, false
+	}
+	elem := s.shared[n-1]
+	s.shared[n-1] = nil
+	s.shared = s.shared[:n-1]
+
+	return elem, true
+}
+
+// push adds an element to the shared list of the shard, returning false if it is full
+func (s *memPoolShard) push(elem []byte) bool {
+	s.Lock()
+	defer s.Unlock()
+
+	if len(s.shared) == cap(s.shared) {
+		return false
+	}
+	s.shared = append(s.shared, elem)
+
+	return true
+}
+
+// procPin pins the calling goroutine to its P (preventing preemption), returning the id of the P
+//
+//go:linkname procPin runtime.procPin
+func procPin() int
+
+// procUnpin unpins the calling goroutine (cf. procPin)
+//
+//go:linkname procUnpin runtime.procUnpin
+func procUnpin()
+
+// memPoolElements provides the elements of a channel-based memory pool, supporting to close
+// the pool (releasing all elements and unblocking any pending retrieval)
+type memPoolElements struct {
+	elements chan []byte
+	done     chan struct{}
+	closed   atomic.Bool
+
+	// Minimum number of idle elements since the last shrink (cf. WithIdleShrink)
+	minIdle atomic.Int64
+}
+
+func (e *memPoolElements) init(n int) {
+	e.elements, e.done = make(chan []byte, n), make(chan struct{})
+	e.minIdle.Store(int64(n))
+}
+
+// receive retrieves an element, returning false if the pool has been closed
+func (e *memPoolElements) receive() ([]byte, bool) {
+	select {
+	case elem := <-e.elements:
+		if e.closed.Load() {
+			return nil, false
+		}
+		e.trackIdle()
+		return elem, true
+	case <-e.done:
+		return nil, false
+	}
+}
+
+// send returns an element (which is dropped if the pool has been closed in the meantime)
+func (e *memPoolElements) send(elem []byte) {
+	e.elements <- elem
+	if e.closed.Load() {
+		e.drain()
+	}
+}
+
+// close closes the pool and releases all elements, returning false if the pool has already
+// been closed
+func (e *memPoolElements) close() bool {
+	if !e.closed.CompareAndSwap(false, true) {
+		return false
+	}
+	close(e.done)
+	e.drain()
+
+	return true
+}
+
+// trackIdle updates the minimum number of idle elements since the last shrink
+func (e *memPoolElements) trackIdle() {
+	idle := int64(len(e.elements))
+	for {
+		minIdle := e.minIdle.Load()
+		if idle >= minIdle || e.minIdle.CompareAndSwap(minIdle, idle) {
+			return
+		}
+	}
+}
+
+// shrink replaces all elements that have remained idle since the last shrink by empty ones (the
+// least recently returned elements being retrieved first), returning the number of released elements
+func (e *memPoolElements) shrink() (released int) {
+	n := e.minIdle.Swap(int64(len(e.elements)))
+	for i := int64(0); i < n; i++ {
+		select {
+		case elem := <-e.elements:
+			if cap(elem) > 0 {
+				released++
+			}
+			e.send(make([]byte, 0))
+		default:
+			return
+		}
+	}
+	return
+}
+
+func (e *memPoolElements) drain() {
+	for {
+		select {
+		case <-e.elements:
+		default:
+			return
+		}
+	}
+}
+
+// memPoolStats tracks the usage statistics of a memory pool. Elements are accounted for with the
+// capacity they were retrieved with (ReadWriters) or returned with (plain slices), hence the number
+// of bytes in use is approximate if plain slices are grown while in use
+type memPoolStats struct {
+	gets, puts, misses, dropped, shrunk atomic.Uint64
+	bytesInUse, highWaterMark           atomic.Int64
+}
+
+// Stats returns the usage statistics of the memory pool
+func (s *memPoolStats) Stats() MemPoolStats {
+	return MemPoolStats{
+		Gets:          s.gets.Load(),
+		Puts:          s.puts.Load(),
+		Misses:        s.misses.Load(),
+		Dropped:       s.dropped.Load(),
+		Shrunk:        s.shrunk.Load(),
+		BytesInUse:    s.bytesInUse.Load(),
+		HighWaterMark: s.highWaterMark.Load(),
+	}
+}
+
+func (s *memPoolStats) recordGet(size int, miss bool) {
+	s.gets.Add(1)
+	if miss {
+		s.misses.Add(1)
+	}
+
+	inUse := s.bytesInUse.Add(int64(size))
+	for {
+		hwm := s.highWaterMark.Load()
+		if inUse <= hwm || s.highWaterMark.CompareAndSwap(hwm, inUse) {
+			return
+		}
+	}
+}
+
+func (s *memPoolStats) recordPut(size int) {
+	s.puts.Add(1)
+
+	// Never drop below zero (in case a plain slice was grown while in use)
+	for {
+		inUse := s.bytesInUse.Load()
+		newInUse := inUse - int64(size)
+		if newInUse < 0 {
+			newInUse = 0
+		}
+		if s.bytesInUse.CompareAndSwap(inUse, newInUse) {
+			return
+		}
+	}
+}
+
+func (s *memPoolStats) recordDrop() {
+	s.dropped.Add(1)
+}
+
+func (s *memPoolStats) recordShrink(n int) {
+	s.shrunk.Add(uint64(n)) // #nosec G115
+}
+
+// sizeClass returns the exponent of the smallest power of two not smaller than size
+func sizeClass(size int) int {
+	if size <= 1 {
+		return 0
+	}
+	return bits.Len(uint(size - 1)) // #nosec G115
 }
 
 // Helper function to get the pointer to the first element in a slice, to be
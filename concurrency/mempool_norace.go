@@ -0,0 +1,6 @@
+//go:build !race
+
+package concurrency
+
+// raceEnabled denotes if the race detector is active
+const raceEnabled = false
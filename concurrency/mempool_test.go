@@ -1,9 +1,19 @@
 package concurrency
 
 import (
+	"bytes"
 	"compress/gzip"
+	"crypto/sha256"
+	"hash"
+	"hash/fnv"
 	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync"
 	"testing"
+	"testing/iotest"
+	"time"
 
 	"github.com/stretchr/testify/require"
 )
@@ -26,6 +36,8 @@ func TestReaderWriter(t *testing.T) {
 	for _, pool := range []MemPool{
 		NewMemPoolNoLimit(),
 		NewMemPool(2),
+		NewMemPoolTiered(0, 0),
+		NewMemPoolSharded(),
 	} {
 
 		maxTestInputLen := 0
@@ -68,3 +80,866 @@ func TestReaderWriter(t *testing.T) {
 		require.Zero(t, len(elem2.data))
 	}
 }
+
+func TestMemPoolTiered(t *testing.T) {
+	pool := NewMemPoolTiered(1024, 1024*1024)
+
+	// Elements are obtained from the smallest size class fitting the requested size
+	for _, cs := range []struct {
+		size, expectedCap int
+	}{
+		{0, 1024},
+		{1, 1024},
+		{1024, 1024},
+		{1025, 2048},
+		{1024 * 1024, 1024 * 1024},
+		{1024*1024 + 1, 1024*1024 + 1},
+	} {
+		elem := pool.Get(cs.size)
+		require.Len(t, elem, cs.size)
+		require.Equal(t, cs.expectedCap, cap(elem))
+		pool.Put(elem)
+	}
+
+	// A large element returned to the pool must never be handed out for a small request
+	pool.Put(make([]byte, 10*1024*1024))
+	pool.Put(make([]byte, 512*1024))
+	for i := 0; i < 10; i++ {
+		require.LessOrEqual(t, cap(pool.Get(1024)), 2*1024)
+	}
+
+	// Elements not matching a power of two are returned to the bucket of the next smaller size class
+	pool.Put(make([]byte, 3000))
+	elem := pool.Get(2048)
+	require.GreaterOrEqual(t, cap(elem), 2048)
+	require.Less(t, cap(elem), 4096)
+
+	// Chains can use the tiered pool
+	pool = NewMemPoolTiered(0, 0)
+	input := []byte("This is a test")
+	var output []byte
+	require.Nil(t, NewWriterChain().MemPool(pool).AddWriter(NewGZIPWriter()).PostFn(func(rw *ReadWriter) error {
+		return NewReaderChain(rw).MemPool(pool).AddReader(NewGZIPReader()).Build().DecodeAndClose(BytesDecoder, &output)
+	}).Build().EncodeAndClose(BytesEncoder, input))
+	require.Equal(t, input, output)
+}
+
+func TestMemPoolStats(t *testing.T) {
+	for _, pool := range []interface {
+		Get(size int) []byte
+		Put(elem []byte)
+		Stats() MemPoolStats
+	}{
+		NewMemPoolNoLimit(),
+		NewMemPool(2),
+		NewMemPoolLimitUnique(2, 256),
+		NewMemPoolTiered(0, 0),
+		NewMemPoolSharded(),
+	} {
+		require.Equal(t, MemPoolStats{}, pool.Stats())
+
+		elem := pool.Get(1024)
+		elem2 := pool.Get(512)
+		stats := pool.Stats()
+		require.Equal(t, uint64(2), stats.Gets)
+		require.Zero(t, stats.Puts)
+		require.Equal(t, uint64(2), stats.Misses)
+		require.GreaterOrEqual(t, stats.BytesInUse, int64(1024+512))
+		require.Equal(t, stats.BytesInUse, stats.HighWaterMark)
+
+		pool.Put(elem)
+		pool.Put(elem2)
+		stats = pool.Stats()
+		require.Equal(t, uint64(2), stats.Puts)
+		require.Zero(t, stats.BytesInUse)
+		require.GreaterOrEqual(t, stats.HighWaterMark, int64(1024+512))
+
+		// Elements grown while in use (e.g. ReadWriters) must not distort the accounting
+		rwPool, ok := pool.(MemPool)
+		if !ok {
+			continue
+		}
+		rw := rwPool.GetReadWriter(0)
+		_, err := rw.Write(make([]byte, 10*1024))
+		require.Nil(t, err)
+		require.LessOrEqual(t, pool.Stats().BytesInUse, int64(2*1024))
+		rwPool.PutReadWriter(rw)
+		require.Zero(t, pool.Stats().BytesInUse)
+	}
+}
+
+func TestMemPoolMaxRetainedSize(t *testing.T) {
+	for _, pool := range []interface {
+		MemPool
+		Stats() MemPoolStats
+	}{
+		NewMemPoolNoLimit(WithMaxRetainedSize(4096)),
+		NewMemPool(1, WithMaxRetainedSize(4096)),
+		NewMemPoolTiered(0, 0, WithMaxRetainedSize(4096)),
+		NewMemPoolSharded(WithMaxRetainedSize(4096)),
+	} {
+
+		// Elements within the threshold are retained
+		pool.Put(pool.Get(1024))
+		require.Zero(t, pool.Stats().Dropped)
+
+		// Elements grown beyond the threshold while in use are dropped
+		rw := pool.GetReadWriter(0)
+		_, err := rw.Write(make([]byte, 1024*1024))
+		require.Nil(t, err)
+		pool.PutReadWriter(rw)
+		require.Equal(t, uint64(1), pool.Stats().Dropped)
+
+		// Repeat test a couple of times to ensure no oversized element is handed out
+		for i := 0; i < 10; i++ {
+			elem := pool.Get(0)
+			require.LessOrEqual(t, cap(elem), 4096)
+			pool.Put(elem)
+		}
+	}
+}
+
+func TestMemPoolSharded(t *testing.T) {
+	pool := NewMemPoolSharded(WithPoisoning())
+	require.Equal(t, runtime.GOMAXPROCS(0), len(pool.shards))
+
+	// The number of retained elements is bounded, excess elements are dropped
+	nElems := len(pool.shards)*memPoolShardedMaxElements + 10
+	elems := make([][]byte, nElems)
+	for i := range elems {
+		elems[i] = pool.Get(1024)
+	}
+	for _, elem := range elems {
+		pool.Put(elem)
+	}
+	nRetained := 0
+	for i := range pool.shards {
+		nRetained += len(pool.shards[i].shared)
+		if pool.shards[i].private != nil {
+			nRetained++
+		}
+	}
+	require.GreaterOrEqual(t, nRetained, memPoolShardedMaxElements)
+	require.LessOrEqual(t, nRetained, len(pool.shards)*(memPoolShardedMaxElements+1))
+	require.Equal(t, uint64(nElems-nRetained), pool.Stats().Dropped)
+
+	// All retained elements can be retrieved again (from any shard)
+	for i := 0; i < nRetained; i++ {
+		elem := pool.Get(1024)
+		require.Equal(t, 2048, cap(elem))
+	}
+	require.Equal(t, uint64(nElems), pool.Stats().Misses)
+}
+
+func TestMemPoolOwnershipTransfer(t *testing.T) {
+	src, dst, limit := NewMemPool(1), NewMemPoolSharded(), NewMemPool(1)
+
+	// Detaching transfers the unread portion and updates the tracking of the originating pool
+	rw := src.GetReadWriter(0)
+	_, err := rw.Write([]byte("This is a test"))
+	require.Nil(t, err)
+	_, err = rw.Read(make([]byte, 5))
+	require.Nil(t, err)
+	data, err := rw.Detach()
+	require.Nil(t, err)
+	require.Equal(t, "is a test", string(data))
+	require.Empty(t, rw.Bytes())
+	src.PutReadWriter(rw)
+	require.Zero(t, src.Stats().BytesInUse)
+	elem := src.Get(0)
+	require.Zero(t, cap(elem))
+	src.Put(elem)
+
+	// All pools provided by this package support adoption
+	for _, pool := range []MemPool{NewMemPoolNoLimit(), NewMemPoolTiered(0, 0), limit} {
+		_, ok := pool.(MemPoolAdopter)
+		require.True(t, ok)
+	}
+	adopter, ok := MemPool(dst).(MemPoolAdopter)
+	require.True(t, ok)
+	adopter.Adopt(data)
+	require.Equal(t, cap(data), cap(dst.Get(0)))
+
+	// Spilled data is read back
+	rw = src.GetReadWriter(0)
+	rw.SpillToDisk(8, t.TempDir())
+	_, err = rw.Write([]byte("This is a test"))
+	require.Nil(t, err)
+	require.True(t, rw.Spilled())
+	data, err = rw.Detach()
+	require.Nil(t, err)
+	require.Equal(t, "This is a test", string(data))
+	require.False(t, rw.Spilled())
+	src.PutReadWriter(rw)
+
+	// Limited pools replace an idle element of smaller capacity, otherwise the element is dropped
+	limit.Adopt(make([]byte, 1024))
+	elem = limit.Get(512)
+	require.Equal(t, 1024, cap(elem))
+	require.Zero(t, limit.Stats().Misses)
+	limit.Adopt(make([]byte, 2048))
+	require.Equal(t, uint64(1), limit.Stats().Dropped)
+	limit.Put(elem)
+	limit.Adopt(make([]byte, 512))
+	require.Equal(t, uint64(2), limit.Stats().Dropped)
+
+	// Adopted elements are tracked by a MemPoolLimitUnique, replaced ones are not
+	unique := NewMemPoolLimitUnique(1, 16)
+	replaced := unique.Get(16)
+	require.Nil(t, unique.PutSafe(replaced))
+	unique.Adopt(make([]byte, 1024))
+	elem = unique.Get(512)
+	require.Equal(t, 1024, cap(elem))
+	require.Nil(t, unique.PutSafe(elem))
+	require.ErrorIs(t, unique.PutSafe(replaced), ErrUntrackedElement)
+	unique.Adopt(elem)
+	require.Equal(t, uint64(1), unique.Stats().Dropped)
+}
+
+func TestMemPoolLimitUniqueSafe(t *testing.T) {
+	pool := NewMemPoolLimitUnique(1, 16)
+
+	// Untracked elements must be reported (instead of panicking)
+	require.ErrorIs(t, pool.PutSafe(make([]byte, 16)), ErrUntrackedElement)
+	require.ErrorIs(t, pool.PutSafe(nil), ErrUntrackedElement)
+	_, err := pool.ResizeSafe(make([]byte, 16), 32)
+	require.ErrorIs(t, err, ErrUntrackedElement)
+	_, err = pool.ResizeSafe(nil, 32)
+	require.ErrorIs(t, err, ErrUntrackedElement)
+	require.Panics(t, func() { pool.Put(make([]byte, 16)) })
+	require.Panics(t, func() { pool.Resize(make([]byte, 16), 32) })
+
+	// Tracked elements can be resized and returned, duplicate returns are reported
+	elem := pool.Get(8)
+	elem, err = pool.ResizeSafe(elem, 64)
+	require.Nil(t, err)
+	require.Len(t, elem, 64)
+	require.Nil(t, pool.PutSafe(elem))
+	require.ErrorIs(t, pool.PutSafe(elem), ErrElementNotTaken)
+	require.NotPanics(t, func() { pool.Put(elem) })
+
+	require.Equal(t, 64, cap(pool.Get(0)))
+}
+
+func TestMemPoolLimitUniqueLock(t *testing.T) {
+	pool := NewMemPoolLimitUnique(1, 16)
+	var _ sync.Locker = pool
+
+	// Locking the pool blocks any operation requiring access to the element tracker
+	elem := pool.Get(16)
+	pool.Lock()
+	done := make(chan struct{})
+	go func() {
+		pool.Put(elem)
+		close(done)
+	}()
+	select {
+	case <-done:
+		t.Fatal("returning an element to a locked pool must block")
+	case <-time.After(10 * time.Millisecond):
+	}
+	pool.Unlock()
+	<-done
+	require.Equal(t, 16, cap(pool.Get(0)))
+}
+
+func TestMemPoolIdleShrink(t *testing.T) {
+	pool := NewMemPool(4, WithIdleShrink(time.Hour))
+	defer pool.Clear()
+
+	// All elements have been in use since creation of the pool, hence nothing is released
+	elems := make([][]byte, 4)
+	for i := range elems {
+		elems[i] = pool.Get(1024)
+	}
+	for _, elem := range elems {
+		pool.Put(elem)
+	}
+	require.Zero(t, pool.shrink())
+
+	// Only the single element in use is retained, all idle ones are released
+	pool.Put(pool.Get(1024))
+	require.Equal(t, 3, pool.shrink())
+	nRetained := 0
+	for i := range elems {
+		elems[i] = pool.Get(0)
+		if cap(elems[i]) > 0 {
+			nRetained++
+		}
+	}
+	require.Equal(t, 1, nRetained)
+	for _, elem := range elems {
+		pool.Put(elem)
+	}
+
+	// The background shrink policy eventually releases all idle elements
+	shrinkPool := NewMemPool(4, WithIdleShrink(10*time.Millisecond))
+	for i := range elems {
+		elems[i] = shrinkPool.Get(1024)
+	}
+	for _, elem := range elems {
+		shrinkPool.Put(elem)
+	}
+	require.Eventually(t, func() bool {
+		return shrinkPool.Stats().Shrunk == 4
+	}, time.Second, 10*time.Millisecond)
+	shrinkPool.Clear()
+}
+
+func TestMemPoolPoisoning(t *testing.T) {
+	for _, pool := range []MemPool{
+		NewMemPool(1, WithPoisoning()),
+		NewMemPoolNoLimit(WithPoisoning()),
+		NewMemPoolTiered(0, 0, WithPoisoning()),
+		NewMemPoolSharded(WithPoisoning()),
+	} {
+		// Returned elements are poisoned, regular use is unaffected
+		elem := pool.Get(64)
+		copy(elem, "This is a test")
+		pool.Put(elem)
+		require.Equal(t, bytes.Repeat([]byte{memPoolPoison}, cap(elem)), elem[:cap(elem)])
+		require.NotPanics(t, func() {
+			pool.Put(pool.Get(64))
+			pool.PutReadWriter(pool.GetReadWriter(64))
+		})
+	}
+
+	// Modifications after returning an element are detected upon its retrieval
+	pool := NewMemPool(1, WithPoisoning())
+	elem := pool.Get(64)
+	pool.Put(elem)
+	elem[10] = 0
+	require.PanicsWithValue(t, "memory element modified after being returned to pool (offset 10 of 128)", func() {
+		pool.Get(64)
+	})
+
+	// Returning an element twice is detected
+	pool = NewMemPool(2, WithPoisoning())
+	elem = pool.Get(64)
+	pool.Put(elem)
+	require.PanicsWithValue(t, "memory element returned to pool twice", func() {
+		pool.Put(elem)
+	})
+}
+
+func TestMemPoolClear(t *testing.T) {
+	limit, limitUnique := NewMemPool(1), NewMemPoolLimitUnique(1, 16)
+
+	// Retrievals blocked on an exhausted pool must be released upon Clear()
+	elem, elemUnique := limit.Get(8), limitUnique.Get(8)
+	errs := make(chan error, 2)
+	go func() {
+		_, err := limit.GetSafe(8)
+		errs <- err
+	}()
+	go func() {
+		_, err := limitUnique.GetSafe(8)
+		errs <- err
+	}()
+	limit.Clear()
+	limitUnique.Clear()
+	require.ErrorIs(t, <-errs, ErrPoolClosed)
+	require.ErrorIs(t, <-errs, ErrPoolClosed)
+
+	// Clearing is idempotent and all subsequent operations are well-defined
+	limit.Clear()
+	limitUnique.Clear()
+
+	require.ErrorIs(t, limit.PutSafe(elem), ErrPoolClosed)
+	require.ErrorIs(t, limitUnique.PutSafe(elemUnique), ErrPoolClosed)
+	_, err := limit.GetSafe(8)
+	require.ErrorIs(t, err, ErrPoolClosed)
+	_, err = limitUnique.GetSafe(8)
+	require.ErrorIs(t, err, ErrPoolClosed)
+	_, err = limitUnique.ResizeSafe(elemUnique, 32)
+	require.ErrorIs(t, err, ErrPoolClosed)
+
+	require.NotPanics(t, func() {
+		limit.Put(elem)
+		limitUnique.Put(elemUnique)
+		limit.PutReadWriter(limit.GetReadWriter(8))
+		require.Len(t, limit.Get(8), 8)
+		require.Len(t, limitUnique.Get(8), 8)
+		require.Len(t, limitUnique.Resize(elemUnique, 32), 32)
+	})
+	require.Empty(t, limit.elements)
+	require.Empty(t, limitUnique.elements)
+}
+
+func TestReadWriterReadFromWriteTo(t *testing.T) {
+	input := bytes.Repeat([]byte("This is a test"), 10000)
+
+	for _, threshold := range []int{0, 1000} {
+		rw := defaultMemPool.GetReadWriter(0)
+		rw.SpillToDisk(threshold, t.TempDir())
+
+		// Partially consumed buffers must retain their unread portion
+		_, err := rw.Write([]byte("prefix"))
+		require.Nil(t, err)
+		_, err = rw.Read(make([]byte, 3))
+		require.Nil(t, err)
+
+		n, err := rw.ReadFrom(iotest.HalfReader(bytes.NewReader(input)))
+		require.Nil(t, err)
+		require.Equal(t, int64(len(input)), n)
+		require.Equal(t, threshold > 0, rw.Spilled())
+
+		output := new(bytes.Buffer)
+		n, err = rw.WriteTo(output)
+		require.Nil(t, err)
+		require.Equal(t, int64(len(input)+3), n)
+		require.Equal(t, append([]byte("fix"), input...), output.Bytes())
+
+		// The buffer has been drained
+		n, err = rw.WriteTo(output)
+		require.Nil(t, err)
+		require.Zero(t, n)
+		defaultMemPool.PutReadWriter(rw)
+	}
+
+	// Errors are propagated
+	rw := defaultMemPool.GetReadWriter(0)
+	_, err := rw.ReadFrom(iotest.ErrReader(io.ErrClosedPipe))
+	require.ErrorIs(t, err, io.ErrClosedPipe)
+	_, err = rw.Write(input)
+	require.Nil(t, err)
+	_, err = rw.WriteTo(failingWriter{err: io.ErrClosedPipe})
+	require.ErrorIs(t, err, io.ErrClosedPipe)
+	defaultMemPool.PutReadWriter(rw)
+}
+
+func BenchmarkReadWriterCopy(b *testing.B) {
+	input := bytes.Repeat([]byte("This is a test"), 10000)
+	src := bytes.NewReader(input)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		src.Reset(input)
+		rw := defaultMemPool.GetReadWriter(0)
+		if _, err := io.Copy(rw, struct{ io.Reader }{src}); err != nil {
+			b.Fatal(err)
+		}
+		if _, err := io.Copy(io.Discard, rw); err != nil {
+			b.Fatal(err)
+		}
+		defaultMemPool.PutReadWriter(rw)
+	}
+}
+
+func TestReadWriterSeek(t *testing.T) {
+	input := []byte("0123456789")
+
+	for _, threshold := range []int{0, 5} {
+		rw := defaultMemPool.GetReadWriter(0)
+		rw.SpillToDisk(threshold, t.TempDir())
+		_, err := rw.Write(input)
+		require.Nil(t, err)
+		require.Equal(t, threshold > 0, rw.Spilled())
+
+		var _ io.ReadSeeker = rw
+		for _, cs := range []struct {
+			offset   int64
+			whence   int
+			expected string
+		}{
+			{3, io.SeekStart, "3456789"},
+			{-2, io.SeekEnd, "89"},
+			{-4, io.SeekCurrent, "6789"},
+			{0, io.SeekStart, "0123456789"},
+			{0, io.SeekEnd, ""},
+		} {
+			_, err = rw.Seek(cs.offset, cs.whence)
+			require.Nil(t, err)
+			res, err := io.ReadAll(rw)
+			require.Nil(t, err)
+			require.Equal(t, cs.expected, string(res))
+		}
+
+		// Invalid positions / whence values must be rejected (retaining the current position)
+		pos, err := rw.Seek(4, io.SeekStart)
+		require.Nil(t, err)
+		require.Equal(t, int64(4), pos)
+		for _, cs := range []struct {
+			offset int64
+			whence int
+		}{
+			{-1, io.SeekStart},
+			{11, io.SeekStart},
+			{1, io.SeekEnd},
+			{0, 42},
+		} {
+			pos, err = rw.Seek(cs.offset, cs.whence)
+			require.Error(t, err)
+			require.Equal(t, int64(4), pos)
+		}
+
+		defaultMemPool.PutReadWriter(rw)
+	}
+}
+
+func TestReadWriterLimitSize(t *testing.T) {
+	input := bytes.Repeat([]byte("This is a test"), 1000)
+
+	for _, threshold := range []int{0, 100} {
+
+		// Writes up to the maximum size succeed, any exceeding write fails
+		rw := defaultMemPool.GetReadWriter(0)
+		rw.SpillToDisk(threshold, t.TempDir())
+		rw.LimitSize(len(input))
+		_, err := rw.Write(input[:len(input)-1])
+		require.Nil(t, err)
+		_, err = rw.Write([]byte("xx"))
+		require.ErrorIs(t, err, ErrTooLarge)
+		_, err = rw.Write([]byte("x"))
+		require.Nil(t, err)
+
+		// Reading frees up space
+		_, err = rw.Read(make([]byte, 10))
+		require.Nil(t, err)
+		_, err = rw.Write(make([]byte, 10))
+		require.Nil(t, err)
+		defaultMemPool.PutReadWriter(rw)
+
+		// ReadFrom is limited as well (succeeding if the input fits exactly)
+		for _, limit := range []int{len(input) - 1, len(input)} {
+			rw = defaultMemPool.GetReadWriter(0)
+			rw.SpillToDisk(threshold, t.TempDir())
+			rw.LimitSize(limit)
+			_, err = rw.ReadFrom(iotest.HalfReader(bytes.NewReader(input)))
+			if limit < len(input) {
+				require.ErrorIs(t, err, ErrTooLarge)
+			} else {
+				require.Nil(t, err)
+				output, err := io.ReadAll(rw)
+				require.Nil(t, err)
+				require.Equal(t, input, output)
+			}
+			defaultMemPool.PutReadWriter(rw)
+		}
+	}
+
+	// Runaway encodings on a chain are stopped
+	_, err := NewWriterChain().LimitSize(100).Build().Encode(BytesEncoder, input)
+	require.ErrorIs(t, err, ErrTooLarge)
+	_, err = NewWriterChain().LimitSize(100).Build().Encode(JSONEncoder, input)
+	require.ErrorIs(t, err, ErrTooLarge)
+
+	var output []byte
+	require.Nil(t, NewWriterChain().LimitSize(len(input)).PostFn(func(rw *ReadWriter) error {
+		output = rw.BytesCopy()
+		return nil
+	}).Build().EncodeAndClose(BytesEncoder, input))
+	require.Equal(t, input, output)
+}
+
+func TestMemFileBuffer(t *testing.T) {
+	input := bytes.Repeat([]byte("This is a test"), 1000)
+
+	for _, pool := range []MemPool{
+		NewMemPoolNoLimit(),
+		NewMemPoolTiered(0, 0),
+		NewMemPool(1),
+	} {
+		mf := NewMemFileBuffer(pool)
+		for i := 0; i < len(input); i += 100 {
+			n, err := mf.Write(input[i : i+100])
+			require.Nil(t, err)
+			require.Equal(t, 100, n)
+		}
+		require.Equal(t, input, mf.Data())
+		stat, err := mf.Stat()
+		require.Nil(t, err)
+		require.Equal(t, int64(len(input)), stat.Size())
+
+		// Overwrite a section across the current end of the data and append after seeking to the end
+		_, err = mf.Seek(int64(len(input)-2), io.SeekStart)
+		require.Nil(t, err)
+		_, err = mf.Write([]byte("xxxx"))
+		require.Nil(t, err)
+		_, err = mf.Seek(int64(len(input)+2), io.SeekStart)
+		require.Nil(t, err)
+		_, err = mf.Write([]byte("yy"))
+		require.Nil(t, err)
+		expected := append(bytes.Clone(input[:len(input)-2]), "xxxxyy"...)
+		require.Equal(t, expected, mf.Data())
+
+		// Flush to a file and reload it
+		path := filepath.Join(t.TempDir(), "memfile")
+		require.Nil(t, mf.WriteFile(path, 0600))
+		require.Nil(t, mf.Close())
+
+		f, err := os.Open(filepath.Clean(path))
+		require.Nil(t, err)
+		mf, err = NewMemFile(f, pool)
+		require.Nil(t, err)
+		require.Equal(t, expected, mf.Data())
+
+		// A MemFile loaded from a file cannot grow
+		_, err = mf.Seek(int64(len(expected)-1), io.SeekStart)
+		require.Nil(t, err)
+		_, err = mf.Write([]byte("zz"))
+		require.Error(t, err)
+		require.Nil(t, mf.Close())
+	}
+}
+
+func TestMemFileReadAtWriteAt(t *testing.T) {
+	input := bytes.Repeat([]byte("0123456789"), 100)
+
+	// Pooled memory holding stale data must not leak into the gap of a growing write
+	pool := NewMemPoolNoLimit()
+	pool.Put(bytes.Repeat([]byte{0xFF}, 4096))
+
+	mf := NewMemFileBuffer(pool)
+	var (
+		_ io.ReaderAt = mf
+		_ io.WriterAt = mf
+	)
+
+	// Growing writes at arbitrary offsets (the gap being zero-filled)
+	_, err := mf.WriteAt(input[500:], 500)
+	require.Nil(t, err)
+	require.Len(t, mf.Data(), len(input))
+	require.Equal(t, make([]byte, 500), mf.Data()[:500])
+
+	// Concurrent writes / reads of disjoint ranges (not requiring growth)
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func(off int) {
+			defer wg.Done()
+			_, err := mf.WriteAt(input[off:off+100], int64(off))
+			require.Nil(t, err)
+		}(i * 100)
+	}
+	wg.Wait()
+	require.Equal(t, input, mf.Data())
+
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(off int) {
+			defer wg.Done()
+			buf := make([]byte, 100)
+			n, err := mf.ReadAt(buf, int64(off))
+			require.Nil(t, err)
+			require.Equal(t, 100, n)
+			require.Equal(t, input[off:off+100], buf)
+		}(i * 100)
+	}
+	wg.Wait()
+
+	// The position of the MemFile remains unaffected
+	buf := make([]byte, 10)
+	_, err = mf.Read(buf)
+	require.Nil(t, err)
+	require.Equal(t, input[:10], buf)
+
+	// Reads beyond the end of the data
+	n, err := mf.ReadAt(make([]byte, 20), int64(len(input)-10))
+	require.ErrorIs(t, err, io.EOF)
+	require.Equal(t, 10, n)
+	_, err = mf.ReadAt(buf, int64(len(input)))
+	require.ErrorIs(t, err, io.EOF)
+	_, err = mf.ReadAt(buf, -1)
+	require.Error(t, err)
+	_, err = mf.WriteAt(buf, -1)
+	require.Error(t, err)
+	require.Nil(t, mf.Close())
+
+	// A MemFile loaded from a file cannot grow
+	path := filepath.Join(t.TempDir(), "memfile")
+	require.Nil(t, os.WriteFile(path, input, 0600))
+	f, err := os.Open(filepath.Clean(path))
+	require.Nil(t, err)
+	mf, err = NewMemFile(f, NewMemPoolNoLimit())
+	require.Nil(t, err)
+	_, err = mf.WriteAt(buf, int64(len(input)-5))
+	require.Error(t, err)
+	_, err = mf.WriteAt(buf, int64(len(input)+5))
+	require.Error(t, err)
+	require.Nil(t, mf.Close())
+}
+
+func TestMemFileSync(t *testing.T) {
+	for _, opts := range [][]MemFileSyncOption{
+		nil,
+		{WithAtomicSync()},
+	} {
+		path := filepath.Join(t.TempDir(), "memfile")
+		require.Nil(t, os.WriteFile(path, []byte("This is a test"), 0600))
+
+		f, err := os.Open(filepath.Clean(path))
+		require.Nil(t, err)
+		mf, err := NewMemFile(f, NewMemPoolNoLimit())
+		require.Nil(t, err)
+
+		// Modify the content and persist it back to the originating file
+		_, err = mf.WriteAt([]byte("XXXX"), 10)
+		require.Nil(t, err)
+		require.Nil(t, mf.Sync(opts...))
+		require.Nil(t, mf.Close())
+
+		data, err := os.ReadFile(filepath.Clean(path))
+		require.Nil(t, err)
+		require.Equal(t, "This is a XXXX", string(data))
+		info, err := os.Stat(path)
+		require.Nil(t, err)
+		require.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+		// No temporary files must remain
+		entries, err := os.ReadDir(filepath.Dir(path))
+		require.Nil(t, err)
+		require.Len(t, entries, 1)
+	}
+
+	// A MemFile not originating from a named file cannot be synced
+	mf := NewMemFileBuffer(NewMemPoolNoLimit())
+	require.ErrorIs(t, mf.Sync(), ErrNoSourceFile)
+	require.ErrorIs(t, mf.Sync(WithAtomicSync()), ErrNoSourceFile)
+}
+
+func TestMemFileChecksum(t *testing.T) {
+	data := []byte("This is a test")
+	path := filepath.Join(t.TempDir(), "memfile")
+	require.Nil(t, os.WriteFile(path, data, 0600))
+
+	sum := sha256.Sum256(data)
+	fnvHash := fnv.New64a()
+	_, err := fnvHash.Write(data)
+	require.Nil(t, err)
+	fnvSum := fnvHash.Sum(nil)
+
+	newFNV := func() hash.Hash { return fnv.New64a() }
+
+	for _, cs := range []struct {
+		opt         MemFileOption
+		expectedErr error
+	}{
+		{WithChecksum(sum[:]), nil},
+		{WithChecksum(make([]byte, sha256.Size)), ErrChecksumMismatch},
+		{WithChecksumHash(newFNV, fnvSum), nil},
+		{WithChecksumHash(newFNV, sum[:]), ErrChecksumMismatch},
+	} {
+		f, err := os.Open(filepath.Clean(path))
+		require.Nil(t, err)
+
+		// The underlying file is closed in any case
+		mf, err := NewMemFile(f, NewMemPoolNoLimit(), cs.opt)
+		require.NotNil(t, f.Close())
+		if cs.expectedErr != nil {
+			require.ErrorIs(t, err, cs.expectedErr)
+			continue
+		}
+		require.Nil(t, err)
+		require.Equal(t, data, mf.Data())
+		require.Nil(t, mf.Close())
+	}
+
+	// A checksum option can be shared between concurrent calls
+	var (
+		opt = WithChecksum(sum[:])
+		wg  sync.WaitGroup
+	)
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			f, err := os.Open(filepath.Clean(path))
+			require.Nil(t, err)
+			mf, err := NewMemFile(f, NewMemPoolNoLimit(), opt)
+			require.Nil(t, err)
+			require.Equal(t, data, mf.Data())
+			require.Nil(t, mf.Close())
+		}()
+	}
+	wg.Wait()
+}
+
+func TestLazyMemFile(t *testing.T) {
+	data := make([]byte, 10000)
+	for i := range data {
+		data[i] = byte(i % 251)
+	}
+	path := filepath.Join(t.TempDir(), "lazymemfile")
+	require.Nil(t, os.WriteFile(path, data, 0600))
+
+	// A pool limited to the maximum number of resident windows must suffice
+	for _, pool := range []MemPool{NewMemPoolNoLimit(), NewMemPool(2)} {
+		f, err := os.Open(filepath.Clean(path))
+		require.Nil(t, err)
+		mf, err := NewLazyMemFile(f, pool, 1024, 2)
+		require.Nil(t, err)
+		require.Equal(t, int64(len(data)), mf.Size())
+		require.Zero(t, mf.NumResident())
+
+		// Random access spanning window boundaries only loads the required windows
+		buf := make([]byte, 100)
+		n, err := mf.ReadAt(buf, 1000)
+		require.Nil(t, err)
+		require.Equal(t, 100, n)
+		require.Equal(t, data[1000:1100], buf)
+		require.Equal(t, 2, mf.NumResident())
+
+		// The number of resident windows is capped, with the last (partial) window readable up to EOF
+		n, err = mf.ReadAt(buf, 9950)
+		require.ErrorIs(t, err, io.EOF)
+		require.Equal(t, 50, n)
+		require.Equal(t, data[9950:], buf[:n])
+		require.Equal(t, 2, mf.NumResident())
+
+		// Sequential reads and seeks
+		pos, err := mf.Seek(-200, io.SeekEnd)
+		require.Nil(t, err)
+		require.Equal(t, int64(9800), pos)
+		pos, err = mf.Seek(-9800, io.SeekCurrent)
+		require.Nil(t, err)
+		require.Zero(t, pos)
+		_, err = mf.Seek(-1, io.SeekStart)
+		require.NotNil(t, err)
+
+		read, err := io.ReadAll(iotest.OneByteReader(mf))
+		require.Nil(t, err)
+		require.Equal(t, data, read)
+		require.Equal(t, 2, mf.NumResident())
+
+		require.Nil(t, iotest.TestReader(io.NewSectionReader(mf, 0, mf.Size()), data))
+
+		require.Nil(t, mf.Close())
+		require.Zero(t, mf.NumResident())
+		require.NotNil(t, f.Close())
+	}
+}
+
+func BenchmarkMemPoolLimitUniqueParallel(b *testing.B) {
+	pool := NewMemPoolLimitUnique(64, 1024)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			pool.Put(pool.Get(512))
+		}
+	})
+}
+
+func BenchmarkMemPoolParallel(b *testing.B) {
+	for _, bench := range []struct {
+		name string
+		pool MemPool
+	}{
+		{"NoLimit", NewMemPoolNoLimit()},
+		{"Tiered", NewMemPoolTiered(0, 0)},
+		{"Sharded", NewMemPoolSharded()},
+	} {
+		b.Run(bench.name, func(b *testing.B) {
+			b.ReportAllocs()
+			b.ResetTimer()
+			b.RunParallel(func(pb *testing.PB) {
+				for pb.Next() {
+					bench.pool.Put(bench.pool.Get(1024))
+				}
+			})
+		})
+	}
+}
@@ -0,0 +1,170 @@
+package concurrency
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+
+	jsoniter "github.com/json-iterator/go"
+)
+
+// ErrExpectSliceOrChan denotes that the argument of an NDJSON decoder is neither a slice pointer nor
+// a channel
+var ErrExpectSliceOrChan = errors.New("expected slice pointer or channel argument")
+
+// Line-oriented (newline-delimited) JSON encoder / decoder functions
+var (
+	NDJSONEncoder = func(w io.Writer) Encoder {
+		return &ndjsonEncoder{
+			jsonEncoder: newJSONEncoder(w),
+		}
+	}
+	NDJSONDecoder = func(r io.Reader) Decoder {
+		return newNDJSONDecoder(r)
+	}
+)
+
+// ndjsonEncoder writes one JSON document per line for each element of a slice / array or each value
+// received from a channel (until it is closed). Any other value is written as single line
+type ndjsonEncoder struct {
+	*jsonEncoder
+}
+
+// Encode writes the JSON encoding of all elements of v to the underlying io.Writer (one per line),
+// flushing the output regularly (or after each element received from a channel) instead of
+// buffering it as a whole
+func (e *ndjsonEncoder) Encode(v any) error {
+	val := reflect.ValueOf(v)
+
+	switch val.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < val.Len(); i++ {
+			if err := e.writeLine(val.Index(i).Interface(), false); err != nil {
+				return err
+			}
+		}
+	case reflect.Chan:
+		if val.Type().ChanDir()&reflect.RecvDir == 0 {
+			return ErrExpectSliceOrChan
+		}
+		for {
+			elem, ok := val.Recv()
+			if !ok {
+				break
+			}
+			if err := e.writeLine(elem.Interface(), true); err != nil {
+				return err
+			}
+		}
+	default:
+		if err := e.writeLine(v, false); err != nil {
+			return err
+		}
+	}
+
+	if err := e.stream.Flush(); err != nil {
+		return err
+	}
+	return e.stream.Error
+}
+
+func (e *ndjsonEncoder) release() {
+	e.jsonEncoder.release()
+	e.jsonEncoder = nil
+}
+
+func (e *ndjsonEncoder) writeLine(v any, flush bool) error {
+	e.stream.WriteVal(v)
+	e.stream.WriteRaw("\n")
+	if flush || e.stream.Buffered() >= jsonBufSize {
+		if err := e.stream.Flush(); err != nil {
+			return err
+		}
+	}
+
+	return e.stream.Error
+}
+
+// ndjsonDecoder reads one JSON document per line, appending all decoded values to a slice or sending
+// them to a channel (which is not closed). Empty lines are skipped
+type ndjsonDecoder struct {
+	r    *bufio.Reader
+	line []byte
+	n    int
+}
+
+func newNDJSONDecoder(r io.Reader) *ndjsonDecoder {
+	br, ok := r.(*bufio.Reader)
+	if !ok {
+		br = bufio.NewReader(r)
+	}
+
+	return &ndjsonDecoder{
+		r: br,
+	}
+}
+
+// Decode reads all lines from the underlying io.Reader, decoding each into a new element of the
+// slice pointed to by v (or sending it to the channel v)
+func (d *ndjsonDecoder) Decode(v any) error {
+	val := reflect.ValueOf(v)
+
+	var (
+		elemType reflect.Type
+		emit     func(elem reflect.Value)
+	)
+	switch {
+	case val.Kind() == reflect.Pointer && val.Elem().Kind() == reflect.Slice:
+		slice := val.Elem()
+		elemType = slice.Type().Elem()
+		emit = func(elem reflect.Value) {
+			slice.Set(reflect.Append(slice, elem))
+		}
+	case val.Kind() == reflect.Chan && val.Type().ChanDir()&reflect.SendDir != 0:
+		elemType = val.Type().Elem()
+		emit = val.Send
+	default:
+		return ErrExpectSliceOrChan
+	}
+
+	for {
+		line, err := d.readLine()
+		if err != nil && !errors.Is(err, io.EOF) {
+			return err
+		}
+
+		if line = bytes.TrimSpace(line); len(line) > 0 {
+			elem := reflect.New(elemType)
+			if uErr := jsoniter.ConfigDefault.Unmarshal(line, elem.Interface()); uErr != nil {
+				return fmt.Errorf("failed to decode line %d: %w", d.n, uErr)
+			}
+			emit(elem.Elem())
+		}
+
+		if err != nil {
+			return nil
+		}
+	}
+}
+
+func (d *ndjsonDecoder) readLine() ([]byte, error) {
+	d.line = d.line[:0]
+	d.n++
+
+	for {
+		chunk, err := d.r.ReadSlice('\n')
+		if errors.Is(err, bufio.ErrBufferFull) {
+			d.line = append(d.line, chunk...)
+			continue
+		}
+		if len(d.line) > 0 {
+			d.line = append(d.line, chunk...)
+			chunk = d.line
+		}
+
+		return chunk, err
+	}
+}
@@ -0,0 +1,67 @@
+package concurrency
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNDJSON(t *testing.T) {
+	input := make([]testStruct, 100)
+	for i := range input {
+		input[i] = testStruct{Name: strings.Repeat("x", i), Value: i}
+	}
+
+	// From a slice
+	var encoded []byte
+	require.Nil(t, NewWriterChain().AddWriter(NewGZIPWriter()).PostFn(func(rw *ReadWriter) error {
+		encoded = bytes.Clone(rw.Bytes())
+		return nil
+	}).Build().EncodeAndClose(NDJSONEncoder, input))
+
+	var output []testStruct
+	require.Nil(t, NewReaderChain(bytes.NewReader(encoded)).AddReader(NewGZIPReader()).Build().
+		DecodeAndClose(NDJSONDecoder, &output))
+	require.Equal(t, input, output)
+
+	// From a channel (into a channel)
+	ch := make(chan testStruct)
+	go func() {
+		for _, v := range input {
+			ch <- v
+		}
+		close(ch)
+	}()
+	raw := bytes.NewBuffer(nil)
+	require.Nil(t, NewWriterChain().Destination(raw).Build().EncodeAndClose(NDJSONEncoder, ch))
+	require.Equal(t, len(input), strings.Count(raw.String(), "\n"))
+
+	res := make(chan testStruct, len(input))
+	require.Nil(t, NewReaderChain(raw).Build().DecodeAndClose(NDJSONDecoder, res))
+	close(res)
+	output = output[:0]
+	for v := range res {
+		output = append(output, v)
+	}
+	require.Equal(t, input, output)
+
+	// Empty lines, missing trailing newline and long lines
+	long := strings.Repeat("y", 10000)
+	var lines []testStruct
+	require.Nil(t, NDJSONDecoder(strings.NewReader("\n{\"Name\":\"a\"}\n\n  \n{\"Name\":\""+long+"\",\"Value\":2}")).Decode(&lines))
+	require.Equal(t, []testStruct{{Name: "a"}, {Name: long, Value: 2}}, lines)
+
+	// Single values
+	raw.Reset()
+	require.Nil(t, NewWriterChain().Destination(raw).Build().EncodeAndClose(NDJSONEncoder, input[1]))
+	require.Equal(t, `{"Name":"x","Value":1}`+"\n", raw.String())
+
+	// Invalid arguments / data
+	require.ErrorIs(t, NDJSONEncoder(raw).Encode(make(chan<- int)), ErrExpectSliceOrChan)
+	require.ErrorIs(t, NDJSONDecoder(raw).Decode(lines), ErrExpectSliceOrChan)
+	require.ErrorIs(t, NDJSONDecoder(raw).Decode(make(<-chan int)), ErrExpectSliceOrChan)
+	err := NDJSONDecoder(strings.NewReader("{\"Name\":\"a\"}\n{invalid\n")).Decode(&lines)
+	require.ErrorContains(t, err, "line 2")
+}
@@ -0,0 +1,251 @@
+package concurrency
+
+import (
+	"bytes"
+	"encoding/binary"
+	"hash/crc32"
+	"io"
+	"runtime"
+	"sync"
+
+	"github.com/klauspost/compress/flate"
+)
+
+const (
+	// DefaultParallelGZIPBlockSize denotes the default (uncompressed) size of the blocks compressed
+	// independently by a ParallelGZIPWriter
+	DefaultParallelGZIPBlockSize = 1 << 20
+
+	// Size of the dictionary passed on from one block to the next (maximum deflate window size)
+	pgzipDictSize = 32 * 1024
+)
+
+var (
+	flateWPool sync.Pool
+
+	// Minimal gzip header (no name / comment / modification time, deflate, unknown OS)
+	pgzipHeader = []byte{0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0xff}
+
+	// Empty stored deflate block marking the end of the stream (following a sync flush)
+	pgzipFinalBlock = []byte{0x01, 0x00, 0x00, 0xff, 0xff}
+)
+
+// ParallelGZIPWriter provides a Writer stage compressing independent blocks of the stream on multiple
+// goroutines (bounded by a Semaphore), stitching them together into a single, standards-compliant gzip
+// stream (each block using the tail of its predecessor as dictionary in order to retain the compression
+// ratio). Memory usage is bounded to roughly twice the block size per worker
+type ParallelGZIPWriter struct {
+	blockSize int
+	sem       Semaphore
+
+	w             io.Writer
+	buf           []byte
+	queue         []*pgzipBlock
+	written       []byte
+	crc           uint32
+	size          uint32
+	headerWritten bool
+	err           error
+}
+
+// NewParallelGZIPWriter initializes a new parallel gzip Writer stage using the provided block size (in
+// bytes) and maximum number of concurrent workers (if zero, defaults to DefaultParallelGZIPBlockSize and
+// the number of available CPUs, respectively), fulfilling the Writer interface
+func NewParallelGZIPWriter(blockSize, workers int) *ParallelGZIPWriter {
+	if blockSize <= 0 {
+		blockSize = DefaultParallelGZIPBlockSize
+	}
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+
+	return &ParallelGZIPWriter{
+		blockSize: blockSize,
+		sem:       New(workers),
+	}
+}
+
+// Init resets the parallel gzip Writer stage for (re-)use, writing to the provided io.Writer
+func (p *ParallelGZIPWriter) Init(w io.Writer) io.Writer {
+	p.w, p.buf = w, defaultMemPool.Get(p.blockSize)[:0]
+	p.crc, p.size, p.headerWritten, p.err = 0, 0, false, nil
+
+	return p
+}
+
+// Write buffers len(p) bytes, dispatching a block for compression each time the block size is reached
+func (p *ParallelGZIPWriter) Write(data []byte) (n int, err error) {
+	if p.err != nil {
+		return 0, p.err
+	}
+
+	for len(data) > 0 {
+		nCopied := copy(p.buf[len(p.buf):p.blockSize], data)
+		p.buf = p.buf[:len(p.buf)+nCopied]
+		n += nCopied
+		data = data[nCopied:]
+
+		if len(p.buf) == p.blockSize {
+			if p.err = p.dispatch(); p.err != nil {
+				return n, p.err
+			}
+		}
+	}
+
+	return
+}
+
+// Flush compresses the buffered (partial) block and waits for all pending blocks to be written to the
+// underlying io.Writer
+func (p *ParallelGZIPWriter) Flush() error {
+	if p.err == nil && len(p.buf) > 0 {
+		p.err = p.dispatch()
+	}
+
+	// Wait for all pending blocks (even in case of an error, since they hold references to buffers)
+	for len(p.queue) > 0 {
+		if err := p.writeHead(); err != nil && p.err == nil {
+			p.err = err
+		}
+	}
+
+	return p.err
+}
+
+// Close compresses the remaining data, waits for all blocks to be written and writes the gzip trailer
+// (the underlying io.Writer is not closed)
+func (p *ParallelGZIPWriter) Close() error {
+	if err := p.Flush(); err != nil {
+		return err
+	}
+
+	if err := p.writeHeader(); err != nil {
+		return err
+	}
+	trailer := make([]byte, 0, len(pgzipFinalBlock)+8)
+	trailer = append(trailer, pgzipFinalBlock...)
+	trailer = binary.LittleEndian.AppendUint32(trailer, p.crc)
+	trailer = binary.LittleEndian.AppendUint32(trailer, p.size)
+	_, p.err = p.w.Write(trailer)
+
+	return p.err
+}
+
+// Return returns all internal buffers to the pool
+func (p *ParallelGZIPWriter) Return() {
+	for _, buf := range [][]byte{p.buf, p.written} {
+		if buf != nil {
+			defaultMemPool.Put(buf)
+		}
+	}
+	p.buf, p.written, p.w = nil, nil, nil
+}
+
+////////////////////////////////////////////////////////////////////////////////////////
+
+type pgzipBlock struct {
+	in, dict []byte
+	out      *bytes.Buffer
+	done     chan struct{}
+	err      error
+}
+
+func (blk *pgzipBlock) compress() {
+	defer close(blk.done)
+
+	var fw *flate.Writer
+	if fwI := flateWPool.Get(); fwI == nil {
+
+		// Creating a writer can only fail for an invalid compression level, hence the error can be ignored
+		fw, _ = flate.NewWriterDict(blk.out, flate.DefaultCompression, blk.dict)
+	} else {
+		fw = fwI.(*flate.Writer)
+		fw.ResetDict(blk.out, blk.dict)
+	}
+
+	// Flush (instead of Close) terminates the block without marking the end of the stream
+	if _, blk.err = fw.Write(blk.in); blk.err == nil {
+		blk.err = fw.Flush()
+	}
+	fw.ResetDict(nil, nil)
+	flateWPool.Put(fw)
+}
+
+func (p *ParallelGZIPWriter) dispatch() error {
+	blk := &pgzipBlock{
+		in:   p.buf,
+		out:  bytes.NewBuffer(defaultMemPool.Get(0)),
+		done: make(chan struct{}),
+	}
+
+	// The predecessor of the block is either the last pending or the last written block
+	prev := p.written
+	if len(p.queue) > 0 {
+		prev = p.queue[len(p.queue)-1].in
+	}
+	if len(prev) > pgzipDictSize {
+		prev = prev[len(prev)-pgzipDictSize:]
+	}
+	blk.dict = prev
+
+	p.crc = crc32.Update(p.crc, crc32.IEEETable, blk.in)
+	p.size += uint32(len(blk.in)) // #nosec G115 -- ISIZE is defined modulo 2^32
+
+	p.sem.Add()
+	go func() {
+		defer p.sem.Done()
+		blk.compress()
+	}()
+	p.queue = append(p.queue, blk)
+	p.buf = defaultMemPool.Get(p.blockSize)[:0]
+
+	// Write all blocks that have already been compressed and limit the number of pending blocks
+	for len(p.queue) > 0 {
+		if len(p.queue) <= cap(p.sem) {
+			select {
+			case <-p.queue[0].done:
+			default:
+				return nil
+			}
+		}
+		if err := p.writeHead(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (p *ParallelGZIPWriter) writeHead() error {
+	blk := p.queue[0]
+	<-blk.done
+	p.queue[0] = nil
+	p.queue = p.queue[1:]
+
+	// The input of the previously written block is no longer required as dictionary
+	if p.written != nil {
+		defaultMemPool.Put(p.written)
+	}
+	p.written = blk.in
+	defer defaultMemPool.Put(blk.out.Bytes())
+
+	if blk.err != nil {
+		return blk.err
+	}
+	if err := p.writeHeader(); err != nil {
+		return err
+	}
+	_, err := p.w.Write(blk.out.Bytes())
+
+	return err
+}
+
+func (p *ParallelGZIPWriter) writeHeader() error {
+	if p.headerWritten {
+		return nil
+	}
+	p.headerWritten = true
+	_, err := p.w.Write(pgzipHeader)
+
+	return err
+}
@@ -0,0 +1,89 @@
+package concurrency
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParallelGZIPWriter(t *testing.T) {
+	rnd := rand.New(rand.NewSource(42))
+	words := []string{"alpha", "beta", "gamma", "delta", "epsilon", "\n"}
+
+	for _, size := range []int{0, 1, 999, 1000, 1001, 25000} {
+		for _, workers := range []int{1, 4} {
+			t.Run(fmt.Sprintf("%d_%d", size, workers), func(t *testing.T) {
+				input := bytes.NewBuffer(nil)
+				for input.Len() < size {
+					input.WriteString(words[rnd.Intn(len(words))])
+				}
+				input.Truncate(size)
+
+				// Repeat to exercise reuse of the stage
+				pgz := NewParallelGZIPWriter(1000, workers)
+				for i := 0; i < 3; i++ {
+					buf := bytes.NewBuffer(nil)
+					require.Nil(t, NewWriterChain().AddWriter(pgz).Destination(buf).Build().
+						EncodeAndClose(BytesEncoder, input.Bytes()))
+
+					// Decode using the standard library (single gzip member)
+					gr, err := gzip.NewReader(bytes.NewReader(buf.Bytes()))
+					require.Nil(t, err)
+					gr.Multistream(false)
+					output, err := io.ReadAll(gr)
+					require.Nil(t, err)
+					require.Equal(t, input.String(), string(output))
+					require.Nil(t, gr.Close())
+
+					// Decode using a chain
+					var res []byte
+					require.Nil(t, NewReaderChain(buf).AddReader(NewGZIPReader()).Build().
+						DecodeAndClose(BytesDecoder, &res))
+					require.Equal(t, input.String(), string(res))
+				}
+			})
+		}
+	}
+}
+
+func TestParallelGZIPWriterErrors(t *testing.T) {
+	errWrite := errors.New("write failed")
+	input := bytes.Repeat([]byte("This is a test"), 1000)
+
+	wc := NewWriterChain().AddWriter(NewParallelGZIPWriter(100, 2)).Destination(failingWriter{err: errWrite}).Build()
+	_, err := wc.Encode(BytesEncoder, input)
+	require.Error(t, err)
+	require.ErrorIs(t, wc.Close(), errWrite)
+}
+
+func BenchmarkParallelGZIPWriter(b *testing.B) {
+	input := make([]byte, 8<<20)
+	rnd := rand.New(rand.NewSource(42))
+	for i := range input {
+		input[i] = byte(rnd.Intn(16))
+	}
+
+	for _, cs := range []struct {
+		name   string
+		writer func() Writer
+	}{
+		{"gzip", func() Writer { return NewGZIPWriter() }},
+		{"parallel", func() Writer { return NewParallelGZIPWriter(0, 0) }},
+	} {
+		b.Run(cs.name, func(b *testing.B) {
+			w := cs.writer()
+			b.SetBytes(int64(len(input)))
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				_ = NewWriterChain().AddWriter(w).Destination(io.Discard).Build().EncodeAndClose(BytesEncoder, input)
+			}
+		})
+	}
+}
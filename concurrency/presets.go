@@ -0,0 +1,101 @@
+package concurrency
+
+import "io"
+
+// PresetWriterChain provides a (built) chain of Writers bound to a specific encoder function, covering
+// common combinations of stages and encodings in a single call (cf. GZIPJSONWriterChain). All presets
+// use the shared default memory pool
+type PresetWriterChain struct {
+	*WriterChain
+
+	fn EncoderFn
+}
+
+// NewPresetWriterChain builds a chain of Writers using the provided stage (may be nil) and binds it to
+// the provided encoder function
+func NewPresetWriterChain(stage Writer, fn EncoderFn) *PresetWriterChain {
+	wc := NewWriterChain().MemPool(defaultMemPool)
+	if stage != nil {
+		wc.AddWriter(stage)
+	}
+
+	return &PresetWriterChain{
+		WriterChain: wc.Build(),
+		fn:          fn,
+	}
+}
+
+// GZIPJSONWriterChain builds a chain of Writers encoding JSON and compressing it using gzip
+func GZIPJSONWriterChain() *PresetWriterChain {
+	return NewPresetWriterChain(NewGZIPWriter(), JSONEncoder)
+}
+
+// ZSTDJSONWriterChain builds a chain of Writers encoding JSON and compressing it using zstd
+func ZSTDJSONWriterChain() *PresetWriterChain {
+	return NewPresetWriterChain(NewZSTDWriter(), JSONEncoder)
+}
+
+// SnappyJSONWriterChain builds a chain of Writers encoding JSON and compressing it using Snappy
+func SnappyJSONWriterChain() *PresetWriterChain {
+	return NewPresetWriterChain(NewSnappyWriter(), JSONEncoder)
+}
+
+// Encode encodes an object using the bound encoder function (cf. WriterChain.Encode)
+func (pc *PresetWriterChain) Encode(v any) (*ReadWriter, error) {
+	return pc.WriterChain.Encode(pc.fn, v)
+}
+
+// EncodeAndClose encodes an object using the bound encoder function and closes / flushes all Writers
+// in the chain (cf. WriterChain.EncodeAndClose)
+func (pc *PresetWriterChain) EncodeAndClose(v any) error {
+	return pc.WriterChain.EncodeAndClose(pc.fn, v)
+}
+
+// PresetReaderChain provides a (built) chain of Readers bound to a specific decoder function, covering
+// common combinations of stages and encodings in a single call (cf. GZIPJSONReaderChain). All presets
+// use the shared default memory pool
+type PresetReaderChain struct {
+	*ReaderChain
+
+	fn DecoderFn
+}
+
+// NewPresetReaderChain builds a chain of Readers from the provided io.Reader using the provided stage
+// (may be nil) and binds it to the provided decoder function
+func NewPresetReaderChain(r io.Reader, stage Reader, fn DecoderFn) *PresetReaderChain {
+	rc := NewReaderChain(r).MemPool(defaultMemPool)
+	if stage != nil {
+		rc.AddReader(stage)
+	}
+
+	return &PresetReaderChain{
+		ReaderChain: rc.Build(),
+		fn:          fn,
+	}
+}
+
+// GZIPJSONReaderChain builds a chain of Readers decompressing gzip and decoding JSON
+func GZIPJSONReaderChain(r io.Reader) *PresetReaderChain {
+	return NewPresetReaderChain(r, NewGZIPReader(), JSONDecoder)
+}
+
+// ZSTDJSONReaderChain builds a chain of Readers decompressing zstd and decoding JSON
+func ZSTDJSONReaderChain(r io.Reader) *PresetReaderChain {
+	return NewPresetReaderChain(r, NewZSTDReader(), JSONDecoder)
+}
+
+// SnappyJSONReaderChain builds a chain of Readers decompressing Snappy and decoding JSON
+func SnappyJSONReaderChain(r io.Reader) *PresetReaderChain {
+	return NewPresetReaderChain(r, NewSnappyReader(), JSONDecoder)
+}
+
+// Decode decodes an object using the bound decoder function (cf. ReaderChain.Decode)
+func (pc *PresetReaderChain) Decode(v any) error {
+	return pc.ReaderChain.Decode(pc.fn, v)
+}
+
+// DecodeAndClose decodes an object using the bound decoder function and closes all Readers in the
+// chain (cf. ReaderChain.DecodeAndClose)
+func (pc *PresetReaderChain) DecodeAndClose(v any) error {
+	return pc.ReaderChain.DecodeAndClose(pc.fn, v)
+}
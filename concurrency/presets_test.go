@@ -0,0 +1,45 @@
+package concurrency
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPresetChains(t *testing.T) {
+	input := testStruct{Name: "preset", Value: 42}
+
+	for _, preset := range []struct {
+		writer func() *PresetWriterChain
+		reader func(rw *ReadWriter) *PresetReaderChain
+	}{
+		{GZIPJSONWriterChain, func(rw *ReadWriter) *PresetReaderChain { return GZIPJSONReaderChain(rw) }},
+		{ZSTDJSONWriterChain, func(rw *ReadWriter) *PresetReaderChain { return ZSTDJSONReaderChain(rw) }},
+		{SnappyJSONWriterChain, func(rw *ReadWriter) *PresetReaderChain { return SnappyJSONReaderChain(rw) }},
+	} {
+
+		// Repeat test a couple of times to trigger pool re-use scenario
+		for i := 0; i < 10; i++ {
+			wc := preset.writer()
+			wc.PostFn(func(rw *ReadWriter) error {
+				var output testStruct
+				require.Nil(t, preset.reader(rw).DecodeAndClose(&output))
+				require.Equal(t, input, output)
+				return nil
+			})
+			require.Nil(t, wc.EncodeAndClose(input))
+		}
+	}
+
+	// Presets without stage
+	wc := NewPresetWriterChain(nil, BytesEncoder)
+	rw, err := wc.Encode([]byte("This is a test"))
+	require.Nil(t, err)
+	require.Equal(t, "This is a test", string(rw.BytesCopy()))
+	require.Nil(t, wc.Close())
+
+	var output []byte
+	require.Nil(t, NewPresetReaderChain(bytes.NewReader([]byte("This is a test")), nil, BytesDecoder).DecodeAndClose(&output))
+	require.Equal(t, "This is a test", string(output))
+}
@@ -1,18 +1,55 @@
 package concurrency
 
 import (
+	"errors"
+	"fmt"
 	"io"
+	"math"
+	"os"
 )
 
 // minBufferSize is an initial allocation minimal capacity.
 const minBufferSize = 64
 
+// minRead is the minimum slice size passed to a Read call by ReadWriter.ReadFrom.
+const minRead = 512
+
+// ErrTooLarge denotes that data written to a ReadWriter exceeds its maximum size (cf. LimitSize)
+var ErrTooLarge = errors.New("concurrency.ReadWriter: too large")
+
 // ReadWriter denotes a wrapper around a data slice from a memory pool that fulfils the
 // io.Reader and io.Writer interfaces (similar to a bytes.Buffer, on which parts of the
 // implementation are based on)
 type ReadWriter struct {
-	data   []byte
-	offset int
+	data      []byte
+	offset    int
+	pooledCap int
+	maxSize   int
+
+	spillThreshold        int
+	spillDir              string
+	spill                 *os.File
+	spillRead, spillWrite int64
+}
+
+// SpillToDisk causes the ReadWriter to move its data to a temporary file in dir (or the default
+// directory for temporary files if empty) once it exceeds the provided threshold (in bytes), serving
+// all subsequent writes / reads from the file. The file is removed upon Reset() or once the ReadWriter
+// is returned to its memory pool
+func (rw *ReadWriter) SpillToDisk(threshold int, dir string) {
+	rw.spillThreshold, rw.spillDir = threshold, dir
+}
+
+// LimitSize sets the maximum size (in bytes) of the unread portion of the ReadWriter (including any
+// data spilled to disk, cf. SpillToDisk), any write exceeding it fails with ErrTooLarge (instead of
+// growing the buffer unboundedly). If zero, the size is not limited
+func (rw *ReadWriter) LimitSize(n int) {
+	rw.maxSize = n
+}
+
+// Spilled returns if the data of the ReadWriter has been moved to a temporary file (cf. SpillToDisk)
+func (rw *ReadWriter) Spilled() bool {
+	return rw.spill != nil
 }
 
 // Read reads the next len(p) bytes from the buffer or until the buffer
@@ -20,6 +57,9 @@ type ReadWriter struct {
 // buffer has no data to return, err is io.EOF (unless len(p) is zero);
 // otherwise it is nil
 func (rw *ReadWriter) Read(p []byte) (n int, err error) {
+	if rw.spill != nil {
+		return rw.readSpill(p)
+	}
 	if rw.empty() {
 		if len(p) == 0 {
 			return 0, nil
@@ -33,18 +73,152 @@ func (rw *ReadWriter) Read(p []byte) (n int, err error) {
 }
 
 // Write appends the contents of p to the buffer, growing the buffer as
-// needed. The return value n is the length of p; err is always nil
+// needed. The return value n is the length of p; err is always nil (unless
+// the buffer has been / is spilled to disk, cf. SpillToDisk, or p exceeds
+// the maximum size of the buffer, cf. LimitSize, in which case nothing is
+// written and ErrTooLarge is returned)
 func (rw *ReadWriter) Write(p []byte) (int, error) {
-	m := rw.grow(len(p))
+	if rw.exceeds(len(p)) {
+		return 0, ErrTooLarge
+	}
+	if rw.spill == nil && rw.spillThreshold > 0 && rw.len()+len(p) > rw.spillThreshold {
+		if err := rw.spillData(); err != nil {
+			return 0, err
+		}
+	}
+	if rw.spill != nil {
+		n, err := rw.spill.WriteAt(p, rw.spillWrite)
+		rw.spillWrite += int64(n)
+		return n, err
+	}
+
+	m, err := rw.grow(len(p))
+	if err != nil {
+		return 0, err
+	}
 	return copy(rw.data[m:], p), nil
 }
 
+// ReadFrom reads data from r until EOF and appends it to the buffer, growing
+// the buffer as needed. The return value n is the number of bytes read. Any
+// error except io.EOF encountered during the read is also returned (including
+// ErrTooLarge if the data exceeds the maximum size of the buffer, cf. LimitSize).
+// In contrast to io.Copy using an intermediate buffer, data is read into the
+// buffer directly
+func (rw *ReadWriter) ReadFrom(r io.Reader) (n int64, err error) {
+	if rw.spill != nil || rw.spillThreshold > 0 {
+		return rw.readFromSpill(r)
+	}
+
+	for {
+		remaining := rw.remaining()
+		if remaining == 0 {
+			return n, rw.probeEOF(r)
+		}
+
+		if free := cap(rw.data) - len(rw.data); free < minRead && free < remaining {
+
+			// Grow by at least the current length to amortize the cost of growing
+			want := minRead
+			if l := rw.len(); l > want {
+				want = l
+			}
+			if want > remaining {
+				want = remaining
+			}
+			m, err := rw.grow(want)
+			if err != nil {
+				return n, err
+			}
+			rw.data = rw.data[:m]
+		}
+
+		buf := rw.data[len(rw.data):cap(rw.data)]
+		if len(buf) > remaining {
+			buf = buf[:remaining]
+		}
+		m, e := r.Read(buf)
+		rw.data = rw.data[:len(rw.data)+m]
+		n += int64(m)
+		if errors.Is(e, io.EOF) {
+			return n, nil
+		}
+		if e != nil {
+			return n, e
+		}
+	}
+}
+
+// WriteTo writes data to w until the buffer is drained or an error occurs.
+// The return value n is the number of bytes written. Any error encountered
+// during the write is also returned
+func (rw *ReadWriter) WriteTo(w io.Writer) (n int64, err error) {
+	if rw.spill != nil {
+		n, err = io.Copy(w, io.NewSectionReader(rw.spill, rw.spillRead, rw.spillWrite-rw.spillRead))
+		rw.spillRead += n
+		return
+	}
+
+	if nBytes := rw.len(); nBytes > 0 {
+		m, e := w.Write(rw.data[rw.offset:])
+		rw.offset += m
+		n = int64(m)
+		if e != nil {
+			return n, e
+		}
+		if m != nBytes {
+			return n, io.ErrShortWrite
+		}
+	}
+
+	return n, nil
+}
+
+// Seek sets the position for the next Read (fulfilling the io.Seeker interface) and
+// returns the resulting position. Positions are relative to the beginning of the
+// data retained by the buffer, i.e. the data written since the last Reset (note
+// that data which has already been read may be discarded by subsequent writes or
+// when spilling to disk). Seeking beyond the end of the data is not supported
+func (rw *ReadWriter) Seek(offset int64, whence int) (int64, error) {
+	pos, size := int64(rw.offset), int64(len(rw.data))
+	if rw.spill != nil {
+		pos, size = rw.spillRead, rw.spillWrite
+	}
+
+	switch whence {
+	case io.SeekStart:
+	case io.SeekCurrent:
+		offset += pos
+	case io.SeekEnd:
+		offset += size
+	default:
+		return pos, fmt.Errorf("invalid whence: %d", whence)
+	}
+	if offset < 0 || offset > size {
+		return pos, fmt.Errorf("invalid position %d (valid range: [0, %d])", offset, size)
+	}
+
+	if rw.spill != nil {
+		rw.spillRead = offset
+	} else {
+		rw.offset = int(offset)
+	}
+
+	return offset, nil
+}
+
 // Bytes returns a slice holding the unread portion of the ReadWriter, valid for use only
 // until the next buffer modification (that is, only until the next call to a method like
 // Read(), Write() or Reset()
 // The slice aliases the buffer content at least until the next buffer modification,
 // so immediate changes to the slice will affect the result of future reads
-func (rw *ReadWriter) Bytes() []byte { return rw.data[rw.offset:] }
+// If the buffer has been spilled to disk (cf. SpillToDisk), nil is returned
+func (rw *ReadWriter) Bytes() []byte {
+	if rw.spill != nil {
+		return nil
+	}
+	return rw.data[rw.offset:]
+}
 
 // BytesCopy returns a slice holding a copy of the unread portion of the ReadWriter
 func (rw *ReadWriter) BytesCopy() []byte { 
@@ -54,9 +228,30 @@ func (rw *ReadWriter) BytesCopy() []byte {
 	return res
 }
 
+// Detach transfers ownership of the unread portion of the ReadWriter to the caller (e.g. in order to
+// hand it to a different memory pool, cf. MemPoolAdopter), leaving the ReadWriter empty. The (empty)
+// ReadWriter must still be returned to its memory pool in order to update the pool's tracking. If the
+// buffer has been spilled to disk (cf. SpillToDisk), its data is read back into a newly allocated slice
+func (rw *ReadWriter) Detach() ([]byte, error) {
+	if rw.spill != nil {
+		data := make([]byte, rw.len())
+		if _, err := io.ReadFull(rw, data); err != nil {
+			return nil, err
+		}
+		rw.Reset()
+		return data, nil
+	}
+
+	data := rw.data[:copy(rw.data, rw.data[rw.offset:])]
+	rw.data, rw.offset = nil, 0
+
+	return data, nil
+}
+
 // Reset resets the buffer to be empty,
 // but it retains the underlying storage for use by future writes
 func (rw *ReadWriter) Reset() {
+	rw.removeSpill()
 	rw.data = rw.data[:0]
 	rw.offset = 0
 }
@@ -66,12 +261,125 @@ func (rw *ReadWriter) empty() bool { return len(rw.data) <= rw.offset }
 
 // Len returns the number of bytes of the unread portion of the buffer;
 // b.Len() == len(b.Bytes()).
-func (rw *ReadWriter) len() int { return len(rw.data) - rw.offset }
+func (rw *ReadWriter) len() int {
+	if rw.spill != nil {
+		return int(rw.spillWrite - rw.spillRead)
+	}
+	return len(rw.data) - rw.offset
+}
+
+// spillData moves the unread portion of the buffer to a temporary file
+func (rw *ReadWriter) spillData() error {
+	f, err := os.CreateTemp(rw.spillDir, "readwriter-*")
+	if err != nil {
+		return err
+	}
+	n, err := f.Write(rw.data[rw.offset:])
+	if err != nil {
+		_ = f.Close()
+		_ = os.Remove(f.Name())
+		return err
+	}
+
+	rw.spill, rw.spillRead, rw.spillWrite = f, 0, int64(n)
+	rw.data, rw.offset = rw.data[:0], 0
+
+	return nil
+}
+
+// exceeds determines if writing n more bytes would exceed the maximum size of the buffer
+func (rw *ReadWriter) exceeds(n int) bool {
+	return rw.maxSize > 0 && rw.len()+n > rw.maxSize
+}
+
+// remaining returns the number of bytes that can still be written without exceeding the maximum
+// size of the buffer (or math.MaxInt if the size is not limited)
+func (rw *ReadWriter) remaining() int {
+	if rw.maxSize <= 0 {
+		return math.MaxInt
+	}
+	if l := rw.len(); l < rw.maxSize {
+		return rw.maxSize - l
+	}
+	return 0
+}
+
+// probeEOF ensures that r does not provide any more data (the buffer having reached its maximum size)
+func (rw *ReadWriter) probeEOF(r io.Reader) error {
+	var probe [1]byte
+	for {
+		n, err := r.Read(probe[:])
+		if n > 0 {
+			return ErrTooLarge
+		}
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// readFromSpill reads data from r until EOF via Write() (and hence an intermediate
+// buffer), taking into account that the data may be spilled to disk at any point
+func (rw *ReadWriter) readFromSpill(r io.Reader) (n int64, err error) {
+	buf := defaultMemPool.Get(32 * 1024)
+	defer defaultMemPool.Put(buf)
+
+	for {
+		m, e := r.Read(buf)
+		if m > 0 {
+			if _, err = rw.Write(buf[:m]); err != nil {
+				return n, err
+			}
+			n += int64(m)
+		}
+		if errors.Is(e, io.EOF) {
+			return n, nil
+		}
+		if e != nil {
+			return n, e
+		}
+	}
+}
+
+func (rw *ReadWriter) readSpill(p []byte) (n int, err error) {
+	remaining := rw.spillWrite - rw.spillRead
+	if remaining == 0 {
+		if len(p) == 0 {
+			return 0, nil
+		}
+		return 0, io.EOF
+	}
+	if int64(len(p)) > remaining {
+		p = p[:remaining]
+	}
+
+	n, err = rw.spill.ReadAt(p, rw.spillRead)
+	rw.spillRead += int64(n)
+
+	return
+}
+
+// removeSpill closes and removes the temporary file of a spilled buffer (if any)
+func (rw *ReadWriter) removeSpill() {
+	if rw.spill == nil {
+		return
+	}
+	_ = rw.spill.Close()
+	_ = os.Remove(rw.spill.Name())
+	rw.spill, rw.spillRead, rw.spillWrite = nil, 0, 0
+}
 
 // grow grows the buffer to guarantee space for n more bytes.
 // It returns the index where bytes should be written.
-// If the buffer can't grow it will panic with ErrTooLarge.
-func (rw *ReadWriter) grow(n int) int {
+// If the buffer can't grow beyond its maximum size, ErrTooLarge is returned.
+func (rw *ReadWriter) grow(n int) (int, error) {
+	if rw.exceeds(n) {
+		return 0, ErrTooLarge
+	}
+
 	m := rw.len()
 	if m == 0 && rw.offset != 0 {
 		rw.Reset()
@@ -79,7 +387,7 @@ func (rw *ReadWriter) grow(n int) int {
 
 	if rw.data == nil && n <= minBufferSize {
 		rw.data = make([]byte, n, minBufferSize)
-		return 0
+		return 0, nil
 	}
 
 	c := cap(rw.data)
@@ -91,11 +399,10 @@ func (rw *ReadWriter) grow(n int) int {
 
 	rw.offset = 0
 	rw.data = rw.data[:m+n]
-	return m
+	return m, nil
 }
 
 // growSlice grows b by n, preserving the original content of b.
-// If the allocation fails, it panics with ErrTooLarge.
 func growSlice(b []byte, n int) []byte {
 	b2 := append([]byte(nil), make([]byte, len(b)+n)...)
 	copy(b2, b)
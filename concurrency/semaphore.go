@@ -1,9 +1,10 @@
 package concurrency
 
 import (
-	"context"
 	"errors"
 	"time"
+
+	"github.com/fako1024/gotools/clock"
 )
 
 const (
@@ -61,8 +62,14 @@ func (l Semaphore) TryAdd() (func(), error) {
 // TryAddFor attempts to add a new worker / task to be taken into account for
 // a certain period of time, otherwise aborts with an error
 func (l Semaphore) TryAddFor(timeout time.Duration) (func(), error) {
-	ctx, cancel := context.WithTimeout(context.Background(), timeout)
-	defer cancel()
+	return l.TryAddForClock(timeout, clock.System)
+}
+
+// TryAddForClock attempts to add a new worker / task to be taken into account for
+// a certain period of time (tracked using the provided clock), otherwise aborts with an error
+func (l Semaphore) TryAddForClock(timeout time.Duration, clk clock.Clock) (func(), error) {
+	timer := clk.NewTimer(timeout)
+	defer timer.Stop()
 
 	// Try to acquire a slot
 	select {
@@ -70,7 +77,7 @@ func (l Semaphore) TryAddFor(timeout time.Duration) (func(), error) {
 	case l <- struct{}{}:
 		return func() { <-l }, nil
 	// If timeout ensues, return nothing and a sentinel error
-	case <-ctx.Done():
+	case <-timer.C():
 		return nil, ErrNoSlotAvailable
 	}
 }
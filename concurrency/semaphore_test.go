@@ -4,6 +4,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/fako1024/gotools/clock"
 	"github.com/stretchr/testify/require"
 )
 
@@ -44,3 +45,28 @@ func TestSemaphore(t *testing.T) {
 
 	require.Zero(t, len(sem))
 }
+
+func TestSemaphoreTimeoutClock(t *testing.T) {
+
+	sem := New(1)
+	get, err := sem.TryAdd()
+	require.Nil(t, err)
+
+	clk := clock.NewFake(time.Now())
+	errs := make(chan error)
+	go func() {
+		_, err := sem.TryAddForClock(time.Minute, clk)
+		errs <- err
+	}()
+
+	clk.BlockUntil(1)
+	clk.Advance(time.Minute)
+	require.ErrorIs(t, <-errs, ErrNoSlotAvailable)
+	require.Zero(t, clk.Waiters())
+
+	get()
+	getNoTimeout, err := sem.TryAddForClock(time.Minute, clk)
+	require.Nil(t, err)
+	getNoTimeout()
+	require.Zero(t, clk.Waiters())
+}
@@ -0,0 +1,83 @@
+package concurrency
+
+import (
+	"io"
+	"sync"
+
+	"github.com/klauspost/compress/s2"
+)
+
+var snappyWPool, snappyRPool sync.Pool
+
+// SnappyWriter provides a wrapper around an s2.Writer instance producing the Snappy framed format
+// (compatible with standard Snappy tooling)
+type SnappyWriter struct {
+	*s2.Writer
+}
+
+// NewSnappyWriter initializes a new (wrapped) Snappy framed format writer instance, fulfilling the
+// Writer interface
+func NewSnappyWriter() *SnappyWriter {
+	return &SnappyWriter{}
+}
+
+// Init resets a (wrapped) Snappy framed format writer instance from the pool for reuse
+func (s *SnappyWriter) Init(w io.Writer) io.Writer {
+	var sw *s2.Writer
+	if swI := snappyWPool.Get(); swI == nil {
+		sw = s2.NewWriter(w, s2.WriterSnappyCompat())
+	} else {
+		sw = swI.(*s2.Writer)
+		sw.Reset(w)
+	}
+	s.Writer = sw
+
+	return s.Writer
+}
+
+// Close closes a (wrapped) Snappy framed format writer instance
+func (s *SnappyWriter) Close() error {
+	return s.Writer.Close()
+}
+
+// Return returns a (wrapped) Snappy framed format writer instance to the pool
+func (s *SnappyWriter) Return() {
+	snappyWPool.Put(s.Writer)
+}
+
+// SnappyReader provides a wrapper around an s2.Reader instance consuming the Snappy framed format
+type SnappyReader struct {
+	*s2.Reader
+}
+
+// NewSnappyReader initializes a new (wrapped) Snappy framed format reader instance, fulfilling the
+// Reader interface
+func NewSnappyReader() *SnappyReader {
+	return &SnappyReader{}
+}
+
+// Init resets a (wrapped) Snappy framed format reader instance from the pool for reuse
+func (s *SnappyReader) Init(r io.Reader) (io.Reader, error) {
+	var sr *s2.Reader
+	if srI := snappyRPool.Get(); srI == nil {
+		sr = s2.NewReader(r)
+	} else {
+		sr = srI.(*s2.Reader)
+		sr.Reset(r)
+	}
+	s.Reader = sr
+
+	return s.Reader, nil
+}
+
+// Close closes a (wrapped) Snappy framed format reader instance (releasing the reference to the
+// underlying io.Reader)
+func (s *SnappyReader) Close() error {
+	s.Reader.Reset(nil)
+	return nil
+}
+
+// Return returns a (wrapped) Snappy framed format reader instance to the pool
+func (s *SnappyReader) Return() {
+	snappyRPool.Put(s.Reader)
+}
@@ -0,0 +1,71 @@
+package concurrency
+
+import (
+	"errors"
+	"io"
+)
+
+// TeeWriter provides a fan-out Writer stage duplicating all bytes written through it (at its position
+// in the chain) to additional destinations (e.g. a local file or an in-memory buffer), similar to
+// io.MultiWriter. Upon Close, all destinations implementing io.Closer are closed (in reverse order)
+type TeeWriter struct {
+	dsts []io.Writer
+	w    io.Writer
+}
+
+// NewTeeWriter initializes a new fan-out Writer stage duplicating the stream to the provided
+// destinations, fulfilling the Writer interface
+func NewTeeWriter(dsts ...io.Writer) *TeeWriter {
+	return &TeeWriter{
+		dsts: dsts,
+	}
+}
+
+// Init resets the fan-out Writer stage for (re-)use, writing to the provided io.Writer (in addition
+// to the destinations of the stage)
+func (t *TeeWriter) Init(w io.Writer) io.Writer {
+	t.w = w
+	return t
+}
+
+// Write writes len(p) bytes to the underlying io.Writer and all additional destinations
+func (t *TeeWriter) Write(p []byte) (n int, err error) {
+	if n, err = t.w.Write(p); err != nil {
+		return
+	}
+	if n != len(p) {
+		return n, io.ErrShortWrite
+	}
+
+	for _, dst := range t.dsts {
+		nDst, err := dst.Write(p)
+		if err != nil {
+			return nDst, err
+		}
+		if nDst != len(p) {
+			return nDst, io.ErrShortWrite
+		}
+	}
+
+	return len(p), nil
+}
+
+// Close closes all additional destinations implementing io.Closer (in reverse order), attempting
+// to close all of them even if individual ones fail (the underlying io.Writer is not closed)
+func (t *TeeWriter) Close() error {
+	var errs []error
+	for i := len(t.dsts) - 1; i >= 0; i-- {
+		if closer, ok := t.dsts[i].(io.Closer); ok {
+			if err := closer.Close(); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// Return releases the reference to the underlying io.Writer
+func (t *TeeWriter) Return() {
+	t.w = nil
+}
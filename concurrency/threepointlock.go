@@ -4,6 +4,8 @@ import (
 	"errors"
 	"fmt"
 	"time"
+
+	"github.com/fako1024/gotools/clock"
 )
 
 var (
@@ -39,8 +41,9 @@ type ThreePointLock struct {
 	lockRequestFn   func() error
 	unlockRequestFn func() error
 
-	// Timeout for lock operation
+	// Timeout for lock operation (and clock used to track it)
 	timeout time.Duration
+	clock   clock.Clock
 
 	// Memory pool
 	memPool        *MemPoolLimitUnique
@@ -78,6 +81,14 @@ func WithTimeout(timeout time.Duration) ThreePointLockOption {
 	}
 }
 
+// WithClock sets the clock used to track timeouts in the ThreePointLock (e.g. to allow for
+// deterministic testing using a fake clock)
+func WithClock(clk clock.Clock) ThreePointLockOption {
+	return func(tpl *ThreePointLock) {
+		tpl.clock = clk
+	}
+}
+
 // WithMinElementSize sets the minimum element size for the ThreePointLock
 func WithMinElementSize(size int) ThreePointLockOption {
 	return func(tpl *ThreePointLock) {
@@ -92,6 +103,7 @@ func NewThreePointLock(options ...ThreePointLockOption) *ThreePointLock {
 		confirm:        make(chan struct{}),
 		done:           make(chan struct{}, 1),
 		minElementSize: 1, // Should be greater than zero, otherwise slice pointer access will fail
+		clock:          clock.System,
 	}
 
 	// Apply functional options (if present)
@@ -123,10 +135,11 @@ func (tpl *ThreePointLock) Lock() (err error) {
 	} else {
 
 		// If a timeout has been specified, wait until it expires
+		timer := tpl.clock.NewTimer(tpl.timeout)
 		select {
 		case tpl.request <- sem:
-			break
-		case <-time.After(tpl.timeout):
+			timer.Stop()
+		case <-timer.C():
 			err = ErrLockNotifyTimeout
 			tpl.memPool.Put(sem) // Return semaphore on failure
 			return
@@ -150,10 +163,12 @@ func (tpl *ThreePointLock) Lock() (err error) {
 	}
 
 	// If a timeout has been specified, wait until it expires
+	timer := tpl.clock.NewTimer(tpl.timeout)
+	defer timer.Stop()
 	select {
 	case <-tpl.confirm:
 		return
-	case <-time.After(tpl.timeout):
+	case <-timer.C():
 		err = ErrLockConfirmTimeout
 		tpl.memPool.Put(sem) // Return semaphore on failure
 		return
@@ -176,10 +191,11 @@ func (tpl *ThreePointLock) Unlock() (err error) {
 		tpl.done <- struct{}{}
 	} else {
 		// If a timeout has been specified, wait until it expires
+		timer := tpl.clock.NewTimer(tpl.timeout)
 		select {
 		case tpl.done <- struct{}{}:
-			break
-		case <-time.After(tpl.timeout):
+			timer.Stop()
+		case <-timer.C():
 			err = ErrUnlockConfirmTimeout
 			return
 		}
@@ -7,6 +7,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/fako1024/gotools/clock"
 	"github.com/stretchr/testify/require"
 )
 
@@ -65,6 +66,38 @@ func TestSimpleLock(t *testing.T) {
 	}
 }
 
+func TestLockTimeout(t *testing.T) {
+
+	clk := clock.NewFake(time.Now())
+	tpl := NewThreePointLock(WithTimeout(time.Second), WithClock(clk))
+
+	// Without a main routine handling the request, the lock confirmation must time out
+	errs := make(chan error)
+	go func() {
+		errs <- tpl.Lock()
+	}()
+	clk.BlockUntil(1)
+	clk.Advance(time.Second)
+	require.ErrorIs(t, <-errs, ErrLockConfirmTimeout)
+
+	// The previous (unhandled) request is still pending, so the notification must time out
+	go func() {
+		errs <- tpl.Lock()
+	}()
+	clk.BlockUntil(1)
+	clk.Advance(time.Second)
+	require.ErrorIs(t, <-errs, ErrLockNotifyTimeout)
+
+	// The first unlock request is buffered, the second one must time out
+	require.Nil(t, tpl.Unlock())
+	go func() {
+		errs <- tpl.Unlock()
+	}()
+	clk.BlockUntil(1)
+	clk.Advance(time.Second)
+	require.ErrorIs(t, <-errs, ErrUnlockConfirmTimeout)
+}
+
 func loop(ctx context.Context, tpl *ThreePointLock, wg *sync.WaitGroup) {
 	defer func() {
 		wg.Done()
@@ -0,0 +1,15 @@
+package concurrency
+
+// EncodeTyped encodes an object of type T using the provided chain of Writers and encoder function
+// (cf. WriterChain.Encode), providing compile-time type safety for the encoded object
+func EncodeTyped[T any](wc *WriterChain, fn EncoderFn, v T) (*ReadWriter, error) {
+	return wc.Encode(fn, v)
+}
+
+// DecodeTyped decodes an object of type T from the provided chain of Readers using the provided decoder
+// function (cf. ReaderChain.Decode), ensuring that the object is always decoded into the correct type
+func DecodeTyped[T any](rc *ReaderChain, fn DecoderFn) (T, error) {
+	var v T
+	err := rc.Decode(fn, &v)
+	return v, err
+}
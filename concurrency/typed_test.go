@@ -0,0 +1,46 @@
+package concurrency
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTypedEncodeDecode(t *testing.T) {
+	input := testStruct{Name: "typed", Value: 42}
+
+	var encoded []byte
+	wc := NewWriterChain().AddWriter(NewGZIPWriter()).PostFn(func(rw *ReadWriter) error {
+		encoded = bytes.Clone(rw.Bytes())
+		return nil
+	}).Build()
+	_, err := EncodeTyped(wc, JSONEncoder, input)
+	require.Nil(t, err)
+	require.Nil(t, wc.Close())
+
+	rc := NewReaderChain(bytes.NewReader(encoded)).AddReader(NewGZIPReader()).Build()
+	output, err := DecodeTyped[testStruct](rc, JSONDecoder)
+	require.Nil(t, err)
+	require.Nil(t, rc.Close())
+	require.Equal(t, input, output)
+
+	// Raw bytes
+	wc = NewWriterChain().PostFn(func(rw *ReadWriter) error {
+		encoded = bytes.Clone(rw.Bytes())
+		return nil
+	}).Build()
+	_, err = EncodeTyped(wc, BytesEncoder, []byte("typed"))
+	require.Nil(t, err)
+	require.Nil(t, wc.Close())
+
+	res, err := DecodeTyped[[]byte](NewReaderChain(bytes.NewReader(encoded)).Build(), BytesDecoder)
+	require.Nil(t, err)
+	require.Equal(t, []byte("typed"), res)
+
+	// Errors are propagated (e.g. invalid data for the requested type)
+	_, err = DecodeTyped[int](NewReaderChain(bytes.NewBufferString(`{"Name":"typed"}`)).Build(), JSONDecoder)
+	require.Error(t, err)
+	_, err = DecodeTyped[testStruct](NewReaderChain(bytes.NewBufferString("{}")).Build(), nil)
+	require.Error(t, err)
+}
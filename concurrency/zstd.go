@@ -0,0 +1,84 @@
+package concurrency
+
+import (
+	"io"
+	"sync"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+var zstdWPool, zstdRPool sync.Pool
+
+// ZSTDWriter provides a wrapper around a zstd.Encoder instance
+type ZSTDWriter struct {
+	*zstd.Encoder
+}
+
+// NewZSTDWriter initializes a new (wrapped) zstd.Encoder instance, fulfilling the Writer interface
+func NewZSTDWriter() *ZSTDWriter {
+	return &ZSTDWriter{}
+}
+
+// Init resets a (wrapped) zstd.Encoder instance from the pool for reuse
+func (z *ZSTDWriter) Init(w io.Writer) io.Writer {
+	var zw *zstd.Encoder
+	if zwI := zstdWPool.Get(); zwI == nil {
+
+		// Creating an encoder can only fail for invalid options, hence the error can be ignored
+		zw, _ = zstd.NewWriter(w)
+	} else {
+		zw = zwI.(*zstd.Encoder)
+		zw.Reset(w)
+	}
+	z.Encoder = zw
+
+	return z.Encoder
+}
+
+// Close closes a (wrapped) zstd.Encoder instance
+func (z *ZSTDWriter) Close() error {
+	return z.Encoder.Close()
+}
+
+// Return returns a (wrapped) zstd.Encoder instance to the pool
+func (z *ZSTDWriter) Return() {
+	zstdWPool.Put(z.Encoder)
+}
+
+// ZSTDReader provides a wrapper around a zstd.Decoder instance
+type ZSTDReader struct {
+	*zstd.Decoder
+}
+
+// NewZSTDReader initializes a new (wrapped) zstd.Decoder instance, fulfilling the Reader interface
+func NewZSTDReader() *ZSTDReader {
+	return &ZSTDReader{}
+}
+
+// Init resets a (wrapped) zstd.Decoder instance from the pool for reuse
+func (z *ZSTDReader) Init(r io.Reader) (io.Reader, error) {
+	var (
+		zr  *zstd.Decoder
+		err error
+	)
+	if zrI := zstdRPool.Get(); zrI == nil {
+		zr, err = zstd.NewReader(r, zstd.WithDecoderConcurrency(1))
+	} else {
+		zr = zrI.(*zstd.Decoder)
+		err = zr.Reset(r)
+	}
+	z.Decoder = zr
+
+	return z.Decoder, err
+}
+
+// Close closes a (wrapped) zstd.Decoder instance (releasing the reference to the underlying
+// io.Reader, the decoder itself remains usable in order to allow for reuse)
+func (z *ZSTDReader) Close() error {
+	return z.Decoder.Reset(nil)
+}
+
+// Return returns a (wrapped) zstd.Decoder instance to the pool
+func (z *ZSTDReader) Return() {
+	zstdRPool.Put(z.Decoder)
+}
@@ -0,0 +1,160 @@
+package cryptoutils
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+
+	"golang.org/x/crypto/curve25519"
+	"golang.org/x/crypto/nacl/box"
+)
+
+const (
+	// BoxKeySize denotes the size of curve25519 public / private keys (in bytes)
+	BoxKeySize = 32
+
+	boxNonceSize = 24
+)
+
+// ErrBoxOpen denotes that a box could not be opened (authenticated / decrypted)
+var ErrBoxOpen = errors.New("failed to open box")
+
+// BoxKey denotes a curve25519 public / private key pair for use with NaCl box / sealed box
+// (compatible with libsodium crypto_box_easy / crypto_box_seal)
+type BoxKey struct {
+	pubKey, privKey *[BoxKeySize]byte
+}
+
+// NewBoxKey creates a new curve25519 key pair
+func NewBoxKey() (*BoxKey, error) {
+	pubKey, privKey, err := box.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+
+	return &BoxKey{
+		pubKey:  pubKey,
+		privKey: privKey,
+	}, nil
+}
+
+// NewBoxKeyFromString reads a private key / BoxKey object from a base64 encoded string
+func NewBoxKeyFromString(str string) (*BoxKey, error) {
+	privKey, err := parseBoxKey(str)
+	if err != nil {
+		return nil, err
+	}
+
+	pub, err := curve25519.X25519(privKey[:], curve25519.Basepoint)
+	if err != nil {
+		return nil, err
+	}
+	pubKey := new([BoxKeySize]byte)
+	copy(pubKey[:], pub)
+
+	return &BoxKey{
+		pubKey:  pubKey,
+		privKey: privKey,
+	}, nil
+}
+
+// ParseBoxPubKey reads a public key from a base64 encoded string
+func ParseBoxPubKey(str string) (*[BoxKeySize]byte, error) {
+	return parseBoxKey(str)
+}
+
+// PubKey returns the public key
+func (k *BoxKey) PubKey() *[BoxKeySize]byte {
+	return k.pubKey
+}
+
+// PrivKey returns the private key
+func (k *BoxKey) PrivKey() *[BoxKeySize]byte {
+	return k.privKey
+}
+
+// PubKeyString returns the public key as base64 encoded string
+func (k *BoxKey) PubKeyString() string {
+	return base64.StdEncoding.EncodeToString(k.pubKey[:])
+}
+
+// PrivKeyString returns the private key as base64 encoded string
+func (k *BoxKey) PrivKeyString() string {
+	return base64.StdEncoding.EncodeToString(k.privKey[:])
+}
+
+// Seal encrypts and authenticates a message for the owner of the peer public key, prepending a
+// random nonce to the resulting box
+func (k *BoxKey) Seal(clearMsg []byte, peerPubKey *[BoxKeySize]byte) ([]byte, error) {
+	if peerPubKey == nil {
+		return nil, errors.New("invalid (nil) public key provided")
+	}
+
+	var nonce [boxNonceSize]byte
+	if _, err := rand.Read(nonce[:]); err != nil {
+		return nil, err
+	}
+
+	return box.Seal(nonce[:], clearMsg, &nonce, peerPubKey, k.privKey), nil
+}
+
+// Open authenticates and decrypts a box created by the owner of the peer public key
+func (k *BoxKey) Open(cipherMsg []byte, peerPubKey *[BoxKeySize]byte) ([]byte, error) {
+	if peerPubKey == nil {
+		return nil, errors.New("invalid (nil) public key provided")
+	}
+	if len(cipherMsg) < boxNonceSize+box.Overhead {
+		return nil, ErrCipherTextTooShort
+	}
+
+	var nonce [boxNonceSize]byte
+	copy(nonce[:], cipherMsg)
+
+	res, ok := box.Open(nil, cipherMsg[boxNonceSize:], &nonce, peerPubKey, k.privKey)
+	if !ok {
+		return nil, ErrBoxOpen
+	}
+
+	return res, nil
+}
+
+// SealAnonymous encrypts a message for the owner of the provided public key without revealing
+// (or requiring) the identity of the sender (using an ephemeral sender key pair)
+func SealAnonymous(clearMsg []byte, pubKey *[BoxKeySize]byte) ([]byte, error) {
+	if pubKey == nil {
+		return nil, errors.New("invalid (nil) public key provided")
+	}
+	return box.SealAnonymous(nil, clearMsg, pubKey, rand.Reader)
+}
+
+// OpenAnonymous decrypts a message created via SealAnonymous() for the public key of this key pair
+func (k *BoxKey) OpenAnonymous(cipherMsg []byte) ([]byte, error) {
+	if len(cipherMsg) < box.AnonymousOverhead {
+		return nil, ErrCipherTextTooShort
+	}
+
+	res, ok := box.OpenAnonymous(nil, cipherMsg, k.pubKey, k.privKey)
+	if !ok {
+		return nil, ErrBoxOpen
+	}
+
+	return res, nil
+}
+
+////////////////////////////////////////////////////////////////////////////////////////
+
+func parseBoxKey(str string) (*[BoxKeySize]byte, error) {
+	keyBytes, err := base64.StdEncoding.DecodeString(str)
+	if err != nil {
+		return nil, err
+	}
+	if len(keyBytes) != BoxKeySize {
+		return nil, fmt.Errorf("invalid key size (want %d, have %d)", BoxKeySize, len(keyBytes))
+	}
+
+	key := new([BoxKeySize]byte)
+	copy(key[:], keyBytes)
+
+	return key, nil
+}
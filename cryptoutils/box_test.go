@@ -0,0 +1,88 @@
+package cryptoutils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBoxRoundTrip(t *testing.T) {
+	alice, err := NewBoxKey()
+	require.Nil(t, err)
+	bob, err := NewBoxKey()
+	require.Nil(t, err)
+
+	clearText := []byte("This is a test message")
+
+	cipherText, err := alice.Seal(clearText, bob.PubKey())
+	require.Nil(t, err)
+	res, err := bob.Open(cipherText, alice.PubKey())
+	require.Nil(t, err)
+	require.Equal(t, clearText, res)
+
+	// Opening with the wrong sender key must fail
+	eve, err := NewBoxKey()
+	require.Nil(t, err)
+	_, err = bob.Open(cipherText, eve.PubKey())
+	require.ErrorIs(t, err, ErrBoxOpen)
+
+	// Tampering must be detected
+	cipherText[len(cipherText)-1] ^= 0xFF
+	_, err = bob.Open(cipherText, alice.PubKey())
+	require.ErrorIs(t, err, ErrBoxOpen)
+}
+
+func TestSealedBoxRoundTrip(t *testing.T) {
+	recipient, err := NewBoxKey()
+	require.Nil(t, err)
+
+	clearText := []byte("This is an anonymous report")
+
+	// Simulate a sender that only knows the recipient public key as string
+	pubKey, err := ParseBoxPubKey(recipient.PubKeyString())
+	require.Nil(t, err)
+	cipherText, err := SealAnonymous(clearText, pubKey)
+	require.Nil(t, err)
+
+	res, err := recipient.OpenAnonymous(cipherText)
+	require.Nil(t, err)
+	require.Equal(t, clearText, res)
+
+	other, err := NewBoxKey()
+	require.Nil(t, err)
+	_, err = other.OpenAnonymous(cipherText)
+	require.ErrorIs(t, err, ErrBoxOpen)
+}
+
+func TestBoxKeyStringConversion(t *testing.T) {
+	k1, err := NewBoxKey()
+	require.Nil(t, err)
+
+	k2, err := NewBoxKeyFromString(k1.PrivKeyString())
+	require.Nil(t, err)
+	require.Equal(t, *k1, *k2, "initial and re-read instances should be equal on value-level")
+	require.Equal(t, k1.PubKeyString(), k2.PubKeyString())
+}
+
+func TestBoxInvalid(t *testing.T) {
+	k, err := NewBoxKey()
+	require.Nil(t, err)
+
+	_, err = NewBoxKeyFromString("jkhgxdfkjhsgd")
+	require.Error(t, err)
+	_, err = NewBoxKeyFromString("bm9wZQ==")
+	require.Error(t, err)
+	_, err = ParseBoxPubKey("bm9wZQ==")
+	require.Error(t, err)
+
+	_, err = k.Seal(nil, nil)
+	require.Error(t, err)
+	_, err = k.Open(nil, nil)
+	require.Error(t, err)
+	_, err = k.Open([]byte{0x1}, k.PubKey())
+	require.ErrorIs(t, err, ErrCipherTextTooShort)
+	_, err = SealAnonymous(nil, nil)
+	require.Error(t, err)
+	_, err = k.OpenAnonymous([]byte{0x1})
+	require.ErrorIs(t, err, ErrCipherTextTooShort)
+}
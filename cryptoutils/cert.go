@@ -0,0 +1,127 @@
+package cryptoutils
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"math"
+	"net"
+	"time"
+)
+
+// ErrNoCertificates denotes that no certificate could be found in the provided data
+var ErrNoCertificates = errors.New("no certificates found")
+
+// CertInfo denotes a summary of the most relevant properties of a certificate
+type CertInfo struct {
+	Subject      string
+	Issuer       string
+	SerialNumber string
+
+	DNSNames       []string
+	IPAddresses    []net.IP
+	EmailAddresses []string
+
+	NotBefore time.Time
+	NotAfter  time.Time
+	IsCA      bool
+}
+
+// ParseCertificates parses all certificates from a PEM bundle (ignoring any non-certificate blocks)
+func ParseCertificates(pemData []byte) ([]*x509.Certificate, error) {
+	var certs []*x509.Certificate
+	for {
+		var block *pem.Block
+		block, pemData = pem.Decode(pemData)
+		if block == nil {
+			break
+		}
+		if block.Type != pemTypeCertificate {
+			continue
+		}
+
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		certs = append(certs, cert)
+	}
+
+	if len(certs) == 0 {
+		return nil, ErrNoCertificates
+	}
+
+	return certs, nil
+}
+
+// NewCertPool creates a certificate pool from all certificates in a PEM bundle
+func NewCertPool(pemData []byte) (*x509.CertPool, error) {
+	certs, err := ParseCertificates(pemData)
+	if err != nil {
+		return nil, err
+	}
+
+	pool := x509.NewCertPool()
+	for _, cert := range certs {
+		pool.AddCert(cert)
+	}
+
+	return pool, nil
+}
+
+// Inspect extracts the summary information of a certificate
+func Inspect(cert *x509.Certificate) CertInfo {
+	return CertInfo{
+		Subject:        cert.Subject.String(),
+		Issuer:         cert.Issuer.String(),
+		SerialNumber:   cert.SerialNumber.String(),
+		DNSNames:       cert.DNSNames,
+		IPAddresses:    cert.IPAddresses,
+		EmailAddresses: cert.EmailAddresses,
+		NotBefore:      cert.NotBefore,
+		NotAfter:       cert.NotAfter,
+		IsCA:           cert.IsCA,
+	}
+}
+
+// VerifyChain verifies a certificate chain (leaf certificate first, followed by any intermediates,
+// as usually found in a PEM bundle) against a pool of root CAs (falling back to the system pool
+// if nil), optionally validating the leaf certificate against a DNS name
+func VerifyChain(chain []*x509.Certificate, roots *x509.CertPool, dnsName string) error {
+	if len(chain) == 0 {
+		return ErrNoCertificates
+	}
+
+	intermediates := x509.NewCertPool()
+	for _, cert := range chain[1:] {
+		intermediates.AddCert(cert)
+	}
+
+	_, err := chain[0].Verify(x509.VerifyOptions{
+		Roots:         roots,
+		Intermediates: intermediates,
+		DNSName:       dnsName,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
+	})
+
+	return err
+}
+
+// DaysUntilExpiry returns the number of (full) days until a certificate expires relative to the
+// provided point in time (negative if already expired)
+func DaysUntilExpiry(cert *x509.Certificate, now time.Time) int {
+	return int(math.Floor(cert.NotAfter.Sub(now).Hours() / 24))
+}
+
+// EarliestExpiry returns the certificate of a bundle / chain that expires first (e.g. to determine
+// the effective validity of a chain), or nil if the provided list is empty
+func EarliestExpiry(certs []*x509.Certificate) *x509.Certificate {
+	var earliest *x509.Certificate
+	for _, cert := range certs {
+		if earliest == nil || cert.NotAfter.Before(earliest.NotAfter) {
+			earliest = cert
+		}
+	}
+
+	return earliest
+}
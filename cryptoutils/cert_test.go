@@ -0,0 +1,70 @@
+package cryptoutils
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCertInspection(t *testing.T) {
+	caKey, err := New(Bits2048)
+	require.Nil(t, err)
+	ca, err := NewSelfSignedCA(caKey.PrivKey(), "Test CA", 30*24*time.Hour)
+	require.Nil(t, err)
+
+	leafKey, err := New(Bits2048)
+	require.Nil(t, err)
+	csr, err := CreateCSR(CSROptions{
+		Key:        leafKey.PrivKey(),
+		CommonName: "service",
+		DNSNames:   []string{"service.example.org"},
+	})
+	require.Nil(t, err)
+	leafPEM, err := SignCSR(csr, ca, 10*24*time.Hour+time.Hour)
+	require.Nil(t, err)
+
+	// Build a bundle containing the leaf and CA certificates as well as a non-certificate block
+	bundle := pem.EncodeToMemory(leafPEM)
+	bundle = append(bundle, pem.EncodeToMemory(caKey.PubKeyPEM())...)
+	bundle = append(bundle, pem.EncodeToMemory(ca.CertPEM())...)
+
+	certs, err := ParseCertificates(bundle)
+	require.Nil(t, err)
+	require.Len(t, certs, 2)
+
+	info := Inspect(certs[0])
+	require.Equal(t, "CN=service", info.Subject)
+	require.Equal(t, "CN=Test CA", info.Issuer)
+	require.Equal(t, []string{"service.example.org"}, info.DNSNames)
+	require.False(t, info.IsCA)
+	require.True(t, Inspect(certs[1]).IsCA)
+
+	now := time.Now()
+	require.Equal(t, 10, DaysUntilExpiry(certs[0], now))
+	require.Equal(t, -1, DaysUntilExpiry(certs[0], now.Add(10*24*time.Hour+2*time.Hour)))
+	require.Equal(t, certs[0], EarliestExpiry(certs))
+	require.Nil(t, EarliestExpiry(nil))
+
+	// Chain verification
+	roots, err := NewCertPool(pem.EncodeToMemory(ca.CertPEM()))
+	require.Nil(t, err)
+	require.Nil(t, VerifyChain(certs[:1], roots, "service.example.org"))
+	require.Nil(t, VerifyChain(certs, roots, ""))
+	require.Error(t, VerifyChain(certs, roots, "other.example.org"))
+	require.Error(t, VerifyChain(certs, x509.NewCertPool(), ""))
+	require.ErrorIs(t, VerifyChain(nil, roots, ""), ErrNoCertificates)
+}
+
+func TestCertInvalid(t *testing.T) {
+	_, err := ParseCertificates(nil)
+	require.ErrorIs(t, err, ErrNoCertificates)
+	_, err = ParseCertificates([]byte("not a PEM bundle"))
+	require.ErrorIs(t, err, ErrNoCertificates)
+	_, err = ParseCertificates(pem.EncodeToMemory(&pem.Block{Type: pemTypeCertificate, Bytes: []byte{0x1}}))
+	require.Error(t, err)
+	_, err = NewCertPool(nil)
+	require.ErrorIs(t, err, ErrNoCertificates)
+}
@@ -0,0 +1,210 @@
+package cryptoutils
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"net"
+	"time"
+)
+
+const (
+	pemTypeCSR         = "CERTIFICATE REQUEST"
+	pemTypeCertificate = "CERTIFICATE"
+
+	serialNumberBits = 128
+)
+
+// CSROptions denotes the parameters used to create a certificate signing request
+type CSROptions struct {
+
+	// Key is the private key used to sign the request (e.g. obtained via PrivKey())
+	Key crypto.Signer
+
+	// Subject information
+	CommonName         string
+	Organization       []string
+	OrganizationalUnit []string
+	Country            []string
+
+	// Subject alternative names
+	DNSNames       []string
+	IPAddresses    []net.IP
+	EmailAddresses []string
+}
+
+// CA denotes a (minimal) certificate authority, consisting of a certificate and its
+// private key, that can be used to sign certificate signing requests
+type CA struct {
+	cert *x509.Certificate
+	key  crypto.Signer
+}
+
+// NewCA instantiates a certificate authority from an existing certificate (as PEM block)
+// and its private key
+func NewCA(certPEM *pem.Block, key crypto.Signer) (*CA, error) {
+	if certPEM == nil {
+		return nil, errors.New("invalid (nil) pem block provided")
+	}
+	if key == nil {
+		return nil, errors.New("invalid (nil) private key provided")
+	}
+
+	cert, err := x509.ParseCertificate(certPEM.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	if !cert.IsCA {
+		return nil, errors.New("provided certificate is not a CA certificate")
+	}
+
+	return &CA{
+		cert: cert,
+		key:  key,
+	}, nil
+}
+
+// NewSelfSignedCA creates a new self-signed certificate authority for the provided private key,
+// valid for the given period of time
+func NewSelfSignedCA(key crypto.Signer, commonName string, ttl time.Duration) (*CA, error) {
+	if key == nil {
+		return nil, errors.New("invalid (nil) private key provided")
+	}
+
+	serial, err := newSerialNumber()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	template := &x509.Certificate{
+		SerialNumber:          serial,
+		Subject:               pkix.Name{CommonName: commonName},
+		NotBefore:             now,
+		NotAfter:              now.Add(ttl),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign | x509.KeyUsageDigitalSignature,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, key.Public(), key)
+	if err != nil {
+		return nil, err
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, err
+	}
+
+	return &CA{
+		cert: cert,
+		key:  key,
+	}, nil
+}
+
+// Cert returns the certificate of the CA
+func (ca *CA) Cert() *x509.Certificate {
+	return ca.cert
+}
+
+// CertPEM returns the certificate of the CA as PEM block
+func (ca *CA) CertPEM() *pem.Block {
+	return &pem.Block{
+		Type:  pemTypeCertificate,
+		Bytes: ca.cert.Raw,
+	}
+}
+
+// CreateCSR creates a new certificate signing request (as PEM block) based on the provided options
+func CreateCSR(opts CSROptions) (*pem.Block, error) {
+	if opts.Key == nil {
+		return nil, errors.New("invalid (nil) private key provided")
+	}
+
+	template := &x509.CertificateRequest{
+		Subject: pkix.Name{
+			CommonName:         opts.CommonName,
+			Organization:       opts.Organization,
+			OrganizationalUnit: opts.OrganizationalUnit,
+			Country:            opts.Country,
+		},
+		DNSNames:       opts.DNSNames,
+		IPAddresses:    opts.IPAddresses,
+		EmailAddresses: opts.EmailAddresses,
+	}
+
+	der, err := x509.CreateCertificateRequest(rand.Reader, template, opts.Key)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pem.Block{
+		Type:  pemTypeCSR,
+		Bytes: der,
+	}, nil
+}
+
+// SignCSR validates a certificate signing request (as PEM block) and signs it using the provided
+// CA, returning a certificate (as PEM block) valid for the given period of time
+func SignCSR(csrPEM *pem.Block, ca *CA, ttl time.Duration) (*pem.Block, error) {
+	if csrPEM == nil {
+		return nil, errors.New("invalid (nil) pem block provided")
+	}
+	if ca == nil {
+		return nil, errors.New("invalid (nil) CA provided")
+	}
+	if ttl <= 0 {
+		return nil, errors.New("invalid (non-positive) certificate validity period provided")
+	}
+
+	csr, err := x509.ParseCertificateRequest(csrPEM.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	if err = csr.CheckSignature(); err != nil {
+		return nil, err
+	}
+
+	serial, err := newSerialNumber()
+	if err != nil {
+		return nil, err
+	}
+
+	// Limit the validity of the issued certificate to the one of the CA
+	now := time.Now()
+	notAfter := now.Add(ttl)
+	if notAfter.After(ca.cert.NotAfter) {
+		notAfter = ca.cert.NotAfter
+	}
+
+	template := &x509.Certificate{
+		SerialNumber:   serial,
+		Subject:        csr.Subject,
+		DNSNames:       csr.DNSNames,
+		IPAddresses:    csr.IPAddresses,
+		EmailAddresses: csr.EmailAddresses,
+		URIs:           csr.URIs,
+		NotBefore:      now,
+		NotAfter:       notAfter,
+		KeyUsage:       x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment,
+		ExtKeyUsage:    []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth, x509.ExtKeyUsageServerAuth},
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, ca.cert, csr.PublicKey, ca.key)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pem.Block{
+		Type:  pemTypeCertificate,
+		Bytes: der,
+	}, nil
+}
+
+func newSerialNumber() (*big.Int, error) {
+	return rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), serialNumberBits))
+}
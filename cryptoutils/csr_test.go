@@ -0,0 +1,109 @@
+package cryptoutils
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCSRIssuanceFlow(t *testing.T) {
+
+	// Controller side: create the CA
+	caKey, err := New(Bits2048)
+	require.Nil(t, err)
+	ca, err := NewSelfSignedCA(caKey.PrivKey(), "Test CA", 24*time.Hour)
+	require.Nil(t, err)
+
+	// Agent side: create the key + CSR
+	agentKey, err := New(Bits2048)
+	require.Nil(t, err)
+	csr, err := CreateCSR(CSROptions{
+		Key:          agentKey.PrivKey(),
+		CommonName:   "agent01",
+		Organization: []string{"Test Org"},
+		DNSNames:     []string{"agent01.example.org"},
+		IPAddresses:  []net.IP{net.ParseIP("192.0.2.1")},
+	})
+	require.Nil(t, err)
+	require.Equal(t, pemTypeCSR, csr.Type)
+
+	// Controller side: sign the CSR (ensuring a round-trip via PEM encoding)
+	csrDecoded, _ := pem.Decode(pem.EncodeToMemory(csr))
+	certPEM, err := SignCSR(csrDecoded, ca, time.Hour)
+	require.Nil(t, err)
+	require.Equal(t, pemTypeCertificate, certPEM.Type)
+
+	// Agent side: verify the issued certificate
+	cert, err := x509.ParseCertificate(certPEM.Bytes)
+	require.Nil(t, err)
+	require.Equal(t, "agent01", cert.Subject.CommonName)
+	require.Equal(t, []string{"agent01.example.org"}, cert.DNSNames)
+	require.True(t, cert.IPAddresses[0].Equal(net.ParseIP("192.0.2.1")))
+	require.True(t, agentKey.PubKey().Equal(cert.PublicKey))
+	require.WithinDuration(t, time.Now().Add(time.Hour), cert.NotAfter, time.Minute)
+
+	roots := x509.NewCertPool()
+	roots.AddCert(ca.Cert())
+	_, err = cert.Verify(x509.VerifyOptions{
+		Roots:     roots,
+		DNSName:   "agent01.example.org",
+		KeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	})
+	require.Nil(t, err)
+
+	// Re-instantiate the CA from its PEM block and ensure the validity of issued certificates
+	// is capped by the one of the CA
+	ca2, err := NewCA(ca.CertPEM(), caKey.PrivKey())
+	require.Nil(t, err)
+	certPEM, err = SignCSR(csr, ca2, 48*time.Hour)
+	require.Nil(t, err)
+	cert, err = x509.ParseCertificate(certPEM.Bytes)
+	require.Nil(t, err)
+	require.False(t, cert.NotAfter.After(ca.Cert().NotAfter))
+}
+
+func TestCSRInvalid(t *testing.T) {
+	key, err := New(Bits2048)
+	require.Nil(t, err)
+	ca, err := NewSelfSignedCA(key.PrivKey(), "Test CA", time.Hour)
+	require.Nil(t, err)
+
+	_, err = CreateCSR(CSROptions{})
+	require.Error(t, err)
+	_, err = NewSelfSignedCA(nil, "Test CA", time.Hour)
+	require.Error(t, err)
+	_, err = NewCA(nil, key.PrivKey())
+	require.Error(t, err)
+	_, err = NewCA(ca.CertPEM(), nil)
+	require.Error(t, err)
+
+	csr, err := CreateCSR(CSROptions{Key: key.PrivKey(), CommonName: "test"})
+	require.Nil(t, err)
+	_, err = SignCSR(nil, ca, time.Hour)
+	require.Error(t, err)
+	_, err = SignCSR(csr, nil, time.Hour)
+	require.Error(t, err)
+	_, err = SignCSR(csr, ca, 0)
+	require.Error(t, err)
+
+	// Tamper with the CSR, invalidating its signature
+	csr.Bytes[len(csr.Bytes)-1] ^= 0xFF
+	_, err = SignCSR(csr, ca, time.Hour)
+	require.Error(t, err)
+
+	// Attempt to use a non-CA certificate as CA
+	certPEM, err := SignCSR(&pem.Block{Type: pemTypeCSR, Bytes: mustCSR(t, key).Bytes}, ca, time.Hour)
+	require.Nil(t, err)
+	_, err = NewCA(certPEM, key.PrivKey())
+	require.Error(t, err)
+}
+
+func mustCSR(t *testing.T, key *RSA) *pem.Block {
+	csr, err := CreateCSR(CSROptions{Key: key.PrivKey(), CommonName: "test"})
+	require.Nil(t, err)
+	return csr
+}
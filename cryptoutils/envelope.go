@@ -0,0 +1,265 @@
+package cryptoutils
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// EnvelopeVersion denotes the current version of the envelope format
+const EnvelopeVersion = 1
+
+const (
+	keyIDLen = 8
+
+	// magic | version | algorithm | key ID | wrapped key length
+	envelopeHeaderLen = 2 + 1 + 1 + keyIDLen + 2
+
+	symmetricKeyIDContext = "cryptoutils symmetric key id"
+)
+
+var (
+	envelopeMagic = [2]byte{0xCE, 0x01}
+
+	// ErrInvalidEnvelope denotes that an envelope is malformed
+	ErrInvalidEnvelope = errors.New("invalid envelope")
+
+	// ErrUnknownKeyID denotes that no key matching the key identifier of an envelope is available
+	ErrUnknownKeyID = errors.New("no key available for key identifier")
+
+	// ErrAlgorithmMismatch denotes that an envelope was encrypted using a different algorithm
+	// than the requested one
+	ErrAlgorithmMismatch = errors.New("envelope algorithm mismatch")
+)
+
+// KeyID denotes a short identifier of a key, derived from its public key (or, in case of symmetric
+// keys, from the key itself)
+type KeyID [keyIDLen]byte
+
+// NewKeyID derives the key identifier for a public key
+func NewKeyID(pubKey *rsa.PublicKey) KeyID {
+	var id KeyID
+	sum := sha256.Sum256(x509.MarshalPKCS1PublicKey(pubKey))
+	copy(id[:], sum[:keyIDLen])
+	return id
+}
+
+// NewSymmetricKeyID derives the key identifier for a symmetric key
+func NewSymmetricKeyID(key []byte) KeyID {
+	var id KeyID
+	sum := sha256.Sum256(append([]byte(symmetricKeyIDContext), key...))
+	copy(id[:], sum[:keyIDLen])
+	return id
+}
+
+// String returns the hex representation of the key identifier
+func (id KeyID) String() string {
+	return hex.EncodeToString(id[:])
+}
+
+// Envelope denotes the binary container format used by all (non-streaming) encryption helpers
+// of this package, providing all metadata required to decrypt its payload:
+//
+//	magic (2) | version (1) | algorithm (1) | key ID (8) | wrapped key length (2, big endian) |
+//	wrapped key | nonce | cipher text | tag
+//
+// The sizes of nonce and tag are determined by the algorithm. All data preceding the nonce (i.e. the
+// header including the wrapped key) is authenticated along with the payload
+type Envelope struct {
+	Version    uint8
+	Algorithm  Algorithm
+	KeyID      KeyID
+	WrappedKey []byte
+	Nonce      []byte
+	CipherText []byte
+	Tag        []byte
+}
+
+// ParseEnvelope parses (but does not decrypt) an envelope, e.g. for inspection of its metadata
+// The byte slices of the resulting Envelope reference the provided data (zero-copy)
+func ParseEnvelope(data []byte) (*Envelope, error) {
+	if len(data) < envelopeHeaderLen || data[0] != envelopeMagic[0] || data[1] != envelopeMagic[1] {
+		return nil, ErrInvalidEnvelope
+	}
+	if data[2] != EnvelopeVersion {
+		return nil, fmt.Errorf("%w: unsupported version %d", ErrInvalidEnvelope, data[2])
+	}
+
+	env := Envelope{
+		Version:   data[2],
+		Algorithm: Algorithm(data[3]),
+	}
+	copy(env.KeyID[:], data[4:])
+
+	nonceSize, tagSize, err := env.Algorithm.sizes()
+	if err != nil {
+		return nil, err
+	}
+
+	pos := envelopeHeaderLen
+	wrappedKeyLen := int(binary.BigEndian.Uint16(data[pos-2:]))
+	if len(data) < pos+wrappedKeyLen+nonceSize+tagSize {
+		return nil, fmt.Errorf("%w: truncated", ErrInvalidEnvelope)
+	}
+
+	env.WrappedKey = data[pos : pos+wrappedKeyLen]
+	pos += wrappedKeyLen
+	env.Nonce = data[pos : pos+nonceSize]
+	pos += nonceSize
+	env.CipherText = data[pos : len(data)-tagSize]
+	env.Tag = data[len(data)-tagSize:]
+
+	return &env, nil
+}
+
+// Marshal serializes the envelope into its binary representation
+func (e *Envelope) Marshal() []byte {
+	res := e.appendHeader(make([]byte, 0, envelopeHeaderLen+len(e.WrappedKey)+len(e.Nonce)+len(e.CipherText)+len(e.Tag)))
+	res = append(res, e.Nonce...)
+	res = append(res, e.CipherText...)
+	return append(res, e.Tag...)
+}
+
+// appendHeader appends the header of the envelope (including the wrapped key), which is used as
+// additional authenticated data when sealing / opening its payload
+func (e *Envelope) appendHeader(res []byte) []byte {
+	res = append(res, envelopeMagic[0], envelopeMagic[1], e.Version, byte(e.Algorithm))
+	res = append(res, e.KeyID[:]...)
+	res = binary.BigEndian.AppendUint16(res, uint16(len(e.WrappedKey))) // #nosec G115
+
+	return append(res, e.WrappedKey...)
+}
+
+// sealed returns the sealed payload (cipher text + tag) of the envelope
+func (e *Envelope) sealed() []byte {
+	res := make([]byte, 0, len(e.CipherText)+len(e.Tag))
+	res = append(res, e.CipherText...)
+	return append(res, e.Tag...)
+}
+
+// KeyID returns the key identifier of the key pair
+func (e *RSA) KeyID() KeyID {
+	return NewKeyID(&e.privKey.PublicKey)
+}
+
+// EncryptEnvelope encrypts a message of arbitrary size for the owner of the provided public key
+// (using a random AES-256-GCM data key wrapped via RSA-OAEP), embedding the key identifier of the
+// public key in the resulting envelope
+func EncryptEnvelope(pubKey *rsa.PublicKey, clearMsg []byte) ([]byte, error) {
+	if pubKey == nil {
+		return nil, errors.New("invalid (nil) public key provided")
+	}
+
+	dataKey, err := NewSymmetricKey(AES256GCM)
+	if err != nil {
+		return nil, err
+	}
+	wrappedKey, err := rsa.EncryptOAEP(sha256.New(), rand.Reader, pubKey, dataKey, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	env := &Envelope{
+		Version:    EnvelopeVersion,
+		Algorithm:  RSAOAEPAES256GCM,
+		KeyID:      NewKeyID(pubKey),
+		WrappedKey: wrappedKey,
+	}
+	if err = seal(AES256GCM, dataKey, env, clearMsg); err != nil {
+		return nil, err
+	}
+
+	return env.Marshal(), nil
+}
+
+// EnvelopeKeyID extracts the key identifier from an envelope
+func EnvelopeKeyID(envelope []byte) (id KeyID, err error) {
+	env, err := ParseEnvelope(envelope)
+	if err != nil {
+		return id, err
+	}
+	return env.KeyID, nil
+}
+
+// Decryptor denotes a set of private keys (e.g. the current and previous ones) that can be used to
+// decrypt envelopes, automatically selecting the matching key based on the embedded key identifier
+type Decryptor struct {
+	keys map[KeyID]*RSA
+
+	sync.RWMutex
+}
+
+// NewDecryptor instantiates a new Decryptor holding the provided keys
+func NewDecryptor(keys ...*RSA) *Decryptor {
+	obj := &Decryptor{
+		keys: make(map[KeyID]*RSA, len(keys)),
+	}
+	for _, key := range keys {
+		obj.keys[key.KeyID()] = key
+	}
+
+	return obj
+}
+
+// AddKey adds a key to the Decryptor
+func (d *Decryptor) AddKey(key *RSA) {
+	d.Lock()
+	d.keys[key.KeyID()] = key
+	d.Unlock()
+}
+
+// RemoveKey removes a key from the Decryptor
+func (d *Decryptor) RemoveKey(id KeyID) {
+	d.Lock()
+	delete(d.keys, id)
+	d.Unlock()
+}
+
+// Decrypt decrypts an envelope using the key matching its key identifier
+func (d *Decryptor) Decrypt(envelope []byte) ([]byte, error) {
+	env, err := ParseEnvelope(envelope)
+	if err != nil {
+		return nil, err
+	}
+
+	d.RLock()
+	key, exists := d.keys[env.KeyID]
+	d.RUnlock()
+	if !exists {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownKeyID, env.KeyID)
+	}
+
+	return key.open(env)
+}
+
+// DecryptEnvelope decrypts an envelope previously encrypted for the public key of this key pair
+func (e *RSA) DecryptEnvelope(envelope []byte) ([]byte, error) {
+	env, err := ParseEnvelope(envelope)
+	if err != nil {
+		return nil, err
+	}
+
+	return e.open(env)
+}
+
+func (e *RSA) open(env *Envelope) ([]byte, error) {
+	if env.Algorithm != RSAOAEPAES256GCM {
+		return nil, fmt.Errorf("%w: %s", ErrAlgorithmMismatch, env.Algorithm)
+	}
+	if env.KeyID != e.KeyID() {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownKeyID, env.KeyID)
+	}
+
+	dataKey, err := rsa.DecryptOAEP(sha256.New(), rand.Reader, e.privKey, env.WrappedKey, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return open(AES256GCM, dataKey, env)
+}
@@ -0,0 +1,179 @@
+package cryptoutils
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnvelopeKeyRotation(t *testing.T) {
+	oldKey, err := New(Bits2048)
+	require.Nil(t, err)
+	newKey, err := New(Bits2048)
+	require.Nil(t, err)
+	require.NotEqual(t, oldKey.KeyID(), newKey.KeyID())
+
+	clearText := make([]byte, 64*1024)
+	for i := range clearText {
+		clearText[i] = byte(i)
+	}
+
+	// Encrypt payloads using both the previous and the current key
+	envOld, err := EncryptEnvelope(oldKey.PubKey(), clearText)
+	require.Nil(t, err)
+	envNew, err := EncryptEnvelope(newKey.PubKey(), clearText)
+	require.Nil(t, err)
+
+	id, err := EnvelopeKeyID(envOld)
+	require.Nil(t, err)
+	require.Equal(t, oldKey.KeyID(), id)
+	require.Len(t, id.String(), 2*keyIDLen)
+
+	// A Decryptor holding both keys must be able to decrypt both envelopes
+	dec := NewDecryptor(newKey, oldKey)
+	for _, env := range [][]byte{envOld, envNew} {
+		res, err := dec.Decrypt(env)
+		require.Nil(t, err)
+		require.Equal(t, clearText, res)
+	}
+
+	// After retiring the old key, only the new envelope can be decrypted
+	dec.RemoveKey(oldKey.KeyID())
+	_, err = dec.Decrypt(envOld)
+	require.ErrorIs(t, err, ErrUnknownKeyID)
+	res, err := dec.Decrypt(envNew)
+	require.Nil(t, err)
+	require.Equal(t, clearText, res)
+
+	dec.AddKey(oldKey)
+	res, err = dec.Decrypt(envOld)
+	require.Nil(t, err)
+	require.Equal(t, clearText, res)
+
+	// Direct decryption using the wrong key must fail
+	_, err = newKey.DecryptEnvelope(envOld)
+	require.ErrorIs(t, err, ErrUnknownKeyID)
+}
+
+func TestEnvelopeInvalid(t *testing.T) {
+	key, err := New(Bits2048)
+	require.Nil(t, err)
+
+	_, err = EncryptEnvelope(nil, []byte("test"))
+	require.Error(t, err)
+
+	dec := NewDecryptor(key)
+	for _, env := range [][]byte{
+		nil,
+		{},
+		{0x2, 0x0, 0x0, 0x0, 0x0, 0x0, 0x0, 0x0, 0x0, 0x0, 0x0},
+	} {
+		_, err = dec.Decrypt(env)
+		require.ErrorIs(t, err, ErrInvalidEnvelope)
+	}
+
+	// Truncated envelope
+	env, err := EncryptEnvelope(key.PubKey(), []byte("test"))
+	require.Nil(t, err)
+	_, err = dec.Decrypt(env[:envelopeHeaderLen+10])
+	require.ErrorIs(t, err, ErrInvalidEnvelope)
+
+	// Tampered envelope
+	env[len(env)-1] ^= 0xFF
+	_, err = dec.Decrypt(env)
+	require.Error(t, err)
+}
+
+func TestParseEnvelope(t *testing.T) {
+	symKey, err := NewSymmetricKey(XChaCha20Poly1305)
+	require.Nil(t, err)
+	rsaKey, err := New(Bits2048)
+	require.Nil(t, err)
+
+	clearText := []byte("This is a test message")
+
+	data, err := EncryptXChaCha20(symKey, clearText)
+	require.Nil(t, err)
+	env, err := ParseEnvelope(data)
+	require.Nil(t, err)
+	require.EqualValues(t, EnvelopeVersion, env.Version)
+	require.Equal(t, XChaCha20Poly1305, env.Algorithm)
+	require.Equal(t, NewSymmetricKeyID(symKey), env.KeyID)
+	require.Empty(t, env.WrappedKey)
+	require.Len(t, env.Nonce, 24)
+	require.Len(t, env.CipherText, len(clearText))
+	require.Len(t, env.Tag, 16)
+	require.Equal(t, data, env.Marshal())
+
+	data, err = EncryptEnvelope(rsaKey.PubKey(), clearText)
+	require.Nil(t, err)
+	env, err = ParseEnvelope(data)
+	require.Nil(t, err)
+	require.Equal(t, RSAOAEPAES256GCM, env.Algorithm)
+	require.Equal(t, rsaKey.KeyID(), env.KeyID)
+	require.Len(t, env.WrappedKey, Bits2048/8)
+	require.Len(t, env.Nonce, 12)
+	require.Equal(t, data, env.Marshal())
+
+	data, err = rsaKey.Encrypt(clearText, nil)
+	require.Nil(t, err)
+	env, err = ParseEnvelope(data)
+	require.Nil(t, err)
+	require.Equal(t, RSAOAEP, env.Algorithm)
+	require.Equal(t, rsaKey.KeyID(), env.KeyID)
+	require.Empty(t, env.Nonce)
+	require.Len(t, env.CipherText, Bits2048/8)
+	require.Equal(t, data, env.Marshal())
+
+	// Envelopes of the wrong type must be rejected
+	_, err = DecryptAES(symKey, data)
+	require.ErrorIs(t, err, ErrAlgorithmMismatch)
+	data, err = EncryptAES(symKey, clearText)
+	require.Nil(t, err)
+	_, err = rsaKey.DecryptEnvelope(data)
+	require.ErrorIs(t, err, ErrAlgorithmMismatch)
+
+	// Unsupported version / algorithm
+	data[2] = EnvelopeVersion + 1
+	_, err = ParseEnvelope(data)
+	require.ErrorIs(t, err, ErrInvalidEnvelope)
+	data[2], data[3] = EnvelopeVersion, 42
+	_, err = ParseEnvelope(data)
+	require.ErrorIs(t, err, ErrUnsupportedAlgorithm)
+}
+
+func TestEnvelopeAuthenticatedHeader(t *testing.T) {
+	symKey, err := NewSymmetricKey(AES256GCM)
+	require.Nil(t, err)
+	rsaKey, err := New(Bits2048)
+	require.Nil(t, err)
+
+	clearText := []byte("This is a test message")
+
+	// Manipulating the header of an envelope must cause the authentication to fail
+	data, err := EncryptAES(symKey, clearText)
+	require.Nil(t, err)
+	env, err := ParseEnvelope(data)
+	require.Nil(t, err)
+	env.KeyID[0] ^= 0xFF
+	_, err = open(AES256GCM, symKey, env)
+	require.Error(t, err)
+
+	data, err = EncryptEnvelope(rsaKey.PubKey(), clearText)
+	require.Nil(t, err)
+	env, err = ParseEnvelope(data)
+	require.Nil(t, err)
+	env.Version++
+	_, err = rsaKey.open(env)
+	require.Error(t, err)
+
+	// Raw RSA-OAEP cipher texts (as produced by previous versions) can still be decrypted
+	raw, err := rsa.EncryptOAEP(sha256.New(), rand.Reader, rsaKey.PubKey(), clearText, nil)
+	require.Nil(t, err)
+	res, err := rsaKey.Decrypt(raw, nil)
+	require.Nil(t, err)
+	require.Equal(t, clearText, res)
+}
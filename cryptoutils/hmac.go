@@ -0,0 +1,19 @@
+package cryptoutils
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+)
+
+// HMAC computes the HMAC-SHA256 of a message using the provided key (e.g. to derive application
+// specific, non-reversible identifiers from sensitive data)
+func HMAC(key []byte, msg []byte) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write(msg)
+	return mac.Sum(nil)
+}
+
+// VerifyHMAC verifies the HMAC-SHA256 of a message (in constant time)
+func VerifyHMAC(key []byte, msg []byte, mac []byte) bool {
+	return hmac.Equal(HMAC(key, msg), mac)
+}
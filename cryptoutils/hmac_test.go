@@ -0,0 +1,20 @@
+package cryptoutils
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestHMAC(t *testing.T) {
+
+	// RFC 4231, test case 2
+	mac := HMAC([]byte("Jefe"), []byte("what do ya want for nothing?"))
+	require.Equal(t, "5bdcc146bf60754e6a042426089575c75a003f089d2739839dec58b964ec3843", hex.EncodeToString(mac))
+
+	require.True(t, VerifyHMAC([]byte("Jefe"), []byte("what do ya want for nothing?"), mac))
+	require.False(t, VerifyHMAC([]byte("Jeff"), []byte("what do ya want for nothing?"), mac))
+	require.False(t, VerifyHMAC([]byte("Jefe"), []byte("what do ya want for nothing!"), mac))
+	require.False(t, VerifyHMAC([]byte("Jefe"), []byte("what do ya want for nothing?"), mac[:16]))
+}
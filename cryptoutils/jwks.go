@@ -0,0 +1,152 @@
+package cryptoutils
+
+import (
+	"context"
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+)
+
+// maxJWKSSize limits the size of a JWKS document fetched from a remote endpoint
+const maxJWKSSize = 1 << 20
+
+// ErrKeyNotInJWKS denotes that a key identifier could not be found in a JWKS
+var ErrKeyNotInJWKS = errors.New("key not found in JWKS")
+
+// JWK denotes an individual JSON Web Key (limited to the RSA and Ed25519 key types)
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid,omitempty"`
+	Use string `json:"use,omitempty"`
+	Alg string `json:"alg,omitempty"`
+
+	// RSA parameters
+	N string `json:"n,omitempty"`
+	E string `json:"e,omitempty"`
+
+	// OKP (Ed25519) parameters
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+}
+
+// JWKS denotes a JSON Web Key Set
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+// FetchJWKS retrieves a JWKS from the provided URL (using http.DefaultClient if client is nil)
+func FetchJWKS(ctx context.Context, client *http.Client, url string) (*JWKS, error) {
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code fetching JWKS: %d", resp.StatusCode)
+	}
+
+	var jwks JWKS
+	if err = json.NewDecoder(io.LimitReader(resp.Body, maxJWKSSize)).Decode(&jwks); err != nil {
+		return nil, err
+	}
+
+	return &jwks, nil
+}
+
+// Add adds a public key (RSA or Ed25519) with the given key identifier to the JWKS
+func (j *JWKS) Add(kid string, pubKey crypto.PublicKey) error {
+	switch key := pubKey.(type) {
+	case *rsa.PublicKey:
+		j.Keys = append(j.Keys, JWK{
+			Kty: "RSA",
+			Kid: kid,
+			Use: "sig",
+			N:   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+			E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+		})
+	case ed25519.PublicKey:
+		j.Keys = append(j.Keys, JWK{
+			Kty: "OKP",
+			Kid: kid,
+			Use: "sig",
+			Alg: string(EdDSA),
+			Crv: "Ed25519",
+			X:   base64.RawURLEncoding.EncodeToString(key),
+		})
+	default:
+		return fmt.Errorf("unsupported key type: %T", pubKey)
+	}
+
+	return nil
+}
+
+// Key returns the public key with the given key identifier from the JWKS
+func (j *JWKS) Key(kid string) (crypto.PublicKey, error) {
+	for _, key := range j.Keys {
+		if key.Kid == kid {
+			return key.PublicKey()
+		}
+	}
+
+	return nil, fmt.Errorf("%w: %s", ErrKeyNotInJWKS, kid)
+}
+
+// KeyFunc returns a KeyFunc looking up keys in the JWKS (e.g. for use with VerifyJWT())
+func (j *JWKS) KeyFunc() KeyFunc {
+	return j.Key
+}
+
+// PublicKey returns the public key represented by the JWK
+func (k *JWK) PublicKey() (crypto.PublicKey, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := base64.RawURLEncoding.DecodeString(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := base64.RawURLEncoding.DecodeString(k.E)
+		if err != nil {
+			return nil, err
+		}
+		eInt := new(big.Int).SetBytes(e)
+		if !eInt.IsInt64() || eInt.Int64() > 1<<31-1 || eInt.Int64() < 2 {
+			return nil, errors.New("invalid RSA public exponent")
+		}
+		return &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(eInt.Int64()),
+		}, nil
+	case "OKP":
+		if k.Crv != "Ed25519" {
+			return nil, fmt.Errorf("unsupported curve: %s", k.Crv)
+		}
+		x, err := base64.RawURLEncoding.DecodeString(k.X)
+		if err != nil {
+			return nil, err
+		}
+		if len(x) != ed25519.PublicKeySize {
+			return nil, fmt.Errorf("invalid Ed25519 key size (want %d, have %d)", ed25519.PublicKeySize, len(x))
+		}
+		return ed25519.PublicKey(x), nil
+	default:
+		return nil, fmt.Errorf("unsupported key type: %s", k.Kty)
+	}
+}
@@ -0,0 +1,276 @@
+package cryptoutils
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// JWTAlgorithm denotes a JWT signature algorithm
+type JWTAlgorithm string
+
+// Provide the supported JWT signature algorithms
+const (
+	RS256 JWTAlgorithm = "RS256" // RSASSA-PKCS1-v1_5 using SHA-256
+	PS256 JWTAlgorithm = "PS256" // RSASSA-PSS using SHA-256
+	EdDSA JWTAlgorithm = "EdDSA" // Ed25519
+)
+
+var (
+	// ErrInvalidToken denotes that a token is malformed
+	ErrInvalidToken = errors.New("invalid token")
+
+	// ErrInvalidSignature denotes that the signature of a token could not be verified
+	ErrInvalidSignature = errors.New("invalid token signature")
+
+	// ErrUnsupportedJWTAlgorithm denotes that a token uses an unsupported signature algorithm
+	// (or one not matching the type of the provided key / not accepted by the validation)
+	ErrUnsupportedJWTAlgorithm = errors.New("unsupported token signature algorithm")
+
+	// ErrTokenExpired denotes that a token has expired
+	ErrTokenExpired = errors.New("token has expired")
+
+	// ErrTokenNotYetValid denotes that a token is not valid yet
+	ErrTokenNotYetValid = errors.New("token is not valid yet")
+
+	// ErrInvalidAudience denotes that a token was not issued for the expected audience
+	ErrInvalidAudience = errors.New("invalid token audience")
+
+	// ErrInvalidIssuer denotes that a token was not issued by the expected issuer
+	ErrInvalidIssuer = errors.New("invalid token issuer")
+)
+
+// Audience denotes the audience claim of a JWT (which may be encoded as single string or array)
+type Audience []string
+
+// UnmarshalJSON fulfils the json.Unmarshaler interface, accepting both a single string and an array
+func (a *Audience) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*a = Audience{single}
+		return nil
+	}
+
+	var multiple []string
+	if err := json.Unmarshal(data, &multiple); err != nil {
+		return err
+	}
+	*a = multiple
+
+	return nil
+}
+
+// Contains returns if the audience contains the provided value
+func (a Audience) Contains(aud string) bool {
+	for _, v := range a {
+		if v == aud {
+			return true
+		}
+	}
+	return false
+}
+
+// Claims denotes the registered claims of a JWT, to be embedded into custom claim types if required
+type Claims struct {
+	Issuer    string   `json:"iss,omitempty"`
+	Subject   string   `json:"sub,omitempty"`
+	Audience  Audience `json:"aud,omitempty"`
+	ExpiresAt int64    `json:"exp,omitempty"`
+	NotBefore int64    `json:"nbf,omitempty"`
+	IssuedAt  int64    `json:"iat,omitempty"`
+	ID        string   `json:"jti,omitempty"`
+}
+
+// JWTValidation denotes the parameters used to validate the claims of a JWT
+type JWTValidation struct {
+	Audience string        // Expected audience (not validated if empty)
+	Issuer   string        // Expected issuer (not validated if empty)
+	Leeway   time.Duration // Allowed clock skew when validating exp / nbf
+
+	// Accepted signature algorithms (if empty, any supported algorithm matching the key type is
+	// accepted, e.g. both RS256 and PS256 for an RSA key)
+	Algorithms []JWTAlgorithm
+
+	Now func() time.Time // Time source (defaults to time.Now)
+}
+
+// KeyFunc denotes a function providing the public key to verify a token signed by the key with
+// the given key identifier (which may be empty)
+type KeyFunc func(kid string) (crypto.PublicKey, error)
+
+// StaticKey returns a KeyFunc that always provides the same public key (ignoring the key identifier)
+func StaticKey(pubKey crypto.PublicKey) KeyFunc {
+	return func(_ string) (crypto.PublicKey, error) {
+		return pubKey, nil
+	}
+}
+
+type jwtHeader struct {
+	Alg JWTAlgorithm `json:"alg"`
+	Typ string       `json:"typ,omitempty"`
+	Kid string       `json:"kid,omitempty"`
+}
+
+// SignJWT creates a signed JWT from the provided claims (any JSON serializable type, usually a
+// struct embedding Claims) using the given algorithm and key, optionally tagged with a key identifier
+func SignJWT(alg JWTAlgorithm, key crypto.Signer, kid string, claims any) (string, error) {
+	if key == nil {
+		return "", errors.New("invalid (nil) signing key provided")
+	}
+	if err := checkJWTKey(alg, key.Public()); err != nil {
+		return "", err
+	}
+
+	header, err := json.Marshal(jwtHeader{Alg: alg, Typ: "JWT", Kid: kid})
+	if err != nil {
+		return "", err
+	}
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+
+	signingInput := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+
+	var sig []byte
+	switch alg {
+	case RS256:
+		digest := sha256.Sum256([]byte(signingInput))
+		sig, err = key.Sign(rand.Reader, digest[:], crypto.SHA256)
+	case PS256:
+		digest := sha256.Sum256([]byte(signingInput))
+		sig, err = key.Sign(rand.Reader, digest[:], &rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthEqualsHash, Hash: crypto.SHA256})
+	case EdDSA:
+		sig, err = key.Sign(rand.Reader, []byte(signingInput), crypto.Hash(0))
+	}
+	if err != nil {
+		return "", err
+	}
+
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(sig), nil
+}
+
+// VerifyJWT verifies the signature of a JWT (using the key provided by keyFn), validates its
+// registered claims and decodes its payload into claims (if non-nil)
+func VerifyJWT(token string, keyFn KeyFunc, validation JWTValidation, claims any) error {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return fmt.Errorf("%w: unexpected number of segments", ErrInvalidToken)
+	}
+
+	headerRaw, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidToken, err)
+	}
+	var header jwtHeader
+	if err = json.Unmarshal(headerRaw, &header); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidToken, err)
+	}
+	sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidToken, err)
+	}
+
+	if !validation.accepts(header.Alg) {
+		return fmt.Errorf("%w: %s (not accepted)", ErrUnsupportedJWTAlgorithm, header.Alg)
+	}
+	pubKey, err := keyFn(header.Kid)
+	if err != nil {
+		return err
+	}
+	if err = checkJWTKey(header.Alg, pubKey); err != nil {
+		return err
+	}
+
+	var (
+		signingInput = []byte(parts[0] + "." + parts[1])
+		valid        bool
+	)
+	switch header.Alg {
+	case RS256:
+		digest := sha256.Sum256(signingInput)
+		valid = rsa.VerifyPKCS1v15(pubKey.(*rsa.PublicKey), crypto.SHA256, digest[:], sig) == nil
+	case PS256:
+		digest := sha256.Sum256(signingInput)
+		valid = rsa.VerifyPSS(pubKey.(*rsa.PublicKey), crypto.SHA256, digest[:], sig, &rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthEqualsHash}) == nil
+	case EdDSA:
+		valid = ed25519.Verify(pubKey.(ed25519.PublicKey), signingInput, sig)
+	}
+	if !valid {
+		return ErrInvalidSignature
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidToken, err)
+	}
+	var registered Claims
+	if err = json.Unmarshal(payload, &registered); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidToken, err)
+	}
+	if err = registered.validate(validation); err != nil {
+		return err
+	}
+
+	if claims != nil {
+		return json.Unmarshal(payload, claims)
+	}
+	return nil
+}
+
+func (c *Claims) validate(v JWTValidation) error {
+	now := time.Now()
+	if v.Now != nil {
+		now = v.Now()
+	}
+
+	if c.ExpiresAt != 0 && now.After(time.Unix(c.ExpiresAt, 0).Add(v.Leeway)) {
+		return ErrTokenExpired
+	}
+	if c.NotBefore != 0 && now.Before(time.Unix(c.NotBefore, 0).Add(-v.Leeway)) {
+		return ErrTokenNotYetValid
+	}
+	if v.Audience != "" && !c.Audience.Contains(v.Audience) {
+		return ErrInvalidAudience
+	}
+	if v.Issuer != "" && c.Issuer != v.Issuer {
+		return ErrInvalidIssuer
+	}
+
+	return nil
+}
+
+func (v JWTValidation) accepts(alg JWTAlgorithm) bool {
+	if len(v.Algorithms) == 0 {
+		return true
+	}
+	for _, accepted := range v.Algorithms {
+		if alg == accepted {
+			return true
+		}
+	}
+	return false
+}
+
+func checkJWTKey(alg JWTAlgorithm, pubKey crypto.PublicKey) error {
+	var ok bool
+	switch alg {
+	case RS256, PS256:
+		_, ok = pubKey.(*rsa.PublicKey)
+	case EdDSA:
+		_, ok = pubKey.(ed25519.PublicKey)
+	}
+	if !ok {
+		return fmt.Errorf("%w: %s (key type %T)", ErrUnsupportedJWTAlgorithm, alg, pubKey)
+	}
+
+	return nil
+}
@@ -0,0 +1,170 @@
+package cryptoutils
+
+import (
+	"context"
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type testClaims struct {
+	Claims
+	Role string `json:"role"`
+}
+
+func TestJWTRoundTrip(t *testing.T) {
+	rsaKey, err := New(Bits2048)
+	require.Nil(t, err)
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	require.Nil(t, err)
+
+	now := time.Now()
+	claims := testClaims{
+		Claims: Claims{
+			Issuer:    "controller",
+			Subject:   "agent01",
+			Audience:  Audience{"api"},
+			ExpiresAt: now.Add(time.Minute).Unix(),
+			NotBefore: now.Add(-time.Minute).Unix(),
+			IssuedAt:  now.Unix(),
+		},
+		Role: "admin",
+	}
+
+	for _, cs := range []struct {
+		alg JWTAlgorithm
+		key crypto.Signer
+	}{
+		{RS256, rsaKey.PrivKey()},
+		{PS256, rsaKey.PrivKey()},
+		{EdDSA, edKey},
+	} {
+		t.Run(string(cs.alg), func(t *testing.T) {
+			token, err := SignJWT(cs.alg, cs.key, "key1", claims)
+			require.Nil(t, err)
+			require.Len(t, strings.Split(token, "."), 3)
+
+			var res testClaims
+			require.Nil(t, VerifyJWT(token, StaticKey(cs.key.Public()), JWTValidation{
+				Audience: "api",
+				Issuer:   "controller",
+			}, &res))
+			require.Equal(t, claims, res)
+
+			// Claims validation
+			require.ErrorIs(t, VerifyJWT(token, StaticKey(cs.key.Public()), JWTValidation{Audience: "other"}, nil), ErrInvalidAudience)
+			require.ErrorIs(t, VerifyJWT(token, StaticKey(cs.key.Public()), JWTValidation{Issuer: "other"}, nil), ErrInvalidIssuer)
+			require.ErrorIs(t, VerifyJWT(token, StaticKey(cs.key.Public()), JWTValidation{
+				Now: func() time.Time { return now.Add(time.Hour) },
+			}, nil), ErrTokenExpired)
+			require.ErrorIs(t, VerifyJWT(token, StaticKey(cs.key.Public()), JWTValidation{
+				Now: func() time.Time { return now.Add(-time.Hour) },
+			}, nil), ErrTokenNotYetValid)
+			require.Nil(t, VerifyJWT(token, StaticKey(cs.key.Public()), JWTValidation{
+				Now:    func() time.Time { return now.Add(2 * time.Minute) },
+				Leeway: 2 * time.Minute,
+			}, nil))
+
+			// Tampering with the payload must invalidate the signature
+			parts := strings.Split(token, ".")
+			tampered, err := json.Marshal(testClaims{Claims: claims.Claims, Role: "root"})
+			require.Nil(t, err)
+			parts[1] = base64.RawURLEncoding.EncodeToString(tampered)
+			require.Equal(t, ErrInvalidSignature, VerifyJWT(strings.Join(parts, "."), StaticKey(cs.key.Public()), JWTValidation{}, nil))
+		})
+	}
+
+	// Algorithm / key type confusion must be rejected
+	token, err := SignJWT(RS256, rsaKey.PrivKey(), "", claims)
+	require.Nil(t, err)
+	require.ErrorIs(t, VerifyJWT(token, StaticKey(edKey.Public()), JWTValidation{}, nil), ErrUnsupportedJWTAlgorithm)
+
+	// The accepted algorithms can be pinned
+	require.Nil(t, VerifyJWT(token, StaticKey(rsaKey.PubKey()), JWTValidation{Algorithms: []JWTAlgorithm{RS256}}, nil))
+	require.ErrorIs(t, VerifyJWT(token, StaticKey(rsaKey.PubKey()), JWTValidation{Algorithms: []JWTAlgorithm{PS256}}, nil), ErrUnsupportedJWTAlgorithm)
+	_, err = SignJWT(EdDSA, rsaKey.PrivKey(), "", claims)
+	require.ErrorIs(t, err, ErrUnsupportedJWTAlgorithm)
+	_, err = SignJWT(JWTAlgorithm("none"), edKey, "", claims)
+	require.ErrorIs(t, err, ErrUnsupportedJWTAlgorithm)
+}
+
+func TestJWTWithJWKS(t *testing.T) {
+	rsaKey, err := New(Bits2048)
+	require.Nil(t, err)
+	edPub, edKey, err := ed25519.GenerateKey(rand.Reader)
+	require.Nil(t, err)
+
+	jwks := &JWKS{}
+	require.Nil(t, jwks.Add("rsa1", rsaKey.PubKey()))
+	require.Nil(t, jwks.Add("ed1", edPub))
+	require.Error(t, jwks.Add("invalid", "not a key"))
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(jwks)
+	}))
+	defer srv.Close()
+
+	fetched, err := FetchJWKS(context.Background(), nil, srv.URL)
+	require.Nil(t, err)
+	require.Len(t, fetched.Keys, 2)
+
+	for kid, key := range map[string]crypto.Signer{
+		"rsa1": rsaKey.PrivKey(),
+		"ed1":  edKey,
+	} {
+		alg := RS256
+		if kid == "ed1" {
+			alg = EdDSA
+		}
+		token, err := SignJWT(alg, key, kid, Claims{Subject: "agent01"})
+		require.Nil(t, err)
+
+		var res Claims
+		require.Nil(t, VerifyJWT(token, fetched.KeyFunc(), JWTValidation{}, &res))
+		require.Equal(t, "agent01", res.Subject)
+	}
+
+	token, err := SignJWT(RS256, rsaKey.PrivKey(), "unknown", Claims{})
+	require.Nil(t, err)
+	require.ErrorIs(t, VerifyJWT(token, fetched.KeyFunc(), JWTValidation{}, nil), ErrKeyNotInJWKS)
+
+	srvFail := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer srvFail.Close()
+	_, err = FetchJWKS(context.Background(), nil, srvFail.URL)
+	require.Error(t, err)
+}
+
+func TestJWTInvalid(t *testing.T) {
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	require.Nil(t, err)
+	keyFn := StaticKey(edKey.Public())
+
+	_, err = SignJWT(EdDSA, nil, "", Claims{})
+	require.Error(t, err)
+
+	for _, token := range []string{
+		"",
+		"a.b",
+		"!.b.c",
+		"e30.!.c",
+		"e30.e30.!",
+	} {
+		require.ErrorIs(t, VerifyJWT(token, keyFn, JWTValidation{}, nil), ErrInvalidToken)
+	}
+
+	var aud Audience
+	require.Nil(t, json.Unmarshal([]byte(`["a","b"]`), &aud))
+	require.Equal(t, Audience{"a", "b"}, aud)
+	require.Error(t, json.Unmarshal([]byte(`42`), &aud))
+}
@@ -0,0 +1,206 @@
+package cryptoutils
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/fako1024/gotools/concurrency"
+)
+
+// keyPoolRetryInterval denotes the delay before a slot is refilled after a failed key generation
+const keyPoolRetryInterval = 100 * time.Millisecond
+
+// ErrKeyPoolClosed denotes that a KeyPool has been closed
+var ErrKeyPoolClosed = errors.New("key pool closed")
+
+// KeyGenerator denotes a function generating a new private key
+type KeyGenerator func() (crypto.Signer, error)
+
+// RSAKeyGenerator returns a KeyGenerator for RSA keys of the given size
+func RSAKeyGenerator(bits Bits) KeyGenerator {
+	return func() (crypto.Signer, error) {
+		return rsa.GenerateKey(rand.Reader, bits)
+	}
+}
+
+// ECDSAKeyGenerator returns a KeyGenerator for ECDSA keys on the given curve
+func ECDSAKeyGenerator(curve elliptic.Curve) KeyGenerator {
+	return func() (crypto.Signer, error) {
+		return ecdsa.GenerateKey(curve, rand.Reader)
+	}
+}
+
+// KeyPool provides a pool of pre-generated private keys, refilled in the background by a limited
+// number of concurrent workers, so that keys can be handed out without waiting for their generation
+type KeyPool struct {
+	keys  chan crypto.Signer
+	slots chan struct{}
+	genFn KeyGenerator
+	sem   concurrency.Semaphore
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+
+	lastErr error
+	sync.Mutex
+}
+
+// NewKeyPool instantiates a new KeyPool holding up to size keys generated by genFn, using
+// at most nWorkers concurrent generation routines, and starts filling it in the background
+func NewKeyPool(size int, nWorkers int, genFn KeyGenerator) (*KeyPool, error) {
+	if size <= 0 {
+		return nil, fmt.Errorf("invalid key pool size: %d", size)
+	}
+	if nWorkers <= 0 {
+		return nil, fmt.Errorf("invalid number of key pool workers: %d", nWorkers)
+	}
+	if genFn == nil {
+		return nil, errors.New("invalid (nil) key generator provided")
+	}
+
+	obj := &KeyPool{
+		keys:  make(chan crypto.Signer, size),
+		slots: make(chan struct{}, size),
+		genFn: genFn,
+		sem:   concurrency.New(nWorkers),
+	}
+	obj.ctx, obj.cancel = context.WithCancel(context.Background())
+
+	// Mark all slots as empty, then start the refill loop
+	for i := 0; i < size; i++ {
+		obj.slots <- struct{}{}
+	}
+	obj.wg.Add(1)
+	go obj.refill()
+
+	return obj, nil
+}
+
+// Get retrieves a pre-generated key from the pool (or generates one synchronously in case the
+// pool is currently exhausted)
+func (p *KeyPool) Get() (crypto.Signer, error) {
+	select {
+	case <-p.ctx.Done():
+		return nil, ErrKeyPoolClosed
+	default:
+	}
+
+	select {
+	case key := <-p.keys:
+		p.releaseSlot()
+		return key, nil
+	default:
+		return p.genFn()
+	}
+}
+
+// GetWait retrieves a pre-generated key from the pool, waiting for one to become available (or
+// until the context is done)
+func (p *KeyPool) GetWait(ctx context.Context) (crypto.Signer, error) {
+	select {
+	case <-p.ctx.Done():
+		return nil, ErrKeyPoolClosed
+	default:
+	}
+
+	select {
+	case key := <-p.keys:
+		p.releaseSlot()
+		return key, nil
+	case <-p.ctx.Done():
+		return nil, ErrKeyPoolClosed
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// GetRSA retrieves a pre-generated key from the pool as RSA object (requires the pool to be
+// filled by an RSAKeyGenerator)
+func (p *KeyPool) GetRSA() (*RSA, error) {
+	key, err := p.Get()
+	if err != nil {
+		return nil, err
+	}
+
+	rsaKey, ok := key.(*rsa.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("unexpected key type in pool: %T", key)
+	}
+
+	return &RSA{privKey: rsaKey}, nil
+}
+
+// Len returns the number of keys currently available in the pool
+func (p *KeyPool) Len() int {
+	return len(p.keys)
+}
+
+// Err returns the last error encountered during background key generation (if any)
+func (p *KeyPool) Err() error {
+	p.Lock()
+	defer p.Unlock()
+
+	return p.lastErr
+}
+
+// Close stops the background key generation and waits for all workers to terminate
+func (p *KeyPool) Close() {
+	p.cancel()
+	p.wg.Wait()
+}
+
+func (p *KeyPool) releaseSlot() {
+	select {
+	case p.slots <- struct{}{}:
+	default:
+	}
+}
+
+func (p *KeyPool) refill() {
+	defer p.wg.Done()
+
+	for {
+		select {
+		case <-p.ctx.Done():
+			return
+		case <-p.slots:
+		}
+
+		// Limit the number of concurrent key generations
+		p.sem.Add()
+		p.wg.Add(1)
+		go func() {
+			defer func() {
+				p.sem.Done()
+				p.wg.Done()
+			}()
+
+			key, err := p.genFn()
+			if err != nil {
+				p.Lock()
+				p.lastErr = err
+				p.Unlock()
+
+				// The slot is released after a delay (avoiding a busy loop in case of a
+				// persistent error), otherwise the pool would shrink permanently
+				select {
+				case <-p.ctx.Done():
+				case <-time.After(keyPoolRetryInterval):
+					p.releaseSlot()
+				}
+				return
+			}
+
+			p.keys <- key
+		}()
+	}
+}
@@ -0,0 +1,140 @@
+package cryptoutils
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeyPool(t *testing.T) {
+	pool, err := NewKeyPool(4, 2, ECDSAKeyGenerator(elliptic.P256()))
+	require.Nil(t, err)
+	defer pool.Close()
+
+	// Wait for the pool to fill up
+	require.Eventually(t, func() bool {
+		return pool.Len() == 4
+	}, 10*time.Second, time.Millisecond)
+
+	seen := make(map[string]struct{})
+	for i := 0; i < 10; i++ {
+		key, err := pool.Get()
+		require.Nil(t, err)
+		ecKey, ok := key.(*ecdsa.PrivateKey)
+		require.True(t, ok)
+		seen[ecKey.D.String()] = struct{}{}
+	}
+	require.Len(t, seen, 10, "all handed out keys must be unique")
+
+	// Pool must be refilled in the background
+	require.Eventually(t, func() bool {
+		return pool.Len() == 4
+	}, 10*time.Second, time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	_, err = pool.GetWait(ctx)
+	require.Nil(t, err)
+	require.Nil(t, pool.Err())
+}
+
+func TestKeyPoolRSA(t *testing.T) {
+	pool, err := NewKeyPool(1, 1, RSAKeyGenerator(Bits2048))
+	require.Nil(t, err)
+	defer pool.Close()
+
+	key, err := pool.GetRSA()
+	require.Nil(t, err)
+	require.Equal(t, Bits2048/8, key.PrivKey().Size())
+
+	ecPool, err := NewKeyPool(1, 1, ECDSAKeyGenerator(elliptic.P256()))
+	require.Nil(t, err)
+	defer ecPool.Close()
+	_, err = ecPool.GetRSA()
+	require.Error(t, err)
+}
+
+func TestKeyPoolConcurrencyLimit(t *testing.T) {
+	var inFlight, maxInFlight int32
+	genFn := func() (crypto.Signer, error) {
+		cur := atomic.AddInt32(&inFlight, 1)
+		for {
+			prev := atomic.LoadInt32(&maxInFlight)
+			if cur <= prev || atomic.CompareAndSwapInt32(&maxInFlight, prev, cur) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+		atomic.AddInt32(&inFlight, -1)
+		return ECDSAKeyGenerator(elliptic.P256())()
+	}
+
+	pool, err := NewKeyPool(16, 3, genFn)
+	require.Nil(t, err)
+	require.Eventually(t, func() bool {
+		return pool.Len() == 16
+	}, 10*time.Second, time.Millisecond)
+	pool.Close()
+
+	require.LessOrEqual(t, atomic.LoadInt32(&maxInFlight), int32(3))
+
+	_, err = pool.Get()
+	require.ErrorIs(t, err, ErrKeyPoolClosed)
+	_, err = pool.GetWait(context.Background())
+	require.ErrorIs(t, err, ErrKeyPoolClosed)
+}
+
+func TestKeyPoolInvalid(t *testing.T) {
+	_, err := NewKeyPool(0, 1, RSAKeyGenerator(Bits2048))
+	require.Error(t, err)
+	_, err = NewKeyPool(1, 0, RSAKeyGenerator(Bits2048))
+	require.Error(t, err)
+	_, err = NewKeyPool(1, 1, nil)
+	require.Error(t, err)
+
+	errGen := errors.New("generation failed")
+	pool, err := NewKeyPool(1, 1, func() (crypto.Signer, error) {
+		return nil, errGen
+	})
+	require.Nil(t, err)
+	defer pool.Close()
+
+	require.Eventually(t, func() bool {
+		return pool.Err() != nil
+	}, 10*time.Second, time.Millisecond)
+	require.ErrorIs(t, pool.Err(), errGen)
+	_, err = pool.Get()
+	require.ErrorIs(t, err, errGen)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err = pool.GetWait(ctx)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestKeyPoolTransientError(t *testing.T) {
+	var nCalls int32
+	pool, err := NewKeyPool(1, 1, func() (crypto.Signer, error) {
+		if atomic.AddInt32(&nCalls, 1) <= 3 {
+			return nil, errors.New("generation failed")
+		}
+		return ECDSAKeyGenerator(elliptic.P256())()
+	})
+	require.Nil(t, err)
+	defer pool.Close()
+
+	// Failed generations must not permanently consume the slot of the pool
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	key, err := pool.GetWait(ctx)
+	require.Nil(t, err)
+	require.NotNil(t, key)
+	require.Error(t, pool.Err())
+}
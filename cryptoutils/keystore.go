@@ -0,0 +1,337 @@
+package cryptoutils
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+
+	"golang.org/x/crypto/scrypt"
+)
+
+const (
+	keystoreMagic     = "GTKS"
+	keystoreVersion   = 1
+	keystoreSaltSize  = 16
+	keystoreHeaderLen = len(keystoreMagic) + 1 + keystoreSaltSize + 3*4
+
+	keystoreFilePerm = 0600
+
+	// Default scrypt parameters (as recommended for interactive logins as of 2017)
+	defaultScryptN = 1 << 15
+	defaultScryptR = 8
+	defaultScryptP = 1
+
+	// Upper bounds for the scrypt parameters (limiting the memory / CPU cost of the key derivation,
+	// in particular for parameters read from an untrusted keystore file)
+	maxScryptN  = 1 << 20
+	maxScryptRP = 1 << 30
+)
+
+var (
+	// ErrInvalidPassphrase denotes that the keystore could not be decrypted using the provided passphrase
+	ErrInvalidPassphrase = errors.New("invalid keystore passphrase (or corrupt keystore)")
+
+	// ErrInvalidKeystore denotes that the keystore file is malformed
+	ErrInvalidKeystore = errors.New("invalid keystore file")
+
+	// ErrKeyNotFound denotes that a named key does not exist in the keystore
+	ErrKeyNotFound = errors.New("key not found in keystore")
+)
+
+// Keystore denotes a file-based store of named keys, persisted to disk encrypted (using
+// XChaCha20-Poly1305) under a key derived from a master passphrase (using scrypt)
+type Keystore struct {
+	path       string
+	passphrase []byte
+	keys       map[string][]byte
+
+	scryptN, scryptR, scryptP int
+
+	sync.Mutex
+}
+
+// KeystoreOption denotes a functional option for the Keystore type
+type KeystoreOption func(*Keystore)
+
+// WithScryptParams sets the scrypt key derivation parameters used when saving the Keystore
+// (parameters used for an existing file are read from the file itself)
+func WithScryptParams(n, r, p int) KeystoreOption {
+	return func(k *Keystore) {
+		k.scryptN, k.scryptR, k.scryptP = n, r, p
+	}
+}
+
+// NewKeystore instantiates a new (empty) Keystore at the given path, protected by the provided
+// passphrase (call Load() to read an existing keystore from disk)
+func NewKeystore(path string, passphrase []byte, options ...KeystoreOption) *Keystore {
+	obj := &Keystore{
+		path:       path,
+		passphrase: bytes.Clone(passphrase),
+		keys:       make(map[string][]byte),
+		scryptN:    defaultScryptN,
+		scryptR:    defaultScryptR,
+		scryptP:    defaultScryptP,
+	}
+
+	// Apply functional options (if present)
+	for _, opt := range options {
+		opt(obj)
+	}
+
+	return obj
+}
+
+// OpenKeystore instantiates a Keystore and loads its content from the given path
+func OpenKeystore(path string, passphrase []byte, options ...KeystoreOption) (*Keystore, error) {
+	obj := NewKeystore(path, passphrase, options...)
+	if err := obj.Load(); err != nil {
+		return nil, err
+	}
+	return obj, nil
+}
+
+// Load reads and decrypts the keystore from disk, replacing all keys currently held in memory
+func (k *Keystore) Load() error {
+	k.Lock()
+	defer k.Unlock()
+
+	unlock, err := lockFile(k.path + ".lock")
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	data, err := os.ReadFile(filepath.Clean(k.path))
+	if err != nil {
+		return err
+	}
+
+	keys, err := k.decode(data)
+	if err != nil {
+		return err
+	}
+	k.keys = keys
+
+	return nil
+}
+
+// Save encrypts and writes the keystore to disk (atomically replacing any existing file)
+func (k *Keystore) Save() error {
+	k.Lock()
+	defer k.Unlock()
+
+	return k.save()
+}
+
+// Rotate changes the master passphrase of the keystore (generating a new salt in the process)
+// and immediately persists the re-encrypted keystore to disk
+func (k *Keystore) Rotate(newPassphrase []byte) error {
+	k.Lock()
+	defer k.Unlock()
+
+	oldPassphrase := k.passphrase
+	k.passphrase = bytes.Clone(newPassphrase)
+	if err := k.save(); err != nil {
+		k.passphrase = oldPassphrase
+		return err
+	}
+
+	return nil
+}
+
+// Set stores a named key in the keystore (overwriting any existing key with the same name)
+func (k *Keystore) Set(name string, key []byte) {
+	k.Lock()
+	defer k.Unlock()
+
+	k.keys[name] = append([]byte(nil), key...)
+}
+
+// Get retrieves a named key from the keystore
+func (k *Keystore) Get(name string) ([]byte, error) {
+	k.Lock()
+	defer k.Unlock()
+
+	key, exists := k.keys[name]
+	if !exists {
+		return nil, fmt.Errorf("%w: %s", ErrKeyNotFound, name)
+	}
+
+	return append([]byte(nil), key...), nil
+}
+
+// Delete removes a named key from the keystore
+func (k *Keystore) Delete(name string) {
+	k.Lock()
+	defer k.Unlock()
+
+	delete(k.keys, name)
+}
+
+// Names returns the (sorted) names of all keys in the keystore
+func (k *Keystore) Names() []string {
+	k.Lock()
+	defer k.Unlock()
+
+	names := make([]string, 0, len(k.keys))
+	for name := range k.keys {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// SetRSA stores a named RSA private key in the keystore
+func (k *Keystore) SetRSA(name string, key *RSA) {
+	k.Set(name, key.PrivKeyPEM().Bytes)
+}
+
+// GetRSA retrieves a named RSA private key from the keystore
+func (k *Keystore) GetRSA(name string) (*RSA, error) {
+	key, err := k.Get(name)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewFromPEM(&pem.Block{
+		Type:  "RSA PRIVATE KEY",
+		Bytes: key,
+	})
+}
+
+////////////////////////////////////////////////////////////////////////////////////////
+
+func (k *Keystore) save() error {
+	data, err := k.encode()
+	if err != nil {
+		return err
+	}
+
+	unlock, err := lockFile(k.path + ".lock")
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	// Write to a temporary file and rename it to avoid partially written keystores
+	tmpFile, err := os.CreateTemp(filepath.Dir(k.path), filepath.Base(k.path)+".tmp*")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = os.Remove(tmpFile.Name())
+	}()
+
+	if _, err = tmpFile.Write(data); err != nil {
+		_ = tmpFile.Close()
+		return err
+	}
+	if err = tmpFile.Chmod(keystoreFilePerm); err != nil {
+		_ = tmpFile.Close()
+		return err
+	}
+	if err = tmpFile.Sync(); err != nil {
+		_ = tmpFile.Close()
+		return err
+	}
+	if err = tmpFile.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmpFile.Name(), k.path)
+}
+
+func (k *Keystore) encode() ([]byte, error) {
+	payload, err := json.Marshal(k.keys)
+	if err != nil {
+		return nil, err
+	}
+
+	salt := make([]byte, keystoreSaltSize)
+	if _, err = rand.Read(salt); err != nil {
+		return nil, err
+	}
+
+	if err = validateScryptParams(k.scryptN, k.scryptR, k.scryptP); err != nil {
+		return nil, err
+	}
+	key, err := scrypt.Key(k.passphrase, salt, k.scryptN, k.scryptR, k.scryptP, SymmetricKeySize)
+	if err != nil {
+		return nil, err
+	}
+
+	// Header: magic | version | salt | scrypt N | scrypt r | scrypt p
+	buf := bytes.NewBuffer(make([]byte, 0, keystoreHeaderLen+len(payload)+64))
+	buf.WriteString(keystoreMagic)
+	buf.WriteByte(keystoreVersion)
+	buf.Write(salt)
+	for _, param := range []int{k.scryptN, k.scryptR, k.scryptP} {
+		_ = binary.Write(buf, binary.BigEndian, uint32(param)) // #nosec G115
+	}
+
+	cipherText, err := EncryptSymmetric(XChaCha20Poly1305, key, payload)
+	if err != nil {
+		return nil, err
+	}
+	buf.Write(cipherText)
+
+	return buf.Bytes(), nil
+}
+
+func (k *Keystore) decode(data []byte) (map[string][]byte, error) {
+	if len(data) < keystoreHeaderLen ||
+		string(data[:len(keystoreMagic)]) != keystoreMagic ||
+		data[len(keystoreMagic)] != keystoreVersion {
+		return nil, ErrInvalidKeystore
+	}
+
+	pos := len(keystoreMagic) + 1
+	salt := data[pos : pos+keystoreSaltSize]
+	pos += keystoreSaltSize
+
+	var params [3]int
+	for i := range params {
+		params[i] = int(binary.BigEndian.Uint32(data[pos : pos+4]))
+		pos += 4
+	}
+
+	if err := validateScryptParams(params[0], params[1], params[2]); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidKeystore, err)
+	}
+	key, err := scrypt.Key(k.passphrase, salt, params[0], params[1], params[2], SymmetricKeySize)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidKeystore, err)
+	}
+
+	payload, err := DecryptSymmetric(XChaCha20Poly1305, key, data[pos:])
+	if err != nil {
+		return nil, ErrInvalidPassphrase
+	}
+
+	keys := make(map[string][]byte)
+	if err = json.Unmarshal(payload, &keys); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidKeystore, err)
+	}
+
+	return keys, nil
+}
+
+func validateScryptParams(n, r, p int) error {
+	if n <= 1 || n > maxScryptN || n&(n-1) != 0 {
+		return fmt.Errorf("invalid scrypt parameter N: %d (must be a power of two <= %d)", n, maxScryptN)
+	}
+	if r <= 0 || p <= 0 || uint64(r)*uint64(p) >= maxScryptRP { // #nosec G115
+		return fmt.Errorf("invalid scrypt parameters r / p: %d / %d (r * p must be < %d)", r, p, maxScryptRP)
+	}
+
+	return nil
+}
@@ -0,0 +1,140 @@
+package cryptoutils
+
+import (
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// Using very low scrypt cost parameters to avoid timing out
+var testScryptParams = WithScryptParams(1<<10, 8, 1)
+
+func TestKeystoreRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "keys.db")
+
+	rsaKey, err := New(Bits2048)
+	require.Nil(t, err)
+	symKey, err := NewSymmetricKey(AES256GCM)
+	require.Nil(t, err)
+
+	ks := NewKeystore(path, []byte("secret"), testScryptParams)
+	ks.SetRSA("agent", rsaKey)
+	ks.Set("payload", symKey)
+	require.Nil(t, ks.Save())
+
+	// Ensure the key material is not stored in plain text
+	raw, err := os.ReadFile(path)
+	require.Nil(t, err)
+	require.NotContains(t, string(raw), "payload")
+	stat, err := os.Stat(path)
+	require.Nil(t, err)
+	require.Equal(t, os.FileMode(keystoreFilePerm), stat.Mode().Perm())
+
+	ks2, err := OpenKeystore(path, []byte("secret"))
+	require.Nil(t, err)
+	require.Equal(t, []string{"agent", "payload"}, ks2.Names())
+
+	rsaKey2, err := ks2.GetRSA("agent")
+	require.Nil(t, err)
+	require.Equal(t, rsaKey.PrivKeyString(), rsaKey2.PrivKeyString())
+	symKey2, err := ks2.Get("payload")
+	require.Nil(t, err)
+	require.Equal(t, symKey, symKey2)
+
+	ks2.Delete("payload")
+	_, err = ks2.Get("payload")
+	require.ErrorIs(t, err, ErrKeyNotFound)
+	_, err = ks2.GetRSA("payload")
+	require.ErrorIs(t, err, ErrKeyNotFound)
+}
+
+func TestKeystoreRotate(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "keys.db")
+
+	ks := NewKeystore(path, []byte("old"), testScryptParams)
+	ks.Set("key", []byte("value"))
+	require.Nil(t, ks.Save())
+	require.Nil(t, ks.Rotate([]byte("new")))
+
+	_, err := OpenKeystore(path, []byte("old"))
+	require.ErrorIs(t, err, ErrInvalidPassphrase)
+
+	ks2, err := OpenKeystore(path, []byte("new"))
+	require.Nil(t, err)
+	val, err := ks2.Get("key")
+	require.Nil(t, err)
+	require.Equal(t, []byte("value"), val)
+}
+
+func TestKeystoreConcurrentSave(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "keys.db")
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ks := NewKeystore(path, []byte("secret"), testScryptParams)
+			ks.Set("key", []byte("value"))
+			require.Nil(t, ks.Save())
+		}()
+	}
+	wg.Wait()
+
+	ks, err := OpenKeystore(path, []byte("secret"))
+	require.Nil(t, err)
+	require.Equal(t, []string{"key"}, ks.Names())
+}
+
+func TestKeystoreInvalid(t *testing.T) {
+	dir := t.TempDir()
+
+	_, err := OpenKeystore(filepath.Join(dir, "missing.db"), []byte("secret"))
+	require.ErrorIs(t, err, os.ErrNotExist)
+
+	path := filepath.Join(dir, "corrupt.db")
+	require.Nil(t, os.WriteFile(path, []byte("not a keystore"), 0600))
+	_, err = OpenKeystore(path, []byte("secret"))
+	require.ErrorIs(t, err, ErrInvalidKeystore)
+
+	// Tamper with an otherwise valid keystore
+	path = filepath.Join(dir, "tampered.db")
+	ks := NewKeystore(path, []byte("secret"), testScryptParams)
+	ks.Set("key", []byte("value"))
+	require.Nil(t, ks.Save())
+	raw, err := os.ReadFile(path)
+	require.Nil(t, err)
+	raw[len(raw)-1] ^= 0xFF
+	require.Nil(t, os.WriteFile(path, raw, 0600))
+	_, err = OpenKeystore(path, []byte("secret"))
+	require.ErrorIs(t, err, ErrInvalidPassphrase)
+
+	// Excessive or invalid scrypt parameters must be rejected before deriving the key
+	for _, params := range [][3]uint32{
+		{1 << 30, 8, 1},
+		{1<<10 + 1, 8, 1},
+		{1, 8, 1},
+		{1 << 10, 0, 1},
+		{1 << 10, 1 << 16, 1 << 16},
+	} {
+		for i, param := range params {
+			binary.BigEndian.PutUint32(raw[len(keystoreMagic)+1+keystoreSaltSize+4*i:], param)
+		}
+		require.Nil(t, os.WriteFile(path, raw, 0600))
+		_, err = OpenKeystore(path, []byte("secret"))
+		require.ErrorIs(t, err, ErrInvalidKeystore)
+	}
+	require.Error(t, NewKeystore(path, []byte("secret"), WithScryptParams(1<<21, 8, 1)).Save())
+
+	// The passphrase must not be affected by subsequent modifications of the provided slice
+	path = filepath.Join(dir, "passphrase.db")
+	passphrase := []byte("secret")
+	require.Nil(t, NewKeystore(path, passphrase, testScryptParams).Save())
+	copy(passphrase, "public")
+	_, err = OpenKeystore(path, []byte("secret"))
+	require.Nil(t, err)
+}
@@ -0,0 +1,8 @@
+//go:build !unix
+
+package cryptoutils
+
+// lockFile is a no-op on platforms not supporting flock() style advisory locking
+func lockFile(_ string) (func(), error) {
+	return func() {}, nil
+}
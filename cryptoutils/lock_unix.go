@@ -0,0 +1,28 @@
+//go:build unix
+
+package cryptoutils
+
+import (
+	"os"
+	"path/filepath"
+	"syscall"
+)
+
+// lockFile acquires an exclusive (advisory) lock on the provided lock file path, creating it
+// if required, and returns a function to release the lock
+func lockFile(path string) (func(), error) {
+	f, err := os.OpenFile(filepath.Clean(path), os.O_CREATE|os.O_RDWR, keystoreFilePerm)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = syscall.Flock(int(f.Fd()), syscall.LOCK_EX); err != nil { // #nosec G115
+		_ = f.Close()
+		return nil, err
+	}
+
+	return func() {
+		_ = syscall.Flock(int(f.Fd()), syscall.LOCK_UN) // #nosec G115
+		_ = f.Close()
+	}, nil
+}
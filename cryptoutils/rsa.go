@@ -95,17 +95,37 @@ func (e *RSA) PrivKeyString() string {
 }
 
 // Encrypt encrypts a message using RSA-OAEP, using the hash h (falling back to sha256 if nil)
+// The result is an Envelope (see ParseEnvelope()), its header being authenticated via the OAEP
+// label. The message size is limited by the key size (see EncryptEnvelope() for messages of
+// arbitrary size)
 func (e *RSA) Encrypt(clearMsg []byte, h hash.Hash) ([]byte, error) {
 	if h == nil {
 		h = sha256.New()
 	}
-	return rsa.EncryptOAEP(h, rand.Reader, &e.privKey.PublicKey, clearMsg, nil)
+
+	env := &Envelope{
+		Version:   EnvelopeVersion,
+		Algorithm: RSAOAEP,
+		KeyID:     e.KeyID(),
+	}
+	cipherText, err := rsa.EncryptOAEP(h, rand.Reader, &e.privKey.PublicKey, clearMsg, env.appendHeader(nil))
+	if err != nil {
+		return nil, err
+	}
+	env.CipherText = cipherText
+
+	return env.Marshal(), nil
 }
 
 // Decrypt decrypts a message using RSA-OAEP, using the hash h (falling back to sha256 if nil)
+// Raw RSA-OAEP cipher texts (as produced by previous versions of Encrypt()) are still supported
 func (e *RSA) Decrypt(cipherMsg []byte, h hash.Hash) ([]byte, error) {
 	if h == nil {
 		h = sha256.New()
 	}
+
+	if env, err := ParseEnvelope(cipherMsg); err == nil && env.Algorithm == RSAOAEP && env.KeyID == e.KeyID() {
+		return rsa.DecryptOAEP(h, rand.Reader, e.privKey, env.CipherText, env.appendHeader(nil))
+	}
 	return rsa.DecryptOAEP(h, rand.Reader, e.privKey, cipherMsg, nil)
 }
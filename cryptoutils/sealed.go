@@ -0,0 +1,137 @@
+package cryptoutils
+
+import (
+	"bytes"
+	"io"
+
+	"github.com/fako1024/gotools/concurrency"
+)
+
+// Ensure that the sealed stream types fulfil the interfaces of the concurrency encoding chains
+var (
+	_ concurrency.Writer = &SealedWriter{}
+	_ concurrency.Reader = &SealedReader{}
+)
+
+// SealedWriter provides a composed Writer stage applying an (optional) inner stage (e.g. compression)
+// followed by chunked AEAD encryption (cf. EncryptingWriter), ensuring the correct order of operations
+// (compress-then-encrypt, with each chunk being authenticated) when used as single stage of a
+// concurrency.WriterChain
+type SealedWriter struct {
+	stage concurrency.Writer
+	enc   *EncryptingWriter
+}
+
+// NewSealedWriter instantiates a new sealing writer using the provided key and inner stage (may be nil)
+func NewSealedWriter(key []byte, stage concurrency.Writer, options ...StreamOption) (*SealedWriter, error) {
+	enc, err := NewEncryptingWriter(key, options...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SealedWriter{
+		stage: stage,
+		enc:   enc,
+	}, nil
+}
+
+// Init resets the writer for (re-)use, writing to the provided io.Writer
+func (s *SealedWriter) Init(w io.Writer) io.Writer {
+	encW := s.enc.Init(w)
+	if s.stage == nil {
+		return encW
+	}
+
+	return s.stage.Init(encW)
+}
+
+// Close flushes the inner stage and writes the final chunk of the stream (without closing the
+// underlying io.Writer). If the inner stage fails to close, the stream is not finalized (and hence
+// cannot be decrypted)
+func (s *SealedWriter) Close() error {
+	if s.stage != nil {
+		if err := s.stage.Close(); err != nil {
+			return err
+		}
+	}
+
+	return s.enc.Close()
+}
+
+// Return returns the inner stage and the internal buffer to their respective pools
+func (s *SealedWriter) Return() {
+	if s.stage != nil {
+		s.stage.Return()
+	}
+	s.enc.Return()
+}
+
+// SealedReader provides a composed Reader stage for streams produced by a SealedWriter, decrypting
+// and authenticating the whole stream before any plain text is passed on to the (optional) inner stage
+// (e.g. decompression) and hence to any decoder. Consequently, the decrypted stream is held in memory
+type SealedReader struct {
+	stage concurrency.Reader
+	dec   *DecryptingReader
+
+	plain []byte
+	r     bytes.Reader
+}
+
+// NewSealedReader instantiates a new unsealing reader using the provided key and inner stage (may be nil)
+func NewSealedReader(key []byte, stage concurrency.Reader, options ...StreamOption) (*SealedReader, error) {
+	dec, err := NewDecryptingReader(key, options...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SealedReader{
+		stage: stage,
+		dec:   dec,
+	}, nil
+}
+
+// Init resets the reader for (re-)use, reading, decrypting and authenticating the whole stream from
+// the provided io.Reader (failing if any part of the stream is invalid)
+func (s *SealedReader) Init(r io.Reader) (io.Reader, error) {
+	decR, err := s.dec.Init(r)
+	if err != nil {
+		return nil, err
+	}
+
+	buf := bytes.NewBuffer(streamBufPool.Get(0))
+	if _, err = buf.ReadFrom(decR); err != nil {
+		streamBufPool.Put(buf.Bytes())
+		return nil, err
+	}
+	s.plain = buf.Bytes()
+	s.r.Reset(s.plain)
+
+	if s.stage == nil {
+		return &s.r, nil
+	}
+
+	return s.stage.Init(&s.r)
+}
+
+// Close closes the inner stage (without closing the underlying io.Reader)
+func (s *SealedReader) Close() error {
+	if s.stage != nil {
+		return s.stage.Close()
+	}
+
+	return nil
+}
+
+// Return returns the inner stage and the internal buffers to their respective pools
+func (s *SealedReader) Return() {
+	if s.stage != nil {
+		s.stage.Return()
+	}
+	s.dec.Return()
+
+	if s.plain != nil {
+		streamBufPool.Put(s.plain)
+		s.plain = nil
+		s.r.Reset(nil)
+	}
+}
@@ -0,0 +1,373 @@
+package cryptoutils
+
+import (
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/fako1024/gotools/concurrency"
+)
+
+const (
+	streamVersion = 1
+
+	// DefaultStreamChunkSize denotes the default (maximum) plain text size of an individual chunk
+	DefaultStreamChunkSize = 64 * 1024
+
+	// Per-chunk nonce suffix: 4-byte chunk counter + 1-byte final chunk flag
+	streamNonceSuffixLen = 5
+	streamLenPrefixLen   = 4
+
+	// The most significant bit of the chunk length prefix marks the final chunk
+	streamFinalFlag = 1 << 31
+)
+
+var (
+	// ErrTruncatedStream denotes that an encrypted stream ended before its final chunk
+	ErrTruncatedStream = errors.New("encrypted stream truncated")
+
+	// ErrInvalidStream denotes that an encrypted stream is malformed
+	ErrInvalidStream = errors.New("invalid encrypted stream")
+
+	streamBufPool = concurrency.NewMemPoolNoLimit()
+)
+
+// Ensure that the stream types fulfil the interfaces of the concurrency encoding chains
+var (
+	_ concurrency.Writer = &EncryptingWriter{}
+	_ concurrency.Reader = &DecryptingReader{}
+)
+
+// StreamOption denotes a functional option for the encrypting / decrypting stream types
+type StreamOption func(*streamConfig)
+
+type streamConfig struct {
+	alg       Algorithm
+	chunkSize int
+}
+
+// WithStreamAlgorithm sets the encryption algorithm used for an encrypted stream (ignored for
+// decryption, where the algorithm is read from the stream header)
+func WithStreamAlgorithm(alg Algorithm) StreamOption {
+	return func(cfg *streamConfig) {
+		cfg.alg = alg
+	}
+}
+
+// WithStreamChunkSize sets the (maximum) plain text size of individual chunks of an encrypted stream
+// (for decryption, chunks exceeding this size are rejected before allocating any memory for them)
+func WithStreamChunkSize(size int) StreamOption {
+	return func(cfg *streamConfig) {
+		cfg.chunkSize = size
+	}
+}
+
+// EncryptingWriter provides a chunked AEAD encrypting io.Writer, fulfilling the concurrency.Writer
+// interface (and hence usable as stage in a concurrency.WriterChain)
+// The stream consists of a header (version, algorithm and random nonce prefix) followed by length-
+// prefixed chunks, each authenticated individually with a nonce derived from the chunk counter and
+// a flag marking the final chunk (preventing reordering and truncation of the stream)
+type EncryptingWriter struct {
+	key  []byte
+	cfg  streamConfig
+	aead cipher.AEAD
+
+	w             io.Writer
+	nonce         []byte
+	buf           []byte
+	counter       uint32
+	headerWritten bool
+	err           error
+}
+
+// NewEncryptingWriter instantiates a new encrypting writer using the provided key
+func NewEncryptingWriter(key []byte, options ...StreamOption) (*EncryptingWriter, error) {
+	cfg := streamConfig{
+		alg:       AES256GCM,
+		chunkSize: DefaultStreamChunkSize,
+	}
+	for _, opt := range options {
+		opt(&cfg)
+	}
+	if cfg.chunkSize <= 0 {
+		return nil, fmt.Errorf("invalid chunk size: %d", cfg.chunkSize)
+	}
+
+	aead, err := newAEAD(cfg.alg, key)
+	if err != nil {
+		return nil, err
+	}
+
+	return &EncryptingWriter{
+		key:  key,
+		cfg:  cfg,
+		aead: aead,
+	}, nil
+}
+
+// Init resets the writer for (re-)use, writing to the provided io.Writer
+func (e *EncryptingWriter) Init(w io.Writer) io.Writer {
+	e.w = w
+	e.nonce = make([]byte, e.aead.NonceSize())
+	e.buf = streamBufPool.Get(0)
+	e.counter = 0
+	e.headerWritten = false
+	e.err = nil
+
+	if _, err := rand.Read(e.nonce[:len(e.nonce)-streamNonceSuffixLen]); err != nil {
+		e.err = err
+	}
+
+	return e
+}
+
+// Write encrypts and writes len(p) bytes (buffered up to the chunk size)
+func (e *EncryptingWriter) Write(p []byte) (n int, err error) {
+	if e.err != nil {
+		return 0, e.err
+	}
+
+	for len(p) > 0 {
+		nCopy := e.cfg.chunkSize - len(e.buf)
+		if nCopy > len(p) {
+			nCopy = len(p)
+		}
+		e.buf = append(e.buf, p[:nCopy]...)
+		p = p[nCopy:]
+		n += nCopy
+
+		if len(e.buf) == e.cfg.chunkSize {
+			if e.err = e.flushChunk(false); e.err != nil {
+				return n, e.err
+			}
+		}
+	}
+
+	return n, nil
+}
+
+// Close writes the final chunk of the stream (without closing the underlying io.Writer)
+func (e *EncryptingWriter) Close() error {
+	if e.err != nil {
+		return e.err
+	}
+
+	e.err = e.flushChunk(true)
+	if e.err == nil {
+		e.err = ErrInvalidStream // Prevent writing after close
+		return nil
+	}
+	return e.err
+}
+
+// Return returns the internal buffer to the pool
+func (e *EncryptingWriter) Return() {
+	if e.buf != nil {
+		streamBufPool.Put(e.buf)
+		e.buf = nil
+	}
+}
+
+func (e *EncryptingWriter) flushChunk(final bool) error {
+	if !e.headerWritten {
+		header := make([]byte, 0, 2+len(e.nonce)-streamNonceSuffixLen)
+		header = append(header, streamVersion, byte(e.cfg.alg))
+		header = append(header, e.nonce[:len(e.nonce)-streamNonceSuffixLen]...)
+		if _, err := e.w.Write(header); err != nil {
+			return err
+		}
+		e.headerWritten = true
+	}
+
+	setStreamNonce(e.nonce, e.counter, final)
+	e.counter++
+	if e.counter == 0 {
+		return errors.New("maximum number of stream chunks exceeded")
+	}
+
+	out := streamBufPool.Get(streamLenPrefixLen)
+	out = e.aead.Seal(out, e.nonce, e.buf, nil)
+	lenPrefix := uint32(len(out) - streamLenPrefixLen) // #nosec G115
+	if final {
+		lenPrefix |= streamFinalFlag
+	}
+	binary.BigEndian.PutUint32(out, lenPrefix)
+	_, err := e.w.Write(out)
+	streamBufPool.Put(out)
+	e.buf = e.buf[:0]
+
+	return err
+}
+
+// NewAESWriter instantiates a new encrypting writer using AES-256-GCM (cf. NewEncryptingWriter),
+// usable as stage in a concurrency.WriterChain to produce compressed-and-encrypted output in a
+// single pass
+func NewAESWriter(key []byte, options ...StreamOption) (*EncryptingWriter, error) {
+	return NewEncryptingWriter(key, append(options, WithStreamAlgorithm(AES256GCM))...)
+}
+
+// DecryptingReader provides a chunked AEAD decrypting io.Reader (for streams produced by an
+// EncryptingWriter), fulfilling the concurrency.Reader interface (and hence usable as stage in a
+// concurrency.ReaderChain)
+type DecryptingReader struct {
+	key  []byte
+	alg  Algorithm
+	cfg  streamConfig
+	aead cipher.AEAD
+
+	r       io.Reader
+	nonce   []byte
+	buf     []byte
+	plain   []byte
+	counter uint32
+	final   bool
+}
+
+// NewDecryptingReader instantiates a new decrypting reader using the provided key
+func NewDecryptingReader(key []byte, options ...StreamOption) (*DecryptingReader, error) {
+	if len(key) != SymmetricKeySize {
+		return nil, fmt.Errorf("invalid key size (want %d, have %d)", SymmetricKeySize, len(key))
+	}
+
+	cfg := streamConfig{
+		chunkSize: DefaultStreamChunkSize,
+	}
+	for _, opt := range options {
+		opt(&cfg)
+	}
+	if cfg.chunkSize <= 0 {
+		return nil, fmt.Errorf("invalid chunk size: %d", cfg.chunkSize)
+	}
+
+	return &DecryptingReader{
+		key: key,
+		cfg: cfg,
+	}, nil
+}
+
+// NewAESReader instantiates a new decrypting reader (cf. NewDecryptingReader) only accepting streams
+// encrypted using AES-256-GCM, usable as stage in a concurrency.ReaderChain
+func NewAESReader(key []byte, options ...StreamOption) (*DecryptingReader, error) {
+	d, err := NewDecryptingReader(key, options...)
+	if err != nil {
+		return nil, err
+	}
+	d.alg = AES256GCM
+
+	return d, nil
+}
+
+// Init resets the reader for (re-)use, reading the stream header from the provided io.Reader
+func (d *DecryptingReader) Init(r io.Reader) (io.Reader, error) {
+	d.r = r
+	d.counter = 0
+	d.final = false
+	d.plain = nil
+
+	header := make([]byte, 2)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return d, fmt.Errorf("%w: failed to read header: %w", ErrInvalidStream, err)
+	}
+	if header[0] != streamVersion {
+		return d, fmt.Errorf("%w: unsupported version %d", ErrInvalidStream, header[0])
+	}
+
+	if d.alg != 0 && Algorithm(header[1]) != d.alg {
+		return d, fmt.Errorf("%w: want %s, have %s", ErrAlgorithmMismatch, d.alg, Algorithm(header[1]))
+	}
+	aead, err := newAEAD(Algorithm(header[1]), d.key)
+	if err != nil {
+		return d, err
+	}
+	d.aead = aead
+
+	d.nonce = make([]byte, aead.NonceSize())
+	if _, err := io.ReadFull(r, d.nonce[:len(d.nonce)-streamNonceSuffixLen]); err != nil {
+		return d, fmt.Errorf("%w: failed to read nonce: %w", ErrInvalidStream, err)
+	}
+	d.buf = streamBufPool.Get(0)
+
+	return d, nil
+}
+
+// Read reads and decrypts up to len(p) bytes from the stream
+func (d *DecryptingReader) Read(p []byte) (n int, err error) {
+	for len(d.plain) == 0 {
+		if d.final {
+			return 0, io.EOF
+		}
+		if err = d.readChunk(); err != nil {
+			return 0, err
+		}
+	}
+
+	n = copy(p, d.plain)
+	d.plain = d.plain[n:]
+
+	return n, nil
+}
+
+// Close closes the reader (without closing the underlying io.Reader)
+func (d *DecryptingReader) Close() error {
+	return nil
+}
+
+// Return returns the internal buffer to the pool
+func (d *DecryptingReader) Return() {
+	if d.buf != nil {
+		streamBufPool.Put(d.buf)
+		d.buf, d.plain = nil, nil
+	}
+}
+
+func (d *DecryptingReader) readChunk() error {
+	var lenPrefix [streamLenPrefixLen]byte
+	if _, err := io.ReadFull(d.r, lenPrefix[:]); err != nil {
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			return ErrTruncatedStream
+		}
+		return err
+	}
+
+	chunkLen := int(binary.BigEndian.Uint32(lenPrefix[:]) &^ streamFinalFlag)
+	final := binary.BigEndian.Uint32(lenPrefix[:])&streamFinalFlag != 0
+	// The length prefix is not authenticated, hence it is bounded before allocating the chunk buffer
+	if chunkLen < d.aead.Overhead() || chunkLen > d.cfg.chunkSize+d.aead.Overhead() {
+		return fmt.Errorf("%w: invalid chunk length %d", ErrInvalidStream, chunkLen)
+	}
+	if cap(d.buf) < chunkLen {
+		streamBufPool.Put(d.buf)
+		d.buf = streamBufPool.Get(chunkLen)
+	}
+	d.buf = d.buf[:chunkLen]
+	if _, err := io.ReadFull(d.r, d.buf); err != nil {
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			return ErrTruncatedStream
+		}
+		return err
+	}
+
+	// The final chunk flag is part of the nonce, hence any manipulation of the flag in the
+	// length prefix will cause the authentication to fail
+	var err error
+	setStreamNonce(d.nonce, d.counter, final)
+	if d.plain, err = d.aead.Open(d.buf[:0], d.nonce, d.buf, nil); err != nil {
+		return err
+	}
+	d.final = final
+	d.counter++
+
+	return nil
+}
+
+func setStreamNonce(nonce []byte, counter uint32, final bool) {
+	suffix := nonce[len(nonce)-streamNonceSuffixLen:]
+	binary.BigEndian.PutUint32(suffix, counter)
+	suffix[4] = 0
+	if final {
+		suffix[4] = 1
+	}
+}
@@ -0,0 +1,274 @@
+package cryptoutils
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/fako1024/gotools/concurrency"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStreamRoundTrip(t *testing.T) {
+	for _, alg := range []Algorithm{AES256GCM, XChaCha20Poly1305} {
+		for _, size := range []int{0, 1, 127, 128, 129, 1000} {
+			t.Run(fmt.Sprintf("%s_%d", alg, size), func(t *testing.T) {
+				key, err := NewSymmetricKey(alg)
+				require.Nil(t, err)
+
+				clearText := make([]byte, size)
+				for i := range clearText {
+					clearText[i] = byte(i)
+				}
+
+				enc, err := NewEncryptingWriter(key, WithStreamAlgorithm(alg), WithStreamChunkSize(128))
+				require.Nil(t, err)
+				buf := bytes.NewBuffer(nil)
+				w := enc.Init(buf)
+
+				// Write in small portions to exercise chunk boundaries
+				for i := 0; i < len(clearText); i += 7 {
+					end := i + 7
+					if end > len(clearText) {
+						end = len(clearText)
+					}
+					n, err := w.Write(clearText[i:end])
+					require.Nil(t, err)
+					require.Equal(t, end-i, n)
+				}
+				require.Nil(t, enc.Close())
+				enc.Return()
+
+				_, err = w.Write([]byte{0x0})
+				require.Error(t, err)
+
+				cipherText := buf.Bytes()
+				if size > 16 {
+					require.NotContains(t, string(cipherText), string(clearText[:size/2]))
+				}
+
+				dec, err := NewDecryptingReader(key)
+				require.Nil(t, err)
+				r, err := dec.Init(bytes.NewReader(cipherText))
+				require.Nil(t, err)
+				res, err := io.ReadAll(r)
+				require.Nil(t, err)
+				require.Equal(t, clearText, res)
+				require.Nil(t, dec.Close())
+				dec.Return()
+
+				// Truncating the stream at any point must be detected
+				for i := 0; i < len(cipherText); i += 11 {
+					r, err := dec.Init(bytes.NewReader(cipherText[:i]))
+					if err != nil {
+						require.ErrorIs(t, err, ErrInvalidStream)
+						continue
+					}
+					_, err = io.ReadAll(r)
+					require.ErrorIs(t, err, ErrTruncatedStream)
+					dec.Return()
+				}
+
+				// Manipulating the stream must be detected
+				cipherText[len(cipherText)-1] ^= 0xFF
+				r, err = dec.Init(bytes.NewReader(cipherText))
+				require.Nil(t, err)
+				_, err = io.ReadAll(r)
+				require.Error(t, err)
+				dec.Return()
+			})
+		}
+	}
+}
+
+func TestStreamChains(t *testing.T) {
+	key, err := NewSymmetricKey(AES256GCM)
+	require.Nil(t, err)
+
+	input := []byte("This is a test message that is compressed and encrypted in a single pass")
+
+	// Repeat test a couple of times to trigger pool re-use scenario
+	for i := 0; i < 10; i++ {
+		enc, err := NewEncryptingWriter(key)
+		require.Nil(t, err)
+
+		wc := concurrency.NewWriterChain().AddWriter(enc).AddWriter(concurrency.NewGZIPWriter()).PostFn(func(rw *concurrency.ReadWriter) error {
+			dec, err := NewDecryptingReader(key)
+			require.Nil(t, err)
+
+			var res []byte
+			rc := concurrency.NewReaderChain(rw).AddReader(dec).AddReader(concurrency.NewGZIPReader()).Build()
+			require.Nil(t, rc.DecodeAndClose(concurrency.BytesDecoder, &res))
+			require.Equal(t, input, res)
+
+			return nil
+		}).Build()
+		require.Nil(t, wc.EncodeAndClose(concurrency.BytesEncoder, input))
+	}
+}
+
+func TestStreamChainsAES(t *testing.T) {
+	key, err := NewSymmetricKey(AES256GCM)
+	require.Nil(t, err)
+
+	input := bytes.Repeat([]byte("This is a test message that is compressed and encrypted in a single pass"), 100)
+
+	// Repeat test a couple of times to trigger pool re-use scenario
+	for i := 0; i < 10; i++ {
+		enc, err := NewAESWriter(key, WithStreamAlgorithm(XChaCha20Poly1305), WithStreamChunkSize(1024))
+		require.Nil(t, err)
+
+		wc := concurrency.NewWriterChain().AddWriter(enc).AddWriter(concurrency.NewGZIPWriter()).PostFn(func(rw *concurrency.ReadWriter) error {
+			require.Equal(t, byte(AES256GCM), rw.Bytes()[1])
+
+			dec, err := NewAESReader(key)
+			require.Nil(t, err)
+
+			var res []byte
+			rc := concurrency.NewReaderChain(rw).AddReader(dec).AddReader(concurrency.NewGZIPReader()).Build()
+			require.Nil(t, rc.DecodeAndClose(concurrency.BytesDecoder, &res))
+			require.Equal(t, input, res)
+
+			return nil
+		}).Build()
+		require.Nil(t, wc.EncodeAndClose(concurrency.BytesEncoder, input))
+	}
+
+	// Streams using a different algorithm must be rejected
+	key, err = NewSymmetricKey(XChaCha20Poly1305)
+	require.Nil(t, err)
+	enc, err := NewEncryptingWriter(key, WithStreamAlgorithm(XChaCha20Poly1305))
+	require.Nil(t, err)
+	buf := bytes.NewBuffer(nil)
+	w := enc.Init(buf)
+	_, err = w.Write(input)
+	require.Nil(t, err)
+	require.Nil(t, enc.Close())
+
+	dec, err := NewAESReader(key)
+	require.Nil(t, err)
+	_, err = dec.Init(buf)
+	require.ErrorIs(t, err, ErrAlgorithmMismatch)
+
+	_, err = NewAESWriter(make([]byte, 16))
+	require.Error(t, err)
+	_, err = NewAESReader(make([]byte, 16))
+	require.Error(t, err)
+}
+
+func TestStreamInvalid(t *testing.T) {
+	_, err := NewEncryptingWriter(make([]byte, 16))
+	require.Error(t, err)
+	_, err = NewEncryptingWriter(make([]byte, SymmetricKeySize), WithStreamChunkSize(0))
+	require.Error(t, err)
+	_, err = NewEncryptingWriter(make([]byte, SymmetricKeySize), WithStreamAlgorithm(Algorithm(42)))
+	require.ErrorIs(t, err, ErrUnsupportedAlgorithm)
+	_, err = NewDecryptingReader(make([]byte, 16))
+	require.Error(t, err)
+
+	dec, err := NewDecryptingReader(make([]byte, SymmetricKeySize))
+	require.Nil(t, err)
+	_, err = dec.Init(bytes.NewReader([]byte{streamVersion + 1, byte(AES256GCM)}))
+	require.ErrorIs(t, err, ErrInvalidStream)
+	_, err = dec.Init(bytes.NewReader([]byte{streamVersion, 42}))
+	require.ErrorIs(t, err, ErrUnsupportedAlgorithm)
+	_, err = NewDecryptingReader(make([]byte, SymmetricKeySize), WithStreamChunkSize(0))
+	require.Error(t, err)
+
+	// A (unauthenticated) chunk length exceeding the maximum chunk size must be rejected
+	stream := append([]byte{streamVersion, byte(AES256GCM)}, make([]byte, 7)...)
+	stream = append(stream, 0x7F, 0xFF, 0xFF, 0xFF)
+	r, err := dec.Init(bytes.NewReader(stream))
+	require.Nil(t, err)
+	_, err = io.ReadAll(r)
+	require.ErrorIs(t, err, ErrInvalidStream)
+	dec.Return()
+
+	key, err := NewSymmetricKey(AES256GCM)
+	require.Nil(t, err)
+	enc, err := NewEncryptingWriter(key, WithStreamChunkSize(1024))
+	require.Nil(t, err)
+	buf := bytes.NewBuffer(nil)
+	_, err = enc.Init(buf).Write(make([]byte, 1024))
+	require.Nil(t, err)
+	require.Nil(t, enc.Close())
+
+	dec, err = NewDecryptingReader(key, WithStreamChunkSize(128))
+	require.Nil(t, err)
+	r, err = dec.Init(bytes.NewReader(buf.Bytes()))
+	require.Nil(t, err)
+	_, err = io.ReadAll(r)
+	require.ErrorIs(t, err, ErrInvalidStream)
+	dec.Return()
+}
+
+func TestSealedStream(t *testing.T) {
+	key, err := NewSymmetricKey(AES256GCM)
+	require.Nil(t, err)
+
+	input := bytes.Repeat([]byte("This is a test message that is compressed, encrypted and authenticated"), 100)
+
+	var sealed []byte
+	for i := 0; i < 10; i++ {
+		enc, err := NewSealedWriter(key, concurrency.NewGZIPWriter(), WithStreamChunkSize(256))
+		require.Nil(t, err)
+		require.Nil(t, concurrency.NewWriterChain().AddWriter(enc).PostFn(func(rw *concurrency.ReadWriter) error {
+			sealed = bytes.Clone(rw.Bytes())
+			return nil
+		}).Build().EncodeAndClose(concurrency.BytesEncoder, input))
+
+		dec, err := NewSealedReader(key, concurrency.NewGZIPReader())
+		require.Nil(t, err)
+		var res []byte
+		require.Nil(t, concurrency.NewReaderChain(bytes.NewReader(sealed)).AddReader(dec).Build().
+			DecodeAndClose(concurrency.BytesDecoder, &res))
+		require.Equal(t, input, res)
+	}
+
+	// Without inner stage
+	enc, err := NewSealedWriter(key, nil)
+	require.Nil(t, err)
+	var plainSealed []byte
+	require.Nil(t, concurrency.NewWriterChain().AddWriter(enc).PostFn(func(rw *concurrency.ReadWriter) error {
+		plainSealed = bytes.Clone(rw.Bytes())
+		return nil
+	}).Build().EncodeAndClose(concurrency.BytesEncoder, input))
+	dec, err := NewSealedReader(key, nil)
+	require.Nil(t, err)
+	var res []byte
+	require.Nil(t, concurrency.NewReaderChain(bytes.NewReader(plainSealed)).AddReader(dec).Build().
+		DecodeAndClose(concurrency.BytesDecoder, &res))
+	require.Equal(t, input, res)
+
+	// Any manipulation or truncation must fail before plain text reaches the decoder
+	for _, corrupt := range [][]byte{
+		append(bytes.Clone(sealed[:len(sealed)-1]), sealed[len(sealed)-1]^0xFF),
+		sealed[:len(sealed)-10],
+		append(bytes.Clone(sealed[:40]), sealed[41:]...),
+	} {
+		dec, err := NewSealedReader(key, concurrency.NewGZIPReader())
+		require.Nil(t, err)
+
+		decoded := false
+		err = concurrency.NewReaderChain(bytes.NewReader(corrupt)).AddReader(dec).Build().
+			Decode(func(r io.Reader) concurrency.Decoder {
+				decoded = true
+				return concurrency.BytesDecoder(r)
+			}, &res)
+		require.Error(t, err)
+		require.False(t, decoded)
+	}
+
+	wrongKey, err := NewSymmetricKey(AES256GCM)
+	require.Nil(t, err)
+	dec, err = NewSealedReader(wrongKey, concurrency.NewGZIPReader())
+	require.Nil(t, err)
+	_, err = dec.Init(bytes.NewReader(sealed))
+	require.Error(t, err)
+
+	_, err = NewSealedWriter(make([]byte, 16), nil)
+	require.Error(t, err)
+	_, err = NewSealedReader(make([]byte, 16), nil)
+	require.Error(t, err)
+}
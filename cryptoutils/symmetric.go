@@ -0,0 +1,193 @@
+package cryptoutils
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"errors"
+	"fmt"
+
+	"golang.org/x/crypto/chacha20poly1305"
+)
+
+// SymmetricKeySize denotes the size of symmetric keys (in bytes) for all supported algorithms
+const SymmetricKeySize = 32
+
+// Algorithm denotes a symmetric (AEAD) encryption algorithm
+type Algorithm uint8
+
+// Provide the supported symmetric encryption algorithms
+const (
+
+	// AES256GCM denotes AES-256 in Galois/Counter mode (fast on hardware with AES-NI or
+	// similar instruction set extensions)
+	AES256GCM Algorithm = iota + 1
+
+	// XChaCha20Poly1305 denotes XChaCha20-Poly1305 with extended nonce (fast in software,
+	// e.g. on devices without hardware AES support)
+	XChaCha20Poly1305
+
+	// RSAOAEPAES256GCM denotes hybrid encryption using a random AES-256-GCM data key, wrapped
+	// via RSA-OAEP (only used for envelopes, cannot be used for symmetric encryption directly)
+	RSAOAEPAES256GCM
+
+	// RSAOAEP denotes direct encryption via RSA-OAEP (only used for envelopes produced by
+	// RSA.Encrypt(), cannot be used for symmetric encryption directly)
+	RSAOAEP
+)
+
+var (
+	// ErrUnsupportedAlgorithm denotes that an unknown / unsupported algorithm was requested
+	ErrUnsupportedAlgorithm = errors.New("unsupported encryption algorithm")
+
+	// ErrCipherTextTooShort denotes that a cipher text is too short to contain all required data
+	ErrCipherTextTooShort = errors.New("cipher text too short")
+)
+
+// String returns a human-readable representation of the algorithm
+func (a Algorithm) String() string {
+	switch a {
+	case AES256GCM:
+		return "AES-256-GCM"
+	case XChaCha20Poly1305:
+		return "XChaCha20-Poly1305"
+	case RSAOAEPAES256GCM:
+		return "RSA-OAEP+AES-256-GCM"
+	case RSAOAEP:
+		return "RSA-OAEP"
+	default:
+		return fmt.Sprintf("Unknown (%d)", a)
+	}
+}
+
+// NewSymmetricKey generates a new random key suitable for use with the provided algorithm
+func NewSymmetricKey(alg Algorithm) ([]byte, error) {
+	if _, err := newAEAD(alg, make([]byte, SymmetricKeySize)); err != nil {
+		return nil, err
+	}
+
+	key := make([]byte, SymmetricKeySize)
+	if _, err := rand.Read(key); err != nil {
+		return nil, err
+	}
+
+	return key, nil
+}
+
+// EncryptSymmetric encrypts a message using the provided algorithm and key (and a random nonce),
+// returning the result as Envelope (see ParseEnvelope())
+func EncryptSymmetric(alg Algorithm, key []byte, clearMsg []byte) ([]byte, error) {
+	env := &Envelope{
+		Version:   EnvelopeVersion,
+		Algorithm: alg,
+		KeyID:     NewSymmetricKeyID(key),
+	}
+	if err := seal(alg, key, env, clearMsg); err != nil {
+		return nil, err
+	}
+
+	return env.Marshal(), nil
+}
+
+// DecryptSymmetric decrypts (and authenticates) a message previously encrypted using
+// EncryptSymmetric() with the same algorithm and key
+func DecryptSymmetric(alg Algorithm, key []byte, cipherMsg []byte) ([]byte, error) {
+	env, err := ParseEnvelope(cipherMsg)
+	if err != nil {
+		return nil, err
+	}
+	if env.Algorithm != alg {
+		return nil, fmt.Errorf("%w: %s", ErrAlgorithmMismatch, env.Algorithm)
+	}
+	if env.KeyID != NewSymmetricKeyID(key) {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownKeyID, env.KeyID)
+	}
+
+	return open(alg, key, env)
+}
+
+// EncryptAES encrypts a message using AES-256-GCM (see EncryptSymmetric())
+func EncryptAES(key []byte, clearMsg []byte) ([]byte, error) {
+	return EncryptSymmetric(AES256GCM, key, clearMsg)
+}
+
+// DecryptAES decrypts a message using AES-256-GCM (see DecryptSymmetric())
+func DecryptAES(key []byte, cipherMsg []byte) ([]byte, error) {
+	return DecryptSymmetric(AES256GCM, key, cipherMsg)
+}
+
+// EncryptXChaCha20 encrypts a message using XChaCha20-Poly1305 (see EncryptSymmetric())
+func EncryptXChaCha20(key []byte, clearMsg []byte) ([]byte, error) {
+	return EncryptSymmetric(XChaCha20Poly1305, key, clearMsg)
+}
+
+// DecryptXChaCha20 decrypts a message using XChaCha20-Poly1305 (see DecryptSymmetric())
+func DecryptXChaCha20(key []byte, cipherMsg []byte) ([]byte, error) {
+	return DecryptSymmetric(XChaCha20Poly1305, key, cipherMsg)
+}
+
+////////////////////////////////////////////////////////////////////////////////////////
+
+// seal encrypts a message into the payload of an envelope (whose header must already be populated
+// since it is authenticated along with the payload)
+func seal(alg Algorithm, key []byte, env *Envelope, clearMsg []byte) error {
+	aead, err := newAEAD(alg, key)
+	if err != nil {
+		return err
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err = rand.Read(nonce); err != nil {
+		return err
+	}
+	sealed := aead.Seal(nil, nonce, clearMsg, env.appendHeader(nil))
+
+	env.Nonce = nonce
+	env.CipherText = sealed[:len(sealed)-aead.Overhead()]
+	env.Tag = sealed[len(sealed)-aead.Overhead():]
+
+	return nil
+}
+
+// open decrypts (and authenticates) the payload (and header) of an envelope
+func open(alg Algorithm, key []byte, env *Envelope) ([]byte, error) {
+	aead, err := newAEAD(alg, key)
+	if err != nil {
+		return nil, err
+	}
+
+	return aead.Open(nil, env.Nonce, env.sealed(), env.appendHeader(nil))
+}
+
+// sizes returns the nonce and tag sizes of the algorithm
+func (a Algorithm) sizes() (nonceSize int, tagSize int, err error) {
+	switch a {
+	case AES256GCM, RSAOAEPAES256GCM:
+		return 12, 16, nil
+	case RSAOAEP:
+		return 0, 0, nil
+	case XChaCha20Poly1305:
+		return chacha20poly1305.NonceSizeX, chacha20poly1305.Overhead, nil
+	default:
+		return 0, 0, ErrUnsupportedAlgorithm
+	}
+}
+
+func newAEAD(alg Algorithm, key []byte) (cipher.AEAD, error) {
+	if len(key) != SymmetricKeySize {
+		return nil, fmt.Errorf("invalid key size (want %d, have %d)", SymmetricKeySize, len(key))
+	}
+
+	switch alg {
+	case AES256GCM:
+		block, err := aes.NewCipher(key)
+		if err != nil {
+			return nil, err
+		}
+		return cipher.NewGCM(block)
+	case XChaCha20Poly1305:
+		return chacha20poly1305.NewX(key)
+	default:
+		return nil, ErrUnsupportedAlgorithm
+	}
+}
@@ -0,0 +1,83 @@
+package cryptoutils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSymmetricEncryption(t *testing.T) {
+	for _, alg := range []Algorithm{AES256GCM, XChaCha20Poly1305} {
+		t.Run(alg.String(), func(t *testing.T) {
+			key, err := NewSymmetricKey(alg)
+			require.Nil(t, err)
+			require.Len(t, key, SymmetricKeySize)
+
+			for _, clearText := range [][]byte{
+				{},
+				[]byte("This is a test message"),
+				make([]byte, 1024*1024),
+			} {
+				cipherText, err := EncryptSymmetric(alg, key, clearText)
+				require.Nil(t, err)
+
+				// Ensure that two encryptions of the same message differ (random nonce)
+				cipherText2, err := EncryptSymmetric(alg, key, clearText)
+				require.Nil(t, err)
+				require.NotEqual(t, cipherText, cipherText2)
+
+				clearText2, err := DecryptSymmetric(alg, key, cipherText)
+				require.Nil(t, err)
+				require.Equal(t, string(clearText), string(clearText2), "initial cleartext and cleartext after encryption round-trip should be equal")
+
+				// Tamper with the cipher text
+				cipherText[len(cipherText)-1] ^= 0xFF
+				_, err = DecryptSymmetric(alg, key, cipherText)
+				require.Error(t, err)
+			}
+		})
+	}
+}
+
+func TestSymmetricConvenience(t *testing.T) {
+	key, err := NewSymmetricKey(AES256GCM)
+	require.Nil(t, err)
+
+	clearText := []byte("This is a test message")
+
+	cipherText, err := EncryptAES(key, clearText)
+	require.Nil(t, err)
+	clearText2, err := DecryptAES(key, cipherText)
+	require.Nil(t, err)
+	require.Equal(t, clearText, clearText2)
+
+	cipherText, err = EncryptXChaCha20(key, clearText)
+	require.Nil(t, err)
+	clearText2, err = DecryptXChaCha20(key, cipherText)
+	require.Nil(t, err)
+	require.Equal(t, clearText, clearText2)
+
+	// Ensure that algorithms and keys cannot be mixed up
+	_, err = DecryptAES(key, cipherText)
+	require.ErrorIs(t, err, ErrAlgorithmMismatch)
+	key2, err := NewSymmetricKey(XChaCha20Poly1305)
+	require.Nil(t, err)
+	_, err = DecryptXChaCha20(key2, cipherText)
+	require.ErrorIs(t, err, ErrUnknownKeyID)
+}
+
+func TestSymmetricInvalid(t *testing.T) {
+	_, err := NewSymmetricKey(Algorithm(0))
+	require.ErrorIs(t, err, ErrUnsupportedAlgorithm)
+	require.Equal(t, "Unknown (0)", Algorithm(0).String())
+
+	_, err = EncryptSymmetric(AES256GCM, make([]byte, 16), nil)
+	require.Error(t, err)
+	_, err = EncryptSymmetric(Algorithm(42), make([]byte, SymmetricKeySize), nil)
+	require.ErrorIs(t, err, ErrUnsupportedAlgorithm)
+
+	for _, alg := range []Algorithm{AES256GCM, XChaCha20Poly1305} {
+		_, err = DecryptSymmetric(alg, make([]byte, SymmetricKeySize), []byte{0x1, 0x2})
+		require.ErrorIs(t, err, ErrInvalidEnvelope)
+	}
+}
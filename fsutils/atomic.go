@@ -0,0 +1,112 @@
+// Package fsutils provides file system related helpers
+package fsutils
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/fako1024/gotools/concurrency"
+)
+
+// DefaultCopyBufferSize denotes the default size of the buffer used to copy data from a reader
+const DefaultCopyBufferSize = 32 * 1024
+
+// AtomicOption denotes a functional option for atomic file writes
+type AtomicOption func(*atomicWriter)
+
+// WithMemPool sets a memory pool to source the copy buffer from (instead of allocating a new one
+// for each write)
+func WithMemPool(pool concurrency.MemPool) AtomicOption {
+	return func(w *atomicWriter) {
+		w.memPool = pool
+	}
+}
+
+// WithBufferSize sets the size of the buffer used to copy data from a reader
+func WithBufferSize(size int) AtomicOption {
+	return func(w *atomicWriter) {
+		if size > 0 {
+			w.bufSize = size
+		}
+	}
+}
+
+type atomicWriter struct {
+	memPool concurrency.MemPool
+	bufSize int
+}
+
+// WriteFileAtomic writes data to a file atomically, i.e. the file at path either retains its
+// previous content or contains the full new content, even in case of a crash / power loss
+func WriteFileAtomic(path string, data []byte, perm os.FileMode) error {
+	return WriteFileAtomicFrom(path, bytes.NewReader(data), perm)
+}
+
+// WriteFileAtomicFrom writes all data from a reader to a file atomically (see WriteFileAtomic)
+func WriteFileAtomicFrom(path string, r io.Reader, perm os.FileMode, opts ...AtomicOption) error {
+	w := atomicWriter{
+		bufSize: DefaultCopyBufferSize,
+	}
+	for _, opt := range opts {
+		opt(&w)
+	}
+
+	return writeAtomic(path, perm, func(f *os.File) error {
+		var buf []byte
+		if w.memPool != nil {
+			buf = w.memPool.Get(w.bufSize)
+			defer w.memPool.Put(buf)
+		} else {
+			buf = make([]byte, w.bufSize)
+		}
+
+		// Hide any io.ReaderFrom implementation of the file to ensure the buffer is actually used
+		_, err := io.CopyBuffer(struct{ io.Writer }{f}, r, buf)
+		return err
+	})
+}
+
+////////////////////////////////////////////////////////////////////////////////////////
+
+func writeAtomic(path string, perm os.FileMode, writeFn func(f *os.File) error) (err error) {
+	path = filepath.Clean(path)
+	dir := filepath.Dir(path)
+
+	// Write to a temporary file in the same directory (to ensure rename() is atomic)
+	tmpFile, err := os.CreateTemp(dir, filepath.Base(path)+".tmp*")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			_ = os.Remove(tmpFile.Name())
+		}
+	}()
+
+	if err = writeFn(tmpFile); err != nil {
+		_ = tmpFile.Close()
+		return err
+	}
+	if err = tmpFile.Chmod(perm); err != nil {
+		_ = tmpFile.Close()
+		return err
+	}
+
+	// The file content must be persisted before the rename, otherwise a crash could leave
+	// behind an empty / partial file under the target name
+	if err = tmpFile.Sync(); err != nil {
+		_ = tmpFile.Close()
+		return err
+	}
+	if err = tmpFile.Close(); err != nil {
+		return err
+	}
+	if err = os.Rename(tmpFile.Name(), path); err != nil {
+		return err
+	}
+
+	// Persist the rename itself by syncing the parent directory
+	return fsyncDir(dir)
+}
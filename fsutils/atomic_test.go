@@ -0,0 +1,82 @@
+package fsutils
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/fako1024/gotools/concurrency"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteFileAtomic(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "test.dat")
+
+	require.Nil(t, WriteFileAtomic(path, []byte("first"), 0600))
+	data, err := os.ReadFile(path)
+	require.Nil(t, err)
+	require.Equal(t, []byte("first"), data)
+
+	// Overwrite existing file
+	require.Nil(t, WriteFileAtomic(path, []byte("second"), 0640))
+	data, err = os.ReadFile(path)
+	require.Nil(t, err)
+	require.Equal(t, []byte("second"), data)
+
+	info, err := os.Stat(path)
+	require.Nil(t, err)
+	require.Equal(t, os.FileMode(0640), info.Mode().Perm())
+
+	assertNoTempFiles(t, filepath.Dir(path))
+}
+
+func TestWriteFileAtomicFrom(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "test.dat")
+	input := bytes.Repeat([]byte("0123456789"), 10000)
+
+	for _, opts := range [][]AtomicOption{
+		nil,
+		{WithBufferSize(7)},
+		{WithMemPool(concurrency.NewMemPool(1)), WithBufferSize(1024)},
+		{WithMemPool(concurrency.NewMemPoolNoLimit())},
+	} {
+		require.Nil(t, WriteFileAtomicFrom(path, bytes.NewBuffer(input), 0600, opts...))
+		data, err := os.ReadFile(path)
+		require.Nil(t, err)
+		require.Equal(t, input, data)
+	}
+
+	assertNoTempFiles(t, filepath.Dir(path))
+}
+
+type failingReader struct{}
+
+func (failingReader) Read([]byte) (int, error) {
+	return 0, errors.New("read failed")
+}
+
+func TestWriteFileAtomicFailure(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "test.dat")
+	require.Nil(t, WriteFileAtomic(path, []byte("original"), 0600))
+
+	// A failing write must neither modify the existing file nor leave a temporary file behind
+	require.Error(t, WriteFileAtomicFrom(path, failingReader{}, 0600))
+	data, err := os.ReadFile(path)
+	require.Nil(t, err)
+	require.Equal(t, []byte("original"), data)
+	assertNoTempFiles(t, dir)
+
+	// Writing to a non-existent directory must fail
+	require.Error(t, WriteFileAtomic(filepath.Join(dir, "missing", "test.dat"), []byte("data"), 0600))
+}
+
+func assertNoTempFiles(t *testing.T, dir string) {
+	entries, err := os.ReadDir(dir)
+	require.Nil(t, err)
+	for _, entry := range entries {
+		require.NotContains(t, entry.Name(), ".tmp")
+	}
+}
@@ -0,0 +1,452 @@
+package fsutils
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/fako1024/gotools/concurrency"
+)
+
+const (
+	// DefaultCopyParallelism denotes the default number of files copied in parallel
+	DefaultCopyParallelism = 4
+
+	sparseBlockSize = 4096
+	copyDirPerm     = 0700
+)
+
+var (
+	// ErrChecksumMismatch denotes that a copied file does not match its source
+	ErrChecksumMismatch = errors.New("checksum mismatch")
+
+	// ErrNotDirectory denotes that a copy / sync source is not a directory
+	ErrNotDirectory = errors.New("not a directory")
+
+	copyBufPool = concurrency.NewMemPoolNoLimit()
+)
+
+// Progress denotes the state of a running copy / sync operation
+type Progress struct {
+	Path string
+
+	BytesCopied int64
+	BytesTotal  int64
+	FilesCopied int
+	FilesTotal  int
+}
+
+// ProgressFunc denotes a callback function receiving progress updates
+type ProgressFunc func(Progress)
+
+// CopyOption denotes a functional option for directory copy / sync operations
+type CopyOption func(*copier)
+
+// WithParallelism sets the maximum number of files copied in parallel
+func WithParallelism(n int) CopyOption {
+	return func(c *copier) {
+		c.parallelism = n
+	}
+}
+
+// WithVerification enables / disables checksum verification of each copied file against its source
+func WithVerification(enabled bool) CopyOption {
+	return func(c *copier) {
+		c.verify = enabled
+	}
+}
+
+// WithSparse enables / disables sparse file handling, i.e. skipping over zero-filled blocks
+// instead of writing them (enabled by default)
+func WithSparse(enabled bool) CopyOption {
+	return func(c *copier) {
+		c.sparse = enabled
+	}
+}
+
+// WithDelete enables / disables removal of files in the destination that do not exist in
+// the source during a sync operation
+func WithDelete(enabled bool) CopyOption {
+	return func(c *copier) {
+		c.delete = enabled
+	}
+}
+
+// WithProgress sets a callback function that is called whenever progress is made (may be
+// called concurrently from multiple routines, but never in parallel)
+func WithProgress(fn ProgressFunc) CopyOption {
+	return func(c *copier) {
+		c.progressFn = fn
+	}
+}
+
+// CopyDir recursively copies the directory src to dst, preserving permissions, modification times
+// and symbolic links (each individual file is written atomically)
+func CopyDir(ctx context.Context, src, dst string, opts ...CopyOption) error {
+	return newCopier(opts...).run(ctx, src, dst, false)
+}
+
+// SyncDir recursively synchronizes the directory src to dst, only copying files that are missing
+// or differ in size / modification time (and optionally removing extraneous files, see WithDelete)
+func SyncDir(ctx context.Context, src, dst string, opts ...CopyOption) error {
+	return newCopier(opts...).run(ctx, src, dst, true)
+}
+
+////////////////////////////////////////////////////////////////////////////////////////
+
+type copyJob struct {
+	src, dst string
+	info     fs.FileInfo
+}
+
+type copier struct {
+	parallelism int
+	verify      bool
+	sparse      bool
+	delete      bool
+	progressFn  ProgressFunc
+
+	progress Progress
+	sync.Mutex
+}
+
+func newCopier(opts ...CopyOption) *copier {
+	c := &copier{
+		parallelism: DefaultCopyParallelism,
+		sparse:      true,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c
+}
+
+func (c *copier) run(ctx context.Context, src, dst string, syncOnly bool) error {
+	src, dst = filepath.Clean(src), filepath.Clean(dst)
+	srcInfo, err := os.Stat(src)
+	if err != nil {
+		return err
+	}
+	if !srcInfo.IsDir() {
+		return fmt.Errorf("%w: %s", ErrNotDirectory, src)
+	}
+
+	jobs, dirs, err := c.collect(src, dst, syncOnly)
+	if err != nil {
+		return err
+	}
+
+	if err = c.copyFiles(ctx, jobs); err != nil {
+		return err
+	}
+
+	if syncOnly && c.delete {
+		if err = removeExtraneous(src, dst); err != nil {
+			return err
+		}
+	}
+
+	// Apply directory permissions / modification times only after all files have been written
+	// (in reverse order, so that restricted permissions on a parent do not affect its children)
+	for i := len(dirs) - 1; i >= 0; i-- {
+		if err = os.Chmod(dirs[i].dst, dirs[i].info.Mode().Perm()); err != nil {
+			return err
+		}
+		if err = os.Chtimes(dirs[i].dst, dirs[i].info.ModTime(), dirs[i].info.ModTime()); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (c *copier) collect(src, dst string, syncOnly bool) (jobs, dirs []copyJob, err error) {
+	err = filepath.WalkDir(src, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+
+		switch {
+		case d.IsDir():
+			if err := os.MkdirAll(target, copyDirPerm); err != nil {
+				return err
+			}
+			dirs = append(dirs, copyJob{src: path, dst: target, info: info})
+		case d.Type()&fs.ModeSymlink != 0:
+			return copySymlink(path, target)
+		case d.Type().IsRegular():
+			if syncOnly && unchanged(info, target) {
+				return nil
+			}
+			jobs = append(jobs, copyJob{src: path, dst: target, info: info})
+			c.progress.BytesTotal += info.Size()
+			c.progress.FilesTotal++
+		}
+
+		// Any other file types (devices, sockets, pipes, ...) are skipped
+		return nil
+	})
+
+	return
+}
+
+func (c *copier) copyFiles(ctx context.Context, jobs []copyJob) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		sem  = concurrency.New(c.parallelism)
+		wg   sync.WaitGroup
+		errs []error
+		mu   sync.Mutex
+	)
+
+	for _, job := range jobs {
+		sem.Add()
+		if ctx.Err() != nil {
+			sem.Done()
+			break
+		}
+
+		wg.Add(1)
+		go func(job copyJob) {
+			defer func() {
+				sem.Done()
+				wg.Done()
+			}()
+
+			if err := c.copyFile(ctx, job); err != nil {
+				mu.Lock()
+				errs = append(errs, fmt.Errorf("failed to copy %s: %w", job.src, err))
+				mu.Unlock()
+
+				// Abort all other running / pending copy operations
+				cancel()
+			}
+		}(job)
+	}
+	wg.Wait()
+
+	if len(errs) == 0 && ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	return errors.Join(errs...)
+}
+
+func (c *copier) copyFile(ctx context.Context, job copyJob) error {
+	in, err := os.Open(filepath.Clean(job.src))
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = in.Close()
+	}()
+
+	var srcHash hash.Hash
+	if c.verify {
+		srcHash = sha256.New()
+	}
+
+	buf := copyBufPool.Get(DefaultCopyBufferSize)
+	defer copyBufPool.Put(buf)
+
+	if err = writeAtomic(job.dst, job.info.Mode().Perm(), func(f *os.File) error {
+		var written int64
+		for {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+
+			n, rerr := in.Read(buf)
+			if n > 0 {
+				if srcHash != nil {
+					srcHash.Write(buf[:n])
+				}
+				if err := c.write(f, buf[:n]); err != nil {
+					return err
+				}
+				written += int64(n)
+				c.report(job.dst, int64(n), false)
+			}
+			if rerr == io.EOF {
+				break
+			}
+			if rerr != nil {
+				return rerr
+			}
+		}
+
+		// Ensure the file has the correct size (in case it ends with a hole)
+		if c.sparse {
+			return f.Truncate(written)
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	if err = os.Chtimes(job.dst, job.info.ModTime(), job.info.ModTime()); err != nil {
+		return err
+	}
+
+	if srcHash != nil {
+		dstSum, err := fileChecksum(job.dst, buf)
+		if err != nil {
+			return err
+		}
+		if !bytes.Equal(srcHash.Sum(nil), dstSum) {
+			return fmt.Errorf("%w: %s", ErrChecksumMismatch, job.dst)
+		}
+	}
+
+	c.report(job.dst, 0, true)
+
+	return nil
+}
+
+func (c *copier) write(f *os.File, data []byte) error {
+	if !c.sparse {
+		_, err := f.Write(data)
+		return err
+	}
+
+	// Skip over all-zero blocks, leaving a hole in the destination file
+	for len(data) > 0 {
+		n := sparseBlockSize
+		if n > len(data) {
+			n = len(data)
+		}
+
+		var err error
+		if isZero(data[:n]) {
+			_, err = f.Seek(int64(n), io.SeekCurrent)
+		} else {
+			_, err = f.Write(data[:n])
+		}
+		if err != nil {
+			return err
+		}
+		data = data[n:]
+	}
+
+	return nil
+}
+
+func (c *copier) report(path string, n int64, fileDone bool) {
+	if c.progressFn == nil {
+		return
+	}
+
+	c.Lock()
+	defer c.Unlock()
+
+	c.progress.Path = path
+	c.progress.BytesCopied += n
+	if fileDone {
+		c.progress.FilesCopied++
+	}
+	c.progressFn(c.progress)
+}
+
+func copySymlink(src, dst string) error {
+	link, err := os.Readlink(src)
+	if err != nil {
+		return err
+	}
+
+	// Replace any existing link / file at the destination
+	if existing, err := os.Readlink(dst); err == nil && existing == link {
+		return nil
+	}
+	if err = os.RemoveAll(dst); err != nil {
+		return err
+	}
+
+	return os.Symlink(link, dst)
+}
+
+func removeExtraneous(src, dst string) error {
+	return filepath.WalkDir(dst, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(dst, path)
+		if err != nil {
+			return err
+		}
+		if _, err = os.Lstat(filepath.Join(src, rel)); err == nil {
+			return nil
+		} else if !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+
+		if err = os.RemoveAll(path); err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return filepath.SkipDir
+		}
+
+		return nil
+	})
+}
+
+func unchanged(info fs.FileInfo, target string) bool {
+	targetInfo, err := os.Lstat(target)
+	if err != nil {
+		return false
+	}
+
+	return targetInfo.Mode().IsRegular() &&
+		targetInfo.Size() == info.Size() &&
+		targetInfo.ModTime().Equal(info.ModTime())
+}
+
+func fileChecksum(path string, buf []byte) ([]byte, error) {
+	f, err := os.Open(filepath.Clean(path))
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+
+	h := sha256.New()
+	if _, err = io.CopyBuffer(h, f, buf); err != nil {
+		return nil, err
+	}
+
+	return h.Sum(nil), nil
+}
+
+func isZero(data []byte) bool {
+	for _, b := range data {
+		if b != 0 {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,128 @@
+package fsutils
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCopyDir(t *testing.T) {
+	src, dst := createTestTree(t), filepath.Join(t.TempDir(), "dst")
+
+	var last Progress
+	require.Nil(t, CopyDir(context.Background(), src, dst,
+		WithVerification(true),
+		WithParallelism(2),
+		WithProgress(func(p Progress) {
+			require.GreaterOrEqual(t, p.BytesCopied, last.BytesCopied)
+			last = p
+		}),
+	))
+	assertTreesEqual(t, src, dst)
+
+	require.Equal(t, 4, last.FilesTotal)
+	require.Equal(t, 4, last.FilesCopied)
+	require.Equal(t, last.BytesTotal, last.BytesCopied)
+
+	// Copy without sparse file handling
+	dst = filepath.Join(t.TempDir(), "dst")
+	require.Nil(t, CopyDir(context.Background(), src, dst, WithSparse(false)))
+	assertTreesEqual(t, src, dst)
+}
+
+func TestSyncDir(t *testing.T) {
+	src, dst := createTestTree(t), filepath.Join(t.TempDir(), "dst")
+	require.Nil(t, CopyDir(context.Background(), src, dst))
+
+	// Modify the source and add extraneous files to the destination
+	require.Nil(t, os.WriteFile(filepath.Join(src, "a.txt"), []byte("modified content"), 0600))
+	require.Nil(t, os.WriteFile(filepath.Join(dst, "extra.txt"), []byte("extra"), 0600))
+	require.Nil(t, os.MkdirAll(filepath.Join(dst, "extradir", "sub"), 0700))
+
+	var nCopied int
+	require.Nil(t, SyncDir(context.Background(), src, dst, WithProgress(func(p Progress) {
+		nCopied = p.FilesTotal
+	})))
+	require.Equal(t, 1, nCopied, "only the modified file must be copied")
+	data, err := os.ReadFile(filepath.Join(dst, "a.txt"))
+	require.Nil(t, err)
+	require.Equal(t, []byte("modified content"), data)
+	require.FileExists(t, filepath.Join(dst, "extra.txt"))
+
+	require.Nil(t, SyncDir(context.Background(), src, dst, WithDelete(true)))
+	require.NoFileExists(t, filepath.Join(dst, "extra.txt"))
+	require.NoDirExists(t, filepath.Join(dst, "extradir"))
+	assertTreesEqual(t, src, dst)
+}
+
+func TestCopyDirInvalid(t *testing.T) {
+	src := createTestTree(t)
+
+	require.Error(t, CopyDir(context.Background(), filepath.Join(src, "missing"), t.TempDir()))
+	require.ErrorIs(t, CopyDir(context.Background(), filepath.Join(src, "a.txt"), t.TempDir()), ErrNotDirectory)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	require.ErrorIs(t, CopyDir(ctx, src, filepath.Join(t.TempDir(), "dst")), context.Canceled)
+}
+
+func createTestTree(t *testing.T) string {
+	dir := t.TempDir()
+
+	require.Nil(t, os.MkdirAll(filepath.Join(dir, "sub", "nested"), 0750))
+	require.Nil(t, os.WriteFile(filepath.Join(dir, "a.txt"), []byte("content a"), 0600))
+	require.Nil(t, os.WriteFile(filepath.Join(dir, "sub", "b.txt"), []byte("content b"), 0640))
+	require.Nil(t, os.WriteFile(filepath.Join(dir, "sub", "nested", "large.dat"), bytes.Repeat([]byte("0123456789"), 100000), 0600))
+	require.Nil(t, os.Symlink("a.txt", filepath.Join(dir, "link")))
+
+	// Create a sparse file with a hole at the beginning and the end
+	f, err := os.Create(filepath.Join(dir, "sparse.dat"))
+	require.Nil(t, err)
+	_, err = f.WriteAt([]byte("data in the middle"), 3*sparseBlockSize+17)
+	require.Nil(t, err)
+	require.Nil(t, f.Truncate(10*sparseBlockSize))
+	require.Nil(t, f.Close())
+
+	// Ensure modification times are distinguishable from the copies
+	past := time.Now().Add(-time.Hour)
+	require.Nil(t, os.Chtimes(filepath.Join(dir, "a.txt"), past, past))
+
+	return dir
+}
+
+func assertTreesEqual(t *testing.T, src, dst string) {
+	require.Nil(t, filepath.Walk(src, func(path string, info os.FileInfo, err error) error {
+		require.Nil(t, err)
+
+		rel, err := filepath.Rel(src, path)
+		require.Nil(t, err)
+		target := filepath.Join(dst, rel)
+
+		targetInfo, err := os.Lstat(target)
+		require.Nil(t, err)
+		require.Equal(t, info.Mode(), targetInfo.Mode(), rel)
+
+		switch {
+		case info.Mode()&os.ModeSymlink != 0:
+			link, err := os.Readlink(target)
+			require.Nil(t, err)
+			expected, err := os.Readlink(path)
+			require.Nil(t, err)
+			require.Equal(t, expected, link)
+		case info.Mode().IsRegular():
+			require.True(t, info.ModTime().Equal(targetInfo.ModTime()), rel)
+			expected, err := os.ReadFile(path)
+			require.Nil(t, err)
+			data, err := os.ReadFile(target)
+			require.Nil(t, err)
+			require.Equal(t, expected, data, rel)
+		}
+
+		return nil
+	}))
+}
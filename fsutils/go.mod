@@ -0,0 +1,25 @@
+module github.com/fako1024/gotools/fsutils
+
+go 1.20
+
+require (
+	github.com/fako1024/gotools/concurrency v0.0.0-00010101000000-000000000000
+	github.com/stretchr/testify v1.10.0
+)
+
+require (
+	github.com/davecgh/go-spew v1.1.1 // indirect
+	github.com/fako1024/gotools/clock v0.0.0-00010101000000-000000000000 // indirect
+	github.com/json-iterator/go v1.1.12 // indirect
+	github.com/klauspost/compress v1.17.9 // indirect
+	github.com/modern-go/concurrent v0.0.0-20180306012644-bacd9c7ef1dd // indirect
+	github.com/modern-go/reflect2 v1.0.2 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+	github.com/ulikunitz/xz v0.5.12 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
+
+replace (
+	github.com/fako1024/gotools/clock => ../clock
+	github.com/fako1024/gotools/concurrency => ../concurrency
+)
@@ -0,0 +1,8 @@
+//go:build !unix
+
+package fsutils
+
+// fsyncDir is a no-op on platforms not supporting fsync() on directories
+func fsyncDir(_ string) error {
+	return nil
+}
@@ -0,0 +1,22 @@
+//go:build unix
+
+package fsutils
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// fsyncDir flushes a directory (and hence any changes to its entries) to disk
+func fsyncDir(path string) error {
+	dir, err := os.Open(filepath.Clean(path))
+	if err != nil {
+		return err
+	}
+	if err = dir.Sync(); err != nil {
+		_ = dir.Close()
+		return err
+	}
+
+	return dir.Close()
+}
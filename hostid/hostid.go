@@ -0,0 +1,186 @@
+// Package hostid provides a stable, privacy-preserving host identifier
+package hostid
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/fako1024/gotools/cryptoutils"
+)
+
+// Size denotes the size of a host identifier (in bytes)
+const Size = 16
+
+var (
+	// ErrNoSource denotes that none of the sources provided a host identifier
+	ErrNoSource = errors.New("no host identifier source available")
+
+	// ErrInvalidNamespace denotes that an empty namespace was provided
+	ErrInvalidNamespace = errors.New("invalid (empty) namespace")
+)
+
+var (
+	machineIDPaths = []string{"/etc/machine-id", "/var/lib/dbus/machine-id"}
+	dmiUUIDPath    = "/sys/class/dmi/id/product_uuid"
+)
+
+// ID denotes a host identifier
+type ID [Size]byte
+
+// String returns the hex representation of the host identifier
+func (id ID) String() string {
+	return hex.EncodeToString(id[:])
+}
+
+// Source denotes a source of raw (unique, but potentially sensitive) host identification data
+type Source func() ([]byte, error)
+
+// Provide the supported sources
+var (
+
+	// MachineID denotes the systemd / D-Bus machine ID
+	MachineID Source = machineID
+
+	// DMIProductUUID denotes the DMI / SMBIOS system UUID (usually requires root privileges)
+	DMIProductUUID Source = dmiProductUUID
+
+	// MACAddress denotes the lowest globally administered MAC address of all network interfaces
+	// (used as last resort, since it might change when replacing hardware)
+	MACAddress Source = macAddress
+)
+
+// Option denotes a functional option for host identifier derivation
+type Option func(*config)
+
+// WithSources sets the sources (in order of preference) to derive the host identifier from
+func WithSources(sources ...Source) Option {
+	return func(c *config) {
+		c.sources = sources
+	}
+}
+
+// Get derives the host identifier for the provided namespace (e.g. an application name) from the
+// first available source, keyed-hashing the raw data so that the identifier is stable, but
+// cannot be correlated across namespaces nor reversed to the original machine data
+func Get(namespace string, opts ...Option) (ID, error) {
+	if namespace == "" {
+		return ID{}, ErrInvalidNamespace
+	}
+
+	cfg := config{
+		sources: []Source{MachineID, DMIProductUUID, MACAddress},
+	}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	var errs []error
+	for _, source := range cfg.sources {
+		data, err := source()
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		var id ID
+		copy(id[:], cryptoutils.HMAC([]byte(namespace), data))
+		return id, nil
+	}
+
+	return ID{}, errors.Join(append([]error{ErrNoSource}, errs...)...)
+}
+
+////////////////////////////////////////////////////////////////////////////////////////
+
+type config struct {
+	sources []Source
+}
+
+func machineID() ([]byte, error) {
+	for _, path := range machineIDPaths {
+		if data, err := readID(path); err == nil {
+			return data, nil
+		}
+	}
+
+	return nil, errors.New("no machine ID found")
+}
+
+func dmiProductUUID() ([]byte, error) {
+	return readID(dmiUUIDPath)
+}
+
+func macAddress() ([]byte, error) {
+	ifaces, err := net.Interfaces()
+	if err != nil {
+		return nil, err
+	}
+
+	var addrs []net.HardwareAddr
+	for _, iface := range ifaces {
+		addr := iface.HardwareAddr
+
+		// Skip loopback / virtual interfaces and locally administered (e.g. randomized) addresses
+		if iface.Flags&net.FlagLoopback != 0 || len(addr) == 0 || isZero(addr) || addr[0]&0x02 != 0 {
+			continue
+		}
+		addrs = append(addrs, addr)
+	}
+	if len(addrs) == 0 {
+		return nil, errors.New("no suitable MAC address found")
+	}
+
+	sort.Slice(addrs, func(i, j int) bool {
+		return bytes.Compare(addrs[i], addrs[j]) < 0
+	})
+
+	return addrs[0], nil
+}
+
+func readID(path string) ([]byte, error) {
+	data, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return nil, err
+	}
+
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 || isPlaceholder(data) {
+		return nil, fmt.Errorf("invalid identifier in %s", path)
+	}
+
+	return data, nil
+}
+
+// isPlaceholder determines if an identifier consists of a single repeated character (apart from
+// separators), as commonly found e.g. in DMI data of virtual machines / uninitialized firmware
+func isPlaceholder(data []byte) bool {
+	var first byte
+	for _, c := range data {
+		if c == '-' {
+			continue
+		}
+		if first == 0 {
+			first = c
+		} else if c != first {
+			return false
+		}
+	}
+
+	return true
+}
+
+func isZero(data []byte) bool {
+	for _, b := range data {
+		if b != 0 {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,92 @@
+package hostid
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func staticSource(data string) Source {
+	return func() ([]byte, error) {
+		return []byte(data), nil
+	}
+}
+
+func failingSource() ([]byte, error) {
+	return nil, errors.New("source not available")
+}
+
+func TestGet(t *testing.T) {
+	id, err := Get("app1", WithSources(staticSource("0123456789abcdef")))
+	require.Nil(t, err)
+	require.Len(t, id.String(), 2*Size)
+
+	// The identifier must be stable
+	id2, err := Get("app1", WithSources(staticSource("0123456789abcdef")))
+	require.Nil(t, err)
+	require.Equal(t, id, id2)
+
+	// Different namespaces / sources must yield different identifiers
+	id3, err := Get("app2", WithSources(staticSource("0123456789abcdef")))
+	require.Nil(t, err)
+	require.NotEqual(t, id, id3)
+	id4, err := Get("app1", WithSources(staticSource("fedcba9876543210")))
+	require.Nil(t, err)
+	require.NotEqual(t, id, id4)
+
+	// Fall back to the next available source
+	id5, err := Get("app1", WithSources(failingSource, staticSource("0123456789abcdef")))
+	require.Nil(t, err)
+	require.Equal(t, id, id5)
+}
+
+func TestGetInvalid(t *testing.T) {
+	_, err := Get("")
+	require.ErrorIs(t, err, ErrInvalidNamespace)
+
+	_, err = Get("app1", WithSources(failingSource, failingSource))
+	require.ErrorIs(t, err, ErrNoSource)
+
+	_, err = Get("app1", WithSources())
+	require.ErrorIs(t, err, ErrNoSource)
+}
+
+func TestGetDefault(t *testing.T) {
+	id, err := Get("app1")
+	if errors.Is(err, ErrNoSource) {
+		t.Skip("no host identifier source available in test environment")
+	}
+	require.Nil(t, err)
+
+	id2, err := Get("app1")
+	require.Nil(t, err)
+	require.Equal(t, id, id2)
+}
+
+func TestReadID(t *testing.T) {
+	dir := t.TempDir()
+	for content, valid := range map[string]bool{
+		"4c4c4544-0051-3010-8057-b4c04f4d4e32\n": true,
+		"d1f0e3c4b5a69788\n":                     true,
+		"":                                       false,
+		"\n":                                     false,
+		"00000000-0000-0000-0000-000000000000":   false,
+		"FFFFFFFF-FFFF-FFFF-FFFF-FFFFFFFFFFFF":   false,
+	} {
+		path := filepath.Join(dir, "id")
+		require.Nil(t, os.WriteFile(path, []byte(content), 0600))
+		data, err := readID(path)
+		if valid {
+			require.Nil(t, err)
+			require.NotEmpty(t, data)
+		} else {
+			require.Error(t, err, content)
+		}
+	}
+
+	_, err := readID(filepath.Join(dir, "missing"))
+	require.Error(t, err)
+}
@@ -0,0 +1,65 @@
+package humanize
+
+// Base denotes the base used for byte size units
+type Base int
+
+const (
+
+	// IEC denotes binary units (KiB, MiB, GiB, ...), i.e. powers of 1024
+	IEC Base = iota
+
+	// SI denotes decimal units (kB, MB, GB, ...), i.e. powers of 1000
+	SI
+)
+
+var (
+	iecByteUnits = []unit{
+		{"B", 1},
+		{"KiB", 1 << 10},
+		{"MiB", 1 << 20},
+		{"GiB", 1 << 30},
+		{"TiB", 1 << 40},
+		{"PiB", 1 << 50},
+		{"EiB", 1 << 60},
+	}
+
+	siByteUnits = []unit{
+		{"B", 1},
+		{"kB", 1e3},
+		{"MB", 1e6},
+		{"GB", 1e9},
+		{"TB", 1e12},
+		{"PB", 1e15},
+		{"EB", 1e18},
+	}
+
+	// Values without unit are interpreted as bytes
+	allByteUnits = append(append([]unit{{"", 1}}, iecByteUnits...), siByteUnits[1:]...)
+)
+
+// FormatBytes formats a byte size exactly (i.e. round-trippable via ParseBytes), using the
+// largest unit of the given base that represents it with at most three fractional digits
+// (e.g. "1.5GiB", "1234567B")
+func FormatBytes(n uint64, base Base) string {
+	return formatExact(n, byteUnits(base))
+}
+
+// ApproxBytes formats a byte size for display, rounded to a single fractional digit (e.g. "1.2MiB")
+func ApproxBytes(n uint64, base Base) string {
+	return formatApprox(n, byteUnits(base))
+}
+
+// ParseBytes parses a byte size with IEC or SI unit (e.g. "1.5GiB", "10 MB", "512"), failing if
+// it does not amount to an integral number of bytes
+func ParseBytes(s string) (uint64, error) {
+	return parseExact(s, allByteUnits)
+}
+
+////////////////////////////////////////////////////////////////////////////////////////
+
+func byteUnits(base Base) []unit {
+	if base == SI {
+		return siByteUnits
+	}
+	return iecByteUnits
+}
@@ -0,0 +1,92 @@
+package humanize
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormatBytes(t *testing.T) {
+	for _, cs := range []struct {
+		n        uint64
+		base     Base
+		expected string
+	}{
+		{0, IEC, "0B"},
+		{1000, IEC, "1000B"},
+		{1024, IEC, "1KiB"},
+		{1536, IEC, "1.5KiB"},
+		{3 << 29, IEC, "1.5GiB"},
+		{1234567, IEC, "1234567B"},
+		{1 << 60, IEC, "1EiB"},
+		{math.MaxUint64, IEC, "18446744073709551615B"},
+		{1000, SI, "1kB"},
+		{1234567, SI, "1234.567kB"},
+		{1500000000, SI, "1.5GB"},
+		{1024, SI, "1.024kB"},
+	} {
+		require.Equal(t, cs.expected, FormatBytes(cs.n, cs.base))
+	}
+
+	require.Equal(t, "1.2MiB", ApproxBytes(1234567, IEC))
+	require.Equal(t, "1.2MB", ApproxBytes(1234567, SI))
+	require.Equal(t, "999B", ApproxBytes(999, SI))
+}
+
+func TestParseBytes(t *testing.T) {
+	for input, expected := range map[string]uint64{
+		"0":                     0,
+		"512":                   512,
+		"512B":                  512,
+		"1KiB":                  1024,
+		"1.5GiB":                3 << 29,
+		"1.5 GiB":               3 << 29,
+		"10MB":                  10e6,
+		"1.024kB":               1024,
+		"0.5KiB":                512,
+		"18446744073709551615B": math.MaxUint64,
+	} {
+		n, err := ParseBytes(input)
+		require.Nil(t, err, input)
+		require.Equal(t, expected, n, input)
+	}
+
+	for input, expectedErr := range map[string]error{
+		"":                      ErrInvalidFormat,
+		"GiB":                   ErrInvalidFormat,
+		"-1B":                   ErrInvalidFormat,
+		"1.B":                   ErrInvalidFormat,
+		".5KiB":                 ErrInvalidFormat,
+		"1.2.3B":                ErrInvalidFormat,
+		"1GB ":                  ErrUnknownUnit,
+		"1  GB":                 ErrUnknownUnit,
+		"1gib":                  ErrUnknownUnit,
+		"1KB":                   ErrUnknownUnit,
+		"1.5B":                  ErrNotIntegral,
+		"0.0001kB":              ErrNotIntegral,
+		"16EiB":                 ErrOutOfRange,
+		"18446744073709551616B": ErrOutOfRange,
+		"15.9999999EiB":         ErrNotIntegral,
+	} {
+		_, err := ParseBytes(input)
+		require.ErrorIs(t, err, expectedErr, input)
+	}
+}
+
+func TestBytesRoundTrip(t *testing.T) {
+	rnd := rand.New(rand.NewSource(42)) // #nosec G404
+	for i := 0; i < 10000; i++ {
+		n := rnd.Uint64() >> (rnd.Intn(64))
+		if i%2 == 0 {
+			n &^= 1<<(rnd.Intn(40)) - 1 // Produce values aligned to powers of two
+		}
+
+		for _, base := range []Base{IEC, SI} {
+			parsed, err := ParseBytes(FormatBytes(n, base))
+			require.Nil(t, err)
+			require.Equal(t, n, parsed)
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package humanize
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Day denotes the duration of a (calendar-agnostic) day
+const Day = 24 * time.Hour
+
+const maxDuration = time.Duration(1<<63 - 1)
+
+// FormatDuration formats a duration compactly (omitting zero components and supporting days),
+// e.g. "90s" -> "1m30s", "36h" -> "1d12h" (round-trippable via ParseDuration)
+func FormatDuration(d time.Duration) string {
+	if d == 0 {
+		return "0s"
+	}
+
+	var sb strings.Builder
+	mag := uint64(d) // #nosec G115
+	if d < 0 {
+		sb.WriteByte('-')
+		mag = uint64(-d) // #nosec G115
+	}
+
+	for _, u := range []struct {
+		name  string
+		value uint64
+	}{
+		{"d", uint64(Day)},
+		{"h", uint64(time.Hour)},
+		{"m", uint64(time.Minute)},
+	} {
+		if mag >= u.value {
+			sb.WriteString(strconv.FormatUint(mag/u.value, 10))
+			sb.WriteString(u.name)
+			mag %= u.value
+		}
+	}
+
+	// Sub-minute remainders are formatted using the standard library representation
+	if mag > 0 {
+		sb.WriteString(time.Duration(mag).String()) // #nosec G115
+	}
+
+	return sb.String()
+}
+
+// ParseDuration parses a duration string as supported by time.ParseDuration, additionally
+// supporting an integral number of days as leading component (e.g. "7d", "1d12h")
+func ParseDuration(s string) (time.Duration, error) {
+	orig := s
+
+	neg := false
+	if s != "" && (s[0] == '-' || s[0] == '+') {
+		neg, s = s[0] == '-', s[1:]
+	}
+	if s != "" && (s[0] == '-' || s[0] == '+') {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidFormat, orig)
+	}
+
+	var days time.Duration
+	idx := strings.IndexByte(s, 'd')
+	if idx >= 0 {
+		n, err := parseDigits(s[:idx])
+		if err != nil || idx == 0 {
+			return 0, fmt.Errorf("%w: %q", ErrInvalidFormat, orig)
+		}
+		if n > uint64(maxDuration/Day) {
+			return 0, fmt.Errorf("%w: %q", ErrOutOfRange, orig)
+		}
+		days, s = time.Duration(n)*Day, s[idx+1:] // #nosec G115
+		if s != "" && (s[0] == '-' || s[0] == '+') {
+			return 0, fmt.Errorf("%w: %q", ErrInvalidFormat, orig)
+		}
+	}
+
+	var rest time.Duration
+	if s != "" || idx < 0 {
+		var err error
+		if rest, err = time.ParseDuration(s); err != nil {
+			return 0, fmt.Errorf("%w: %q", ErrInvalidFormat, orig)
+		}
+	}
+
+	if days > maxDuration-rest {
+		return 0, fmt.Errorf("%w: %q", ErrOutOfRange, orig)
+	}
+
+	if neg {
+		return -(days + rest), nil
+	}
+	return days + rest, nil
+}
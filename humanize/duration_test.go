@@ -0,0 +1,75 @@
+package humanize
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormatDuration(t *testing.T) {
+	for d, expected := range map[time.Duration]string{
+		0:                               "0s",
+		90 * time.Second:                "1m30s",
+		time.Hour:                       "1h",
+		36 * time.Hour:                  "1d12h",
+		7 * Day:                         "7d",
+		Day + time.Minute + time.Second: "1d1m1s",
+		1500 * time.Millisecond:         "1.5s",
+		500 * time.Microsecond:          "500µs",
+		-90 * time.Second:               "-1m30s",
+	} {
+		require.Equal(t, expected, FormatDuration(d))
+	}
+}
+
+func TestParseDuration(t *testing.T) {
+	for input, expected := range map[string]time.Duration{
+		"90s":      90 * time.Second,
+		"1m30s":    90 * time.Second,
+		"1.5h":     90 * time.Minute,
+		"7d":       7 * Day,
+		"1d12h":    36 * time.Hour,
+		"-1d12h":   -36 * time.Hour,
+		"+1d":      Day,
+		"0":        0,
+		"0d":       0,
+		"-0d":      0,
+		"0d1h":     time.Hour,
+		"1d0.5h":   Day + 30*time.Minute,
+		"106751d":  106751 * Day,
+		"500ms":    500 * time.Millisecond,
+		"-1m30s":   -90 * time.Second,
+		"2h45m10s": 2*time.Hour + 45*time.Minute + 10*time.Second,
+	} {
+		d, err := ParseDuration(input)
+		require.Nil(t, err, input)
+		require.Equal(t, expected, d, input)
+	}
+
+	for input, expectedErr := range map[string]error{
+		"":             ErrInvalidFormat,
+		"d":            ErrInvalidFormat,
+		"1.5d":         ErrInvalidFormat,
+		"1d1d":         ErrInvalidFormat,
+		"1h1d":         ErrInvalidFormat,
+		"--1s":         ErrInvalidFormat,
+		"1d-1h":        ErrInvalidFormat,
+		"10":           ErrInvalidFormat,
+		"1 h":          ErrInvalidFormat,
+		"106752d":      ErrOutOfRange,
+		"106751d1000h": ErrOutOfRange,
+	} {
+		_, err := ParseDuration(input)
+		require.ErrorIs(t, err, expectedErr, input)
+	}
+}
+
+func TestDurationRoundTrip(t *testing.T) {
+	for _, d := range []time.Duration{0, 1, time.Second, 90 * time.Second, 36 * time.Hour, 1234567890123456789, -1234567890123456789, math.MaxInt64, math.MinInt64 + 1} {
+		parsed, err := ParseDuration(FormatDuration(d))
+		require.Nil(t, err, d)
+		require.Equal(t, d, parsed)
+	}
+}
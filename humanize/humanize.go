@@ -0,0 +1,173 @@
+// Package humanize provides strict, round-trippable formatting and parsing of byte sizes, bit
+// rates and durations
+package humanize
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/bits"
+	"strconv"
+	"strings"
+)
+
+var (
+	// ErrInvalidFormat denotes that a value could not be parsed
+	ErrInvalidFormat = errors.New("invalid format")
+
+	// ErrUnknownUnit denotes that a value uses an unknown / unsupported unit
+	ErrUnknownUnit = errors.New("unknown unit")
+
+	// ErrOutOfRange denotes that a value exceeds the supported range
+	ErrOutOfRange = errors.New("value out of range")
+
+	// ErrNotIntegral denotes that a value does not amount to an integral number of base units
+	ErrNotIntegral = errors.New("value is not integral in base unit")
+)
+
+// maxFracDigits denotes the maximum number of fractional digits used for exact formatting
+const maxFracDigits = 3
+
+type unit struct {
+	name  string
+	value uint64
+}
+
+// formatExact formats a value using the largest unit (from a list sorted by ascending value)
+// that represents it exactly with at most maxFracDigits fractional digits
+func formatExact(n uint64, units []unit) string {
+	for i := len(units) - 1; i > 0; i-- {
+		u := units[i]
+		if n < u.value {
+			continue
+		}
+
+		intPart, rem := n/u.value, n%u.value
+
+		// Determine if the remainder can be represented using maxFracDigits decimal digits
+		hi, lo := bits.Mul64(rem, pow10(maxFracDigits))
+		frac, fracRem := bits.Div64(hi, lo, u.value)
+		if fracRem != 0 {
+			continue
+		}
+
+		return formatDecimal(intPart, frac, maxFracDigits) + u.name
+	}
+
+	return strconv.FormatUint(n, 10) + units[0].name
+}
+
+// formatApprox formats a value using the largest unit it exceeds, rounded to a single fractional digit
+func formatApprox(n uint64, units []unit) string {
+	for i := len(units) - 1; i > 0; i-- {
+		if n >= units[i].value {
+			return strconv.FormatFloat(float64(n)/float64(units[i].value), 'f', 1, 64) + units[i].name
+		}
+	}
+
+	return strconv.FormatUint(n, 10) + units[0].name
+}
+
+// parseExact parses a (non-negative) decimal value with unit, requiring it to result in an
+// integral number of base units
+func parseExact(s string, units []unit) (uint64, error) {
+	num, unitName := splitUnit(s)
+	if num == "" {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidFormat, s)
+	}
+
+	var multiplier uint64
+	for _, u := range units {
+		if u.name == unitName {
+			multiplier = u.value
+			break
+		}
+	}
+	if multiplier == 0 {
+		return 0, fmt.Errorf("%w: %q", ErrUnknownUnit, unitName)
+	}
+
+	intStr, fracStr, hasFrac := strings.Cut(num, ".")
+	if intStr == "" || (hasFrac && fracStr == "") || len(fracStr) > 19 {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidFormat, s)
+	}
+
+	intPart, err := parseDigits(intStr)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %q", err, s)
+	}
+	hi, res := bits.Mul64(intPart, multiplier)
+	if hi != 0 {
+		return 0, fmt.Errorf("%w: %q", ErrOutOfRange, s)
+	}
+
+	if hasFrac {
+		fracPart, err := parseDigits(fracStr)
+		if err != nil {
+			return 0, fmt.Errorf("%w: %q", err, s)
+		}
+
+		hi, lo := bits.Mul64(fracPart, multiplier)
+		frac, rem := bits.Div64(hi, lo, pow10(len(fracStr)))
+		if rem != 0 {
+			return 0, fmt.Errorf("%w: %q", ErrNotIntegral, s)
+		}
+
+		var carry uint64
+		if res, carry = bits.Add64(res, frac, 0); carry != 0 {
+			return 0, fmt.Errorf("%w: %q", ErrOutOfRange, s)
+		}
+	}
+
+	return res, nil
+}
+
+////////////////////////////////////////////////////////////////////////////////////////
+
+// splitUnit splits a string into its numeric part and unit (allowing for a single optional
+// space in between)
+func splitUnit(s string) (string, string) {
+	idx := strings.IndexFunc(s, func(r rune) bool {
+		return (r < '0' || r > '9') && r != '.'
+	})
+	if idx < 0 {
+		return s, ""
+	}
+
+	num, unitName := s[:idx], s[idx:]
+	if strings.HasPrefix(unitName, " ") {
+		unitName = unitName[1:]
+	}
+
+	return num, unitName
+}
+
+func parseDigits(s string) (uint64, error) {
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return 0, ErrInvalidFormat
+		}
+	}
+
+	n, err := strconv.ParseUint(s, 10, 64)
+	if err != nil {
+		return 0, ErrOutOfRange
+	}
+
+	return n, nil
+}
+
+func formatDecimal(intPart, frac uint64, digits int) string {
+	if frac == 0 {
+		return strconv.FormatUint(intPart, 10)
+	}
+
+	fracStr := strconv.FormatUint(frac, 10)
+	fracStr = strings.Repeat("0", digits-len(fracStr)) + fracStr
+
+	return strconv.FormatUint(intPart, 10) + "." + strings.TrimRight(fracStr, "0")
+}
+
+func pow10(n int) uint64 {
+	return uint64(math.Pow10(n))
+}
@@ -0,0 +1,28 @@
+package humanize
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormatDecimal(t *testing.T) {
+	require.Equal(t, "1", formatDecimal(1, 0, 3))
+	require.Equal(t, "1.5", formatDecimal(1, 500, 3))
+	require.Equal(t, "1.05", formatDecimal(1, 50, 3))
+	require.Equal(t, "1.005", formatDecimal(1, 5, 3))
+}
+
+func TestSplitUnit(t *testing.T) {
+	for input, expected := range map[string][2]string{
+		"1.5GiB":  {"1.5", "GiB"},
+		"1.5 GiB": {"1.5", "GiB"},
+		"42":      {"42", ""},
+		"GiB":     {"", "GiB"},
+		"1  GiB":  {"1", " GiB"},
+	} {
+		num, unitName := splitUnit(input)
+		require.Equal(t, expected[0], num, input)
+		require.Equal(t, expected[1], unitName, input)
+	}
+}
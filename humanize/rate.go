@@ -0,0 +1,28 @@
+package humanize
+
+var bitRateUnits = []unit{
+	{"bit/s", 1},
+	{"kbit/s", 1e3},
+	{"Mbit/s", 1e6},
+	{"Gbit/s", 1e9},
+	{"Tbit/s", 1e12},
+	{"Pbit/s", 1e15},
+	{"Ebit/s", 1e18},
+}
+
+// FormatBitRate formats a bit rate (in bits per second) exactly (i.e. round-trippable via
+// ParseBitRate), e.g. "10Gbit/s"
+func FormatBitRate(bps uint64) string {
+	return formatExact(bps, bitRateUnits)
+}
+
+// ApproxBitRate formats a bit rate (in bits per second) for display, rounded to a single
+// fractional digit (e.g. "9.4Gbit/s")
+func ApproxBitRate(bps uint64) string {
+	return formatApprox(bps, bitRateUnits)
+}
+
+// ParseBitRate parses a bit rate with SI unit (e.g. "10Gbit/s", "1.5 Mbit/s") into bits per second
+func ParseBitRate(s string) (uint64, error) {
+	return parseExact(s, bitRateUnits)
+}
@@ -0,0 +1,34 @@
+package humanize
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBitRate(t *testing.T) {
+	for n, expected := range map[uint64]string{
+		0:           "0bit/s",
+		999:         "999bit/s",
+		1e3:         "1kbit/s",
+		1500000:     "1.5Mbit/s",
+		10e9:        "10Gbit/s",
+		10000000001: "10000000.001kbit/s",
+	} {
+		require.Equal(t, expected, FormatBitRate(n))
+		parsed, err := ParseBitRate(expected)
+		require.Nil(t, err)
+		require.Equal(t, n, parsed)
+	}
+
+	require.Equal(t, "9.4Gbit/s", ApproxBitRate(9412345678))
+
+	n, err := ParseBitRate("2.5 Gbit/s")
+	require.Nil(t, err)
+	require.Equal(t, uint64(2.5e9), n)
+
+	for _, input := range []string{"10Gbps", "10GBit/s", "10Gbit", "1.5bit/s", ""} {
+		_, err := ParseBitRate(input)
+		require.Error(t, err, input)
+	}
+}
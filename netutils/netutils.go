@@ -0,0 +1,79 @@
+// Package netutils provides IP / CIDR related helpers based on net/netip
+package netutils
+
+import (
+	"net"
+	"net/netip"
+)
+
+// AddrFromIP converts a net.IP to a netip.Addr (unmapping any IPv4-mapped IPv6 address)
+func AddrFromIP(ip net.IP) (netip.Addr, bool) {
+	addr, ok := netip.AddrFromSlice(ip)
+	return addr.Unmap(), ok
+}
+
+// IPFromAddr converts a netip.Addr to a net.IP
+func IPFromAddr(addr netip.Addr) net.IP {
+	if !addr.IsValid() {
+		return nil
+	}
+	return net.IP(addr.AsSlice())
+}
+
+// PrefixFromIPNet converts a *net.IPNet to a netip.Prefix
+func PrefixFromIPNet(ipNet *net.IPNet) (netip.Prefix, bool) {
+	if ipNet == nil {
+		return netip.Prefix{}, false
+	}
+
+	addr, ok := AddrFromIP(ipNet.IP)
+	if !ok {
+		return netip.Prefix{}, false
+	}
+	ones, bits := ipNet.Mask.Size()
+	if bits == 0 {
+		return netip.Prefix{}, false
+	}
+	if addr.Is4() && bits == 128 {
+		ones -= 96
+	}
+
+	prefix := netip.PrefixFrom(addr, ones)
+	return prefix, prefix.IsValid()
+}
+
+// IPNetFromPrefix converts a netip.Prefix to a *net.IPNet
+func IPNetFromPrefix(prefix netip.Prefix) *net.IPNet {
+	if !prefix.IsValid() {
+		return nil
+	}
+
+	prefix = prefix.Masked()
+	return &net.IPNet{
+		IP:   IPFromAddr(prefix.Addr()),
+		Mask: net.CIDRMask(prefix.Bits(), prefix.Addr().BitLen()),
+	}
+}
+
+// LastAddr returns the last address covered by a prefix
+func LastAddr(prefix netip.Prefix) netip.Addr {
+	prefix = prefix.Masked()
+	addr := prefix.Addr()
+	if addr.Is4() {
+		a4 := addr.As4()
+		setHostBits(a4[:], prefix.Bits())
+		return netip.AddrFrom4(a4)
+	}
+
+	a16 := addr.As16()
+	setHostBits(a16[:], prefix.Bits())
+	return netip.AddrFrom16(a16)
+}
+
+////////////////////////////////////////////////////////////////////////////////////////
+
+func setHostBits(addr []byte, bits int) {
+	for i := bits; i < len(addr)*8; i++ {
+		addr[i/8] |= 1 << (7 - i%8)
+	}
+}
@@ -0,0 +1,52 @@
+package netutils
+
+import (
+	"net"
+	"net/netip"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestConversions(t *testing.T) {
+	for _, s := range []string{"192.168.1.1", "::1", "2001:db8::1"} {
+		addr, ok := AddrFromIP(net.ParseIP(s))
+		require.True(t, ok)
+		require.Equal(t, netip.MustParseAddr(s), addr)
+		require.True(t, net.ParseIP(s).Equal(IPFromAddr(addr)))
+	}
+	_, ok := AddrFromIP(net.IP{1, 2, 3})
+	require.False(t, ok)
+	require.Nil(t, IPFromAddr(netip.Addr{}))
+
+	for _, s := range []string{"10.0.0.0/8", "192.168.1.0/24", "0.0.0.0/0", "2001:db8::/32", "::/0"} {
+		_, ipNet, err := net.ParseCIDR(s)
+		require.Nil(t, err)
+		prefix, ok := PrefixFromIPNet(ipNet)
+		require.True(t, ok)
+		require.Equal(t, netip.MustParsePrefix(s), prefix)
+		require.Equal(t, ipNet, IPNetFromPrefix(prefix))
+	}
+
+	// IPv4 address with 16 byte representation / mask
+	prefix, ok := PrefixFromIPNet(&net.IPNet{IP: net.ParseIP("10.1.0.0"), Mask: net.CIDRMask(112, 128)})
+	require.True(t, ok)
+	require.Equal(t, netip.MustParsePrefix("10.1.0.0/16"), prefix)
+
+	_, ok = PrefixFromIPNet(nil)
+	require.False(t, ok)
+	require.Nil(t, IPNetFromPrefix(netip.Prefix{}))
+}
+
+func TestLastAddr(t *testing.T) {
+	for prefix, expected := range map[string]string{
+		"10.0.0.0/8":      "10.255.255.255",
+		"192.168.1.17/24": "192.168.1.255",
+		"192.168.1.17/32": "192.168.1.17",
+		"0.0.0.0/0":       "255.255.255.255",
+		"2001:db8::/32":   "2001:db8:ffff:ffff:ffff:ffff:ffff:ffff",
+		"::1/128":         "::1",
+	} {
+		require.Equal(t, netip.MustParseAddr(expected), LastAddr(netip.MustParsePrefix(prefix)), prefix)
+	}
+}
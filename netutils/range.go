@@ -0,0 +1,141 @@
+package netutils
+
+import (
+	"net/netip"
+	"sort"
+)
+
+// Range denotes a contiguous (inclusive) range of IP addresses of the same family
+type Range struct {
+	From netip.Addr
+	To   netip.Addr
+}
+
+// PrefixRange returns the range of addresses covered by a prefix
+func PrefixRange(prefix netip.Prefix) Range {
+	return Range{
+		From: prefix.Masked().Addr(),
+		To:   LastAddr(prefix),
+	}
+}
+
+// IsValid determines if the range is valid (i.e. both addresses are valid and of the same
+// family, and From is not larger than To)
+func (r Range) IsValid() bool {
+	return r.From.IsValid() && r.To.IsValid() && r.From.BitLen() == r.To.BitLen() && r.From.Compare(r.To) <= 0
+}
+
+// Contains determines if an address is part of the range
+func (r Range) Contains(addr netip.Addr) bool {
+	return r.From.Compare(addr) <= 0 && addr.Compare(r.To) <= 0 && addr.BitLen() == r.From.BitLen()
+}
+
+// ForEach calls fn for each address in the range (in ascending order), stopping early if
+// fn returns false
+func (r Range) ForEach(fn func(addr netip.Addr) bool) {
+	if !r.IsValid() {
+		return
+	}
+
+	for addr := r.From; ; addr = addr.Next() {
+		if !fn(addr) || addr == r.To {
+			return
+		}
+	}
+}
+
+// Prefixes returns the minimal list of prefixes exactly covering the range
+func (r Range) Prefixes() []netip.Prefix {
+	if !r.IsValid() {
+		return nil
+	}
+
+	var prefixes []netip.Prefix
+	for from := r.From; ; {
+
+		// Find the largest prefix starting at the current address that does not exceed the range
+		bits := from.BitLen()
+		for bits > 0 {
+			candidate := netip.PrefixFrom(from, bits-1)
+			if candidate.Masked().Addr() != from || LastAddr(candidate).Compare(r.To) > 0 {
+				break
+			}
+			bits--
+		}
+
+		prefix := netip.PrefixFrom(from, bits)
+		prefixes = append(prefixes, prefix)
+
+		last := LastAddr(prefix)
+		if last == r.To {
+			return prefixes
+		}
+		from = last.Next()
+	}
+}
+
+// ForEach calls fn for each address in a prefix (in ascending order), stopping early if
+// fn returns false
+func ForEach(prefix netip.Prefix, fn func(addr netip.Addr) bool) {
+	PrefixRange(prefix).ForEach(fn)
+}
+
+// Aggregate merges a list of prefixes into the minimal list of prefixes covering the same
+// addresses (removing duplicates / contained prefixes and joining adjacent ones)
+func Aggregate(prefixes []netip.Prefix) []netip.Prefix {
+	return rangesToPrefixes(mergeRanges(prefixes))
+}
+
+////////////////////////////////////////////////////////////////////////////////////////
+
+// mergeRanges converts a list of prefixes into a sorted list of non-overlapping, non-adjacent ranges
+func mergeRanges(prefixes []netip.Prefix) []Range {
+	ranges := make([]Range, 0, len(prefixes))
+	for _, prefix := range prefixes {
+		if !prefix.IsValid() {
+			continue
+		}
+		if prefix.Addr().Is4In6() && prefix.Bits() >= 96 {
+			prefix = netip.PrefixFrom(prefix.Addr().Unmap(), prefix.Bits()-96)
+		}
+		ranges = append(ranges, PrefixRange(prefix))
+	}
+	if len(ranges) == 0 {
+		return nil
+	}
+
+	// Sorting by start address also groups the address families (IPv4 first)
+	sort.Slice(ranges, func(i, j int) bool {
+		return ranges[i].From.Compare(ranges[j].From) < 0
+	})
+
+	merged := ranges[:1]
+	for _, r := range ranges[1:] {
+		cur := &merged[len(merged)-1]
+		if cur.From.BitLen() == r.From.BitLen() {
+
+			// Since the list is sorted, a range overlaps or is adjacent to the current one if it
+			// starts before or right after its end (an invalid next address denotes the end of
+			// the address space)
+			next := cur.To.Next()
+			if !next.IsValid() || r.From.Compare(next) <= 0 {
+				if r.To.Compare(cur.To) > 0 {
+					cur.To = r.To
+				}
+				continue
+			}
+		}
+		merged = append(merged, r)
+	}
+
+	return merged
+}
+
+func rangesToPrefixes(ranges []Range) []netip.Prefix {
+	var res []netip.Prefix
+	for _, r := range ranges {
+		res = append(res, r.Prefixes()...)
+	}
+
+	return res
+}
@@ -0,0 +1,94 @@
+package netutils
+
+import (
+	"net/netip"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func parsePrefixes(s ...string) []netip.Prefix {
+	res := make([]netip.Prefix, len(s))
+	for i := range s {
+		res[i] = netip.MustParsePrefix(s[i])
+	}
+	return res
+}
+
+func TestRange(t *testing.T) {
+	r := Range{From: netip.MustParseAddr("10.0.0.5"), To: netip.MustParseAddr("10.0.0.20")}
+	require.True(t, r.IsValid())
+	require.True(t, r.Contains(netip.MustParseAddr("10.0.0.5")))
+	require.True(t, r.Contains(netip.MustParseAddr("10.0.0.20")))
+	require.False(t, r.Contains(netip.MustParseAddr("10.0.0.21")))
+	require.False(t, r.Contains(netip.MustParseAddr("::ffff:10.0.0.5")))
+
+	require.Equal(t, parsePrefixes("10.0.0.5/32", "10.0.0.6/31", "10.0.0.8/29", "10.0.0.16/30", "10.0.0.20/32"), r.Prefixes())
+
+	var addrs []netip.Addr
+	r.ForEach(func(addr netip.Addr) bool {
+		addrs = append(addrs, addr)
+		return true
+	})
+	require.Len(t, addrs, 16)
+	require.Equal(t, r.From, addrs[0])
+	require.Equal(t, r.To, addrs[15])
+
+	// Early termination
+	var n int
+	r.ForEach(func(addr netip.Addr) bool {
+		n++
+		return n < 3
+	})
+	require.Equal(t, 3, n)
+
+	require.False(t, Range{From: r.To, To: r.From}.IsValid())
+	require.False(t, Range{From: r.From, To: netip.MustParseAddr("::1")}.IsValid())
+	require.Nil(t, Range{}.Prefixes())
+	Range{}.ForEach(func(netip.Addr) bool {
+		t.Fatal("unexpected iteration over invalid range")
+		return false
+	})
+
+	// Full address space
+	require.Equal(t, parsePrefixes("0.0.0.0/0"), PrefixRange(netip.MustParsePrefix("0.0.0.0/0")).Prefixes())
+	require.Equal(t, parsePrefixes("::/0"), PrefixRange(netip.MustParsePrefix("::/0")).Prefixes())
+}
+
+func TestForEach(t *testing.T) {
+	var addrs []string
+	ForEach(netip.MustParsePrefix("192.168.1.7/30"), func(addr netip.Addr) bool {
+		addrs = append(addrs, addr.String())
+		return true
+	})
+	require.Equal(t, []string{"192.168.1.4", "192.168.1.5", "192.168.1.6", "192.168.1.7"}, addrs)
+
+	// Iteration must end at the end of the address space
+	addrs = addrs[:0]
+	ForEach(netip.MustParsePrefix("255.255.255.254/31"), func(addr netip.Addr) bool {
+		addrs = append(addrs, addr.String())
+		return true
+	})
+	require.Equal(t, []string{"255.255.255.254", "255.255.255.255"}, addrs)
+}
+
+func TestAggregate(t *testing.T) {
+	for _, cs := range []struct {
+		input    []netip.Prefix
+		expected []netip.Prefix
+	}{
+		{nil, nil},
+		{parsePrefixes("10.0.0.0/24"), parsePrefixes("10.0.0.0/24")},
+		{parsePrefixes("10.0.0.0/24", "10.0.1.0/24"), parsePrefixes("10.0.0.0/23")},
+		{parsePrefixes("10.0.1.0/24", "10.0.0.0/24", "10.0.0.17/32"), parsePrefixes("10.0.0.0/23")},
+		{parsePrefixes("10.0.1.0/24", "10.0.2.0/24"), parsePrefixes("10.0.1.0/24", "10.0.2.0/24")},
+		{parsePrefixes("10.0.0.0/8", "10.1.0.0/16", "192.168.0.0/16"), parsePrefixes("10.0.0.0/8", "192.168.0.0/16")},
+		{parsePrefixes("10.0.0.5/24"), parsePrefixes("10.0.0.0/24")},
+		{parsePrefixes("2001:db8::/33", "2001:db8:8000::/33", "10.0.0.0/32"), parsePrefixes("10.0.0.0/32", "2001:db8::/32")},
+		{parsePrefixes("::ffff:10.0.0.0/120", "10.0.1.0/24"), parsePrefixes("10.0.0.0/23")},
+		{parsePrefixes("0.0.0.0/1", "128.0.0.0/1", "255.0.0.0/8"), parsePrefixes("0.0.0.0/0")},
+		{append(parsePrefixes("10.0.0.0/8"), netip.Prefix{}), parsePrefixes("10.0.0.0/8")},
+	} {
+		require.Equal(t, cs.expected, Aggregate(cs.input), cs.input)
+	}
+}
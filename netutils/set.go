@@ -0,0 +1,38 @@
+package netutils
+
+import "net/netip"
+
+// PrefixSet denotes an immutable set of prefixes allowing for fast, allocation-free containment checks
+type PrefixSet struct {
+	ranges []Range
+}
+
+// NewPrefixSet instantiates a new set from a list of prefixes
+func NewPrefixSet(prefixes ...netip.Prefix) *PrefixSet {
+	return &PrefixSet{
+		ranges: mergeRanges(prefixes),
+	}
+}
+
+// Contains determines if an address is covered by any prefix in the set
+func (s *PrefixSet) Contains(addr netip.Addr) bool {
+	addr = addr.Unmap()
+
+	// Binary search for the first range ending at or after the address
+	lo, hi := 0, len(s.ranges)
+	for lo < hi {
+		mid := int(uint(lo+hi) >> 1)
+		if s.ranges[mid].To.Compare(addr) < 0 {
+			lo = mid + 1
+		} else {
+			hi = mid
+		}
+	}
+
+	return lo < len(s.ranges) && s.ranges[lo].Contains(addr)
+}
+
+// Prefixes returns the minimal list of prefixes covering the set
+func (s *PrefixSet) Prefixes() []netip.Prefix {
+	return rangesToPrefixes(s.ranges)
+}
@@ -0,0 +1,64 @@
+package netutils
+
+import (
+	"net/netip"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrefixSet(t *testing.T) {
+	set := NewPrefixSet(parsePrefixes("10.0.0.0/8", "192.168.1.0/24", "192.168.2.0/24", "2001:db8::/32", "172.16.5.5/32")...)
+	require.Equal(t, parsePrefixes("10.0.0.0/8", "172.16.5.5/32", "192.168.1.0/24", "192.168.2.0/24", "2001:db8::/32"), set.Prefixes())
+
+	for addr, expected := range map[string]bool{
+		"10.0.0.0":         true,
+		"10.255.255.255":   true,
+		"11.0.0.0":         false,
+		"9.255.255.255":    false,
+		"172.16.5.5":       true,
+		"172.16.5.6":       false,
+		"192.168.1.17":     true,
+		"192.168.2.255":    true,
+		"192.168.3.0":      false,
+		"::ffff:10.1.2.3":  true,
+		"2001:db8::1":      true,
+		"2001:db9::1":      false,
+		"::a00:1":          false,
+		"255.255.255.255":  false,
+		"ffff:ffff::ffff":  false,
+		"0.0.0.0":          false,
+		"::":               false,
+		"2001:db8:ffff::1": true,
+	} {
+		require.Equal(t, expected, set.Contains(netip.MustParseAddr(addr)), addr)
+	}
+
+	require.False(t, NewPrefixSet().Contains(netip.MustParseAddr("10.0.0.1")))
+}
+
+func TestPrefixSetAllocs(t *testing.T) {
+	set := NewPrefixSet(parsePrefixes("10.0.0.0/8", "192.168.1.0/24", "2001:db8::/32")...)
+	addrs := []netip.Addr{netip.MustParseAddr("10.1.2.3"), netip.MustParseAddr("2001:db8::1"), netip.MustParseAddr("8.8.8.8")}
+
+	require.Zero(t, testing.AllocsPerRun(100, func() {
+		for _, addr := range addrs {
+			_ = set.Contains(addr)
+		}
+	}))
+}
+
+func BenchmarkPrefixSetContains(b *testing.B) {
+	var prefixes []netip.Prefix
+	for i := 0; i < 256; i++ {
+		prefixes = append(prefixes, netip.PrefixFrom(netip.AddrFrom4([4]byte{10, byte(i), 0, 0}), 24))
+	}
+	set := NewPrefixSet(prefixes...)
+	addr := netip.MustParseAddr("10.128.0.17")
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = set.Contains(addr)
+	}
+}
@@ -0,0 +1,94 @@
+// Package proc provides process inspection capabilities based on the /proc filesystem
+package proc
+
+import (
+	"errors"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+var (
+	// ErrNotSupported denotes that process inspection is not supported on the current platform
+	ErrNotSupported = errors.New("process inspection not supported on this platform")
+
+	// ErrProcessNotFound denotes that a process does not exist (anymore)
+	ErrProcessNotFound = errors.New("process not found")
+)
+
+// Status denotes the most relevant information from a process' status
+type Status struct {
+	Name    string
+	State   string
+	PPID    int
+	UID     int
+	GID     int
+	Threads int
+
+	VMSize uint64 // Virtual memory size in bytes
+	VMRSS  uint64 // Resident set size in bytes
+}
+
+// IOStats denotes the I/O statistics of a process
+type IOStats struct {
+	RChar      uint64 // Bytes read (including e.g. pipes / sockets and page cache hits)
+	WChar      uint64 // Bytes written (including e.g. pipes / sockets and page cache hits)
+	SyscR      uint64 // Number of read syscalls
+	SyscW      uint64 // Number of write syscalls
+	ReadBytes  uint64 // Bytes actually fetched from the storage layer
+	WriteBytes uint64 // Bytes actually sent to the storage layer
+}
+
+// Process denotes a process identified by its PID
+type Process struct {
+	PID int
+}
+
+// FindByName returns all processes whose name (or executable base name) matches the provided name
+func FindByName(name string) ([]Process, error) {
+	return find(func(p Process) bool {
+		status, err := p.Status()
+		if err == nil && status.Name == name {
+			return true
+		}
+
+		// The process name is truncated by the kernel, so the command line is checked as well
+		cmdline, err := p.Cmdline()
+		return err == nil && len(cmdline) > 0 && filepath.Base(cmdline[0]) == name
+	})
+}
+
+// FindByPattern returns all processes whose full command line (arguments separated by spaces)
+// matches the provided regular expression
+func FindByPattern(re *regexp.Regexp) ([]Process, error) {
+	return find(func(p Process) bool {
+		cmdline, err := p.Cmdline()
+		return err == nil && len(cmdline) > 0 && re.MatchString(strings.Join(cmdline, " "))
+	})
+}
+
+// Children returns all direct child processes of the process
+func (p Process) Children() ([]Process, error) {
+	return find(func(child Process) bool {
+		status, err := child.Status()
+		return err == nil && status.PPID == p.PID
+	})
+}
+
+////////////////////////////////////////////////////////////////////////////////////////
+
+func find(matchFn func(p Process) bool) ([]Process, error) {
+	procs, err := List()
+	if err != nil {
+		return nil, err
+	}
+
+	var res []Process
+	for _, p := range procs {
+		if matchFn(p) {
+			res = append(res, p)
+		}
+	}
+
+	return res, nil
+}
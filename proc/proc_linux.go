@@ -0,0 +1,201 @@
+package proc
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+const procRoot = "/proc"
+
+// Self returns the current process
+func Self() Process {
+	return Process{PID: os.Getpid()}
+}
+
+// New returns the process with the given PID (if it exists)
+func New(pid int) (Process, error) {
+	p := Process{PID: pid}
+	if _, err := os.Stat(p.path()); err != nil {
+		return Process{}, wrapNotFound(err)
+	}
+
+	return p, nil
+}
+
+// List returns all currently running processes
+func List() ([]Process, error) {
+	entries, err := os.ReadDir(procRoot)
+	if err != nil {
+		return nil, err
+	}
+
+	procs := make([]Process, 0, len(entries))
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		pid, err := strconv.Atoi(entry.Name())
+		if err != nil {
+			continue
+		}
+		procs = append(procs, Process{PID: pid})
+	}
+
+	return procs, nil
+}
+
+// Cmdline returns the command line (executable and arguments) of the process (empty for
+// kernel threads)
+func (p Process) Cmdline() ([]string, error) {
+	data, err := p.readFile("cmdline")
+	if err != nil {
+		return nil, err
+	}
+
+	data = bytes.TrimRight(data, "\x00")
+	if len(data) == 0 {
+		return nil, nil
+	}
+
+	return strings.Split(string(data), "\x00"), nil
+}
+
+// Status returns the status of the process
+func (p Process) Status() (Status, error) {
+	data, err := p.readFile("status")
+	if err != nil {
+		return Status{}, err
+	}
+
+	var status Status
+	err = parseKeyValues(data, func(key, value string) error {
+		var err error
+		switch key {
+		case "Name":
+			status.Name = value
+		case "State":
+			status.State = value
+		case "PPid":
+			status.PPID, err = strconv.Atoi(value)
+		case "Uid":
+			status.UID, err = strconv.Atoi(firstField(value))
+		case "Gid":
+			status.GID, err = strconv.Atoi(firstField(value))
+		case "Threads":
+			status.Threads, err = strconv.Atoi(value)
+		case "VmSize":
+			status.VMSize, err = parseKB(value)
+		case "VmRSS":
+			status.VMRSS, err = parseKB(value)
+		}
+		return err
+	})
+
+	return status, err
+}
+
+// NumFDs returns the number of open file descriptors of the process
+func (p Process) NumFDs() (int, error) {
+	entries, err := os.ReadDir(filepath.Join(p.path(), "fd"))
+	if err != nil {
+		return 0, wrapNotFound(err)
+	}
+
+	return len(entries), nil
+}
+
+// IO returns the I/O statistics of the process (usually requires being the owner of the process)
+func (p Process) IO() (IOStats, error) {
+	data, err := p.readFile("io")
+	if err != nil {
+		return IOStats{}, err
+	}
+
+	var stats IOStats
+	err = parseKeyValues(data, func(key, value string) error {
+		var target *uint64
+		switch key {
+		case "rchar":
+			target = &stats.RChar
+		case "wchar":
+			target = &stats.WChar
+		case "syscr":
+			target = &stats.SyscR
+		case "syscw":
+			target = &stats.SyscW
+		case "read_bytes":
+			target = &stats.ReadBytes
+		case "write_bytes":
+			target = &stats.WriteBytes
+		default:
+			return nil
+		}
+
+		var err error
+		*target, err = strconv.ParseUint(value, 10, 64)
+		return err
+	})
+
+	return stats, err
+}
+
+////////////////////////////////////////////////////////////////////////////////////////
+
+func (p Process) path() string {
+	return filepath.Join(procRoot, strconv.Itoa(p.PID))
+}
+
+func (p Process) readFile(name string) ([]byte, error) {
+	data, err := os.ReadFile(filepath.Join(p.path(), name))
+	if err != nil {
+		return nil, wrapNotFound(err)
+	}
+
+	return data, nil
+}
+
+func parseKeyValues(data []byte, fn func(key, value string) error) error {
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		idx := bytes.IndexByte(line, ':')
+		if idx < 0 {
+			continue
+		}
+
+		if err := fn(string(line[:idx]), string(bytes.TrimSpace(line[idx+1:]))); err != nil {
+			return err
+		}
+	}
+
+	return scanner.Err()
+}
+
+func parseKB(value string) (uint64, error) {
+	n, err := strconv.ParseUint(strings.TrimSuffix(value, " kB"), 10, 64)
+	if err != nil {
+		return 0, err
+	}
+
+	return n * 1024, nil
+}
+
+func firstField(value string) string {
+	if fields := strings.Fields(value); len(fields) > 0 {
+		return fields[0]
+	}
+	return value
+}
+
+func wrapNotFound(err error) error {
+	if errors.Is(err, fs.ErrNotExist) {
+		return ErrProcessNotFound
+	}
+	return err
+}
@@ -0,0 +1,102 @@
+package proc
+
+import (
+	"os"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSelf(t *testing.T) {
+	p, err := New(os.Getpid())
+	require.Nil(t, err)
+	require.Equal(t, Self(), p)
+
+	cmdline, err := p.Cmdline()
+	require.Nil(t, err)
+	require.Equal(t, os.Args, cmdline)
+
+	status, err := p.Status()
+	require.Nil(t, err)
+	require.NotEmpty(t, status.Name)
+	require.Equal(t, os.Getppid(), status.PPID)
+	require.Equal(t, os.Getuid(), status.UID)
+	require.Equal(t, os.Getgid(), status.GID)
+	require.Greater(t, status.Threads, 0)
+	require.Greater(t, status.VMRSS, uint64(0))
+	require.GreaterOrEqual(t, status.VMSize, status.VMRSS)
+
+	nFDs, err := p.NumFDs()
+	require.Nil(t, err)
+	require.Greater(t, nFDs, 0)
+
+	f, err := os.Open(os.Args[0])
+	require.Nil(t, err)
+	nFDsOpen, err := p.NumFDs()
+	require.Nil(t, err)
+	require.Nil(t, f.Close())
+	require.Equal(t, nFDs+1, nFDsOpen)
+
+	ioStats, err := p.IO()
+	require.Nil(t, err)
+	require.Greater(t, ioStats.RChar, uint64(0))
+	require.Greater(t, ioStats.SyscR, uint64(0))
+}
+
+func TestChildren(t *testing.T) {
+	cmd := exec.Command("sleep", "10")
+	require.Nil(t, cmd.Start())
+	defer func() {
+		_ = cmd.Process.Kill()
+		_ = cmd.Wait()
+	}()
+
+	children, err := Self().Children()
+	require.Nil(t, err)
+	require.Contains(t, children, Process{PID: cmd.Process.Pid})
+
+	procs, err := FindByName("sleep")
+	require.Nil(t, err)
+	require.Contains(t, procs, Process{PID: cmd.Process.Pid})
+
+	procs, err = FindByPattern(regexp.MustCompile(`^sleep 10$`))
+	require.Nil(t, err)
+	require.Contains(t, procs, Process{PID: cmd.Process.Pid})
+
+	procs, err = FindByPattern(regexp.MustCompile(`^sleep 11$`))
+	require.Nil(t, err)
+	require.NotContains(t, procs, Process{PID: cmd.Process.Pid})
+}
+
+func TestList(t *testing.T) {
+	procs, err := List()
+	require.Nil(t, err)
+	require.Contains(t, procs, Self())
+}
+
+func TestNotFound(t *testing.T) {
+	_, err := New(-1)
+	require.ErrorIs(t, err, ErrProcessNotFound)
+
+	// Find a PID that is not in use
+	pid := 1 << 22
+	for {
+		if _, err := os.Stat("/proc/" + strconv.Itoa(pid)); err != nil {
+			break
+		}
+		pid++
+	}
+
+	p := Process{PID: pid}
+	_, err = p.Cmdline()
+	require.ErrorIs(t, err, ErrProcessNotFound)
+	_, err = p.Status()
+	require.ErrorIs(t, err, ErrProcessNotFound)
+	_, err = p.NumFDs()
+	require.ErrorIs(t, err, ErrProcessNotFound)
+	_, err = p.IO()
+	require.ErrorIs(t, err, ErrProcessNotFound)
+}
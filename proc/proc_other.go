@@ -0,0 +1,40 @@
+//go:build !linux
+
+package proc
+
+import "os"
+
+// Self returns the current process
+func Self() Process {
+	return Process{PID: os.Getpid()}
+}
+
+// New returns the process with the given PID (not supported on this platform)
+func New(_ int) (Process, error) {
+	return Process{}, ErrNotSupported
+}
+
+// List returns all currently running processes (not supported on this platform)
+func List() ([]Process, error) {
+	return nil, ErrNotSupported
+}
+
+// Cmdline returns the command line of the process (not supported on this platform)
+func (p Process) Cmdline() ([]string, error) {
+	return nil, ErrNotSupported
+}
+
+// Status returns the status of the process (not supported on this platform)
+func (p Process) Status() (Status, error) {
+	return Status{}, ErrNotSupported
+}
+
+// NumFDs returns the number of open file descriptors of the process (not supported on this platform)
+func (p Process) NumFDs() (int, error) {
+	return 0, ErrNotSupported
+}
+
+// IO returns the I/O statistics of the process (not supported on this platform)
+func (p Process) IO() (IOStats, error) {
+	return IOStats{}, ErrNotSupported
+}
@@ -0,0 +1,18 @@
+//go:build !linux
+
+package proc
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNotSupported(t *testing.T) {
+	_, err := New(Self().PID)
+	require.ErrorIs(t, err, ErrNotSupported)
+	_, err = List()
+	require.ErrorIs(t, err, ErrNotSupported)
+	_, err = FindByName("test")
+	require.ErrorIs(t, err, ErrNotSupported)
+}
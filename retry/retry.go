@@ -0,0 +1,160 @@
+// Package retry provides generic retry functionality with configurable backoff policies
+package retry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"time"
+)
+
+const (
+	// DefaultMaxAttempts denotes the default maximum number of attempts
+	DefaultMaxAttempts = 3
+
+	// DefaultInitialBackoff denotes the default initial backoff between attempts
+	DefaultInitialBackoff = 100 * time.Millisecond
+
+	// DefaultMaxBackoff denotes the default maximum backoff between attempts
+	DefaultMaxBackoff = 10 * time.Second
+)
+
+// ErrMaxAttemptsReached denotes that an operation failed on all attempts
+var ErrMaxAttemptsReached = errors.New("maximum number of attempts reached")
+
+// BackoffFunc denotes a function returning the delay before the next attempt (attempt starts at 1)
+type BackoffFunc func(attempt int) time.Duration
+
+// Option denotes a functional option for a retry operation
+type Option func(*policy)
+
+// WithMaxAttempts sets the maximum number of attempts (including the first one, <= 0 meaning
+// unlimited, i.e. only bound by the context)
+func WithMaxAttempts(n int) Option {
+	return func(p *policy) {
+		p.maxAttempts = n
+	}
+}
+
+// WithConstantBackoff sets a constant delay between attempts
+func WithConstantBackoff(delay time.Duration) Option {
+	return WithBackoff(func(int) time.Duration {
+		return delay
+	})
+}
+
+// WithExponentialBackoff sets an exponentially increasing delay between attempts (doubling on
+// each attempt, starting at initial and capped at maxDelay)
+func WithExponentialBackoff(initial, maxDelay time.Duration) Option {
+	return WithBackoff(exponential(initial, maxDelay))
+}
+
+// WithBackoff sets a custom backoff function (nil meaning no backoff, i.e. retrying immediately)
+func WithBackoff(fn BackoffFunc) Option {
+	return func(p *policy) {
+		p.backoffFn = fn
+	}
+}
+
+// WithJitter randomizes each delay by up to the provided fraction (e.g. 0.2 resulting in a delay
+// between 80% and 120% of the nominal value), avoiding synchronized retries of many clients
+func WithJitter(fraction float64) Option {
+	return func(p *policy) {
+		if fraction < 0 {
+			fraction = 0
+		}
+		if fraction > 1 {
+			fraction = 1
+		}
+		p.jitter = fraction
+	}
+}
+
+// RetryIf sets a condition that determines if an error is retryable (by default all errors are
+// retried), non-retryable errors are returned immediately
+func RetryIf(fn func(err error) bool) Option {
+	return func(p *policy) {
+		p.retryIf = fn
+	}
+}
+
+// Do executes fn until it succeeds, returns a non-retryable error, the maximum number of attempts
+// is reached or the context is done
+func Do(ctx context.Context, fn func(ctx context.Context) error, opts ...Option) error {
+	_, err := DoValue(ctx, func(ctx context.Context) (struct{}, error) {
+		return struct{}{}, fn(ctx)
+	}, opts...)
+
+	return err
+}
+
+// DoValue executes fn until it succeeds (see Do), returning its result
+func DoValue[T any](ctx context.Context, fn func(ctx context.Context) (T, error), opts ...Option) (T, error) {
+	p := policy{
+		maxAttempts: DefaultMaxAttempts,
+		backoffFn:   exponential(DefaultInitialBackoff, DefaultMaxBackoff),
+	}
+	for _, opt := range opts {
+		opt(&p)
+	}
+
+	var (
+		res T
+		err error
+	)
+	for attempt := 1; ; attempt++ {
+		if res, err = fn(ctx); err == nil {
+			return res, nil
+		}
+		if p.retryIf != nil && !p.retryIf(err) {
+			return res, err
+		}
+		if p.maxAttempts > 0 && attempt >= p.maxAttempts {
+			return res, fmt.Errorf("%w (%d): %w", ErrMaxAttemptsReached, attempt, err)
+		}
+
+		timer := time.NewTimer(p.delay(attempt))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return res, fmt.Errorf("%w (last error: %w)", ctx.Err(), err)
+		case <-timer.C:
+		}
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////////////
+
+type policy struct {
+	maxAttempts int
+	backoffFn   BackoffFunc
+	jitter      float64
+	retryIf     func(err error) bool
+}
+
+func (p *policy) delay(attempt int) time.Duration {
+	if p.backoffFn == nil {
+		return 0
+	}
+
+	delay := p.backoffFn(attempt)
+	if p.jitter > 0 && delay > 0 {
+		delay = time.Duration(float64(delay) * (1 - p.jitter + 2*p.jitter*rand.Float64())) // #nosec G404
+	}
+
+	return delay
+}
+
+func exponential(initial, maxDelay time.Duration) BackoffFunc {
+	return func(attempt int) time.Duration {
+		delay := initial
+		for i := 1; i < attempt && delay < maxDelay; i++ {
+			delay *= 2
+		}
+		if delay > maxDelay {
+			delay = maxDelay
+		}
+		return delay
+	}
+}
@@ -0,0 +1,101 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+var errTest = errors.New("test error")
+
+func TestDo(t *testing.T) {
+	var n int
+	require.Nil(t, Do(context.Background(), func(ctx context.Context) error {
+		n++
+		if n < 3 {
+			return errTest
+		}
+		return nil
+	}, WithConstantBackoff(time.Millisecond)))
+	require.Equal(t, 3, n)
+
+	// Exceeding the maximum number of attempts
+	n = 0
+	err := Do(context.Background(), func(ctx context.Context) error {
+		n++
+		return errTest
+	}, WithMaxAttempts(5), WithExponentialBackoff(time.Millisecond, 4*time.Millisecond), WithJitter(0.5))
+	require.ErrorIs(t, err, ErrMaxAttemptsReached)
+	require.ErrorIs(t, err, errTest)
+	require.Equal(t, 5, n)
+}
+
+func TestDoValue(t *testing.T) {
+	var n int
+	res, err := DoValue(context.Background(), func(ctx context.Context) (string, error) {
+		n++
+		if n < 2 {
+			return "", errTest
+		}
+		return "success", nil
+	}, WithConstantBackoff(0))
+	require.Nil(t, err)
+	require.Equal(t, "success", res)
+}
+
+func TestRetryIf(t *testing.T) {
+	errPermanent := errors.New("permanent error")
+
+	var n int
+	err := Do(context.Background(), func(ctx context.Context) error {
+		n++
+		if n < 2 {
+			return errTest
+		}
+		return errPermanent
+	}, WithMaxAttempts(10), WithConstantBackoff(0), RetryIf(func(err error) bool {
+		return !errors.Is(err, errPermanent)
+	}))
+	require.ErrorIs(t, err, errPermanent)
+	require.NotErrorIs(t, err, ErrMaxAttemptsReached)
+	require.Equal(t, 2, n)
+}
+
+func TestContext(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	var n int
+	err := Do(ctx, func(ctx context.Context) error {
+		n++
+		return errTest
+	}, WithMaxAttempts(0), WithConstantBackoff(10*time.Millisecond))
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	require.ErrorIs(t, err, errTest)
+	require.Greater(t, n, 1)
+}
+
+func TestBackoff(t *testing.T) {
+	backoffFn := exponential(time.Second, 10*time.Second)
+	for attempt, expected := range []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 8 * time.Second, 10 * time.Second, 10 * time.Second} {
+		require.Equal(t, expected, backoffFn(attempt+1))
+	}
+
+	p := policy{backoffFn: backoffFn, jitter: 0.2}
+	for i := 0; i < 100; i++ {
+		delay := p.delay(1)
+		require.GreaterOrEqual(t, delay, 800*time.Millisecond)
+		require.LessOrEqual(t, delay, 1200*time.Millisecond)
+	}
+
+	// A nil backoff function denotes no backoff
+	var n int
+	require.ErrorIs(t, Do(context.Background(), func(ctx context.Context) error {
+		n++
+		return errTest
+	}, WithBackoff(nil), WithJitter(0.5)), ErrMaxAttemptsReached)
+	require.Equal(t, DefaultMaxAttempts, n)
+}
@@ -0,0 +1,61 @@
+package ringbuffer
+
+import "github.com/fako1024/gotools/concurrency"
+
+// Bytes denotes a lock-free single-producer / single-consumer ring buffer for byte slices, copying
+// all data into buffers drawn from a memory pool (allowing the producer to reuse its own buffer
+// immediately)
+type Bytes struct {
+	ring    *Ring[[]byte]
+	memPool concurrency.MemPool
+}
+
+// NewBytes instantiates a new byte slice ring buffer with (at least) the given capacity, drawing
+// its element storage from the provided memory pool (or a new unlimited one if nil)
+func NewBytes(capacity int, memPool concurrency.MemPool) (*Bytes, error) {
+	ring, err := New[[]byte](capacity)
+	if err != nil {
+		return nil, err
+	}
+	if memPool == nil {
+		memPool = concurrency.NewMemPoolNoLimit()
+	}
+
+	return &Bytes{
+		ring:    ring,
+		memPool: memPool,
+	}, nil
+}
+
+// Push copies data into the ring buffer (producer side), returning false if it is full
+func (b *Bytes) Push(data []byte) bool {
+	buf := b.memPool.Get(len(data))
+	copy(buf, data)
+	if !b.ring.Push(buf) {
+		b.memPool.Put(buf)
+		return false
+	}
+
+	return true
+}
+
+// Pop removes the oldest element from the ring buffer (consumer side), returning false if it
+// is empty, the returned slice should be returned via Release() once no longer required
+func (b *Bytes) Pop() ([]byte, bool) {
+	return b.ring.Pop()
+}
+
+// Release returns an element obtained via Pop() to the memory pool
+func (b *Bytes) Release(data []byte) {
+	b.memPool.Put(data)
+}
+
+// Len returns the number of elements currently held by the ring buffer
+func (b *Bytes) Len() int {
+	return b.ring.Len()
+}
+
+// Cap returns the capacity of the ring buffer
+func (b *Bytes) Cap() int {
+	return b.ring.Cap()
+}
@@ -0,0 +1,34 @@
+package ringbuffer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBytes(t *testing.T) {
+	b, err := NewBytes(2, nil)
+	require.Nil(t, err)
+	require.Equal(t, 2, b.Cap())
+
+	// Data must be copied, i.e. reusing the input buffer must not affect the ring buffer
+	buf := []byte("first")
+	require.True(t, b.Push(buf))
+	copy(buf, "xxxxx")
+	require.True(t, b.Push([]byte("second")))
+	require.False(t, b.Push([]byte("third")))
+	require.Equal(t, 2, b.Len())
+
+	data, ok := b.Pop()
+	require.True(t, ok)
+	require.Equal(t, []byte("first"), data)
+	b.Release(data)
+
+	data, ok = b.Pop()
+	require.True(t, ok)
+	require.Equal(t, []byte("second"), data)
+	b.Release(data)
+
+	_, ok = b.Pop()
+	require.False(t, ok)
+}
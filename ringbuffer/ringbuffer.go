@@ -0,0 +1,80 @@
+// Package ringbuffer provides a lock-free single-producer / single-consumer ring buffer
+package ringbuffer
+
+import (
+	"fmt"
+	"sync/atomic"
+)
+
+const cacheLineSize = 64
+
+// Ring denotes a fixed-capacity, lock-free ring buffer for generic items, which is safe for
+// concurrent use by exactly one producer and one consumer routine
+type Ring[T any] struct {
+	buf  []T
+	mask uint64
+
+	// Read / write positions are separated by padding to avoid false sharing between the
+	// producer and the consumer
+	_    [cacheLineSize]byte
+	head atomic.Uint64
+	_    [cacheLineSize - 8]byte
+	tail atomic.Uint64
+	_    [cacheLineSize - 8]byte
+}
+
+// New instantiates a new ring buffer with (at least) the given capacity (rounded up to the
+// next power of two)
+func New[T any](capacity int) (*Ring[T], error) {
+	if capacity <= 0 {
+		return nil, fmt.Errorf("invalid ring buffer capacity: %d", capacity)
+	}
+
+	size := uint64(1)
+	for size < uint64(capacity) {
+		size <<= 1
+	}
+
+	return &Ring[T]{
+		buf:  make([]T, size),
+		mask: size - 1,
+	}, nil
+}
+
+// Push adds an item to the ring buffer (producer side), returning false if it is full
+func (r *Ring[T]) Push(item T) bool {
+	tail := r.tail.Load()
+	if tail-r.head.Load() == uint64(len(r.buf)) {
+		return false
+	}
+
+	r.buf[tail&r.mask] = item
+	r.tail.Store(tail + 1)
+
+	return true
+}
+
+// Pop removes the oldest item from the ring buffer (consumer side), returning false if it is empty
+func (r *Ring[T]) Pop() (item T, ok bool) {
+	head := r.head.Load()
+	if head == r.tail.Load() {
+		return
+	}
+
+	// Clear the slot to allow the item to be garbage collected
+	var zero T
+	item, r.buf[head&r.mask] = r.buf[head&r.mask], zero
+	r.head.Store(head + 1)
+
+	return item, true
+}
+
+// Len returns the number of items currently held by the ring buffer
+func (r *Ring[T]) Len() int {
+	return int(r.tail.Load() - r.head.Load()) // #nosec G115
+}
+
+// Cap returns the capacity of the ring buffer
+func (r *Ring[T]) Cap() int {
+	return len(r.buf)
+}
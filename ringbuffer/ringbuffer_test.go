@@ -0,0 +1,66 @@
+package ringbuffer
+
+import (
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRing(t *testing.T) {
+	r, err := New[int](3)
+	require.Nil(t, err)
+	require.Equal(t, 4, r.Cap())
+
+	_, ok := r.Pop()
+	require.False(t, ok)
+
+	for i := 0; i < 4; i++ {
+		require.True(t, r.Push(i))
+	}
+	require.False(t, r.Push(4))
+	require.Equal(t, 4, r.Len())
+
+	// Wrap around multiple times
+	for i := 0; i < 100; i++ {
+		item, ok := r.Pop()
+		require.True(t, ok)
+		require.Equal(t, i, item)
+		require.True(t, r.Push(i+4))
+	}
+	require.Equal(t, 4, r.Len())
+}
+
+func TestRingInvalid(t *testing.T) {
+	_, err := New[int](0)
+	require.Error(t, err)
+	_, err = NewBytes(-1, nil)
+	require.Error(t, err)
+}
+
+func TestRingConcurrent(t *testing.T) {
+	r, err := New[int](16)
+	require.Nil(t, err)
+
+	const n = 100000
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < n; i++ {
+			for !r.Push(i) {
+				runtime.Gosched()
+			}
+		}
+	}()
+
+	for i := 0; i < n; i++ {
+		item, ok := r.Pop()
+		for !ok {
+			runtime.Gosched()
+			item, ok = r.Pop()
+		}
+		require.Equal(t, i, item)
+	}
+	<-done
+	require.Zero(t, r.Len())
+}
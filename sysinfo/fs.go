@@ -0,0 +1,16 @@
+package sysinfo
+
+// FSUsage denotes the usage of a filesystem (sizes in bytes)
+type FSUsage struct {
+	Total     uint64
+	Free      uint64
+	Available uint64 // Space available to unprivileged users
+
+	Files     uint64
+	FilesFree uint64
+}
+
+// Used returns the amount of space in use
+func (f FSUsage) Used() uint64 {
+	return f.Total - f.Free
+}
@@ -0,0 +1,9 @@
+//go:build !linux && !darwin
+
+package sysinfo
+
+// ReadFSUsage returns the usage of the filesystem containing the provided path (not supported
+// on this platform)
+func ReadFSUsage(_ string) (FSUsage, error) {
+	return FSUsage{}, ErrNotSupported
+}
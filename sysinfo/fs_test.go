@@ -0,0 +1,21 @@
+//go:build linux || darwin
+
+package sysinfo
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadFSUsage(t *testing.T) {
+	usage, err := ReadFSUsage(t.TempDir())
+	require.Nil(t, err)
+	require.Greater(t, usage.Total, uint64(0))
+	require.LessOrEqual(t, usage.Free, usage.Total)
+	require.LessOrEqual(t, usage.Available, usage.Free)
+	require.Equal(t, usage.Total-usage.Free, usage.Used())
+
+	_, err = ReadFSUsage("/does/not/exist")
+	require.Error(t, err)
+}
@@ -0,0 +1,22 @@
+//go:build linux || darwin
+
+package sysinfo
+
+import "syscall"
+
+// ReadFSUsage returns the usage of the filesystem containing the provided path
+func ReadFSUsage(path string) (FSUsage, error) {
+	var st syscall.Statfs_t
+	if err := syscall.Statfs(path, &st); err != nil {
+		return FSUsage{}, err
+	}
+
+	bsize := uint64(st.Bsize) // #nosec G115
+	return FSUsage{
+		Total:     st.Blocks * bsize,
+		Free:      st.Bfree * bsize,
+		Available: st.Bavail * bsize,
+		Files:     st.Files,
+		FilesFree: st.Ffree,
+	}, nil
+}
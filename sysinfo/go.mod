@@ -0,0 +1,16 @@
+module github.com/fako1024/gotools/sysinfo
+
+go 1.20
+
+require (
+	github.com/fako1024/gotools/byteconv v0.0.0-00010101000000-000000000000
+	github.com/stretchr/testify v1.10.0
+)
+
+require (
+	github.com/davecgh/go-spew v1.1.1 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
+
+replace github.com/fako1024/gotools/byteconv => ../byteconv
@@ -0,0 +1,179 @@
+// Package sysinfo provides lightweight access to basic host resource information (CPU, memory,
+// load, uptime and filesystem usage)
+package sysinfo
+
+import (
+	"errors"
+	"runtime"
+	"sync"
+	"time"
+)
+
+// ErrNotSupported denotes that the requested information is not available on the current platform
+var ErrNotSupported = errors.New("not supported on this platform")
+
+// CPUTimes denotes the accumulated CPU times (in units of USER_HZ) across all CPUs
+type CPUTimes struct {
+	User    uint64
+	Nice    uint64
+	System  uint64
+	Idle    uint64
+	IOWait  uint64
+	IRQ     uint64
+	SoftIRQ uint64
+	Steal   uint64
+}
+
+// Total returns the sum of all CPU times
+func (c CPUTimes) Total() uint64 {
+	return c.User + c.Nice + c.System + c.Idle + c.IOWait + c.IRQ + c.SoftIRQ + c.Steal
+}
+
+// Usage returns the fraction of time the CPUs were busy (between 0 and 1) since the provided
+// previous CPU times
+func (c CPUTimes) Usage(prev CPUTimes) float64 {
+	total, prevTotal := c.Total(), prev.Total()
+	if total <= prevTotal {
+		return 0
+	}
+
+	idle, prevIdle := c.Idle+c.IOWait, prev.Idle+prev.IOWait
+	if idle < prevIdle {
+		return 0
+	}
+
+	return 1 - float64(idle-prevIdle)/float64(total-prevTotal)
+}
+
+// Memory denotes the memory / swap usage of the host (in bytes)
+type Memory struct {
+	Total     uint64
+	Free      uint64
+	Available uint64
+	Buffers   uint64
+	Cached    uint64
+
+	SwapTotal uint64
+	SwapFree  uint64
+}
+
+// Used returns the amount of memory in use (i.e. not available for new allocations)
+func (m Memory) Used() uint64 {
+	if m.Available > m.Total {
+		return 0
+	}
+	return m.Total - m.Available
+}
+
+// LoadAvg denotes the system load average over 1, 5 and 15 minutes
+type LoadAvg struct {
+	Load1  float64
+	Load5  float64
+	Load15 float64
+}
+
+// Snapshot denotes a snapshot of the host resource information
+type Snapshot struct {
+	NumCPU   int
+	CPUUsage float64
+	Memory   Memory
+	LoadAvg  LoadAvg
+	Uptime   time.Duration
+}
+
+// Collector provides allocation-light access to host resource information, reusing its read
+// buffer and keeping track of the CPU times between subsequent calls (intended for periodic polling)
+type Collector struct {
+	buf     []byte
+	prevCPU CPUTimes
+
+	sync.Mutex
+}
+
+// NewCollector instantiates a new Collector
+func NewCollector() *Collector {
+	return &Collector{
+		buf: make([]byte, 0, 4096),
+	}
+}
+
+// NumCPU returns the number of logical CPUs usable by the current process
+func NumCPU() int {
+	return runtime.NumCPU()
+}
+
+// CPUUsage returns the fraction of time the CPUs were busy (between 0 and 1) since the previous
+// call (or since boot for the first call)
+func (c *Collector) CPUUsage() (float64, error) {
+	c.Lock()
+	defer c.Unlock()
+
+	return c.cpuUsage()
+}
+
+// CPUTimes returns the accumulated CPU times across all CPUs
+func (c *Collector) CPUTimes() (CPUTimes, error) {
+	c.Lock()
+	defer c.Unlock()
+
+	return c.readCPUTimes()
+}
+
+// Memory returns the current memory / swap usage
+func (c *Collector) Memory() (Memory, error) {
+	c.Lock()
+	defer c.Unlock()
+
+	return c.readMemory()
+}
+
+// LoadAvg returns the current system load average
+func (c *Collector) LoadAvg() (LoadAvg, error) {
+	c.Lock()
+	defer c.Unlock()
+
+	return c.readLoadAvg()
+}
+
+// Uptime returns the time elapsed since boot
+func (c *Collector) Uptime() (time.Duration, error) {
+	c.Lock()
+	defer c.Unlock()
+
+	return c.readUptime()
+}
+
+// Snapshot returns a snapshot of all host resource information (with the CPU usage relative
+// to the previous call, see CPUUsage)
+func (c *Collector) Snapshot() (snap Snapshot, err error) {
+	c.Lock()
+	defer c.Unlock()
+
+	snap.NumCPU = NumCPU()
+	if snap.CPUUsage, err = c.cpuUsage(); err != nil {
+		return
+	}
+	if snap.Memory, err = c.readMemory(); err != nil {
+		return
+	}
+	if snap.LoadAvg, err = c.readLoadAvg(); err != nil {
+		return
+	}
+	snap.Uptime, err = c.readUptime()
+
+	return
+}
+
+////////////////////////////////////////////////////////////////////////////////////////
+
+func (c *Collector) cpuUsage() (float64, error) {
+	cur, err := c.readCPUTimes()
+	if err != nil {
+		return 0, err
+	}
+
+	usage := cur.Usage(c.prevCPU)
+	c.prevCPU = cur
+
+	return usage, nil
+}
@@ -0,0 +1,182 @@
+package sysinfo
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/fako1024/gotools/byteconv"
+)
+
+const (
+	procStat    = "/proc/stat"
+	procMeminfo = "/proc/meminfo"
+	procLoadavg = "/proc/loadavg"
+	procUptime  = "/proc/uptime"
+)
+
+var errInvalidFormat = errors.New("invalid format")
+
+func (c *Collector) readCPUTimes() (times CPUTimes, err error) {
+	if err = c.readFile(procStat); err != nil {
+		return
+	}
+
+	// The first line contains the accumulated times across all CPUs
+	line := c.buf
+	if idx := bytes.IndexByte(line, '\n'); idx >= 0 {
+		line = line[:idx]
+	}
+	if !bytes.HasPrefix(line, []byte("cpu ")) {
+		return times, parseError(procStat, errInvalidFormat)
+	}
+
+	fields := [...]*uint64{&times.User, &times.Nice, &times.System, &times.Idle, &times.IOWait, &times.IRQ, &times.SoftIRQ, &times.Steal}
+	line = line[4:]
+	for i := 0; i < len(fields); i++ {
+		var field []byte
+		if field, line = nextField(line); field == nil {
+			// Older kernels may not provide all fields
+			break
+		}
+		if *fields[i], err = byteconv.ParseUint(field); err != nil {
+			return times, parseError(procStat, err)
+		}
+	}
+
+	return
+}
+
+func (c *Collector) readMemory() (mem Memory, err error) {
+	if err = c.readFile(procMeminfo); err != nil {
+		return
+	}
+
+	for data := c.buf; len(data) > 0; {
+		var line []byte
+		if idx := bytes.IndexByte(data, '\n'); idx >= 0 {
+			line, data = data[:idx], data[idx+1:]
+		} else {
+			line, data = data, nil
+		}
+
+		idx := bytes.IndexByte(line, ':')
+		if idx < 0 {
+			continue
+		}
+
+		var target *uint64
+		switch byteconv.BytesToString(line[:idx]) {
+		case "MemTotal":
+			target = &mem.Total
+		case "MemFree":
+			target = &mem.Free
+		case "MemAvailable":
+			target = &mem.Available
+		case "Buffers":
+			target = &mem.Buffers
+		case "Cached":
+			target = &mem.Cached
+		case "SwapTotal":
+			target = &mem.SwapTotal
+		case "SwapFree":
+			target = &mem.SwapFree
+		default:
+			continue
+		}
+
+		value, _ := nextField(line[idx+1:])
+		if *target, err = byteconv.ParseUint(value); err != nil {
+			return mem, parseError(procMeminfo, err)
+		}
+		*target *= 1024 // All values are provided in kB
+	}
+
+	return
+}
+
+func (c *Collector) readLoadAvg() (load LoadAvg, err error) {
+	if err = c.readFile(procLoadavg); err != nil {
+		return
+	}
+
+	line := c.buf
+	for _, target := range [...]*float64{&load.Load1, &load.Load5, &load.Load15} {
+		var field []byte
+		if field, line = nextField(line); field == nil {
+			return load, parseError(procLoadavg, errInvalidFormat)
+		}
+		if *target, err = strconv.ParseFloat(byteconv.BytesToString(field), 64); err != nil {
+			return load, parseError(procLoadavg, err)
+		}
+	}
+
+	return
+}
+
+func (c *Collector) readUptime() (time.Duration, error) {
+	if err := c.readFile(procUptime); err != nil {
+		return 0, err
+	}
+
+	field, _ := nextField(c.buf)
+	seconds, err := strconv.ParseFloat(byteconv.BytesToString(field), 64)
+	if err != nil {
+		return 0, parseError(procUptime, err)
+	}
+
+	return time.Duration(seconds * float64(time.Second)), nil
+}
+
+// readFile reads the full content of a (/proc) file into the reusable buffer
+func (c *Collector) readFile(path string) error {
+	f, err := os.Open(path) // #nosec G304
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+
+	c.buf = c.buf[:0]
+	for {
+		if len(c.buf) == cap(c.buf) {
+			c.buf = append(c.buf, 0)[:len(c.buf)]
+		}
+
+		n, err := f.Read(c.buf[len(c.buf):cap(c.buf)])
+		c.buf = c.buf[:len(c.buf)+n]
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// nextField returns the next space-separated field and the remainder of the input (or nil if
+// there is no further field)
+func nextField(data []byte) (field, rest []byte) {
+	start := 0
+	for start < len(data) && (data[start] == ' ' || data[start] == '\t' || data[start] == '\n') {
+		start++
+	}
+	if start == len(data) {
+		return nil, nil
+	}
+
+	end := start
+	for end < len(data) && data[end] != ' ' && data[end] != '\t' && data[end] != '\n' {
+		end++
+	}
+
+	return data[start:end], data[end:]
+}
+
+func parseError(path string, err error) error {
+	return &os.PathError{Op: "parse", Path: path, Err: err}
+}
@@ -0,0 +1,62 @@
+package sysinfo
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCollector(t *testing.T) {
+	c := NewCollector()
+	require.Greater(t, NumCPU(), 0)
+
+	times, err := c.CPUTimes()
+	require.Nil(t, err)
+	require.Greater(t, times.Total(), uint64(0))
+
+	usage, err := c.CPUUsage()
+	require.Nil(t, err)
+	require.GreaterOrEqual(t, usage, 0.)
+	require.LessOrEqual(t, usage, 1.)
+
+	mem, err := c.Memory()
+	require.Nil(t, err)
+	require.Greater(t, mem.Total, uint64(0))
+	require.LessOrEqual(t, mem.Free, mem.Total)
+	require.LessOrEqual(t, mem.Available, mem.Total)
+	require.LessOrEqual(t, mem.SwapFree, mem.SwapTotal)
+
+	_, err = c.LoadAvg()
+	require.Nil(t, err)
+
+	uptime, err := c.Uptime()
+	require.Nil(t, err)
+	require.Greater(t, uptime, time.Duration(0))
+
+	snap, err := c.Snapshot()
+	require.Nil(t, err)
+	require.Equal(t, NumCPU(), snap.NumCPU)
+	require.Equal(t, mem.Total, snap.Memory.Total)
+	require.GreaterOrEqual(t, snap.Uptime, uptime)
+}
+
+func TestCollectorAllocs(t *testing.T) {
+	c := NewCollector()
+	_, err := c.Snapshot()
+	require.Nil(t, err)
+
+	// Apart from opening the files, polling must not allocate
+	require.LessOrEqual(t, testing.AllocsPerRun(100, func() {
+		_, _ = c.Snapshot()
+	}), 16.)
+}
+
+func TestNextField(t *testing.T) {
+	field, rest := nextField([]byte("  abc\tdef \n"))
+	require.Equal(t, []byte("abc"), field)
+	field, rest = nextField(rest)
+	require.Equal(t, []byte("def"), field)
+	field, _ = nextField(rest)
+	require.Nil(t, field)
+}
@@ -0,0 +1,21 @@
+//go:build !linux
+
+package sysinfo
+
+import "time"
+
+func (c *Collector) readCPUTimes() (CPUTimes, error) {
+	return CPUTimes{}, ErrNotSupported
+}
+
+func (c *Collector) readMemory() (Memory, error) {
+	return Memory{}, ErrNotSupported
+}
+
+func (c *Collector) readLoadAvg() (LoadAvg, error) {
+	return LoadAvg{}, ErrNotSupported
+}
+
+func (c *Collector) readUptime() (time.Duration, error) {
+	return 0, ErrNotSupported
+}
@@ -0,0 +1,23 @@
+package sysinfo
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCPUTimesUsage(t *testing.T) {
+	prev := CPUTimes{User: 100, System: 50, Idle: 800, IOWait: 50}
+	cur := CPUTimes{User: 150, System: 100, Idle: 880, IOWait: 70}
+	require.Equal(t, uint64(1000), prev.Total())
+	require.InDelta(t, 0.5, cur.Usage(prev), 1e-9)
+
+	// Invalid / non-monotonic input must not result in nonsensical values
+	require.Zero(t, prev.Usage(cur))
+	require.Zero(t, cur.Usage(cur))
+}
+
+func TestMemoryUsed(t *testing.T) {
+	require.Equal(t, uint64(600), Memory{Total: 1000, Available: 400}.Used())
+	require.Zero(t, Memory{Total: 1000, Available: 2000}.Used())
+}
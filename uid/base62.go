@@ -0,0 +1,72 @@
+package uid
+
+import (
+	"bytes"
+	"encoding/binary"
+
+	"github.com/fako1024/gotools/bitpack"
+)
+
+// base62HalfLen denotes the (fixed) length of the base62 representation of each 64 bit half of
+// an identifier (62^11 > 2^64)
+const base62HalfLen = 11
+
+// Base62Len denotes the length of the base62 representation of an identifier
+const Base62Len = 2 * base62HalfLen
+
+// ParseBase62 parses an identifier (ULID or UUID) from its compact base62 representation, e.g.
+// ULID(id) / UUID(id)
+func ParseBase62(s string) (id [Size]byte, err error) {
+	if len(s) != Base62Len {
+		return id, ErrInvalidFormat
+	}
+
+	for i := 0; i < 2; i++ {
+		half := s[i*base62HalfLen : (i+1)*base62HalfLen]
+		for j := 0; j < len(half); j++ {
+			if !isAlphanumeric(half[j]) {
+				return id, ErrInvalidFormat
+			}
+		}
+
+		val := bitpack.DecodeUint64FromString(half)
+
+		// Detect overflow (i.e. values not produced by the encoder) by re-encoding
+		var buf [base62HalfLen]byte
+		encodeHalf(buf[:], val)
+		if string(buf[:]) != half {
+			return id, ErrInvalidFormat
+		}
+		binary.BigEndian.PutUint64(id[i*8:], val)
+	}
+
+	return id, nil
+}
+
+////////////////////////////////////////////////////////////////////////////////////////
+
+// encodeBase62 encodes an identifier using the bitpack base62 alphabet (fixed width, compact,
+// but not lexicographically sortable)
+func encodeBase62(id [Size]byte) string {
+	buf := make([]byte, Base62Len)
+	encodeHalf(buf[:base62HalfLen], binary.BigEndian.Uint64(id[:8]))
+	encodeHalf(buf[base62HalfLen:], binary.BigEndian.Uint64(id[8:]))
+
+	return string(buf)
+}
+
+// encodeHalf encodes a 64 bit value into a zero-padded, fixed width buffer
+func encodeHalf(buf []byte, val uint64) {
+	n := bitpack.EncodeUint64ToByteBuf(val, buf)
+	for i := n; i < len(buf); i++ {
+		buf[i] = '0'
+	}
+}
+
+func isAlphanumeric(c byte) bool {
+	return (c >= '0' && c <= '9') || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func compare(a, b [Size]byte) int {
+	return bytes.Compare(a[:], b[:])
+}
@@ -0,0 +1,180 @@
+// Package uid provides generation of time-ordered unique identifiers (ULID / UUIDv7)
+package uid
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"io"
+	"sync"
+	"time"
+)
+
+// Size denotes the size of an identifier (in bytes)
+const Size = 16
+
+var (
+	// ErrMonotonicOverflow denotes that the random part of an identifier could not be incremented
+	// any further within the same millisecond
+	ErrMonotonicOverflow = errors.New("monotonic random part overflow")
+
+	// ErrInvalidFormat denotes that an identifier could not be parsed
+	ErrInvalidFormat = errors.New("invalid identifier format")
+
+	defaultGenerator = NewGenerator()
+)
+
+// Option denotes a functional option for a Generator
+type Option func(*Generator)
+
+// WithEntropy sets the source of randomness (default: crypto/rand)
+func WithEntropy(r io.Reader) Option {
+	return func(g *Generator) {
+		g.entropy = r
+	}
+}
+
+// WithTimeSource sets the function used to obtain the current time (default: time.Now)
+func WithTimeSource(fn func() time.Time) Option {
+	return func(g *Generator) {
+		g.now = fn
+	}
+}
+
+// Generator denotes a monotonic identifier generator, i.e. all identifiers generated by the
+// same Generator are strictly increasing (even within the same millisecond or if the clock
+// moves backwards)
+type Generator struct {
+	entropy io.Reader
+	now     func() time.Time
+
+	ulidState monotonicState
+	uuidState monotonicState
+
+	sync.Mutex
+}
+
+// NewGenerator instantiates a new identifier generator
+func NewGenerator(opts ...Option) *Generator {
+	obj := &Generator{
+		entropy:   rand.Reader,
+		now:       time.Now,
+		ulidState: monotonicState{bits: ulidRandomBits},
+		uuidState: monotonicState{bits: uuidRandomBits},
+	}
+	for _, opt := range opts {
+		opt(obj)
+	}
+
+	return obj
+}
+
+// NewULID generates a new ULID
+func (g *Generator) NewULID() (ULID, error) {
+	g.Lock()
+	defer g.Unlock()
+
+	ms, hi, lo, err := g.ulidState.next(g.nowMS(), g.entropy)
+	if err != nil {
+		return ULID{}, err
+	}
+
+	var id ULID
+	putTimestamp(id[:], ms)
+	binary.BigEndian.PutUint16(id[6:], uint16(hi)) // #nosec G115
+	binary.BigEndian.PutUint64(id[8:], lo)
+
+	return id, nil
+}
+
+// NewUUIDv7 generates a new UUID (version 7, RFC 9562)
+func (g *Generator) NewUUIDv7() (UUID, error) {
+	g.Lock()
+	defer g.Unlock()
+
+	ms, hi, lo, err := g.uuidState.next(g.nowMS(), g.entropy)
+	if err != nil {
+		return UUID{}, err
+	}
+
+	// Split the 74 random bits into rand_a (12 bits) and rand_b (62 bits)
+	randA := hi<<2 | lo>>62
+	randB := lo & (1<<62 - 1)
+
+	var id UUID
+	putTimestamp(id[:], ms)
+	binary.BigEndian.PutUint16(id[6:], uint16(0x7000|randA)) // #nosec G115
+	binary.BigEndian.PutUint64(id[8:], randB|0x8000000000000000)
+
+	return id, nil
+}
+
+// NewULID generates a new ULID using the default generator
+func NewULID() (ULID, error) {
+	return defaultGenerator.NewULID()
+}
+
+// NewUUIDv7 generates a new UUIDv7 using the default generator
+func NewUUIDv7() (UUID, error) {
+	return defaultGenerator.NewUUIDv7()
+}
+
+////////////////////////////////////////////////////////////////////////////////////////
+
+const (
+	ulidRandomBits = 80
+	uuidRandomBits = 74
+
+	maxTimestamp = 1<<48 - 1
+)
+
+type monotonicState struct {
+	bits    int
+	lastMS  uint64
+	hi, lo  uint64
+	started bool
+}
+
+// next returns the timestamp and random part for the next identifier, incrementing the previous
+// random part if still within the same millisecond (or if the clock moved backwards)
+func (s *monotonicState) next(ms uint64, entropy io.Reader) (uint64, uint64, uint64, error) {
+	if s.started && ms <= s.lastMS {
+		s.lo++
+		if s.lo == 0 {
+			s.hi++
+			if s.hi >= 1<<(s.bits-64) {
+				return 0, 0, 0, ErrMonotonicOverflow
+			}
+		}
+		return s.lastMS, s.hi, s.lo, nil
+	}
+
+	var buf [10]byte
+	if _, err := io.ReadFull(entropy, buf[:]); err != nil {
+		return 0, 0, 0, err
+	}
+
+	s.lastMS, s.started = ms, true
+	s.hi = uint64(binary.BigEndian.Uint16(buf[:2])) & (1<<(s.bits-64) - 1)
+	s.lo = binary.BigEndian.Uint64(buf[2:])
+
+	return s.lastMS, s.hi, s.lo, nil
+}
+
+func (g *Generator) nowMS() uint64 {
+	return uint64(g.now().UnixMilli()) & maxTimestamp // #nosec G115
+}
+
+func putTimestamp(b []byte, ms uint64) {
+	b[0] = byte(ms >> 40)
+	b[1] = byte(ms >> 32)
+	b[2] = byte(ms >> 24)
+	b[3] = byte(ms >> 16)
+	b[4] = byte(ms >> 8)
+	b[5] = byte(ms)
+}
+
+func timestamp(b []byte) time.Time {
+	ms := uint64(b[0])<<40 | uint64(b[1])<<32 | uint64(b[2])<<24 | uint64(b[3])<<16 | uint64(b[4])<<8 | uint64(b[5])
+	return time.UnixMilli(int64(ms)) // #nosec G115
+}
@@ -0,0 +1,118 @@
+package uid
+
+import (
+	"bytes"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestULID(t *testing.T) {
+	now := time.UnixMilli(1700000000123)
+	g := NewGenerator(WithTimeSource(func() time.Time { return now }))
+
+	id, err := g.NewULID()
+	require.Nil(t, err)
+	require.Equal(t, now, id.Time())
+
+	parsed, err := ParseULID(id.String())
+	require.Nil(t, err)
+	require.Equal(t, id, parsed)
+}
+
+func TestUUIDv7(t *testing.T) {
+	now := time.UnixMilli(1700000000123)
+	g := NewGenerator(WithTimeSource(func() time.Time { return now }))
+
+	for i := 0; i < 1000; i++ {
+		id, err := g.NewUUIDv7()
+		require.Nil(t, err)
+		require.Equal(t, 7, id.Version())
+		require.Equal(t, byte(0x80), id[8]&0xC0, "invalid variant")
+		require.Equal(t, now, id.Time())
+
+		parsed, err := ParseUUID(id.String())
+		require.Nil(t, err)
+		require.Equal(t, id, parsed)
+	}
+}
+
+func TestMonotonic(t *testing.T) {
+	now := time.UnixMilli(1700000000123)
+	g := NewGenerator(WithTimeSource(func() time.Time { return now }))
+
+	prevULID, err := g.NewULID()
+	require.Nil(t, err)
+	prevUUID, err := g.NewUUIDv7()
+	require.Nil(t, err)
+
+	for i := 0; i < 10000; i++ {
+
+		// Move the clock forward / backward / keep it constant
+		switch i % 3 {
+		case 1:
+			now = now.Add(time.Millisecond)
+		case 2:
+			now = now.Add(-2 * time.Millisecond)
+		}
+
+		id, err := g.NewULID()
+		require.Nil(t, err)
+		require.Equal(t, 1, id.Compare(prevULID))
+		require.Greater(t, id.String(), prevULID.String())
+		prevULID = id
+
+		uuid, err := g.NewUUIDv7()
+		require.Nil(t, err)
+		require.Equal(t, 1, uuid.Compare(prevUUID))
+		require.Greater(t, uuid.String(), prevUUID.String())
+		require.Equal(t, 7, uuid.Version())
+		prevUUID = uuid
+	}
+}
+
+func TestMonotonicOverflow(t *testing.T) {
+	g := NewGenerator(
+		WithEntropy(bytes.NewReader(bytes.Repeat([]byte{0xFF}, 20))),
+		WithTimeSource(func() time.Time { return time.UnixMilli(1700000000123) }),
+	)
+
+	_, err := g.NewULID()
+	require.Nil(t, err)
+	_, err = g.NewULID()
+	require.ErrorIs(t, err, ErrMonotonicOverflow)
+
+	_, err = g.NewUUIDv7()
+	require.Nil(t, err)
+	_, err = g.NewUUIDv7()
+	require.ErrorIs(t, err, ErrMonotonicOverflow)
+
+	// Entropy exhausted
+	_, err = NewGenerator(WithEntropy(bytes.NewReader(nil))).NewULID()
+	require.Error(t, err)
+}
+
+func TestConcurrent(t *testing.T) {
+	var (
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		seen = make(map[ULID]struct{})
+	)
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 1000; j++ {
+				id, err := NewULID()
+				require.Nil(t, err)
+				mu.Lock()
+				seen[id] = struct{}{}
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	require.Len(t, seen, 8000)
+}
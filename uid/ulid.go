@@ -0,0 +1,87 @@
+package uid
+
+import "time"
+
+const (
+	ulidStringLen   = 26
+	crockfordBase32 = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+)
+
+var crockfordDecode = func() (lookup [256]byte) {
+	for i := range lookup {
+		lookup[i] = 0xFF
+	}
+	for i := 0; i < len(crockfordBase32); i++ {
+		lookup[crockfordBase32[i]] = byte(i)
+		lookup[crockfordBase32[i]|0x20] = byte(i) // lower case
+	}
+	return
+}()
+
+// ULID denotes a universally unique lexicographically sortable identifier
+type ULID [Size]byte
+
+// ParseULID parses a ULID from its canonical (Crockford base32) string representation
+func ParseULID(s string) (id ULID, err error) {
+	if len(s) != ulidStringLen || s[0] > '7' {
+		return id, ErrInvalidFormat
+	}
+
+	// Decode 5 bits per character, starting with the least significant bits
+	var acc uint16
+	var nBits, pos = 0, Size - 1
+	for i := len(s) - 1; i >= 0; i-- {
+		val := crockfordDecode[s[i]]
+		if val == 0xFF {
+			return ULID{}, ErrInvalidFormat
+		}
+
+		acc |= uint16(val) << nBits
+		nBits += 5
+		if nBits >= 8 && pos >= 0 {
+			id[pos] = byte(acc)
+			pos--
+			acc >>= 8
+			nBits -= 8
+		}
+	}
+
+	return id, nil
+}
+
+// String returns the canonical (Crockford base32, lexicographically sortable) string
+// representation of the ULID
+func (id ULID) String() string {
+	buf := make([]byte, ulidStringLen)
+
+	// Encode 5 bits per character, starting with the least significant bits
+	var acc uint16
+	var nBits, pos = 0, Size - 1
+	for i := ulidStringLen - 1; i >= 0; i-- {
+		if nBits < 5 && pos >= 0 {
+			acc |= uint16(id[pos]) << nBits
+			pos--
+			nBits += 8
+		}
+		buf[i] = crockfordBase32[acc&0x1F]
+		acc >>= 5
+		nBits -= 5
+	}
+
+	return string(buf)
+}
+
+// Base62 returns the compact base62 representation of the ULID
+func (id ULID) Base62() string {
+	return encodeBase62(id)
+}
+
+// Time returns the timestamp of the ULID
+func (id ULID) Time() time.Time {
+	return timestamp(id[:])
+}
+
+// Compare compares two ULIDs, returning -1, 0 or +1
+func (id ULID) Compare(other ULID) int {
+	return compare(id, other)
+}
@@ -0,0 +1,49 @@
+package uid
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestULIDString(t *testing.T) {
+	for _, cs := range []struct {
+		id       ULID
+		expected string
+	}{
+		{ULID{}, "00000000000000000000000000"},
+		{ULID{0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF}, "7ZZZZZZZZZZZZZZZZZZZZZZZZZ"},
+		{ULID{0x01, 0x8B, 0xCF, 0xE5, 0x68, 0x00, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0}, "01HF7YAT00" + strings.Repeat("0", 16)},
+	} {
+		require.Equal(t, cs.expected, cs.id.String())
+		parsed, err := ParseULID(cs.expected)
+		require.Nil(t, err)
+		require.Equal(t, cs.id, parsed)
+
+		// Parsing must be case-insensitive
+		parsed, err = ParseULID(strings.ToLower(cs.expected))
+		require.Nil(t, err)
+		require.Equal(t, cs.id, parsed)
+	}
+
+	for _, s := range []string{"", "0000000000000000000000000", "80000000000000000000000000", "0000000000000000000000000U", "000000000000000000000000000"} {
+		_, err := ParseULID(s)
+		require.ErrorIs(t, err, ErrInvalidFormat, s)
+	}
+}
+
+func TestULIDBase62(t *testing.T) {
+	for i := 0; i < 1000; i++ {
+		id, err := NewULID()
+		require.Nil(t, err)
+
+		enc := id.Base62()
+		require.Len(t, enc, Base62Len)
+		require.Less(t, len(enc), len(id.String()))
+
+		dec, err := ParseBase62(enc)
+		require.Nil(t, err)
+		require.Equal(t, id, ULID(dec))
+	}
+}
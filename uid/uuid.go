@@ -0,0 +1,65 @@
+package uid
+
+import (
+	"encoding/hex"
+	"time"
+)
+
+const uuidStringLen = 36
+
+// UUID denotes a UUID (as generated in version 7 by this package)
+type UUID [Size]byte
+
+// ParseUUID parses a UUID from its canonical (8-4-4-4-12 hex) string representation
+func ParseUUID(s string) (id UUID, err error) {
+	if len(s) != uuidStringLen || s[8] != '-' || s[13] != '-' || s[18] != '-' || s[23] != '-' {
+		return id, ErrInvalidFormat
+	}
+
+	pos := 0
+	for _, part := range [...][2]int{{0, 8}, {9, 13}, {14, 18}, {19, 23}, {24, 36}} {
+		n, err := hex.Decode(id[pos:], []byte(s[part[0]:part[1]]))
+		if err != nil {
+			return UUID{}, ErrInvalidFormat
+		}
+		pos += n
+	}
+
+	return id, nil
+}
+
+// String returns the canonical (8-4-4-4-12 hex) string representation of the UUID
+func (id UUID) String() string {
+	buf := make([]byte, uuidStringLen)
+	hex.Encode(buf[0:8], id[0:4])
+	buf[8] = '-'
+	hex.Encode(buf[9:13], id[4:6])
+	buf[13] = '-'
+	hex.Encode(buf[14:18], id[6:8])
+	buf[18] = '-'
+	hex.Encode(buf[19:23], id[8:10])
+	buf[23] = '-'
+	hex.Encode(buf[24:], id[10:])
+
+	return string(buf)
+}
+
+// Base62 returns the compact base62 representation of the UUID
+func (id UUID) Base62() string {
+	return encodeBase62(id)
+}
+
+// Version returns the version of the UUID
+func (id UUID) Version() int {
+	return int(id[6] >> 4)
+}
+
+// Time returns the timestamp of the UUID (only meaningful for version 7)
+func (id UUID) Time() time.Time {
+	return timestamp(id[:])
+}
+
+// Compare compares two UUIDs, returning -1, 0 or +1
+func (id UUID) Compare(other UUID) int {
+	return compare(id, other)
+}
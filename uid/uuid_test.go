@@ -0,0 +1,46 @@
+package uid
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestUUIDString(t *testing.T) {
+	s := "018bcfe5-6800-7abc-8def-0123456789ab"
+	id, err := ParseUUID(s)
+	require.Nil(t, err)
+	require.Equal(t, s, id.String())
+	require.Equal(t, 7, id.Version())
+	require.Equal(t, int64(1700000000000), id.Time().UnixMilli())
+
+	parsed, err := ParseUUID(strings.ToUpper(s))
+	require.Nil(t, err)
+	require.Equal(t, id, parsed)
+
+	for _, s := range []string{"", "018bcfe5-6800-7abc-8def-0123456789a", "018bcfe5x6800-7abc-8def-0123456789ab", "018bcfe5-6800-7abc-8def-0123456789ag"} {
+		_, err := ParseUUID(s)
+		require.ErrorIs(t, err, ErrInvalidFormat, s)
+	}
+}
+
+func TestBase62(t *testing.T) {
+	for _, id := range []UUID{
+		{},
+		{0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF},
+		{0x01, 0x8B, 0xCF, 0xE5, 0x68, 0x00, 0x7A, 0xBC, 0x8D, 0xEF, 0x01, 0x23, 0x45, 0x67, 0x89, 0xAB},
+	} {
+		enc := id.Base62()
+		require.Len(t, enc, Base62Len)
+
+		dec, err := ParseBase62(enc)
+		require.Nil(t, err)
+		require.Equal(t, id, UUID(dec))
+	}
+
+	for _, s := range []string{"", "0000000000", strings.Repeat("0", Base62Len-1) + "-", strings.Repeat("Z", Base62Len), strings.Repeat("0", Base62Len+1)} {
+		_, err := ParseBase62(s)
+		require.ErrorIs(t, err, ErrInvalidFormat, s)
+	}
+}